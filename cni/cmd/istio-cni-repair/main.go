@@ -23,6 +23,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	ocprom "contrib.go.opencensus.io/exporter/prometheus"
 	"github.com/prometheus/client_golang/prometheus"
@@ -85,6 +86,19 @@ func parseFlags() (filters *repair.Filters, options *ControllerOptions) {
 		"true",
 		"The value portion of the label which will be set by the reconciler if --label-pods is true")
 
+	pflag.Int(
+		"max-deletions-per-minute",
+		10,
+		"The maximum number of broken pods deleted per minute if --delete-pods is true, 0 means no limit")
+	pflag.Duration(
+		"backoff-base-delay",
+		time.Second,
+		"The initial delay before retrying to repair a pod, doubled on each failure")
+	pflag.Duration(
+		"backoff-max-delay",
+		5*time.Minute,
+		"The maximum delay before retrying to repair a pod")
+
 	pflag.Bool("help", false, "Print usage information")
 
 	pflag.Parse()
@@ -115,10 +129,15 @@ func parseFlags() (filters *repair.Filters, options *ControllerOptions) {
 			LabelPods:     viper.GetBool("label-pods"),
 			PodLabelKey:   viper.GetString("broken-pod-label-key"),
 			PodLabelValue: viper.GetString("broken-pod-label-value"),
+
+			MaxDeletionsPerMinute: viper.GetInt("max-deletions-per-minute"),
+			BackoffBaseDelay:      viper.GetDuration("backoff-base-delay"),
+			BackoffMaxDelay:       viper.GetDuration("backoff-max-delay"),
 		},
 	}
 
 	if nodeName := viper.GetString("node-name"); nodeName != "" {
+		filters.NodeName = nodeName
 		filters.FieldSelectors = fmt.Sprintf("%s=%s,%s", "spec.nodeName", nodeName, filters.FieldSelectors)
 	}
 
@@ -153,6 +172,12 @@ func logCurrentOptions(bpr *repair.BrokenPodReconciler, options *ControllerOptio
 			bpr.Options.PodLabelValue,
 		)
 	}
+	if bpr.Options.DeletePods && bpr.Options.MaxDeletionsPerMinute > 0 {
+		log.Infof("Controller Option: Deleting at most %d broken pods per minute.", bpr.Options.MaxDeletionsPerMinute)
+	}
+	if bpr.Filters.NodeName != "" {
+		log.Infof("Filter option: Only managing pods on node %s", bpr.Filters.NodeName)
+	}
 	if bpr.Filters.SidecarAnnotation != "" {
 		log.Infof("Filter option: Only managing pods with an annotation with key %s", bpr.Filters.SidecarAnnotation)
 	}
@@ -188,6 +213,7 @@ func main() {
 	}
 
 	podFixer := repair.NewBrokenPodReconciler(clientSet, filters, options.RepairOptions)
+	podFixer.RecordEvents()
 	logCurrentOptions(&podFixer, options)
 	stopCh := make(chan struct{})
 
@@ -26,6 +26,8 @@ var (
 	resultSkip    = "skip"
 	resultFail    = "fail"
 
+	resultRateLimited = "rate_limited"
+
 	podsRepaired = monitoring.NewSum(
 		"istio_cni_repair_pods_repaired_total",
 		"Total number of pods repaired by repair controller",
@@ -16,13 +16,19 @@ package repair
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"go.uber.org/multierr"
+	"golang.org/x/time/rate"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
 
 	"istio.io/pkg/log"
 )
@@ -32,8 +38,16 @@ type Options struct {
 	PodLabelValue string `json:"pod_label_value"`
 	LabelPods     bool   `json:"label_pods"`
 	DeletePods    bool   `json:"delete_broken_pods"`
+	// MaxDeletionsPerMinute limits the number of pods deleted per minute, 0 means no limit.
+	MaxDeletionsPerMinute int `json:"max_deletions_per_minute"`
+	// BackoffBaseDelay and BackoffMaxDelay bound the exponential backoff applied to a pod which could not be repaired.
+	BackoffBaseDelay time.Duration `json:"backoff_base_delay"`
+	BackoffMaxDelay  time.Duration `json:"backoff_max_delay"`
 }
 
+// errDeletionRateLimited is returned when a broken pod is not deleted because of the deletions per minute limit.
+var errDeletionRateLimited = errors.New("pod deletion rate limit exceeded")
+
 type Filters struct {
 	NodeName                        string `json:"node_name"`
 	SidecarAnnotation               string `json:"sidecar_annotation"`
@@ -49,15 +63,36 @@ type BrokenPodReconciler struct {
 	client  client.Interface
 	Filters *Filters
 	Options *Options
+
+	deletionLimiter *rate.Limiter
+	recorder        record.EventRecorder
 }
 
 // Constructs a new BrokenPodReconciler struct.
 func NewBrokenPodReconciler(client client.Interface, filters *Filters, options *Options) BrokenPodReconciler {
-	return BrokenPodReconciler{
+	bpr := BrokenPodReconciler{
 		client:  client,
 		Filters: filters,
 		Options: options,
 	}
+	if options.MaxDeletionsPerMinute > 0 {
+		bpr.deletionLimiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(options.MaxDeletionsPerMinute)), options.MaxDeletionsPerMinute)
+	}
+	return bpr
+}
+
+// RecordEvents makes the reconciler record a Kubernetes Event on each pod it repairs.
+func (bpr *BrokenPodReconciler) RecordEvents() {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: bpr.client.CoreV1().Events("")})
+	bpr.recorder = broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "istio-cni-repair", Host: bpr.Filters.NodeName})
+}
+
+func (bpr BrokenPodReconciler) recordEvent(pod *v1.Pod, reason, messageFmt string, args ...interface{}) {
+	if bpr.recorder == nil {
+		return
+	}
+	bpr.recorder.Eventf(pod, v1.EventTypeWarning, reason, messageFmt, args...)
 }
 
 func (bpr BrokenPodReconciler) ReconcilePod(pod v1.Pod) (err error) {
@@ -115,6 +150,8 @@ func (bpr BrokenPodReconciler) labelBrokenPod(pod v1.Pod) (err error) {
 		return
 	}
 	m.With(resultLabel.Value(resultSuccess)).Increment()
+	bpr.recordEvent(&pod, "BrokenPodLabeled", "Pod failed the Istio CNI configuration check, labeled with %s=%s",
+		bpr.Options.PodLabelKey, bpr.Options.PodLabelValue)
 	return
 }
 
@@ -150,6 +187,11 @@ func (bpr BrokenPodReconciler) deleteBrokenPod(pod v1.Pod) error {
 		m.With(resultLabel.Value(resultSkip)).Increment()
 		return nil
 	}
+	if bpr.deletionLimiter != nil && !bpr.deletionLimiter.Allow() {
+		log.Infof("Pod detected as broken, but the deletion rate limit is exceeded: %s/%s", pod.Namespace, pod.Name)
+		m.With(resultLabel.Value(resultRateLimited)).Increment()
+		return errDeletionRateLimited
+	}
 	log.Infof("Pod detected as broken, deleting: %s/%s", pod.Namespace, pod.Name)
 	err := bpr.client.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{})
 	if err != nil {
@@ -157,6 +199,7 @@ func (bpr BrokenPodReconciler) deleteBrokenPod(pod v1.Pod) error {
 		return err
 	}
 	m.With(resultLabel.Value(resultSuccess)).Increment()
+	bpr.recordEvent(&pod, "BrokenPodDeleted", "Pod failed the Istio CNI configuration check, deleted to be recreated")
 	return nil
 }
 
@@ -198,6 +241,12 @@ func (bpr BrokenPodReconciler) detectPod(pod v1.Pod) bool {
 		return false
 	}
 
+	// Only check pods scheduled on the managed node, if any; each node is
+	// repaired by its own instance.
+	if bpr.Filters.NodeName != "" && pod.Spec.NodeName != bpr.Filters.NodeName {
+		return false
+	}
+
 	// Only check pods that have the sidecar annotation; the rest can be
 	// ignored.
 	if bpr.Filters.SidecarAnnotation != "" {
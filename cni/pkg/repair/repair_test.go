@@ -16,6 +16,7 @@ package repair
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -30,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
 
 	"istio.io/istio/tools/istio-iptables/pkg/constants"
 	"istio.io/pkg/monitoring"
@@ -258,6 +260,34 @@ func TestBrokenPodReconciler_detectPod(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"Testing broken pod on the managed node",
+			fields{
+				&Filters{
+					NodeName:              "TestNode",
+					SidecarAnnotation:     "sidecar.istio.io/status",
+					InitContainerName:     constants.ValidationContainerName,
+					InitContainerExitCode: 126,
+				},
+				&Options{},
+			},
+			args{pod: brokenPodTerminating},
+			true,
+		},
+		{
+			"Testing broken pod on another node",
+			fields{
+				&Filters{
+					NodeName:              "OtherNode",
+					SidecarAnnotation:     "sidecar.istio.io/status",
+					InitContainerName:     constants.ValidationContainerName,
+					InitContainerExitCode: 126,
+				},
+				&Options{},
+			},
+			args{pod: brokenPodTerminating},
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -592,6 +622,45 @@ func TestBrokenPodReconciler_deleteBrokenPods(t *testing.T) {
 	}
 }
 
+func TestBrokenPodReconciler_deleteBrokenPodsRateLimited(t *testing.T) {
+	exp := initStats("deleteBrokenPodsRateLimited")
+	bpr := NewBrokenPodReconciler(
+		labelBrokenPodsClientset(workingPod, brokenPodWaiting, brokenPodTerminating),
+		&Filters{
+			InitContainerName:               constants.ValidationContainerName,
+			InitContainerExitCode:           126,
+			InitContainerTerminationMessage: "Died for some reason",
+		},
+		&Options{DeletePods: true, MaxDeletionsPerMinute: 1},
+	)
+	recorder := record.NewFakeRecorder(10)
+	bpr.recorder = recorder
+
+	if err := bpr.ReconcilePod(brokenPodTerminating); err != nil {
+		t.Fatalf("ReconcilePod() error = %v", err)
+	}
+	if err := bpr.ReconcilePod(brokenPodWaiting); !errors.Is(err, errDeletionRateLimited) {
+		t.Fatalf("ReconcilePod() error = %v, want %v", err, errDeletionRateLimited)
+	}
+
+	havePods, err := bpr.client.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("error listing pods: %v", err)
+	}
+	if wantPods := []v1.Pod{brokenPodWaiting, workingPod}; !reflect.DeepEqual(havePods.Items, wantPods) {
+		t.Errorf("havePods = %v, wantPods = %v", havePods.Items, wantPods)
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("got %d events, want 1", len(recorder.Events))
+	}
+	if event := <-recorder.Events; !strings.Contains(event, "BrokenPodDeleted") {
+		t.Errorf("unexpected event %q", event)
+	}
+	if err := checkStats(1, []tag.Tag{{Key: tag.Key(resultLabel), Value: resultRateLimited}, {Key: tag.Key(typeLabel), Value: deleteType}}, exp); err != nil {
+		t.Error(err)
+	}
+}
+
 type testExporter struct {
 	sync.Mutex
 
@@ -15,10 +15,12 @@
 package repair
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"golang.org/x/time/rate"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
@@ -30,18 +32,39 @@ import (
 	"istio.io/pkg/log"
 )
 
+const (
+	defaultBackoffBaseDelay = 5 * time.Millisecond
+	defaultBackoffMaxDelay  = 1000 * time.Second
+)
+
 type Controller struct {
 	clientset     client.Interface
 	workQueue     workqueue.RateLimitingInterface
+	podStore      cache.Store
 	podController cache.Controller
 
 	reconciler BrokenPodReconciler
 }
 
+// newRateLimiter returns a rate limiter backing off exponentially per pod, with an overall rate limit.
+func newRateLimiter(options *Options) workqueue.RateLimiter {
+	baseDelay, maxDelay := options.BackoffBaseDelay, options.BackoffMaxDelay
+	if baseDelay <= 0 {
+		baseDelay = defaultBackoffBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultBackoffMaxDelay
+	}
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+	)
+}
+
 func NewRepairController(reconciler BrokenPodReconciler) (*Controller, error) {
 	c := &Controller{
 		clientset:  reconciler.client,
-		workQueue:  workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		workQueue:  workqueue.NewRateLimitingQueue(newRateLimiter(reconciler.Options)),
 		reconciler: reconciler,
 	}
 
@@ -70,18 +93,37 @@ func NewRepairController(reconciler BrokenPodReconciler) (*Controller, error) {
 		},
 	)
 
-	_, c.podController = cache.NewInformer(podListWatch, &v1.Pod{}, 0, cache.ResourceEventHandlerFuncs{
+	// Only pods which are newly broken are queued; the pods which could not be repaired are
+	// requeued with backoff by processNextItem.
+	c.podStore, c.podController = cache.NewInformer(podListWatch, &v1.Pod{}, 0, cache.ResourceEventHandlerFuncs{
 		AddFunc: func(newObj interface{}) {
-			c.workQueue.AddRateLimited(newObj)
+			if pod, ok := newObj.(*v1.Pod); ok && c.reconciler.detectPod(*pod) {
+				c.enqueue(pod)
+			}
 		},
-		UpdateFunc: func(_, newObj interface{}) {
-			c.workQueue.AddRateLimited(newObj)
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldPod, ok := oldObj.(*v1.Pod)
+			if !ok {
+				return
+			}
+			if pod, ok := newObj.(*v1.Pod); ok && c.reconciler.detectPod(*pod) && !c.reconciler.detectPod(*oldPod) {
+				c.enqueue(pod)
+			}
 		},
 	})
 
 	return c, nil
 }
 
+func (rc *Controller) enqueue(pod *v1.Pod) {
+	key, err := cache.MetaNamespaceKeyFunc(pod)
+	if err != nil {
+		log.Errorf("Error computing the key of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		return
+	}
+	rc.workQueue.Add(key)
+}
+
 func (rc *Controller) Run(stopCh <-chan struct{}) {
 	go rc.podController.Run(stopCh)
 	if !cache.WaitForCacheSync(stopCh, rc.podController.HasSynced) {
@@ -116,7 +158,7 @@ func (rc *Controller) processNextItem() bool {
 	}
 	defer rc.workQueue.Done(obj)
 
-	pod, ok := obj.(*v1.Pod)
+	key, ok := obj.(string)
 	if !ok {
 		log.Errorf("Error decoding object, invalid type. Dropping.")
 		rc.workQueue.Forget(obj)
@@ -125,13 +167,24 @@ func (rc *Controller) processNextItem() bool {
 		return true
 	}
 
-	err := rc.reconciler.ReconcilePod(*pod)
+	item, exists, err := rc.podStore.GetByKey(key)
+	if err != nil || !exists {
+		log.Debugf("Object '%s' removed, dequeue", key)
+		rc.workQueue.Forget(obj)
+		return true
+	}
+	pod := item.(*v1.Pod)
+
+	err = rc.reconciler.ReconcilePod(*pod)
 
 	if err == nil {
 		log.Debugf("Removing %s/%s from work queue", pod.Namespace, pod.Name)
 		rc.workQueue.Forget(obj)
 	} else if rc.workQueue.NumRequeues(obj) < 50 {
-		if strings.Contains(err.Error(), "the object has been modified; please apply your changes to the latest version and try again") {
+		if errors.Is(err, errDeletionRateLimited) {
+			log.Infof("Deletion rate limit exceeded, re-adding %s/%s to work queue", pod.Namespace, pod.Name)
+			rc.workQueue.AddRateLimited(obj)
+		} else if strings.Contains(err.Error(), "the object has been modified; please apply your changes to the latest version and try again") {
 			log.Debugf("Object '%s/%s' modified, requeue for retry", pod.Namespace, pod.Name)
 			log.Infof("Re-adding %s/%s to work queue", pod.Namespace, pod.Name)
 			rc.workQueue.AddRateLimited(obj)
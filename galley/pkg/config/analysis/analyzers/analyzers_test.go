@@ -320,6 +320,7 @@ var testGrid = []testCase{
 		name: "authorizationpolicies",
 		inputFiles: []string{
 			"testdata/authorizationpolicies.yaml",
+			"testdata/authorizationpolicies-requestauthentication.yaml",
 		},
 		analyzer: &authz.AuthorizationPoliciesAnalyzer{},
 		expected: []message{
@@ -332,6 +333,22 @@ var testGrid = []testCase{
 			{msg.ReferencedResourceNotFound, "AuthorizationPolicy httpbin-bogus-not-ns.httpbin"},
 		},
 	},
+	{
+		name: "authorizationpolicies never match",
+		inputFiles: []string{
+			"testdata/authorizationpolicies-never-match.yaml",
+		},
+		meshConfigFile: "testdata/authorizationpolicies-never-match-meshconfig.yaml",
+		analyzer:       &authz.AuthorizationPoliciesAnalyzer{},
+		expected: []message{
+			{msg.AuthorizationPolicyPortNotExposed, "AuthorizationPolicy httpbin-service-port.httpbin"},
+			{msg.AuthorizationPolicyForeignTrustDomain, "AuthorizationPolicy httpbin-foreign-trust-domain.httpbin"},
+			{msg.AuthorizationPolicyRequestAuthNotConfigured, "AuthorizationPolicy mysql-claims.db"},
+			{msg.AuthorizationPolicyRequestAuthNotConfigured, "AuthorizationPolicy mysql-claims.db"},
+			{msg.AuthorizationPolicyHTTPFieldOnTCPPort, "AuthorizationPolicy mysql-http-fields.db"},
+			{msg.AuthorizationPolicyHTTPFieldOnTCPPort, "AuthorizationPolicy mysql-http-fields.db"},
+		},
+	},
 	{
 		name: "destinationrule with no cacert, simple at destinationlevel",
 		inputFiles: []string{
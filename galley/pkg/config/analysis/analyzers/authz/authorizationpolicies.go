@@ -34,10 +34,7 @@ import (
 // AuthorizationPoliciesAnalyzer checks the validity of authorization policies
 type AuthorizationPoliciesAnalyzer struct{}
 
-var (
-	_          analysis.Analyzer = &AuthorizationPoliciesAnalyzer{}
-	meshConfig *v1alpha1.MeshConfig
-)
+var _ analysis.Analyzer = &AuthorizationPoliciesAnalyzer{}
 
 func (a *AuthorizationPoliciesAnalyzer) Metadata() analysis.Metadata {
 	return analysis.Metadata{
@@ -48,26 +45,49 @@ func (a *AuthorizationPoliciesAnalyzer) Metadata() analysis.Metadata {
 			collections.IstioSecurityV1Beta1Authorizationpolicies.Name(),
 			collections.K8SCoreV1Namespaces.Name(),
 			collections.K8SCoreV1Pods.Name(),
+			collections.K8SCoreV1Services.Name(),
+			collections.IstioSecurityV1Beta1Requestauthentications.Name(),
 		},
 	}
 }
 
 func (a *AuthorizationPoliciesAnalyzer) Analyze(c analysis.Context) {
 	podLabelsMap := initPodLabelsMap(c)
+	workloadsMap := initWorkloadsMap(c)
+	mConf := fetchMeshConfig(c)
+	rootNs := mConf.GetRootNamespace()
+
+	var requestAuthns []*resource.Instance
+	c.ForEach(collections.IstioSecurityV1Beta1Requestauthentications.Name(), func(r *resource.Instance) bool {
+		requestAuthns = append(requestAuthns, r)
+		return true
+	})
 
 	c.ForEach(collections.IstioSecurityV1Beta1Authorizationpolicies.Name(), func(r *resource.Instance) bool {
-		a.analyzeNoMatchingWorkloads(r, c, podLabelsMap)
+		a.analyzeNoMatchingWorkloads(r, c, podLabelsMap, rootNs)
 		a.analyzeNamespaceNotFound(r, c)
+		a.analyzeForeignTrustDomains(r, c, mConf)
+
+		// The remaining checks depend on the workloads the policy applies to.
+		apNs := r.Metadata.FullName.Namespace.String()
+		workloads := selectedWorkloads(r.Message.(*v1beta1.AuthorizationPolicy), apNs, apNs == rootNs, workloadsMap)
+		if len(workloads) == 0 {
+			return true
+		}
+		a.analyzeNotExposedPorts(r, c, workloads)
+		a.analyzeRequestAuthNotConfigured(r, c, workloads, requestAuthns, rootNs)
+		a.analyzeHTTPFieldsOnTCPPorts(r, c, workloads)
 		return true
 	})
 }
 
-func (a *AuthorizationPoliciesAnalyzer) analyzeNoMatchingWorkloads(r *resource.Instance, c analysis.Context, podLabelsMap map[string][]k8s_labels.Set) {
+func (a *AuthorizationPoliciesAnalyzer) analyzeNoMatchingWorkloads(r *resource.Instance, c analysis.Context,
+	podLabelsMap map[string][]k8s_labels.Set, rootNs string) {
 	ap := r.Message.(*v1beta1.AuthorizationPolicy)
 	apNs := r.Metadata.FullName.Namespace.String()
 
 	// If AuthzPolicy is mesh-wide
-	if meshWidePolicy(apNs, c) {
+	if apNs == rootNs {
 		// If it has selector, need further analysis
 		if ap.Selector != nil {
 			apSelector := k8s_labels.SelectorFromSet(ap.Selector.MatchLabels)
@@ -98,18 +118,9 @@ func (a *AuthorizationPoliciesAnalyzer) analyzeNoMatchingWorkloads(r *resource.I
 	}
 }
 
-// Returns true when the namespace is the root namespace.
-// It takes the MeshConfig names istio, if not the last instance found.
-func meshWidePolicy(ns string, c analysis.Context) bool {
-	mConf := fetchMeshConfig(c)
-	return mConf != nil && ns == mConf.GetRootNamespace()
-}
-
+// fetchMeshConfig returns the MeshConfig named istio, if not the last instance found.
 func fetchMeshConfig(c analysis.Context) *v1alpha1.MeshConfig {
-	if meshConfig != nil {
-		return meshConfig
-	}
-
+	var meshConfig *v1alpha1.MeshConfig
 	c.ForEach(collections.IstioMeshV1Alpha1MeshConfig.Name(), func(r *resource.Instance) bool {
 		meshConfig = r.Message.(*v1alpha1.MeshConfig)
 		return r.Metadata.FullName.Name != util.MeshConfigName
@@ -18,6 +18,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pkg/config/protocol"
 )
 
 func TestNamespaceMatch(t *testing.T) {
@@ -31,3 +36,56 @@ func TestNamespaceMatch(t *testing.T) {
 	assert.False(namespaceMatch("test-login", "login-*"))
 	assert.True(namespaceMatch("test-login", "*-login"))
 }
+
+func TestPrincipalTrustDomain(t *testing.T) {
+	assert := assert.New(t)
+
+	td, ok := principalTrustDomain("example.com/ns/default/sa/sleep")
+	assert.True(ok)
+	assert.Equal("example.com", td)
+
+	_, ok = principalTrustDomain("*/ns/default/sa/sleep")
+	assert.False(ok)
+	_, ok = principalTrustDomain("*.example.com/ns/default/sa/sleep")
+	assert.False(ok)
+	_, ok = principalTrustDomain("*")
+	assert.False(ok)
+	_, ok = principalTrustDomain("example.com/ns/default")
+	assert.False(ok)
+}
+
+func TestMatchTrustDomain(t *testing.T) {
+	assert := assert.New(t)
+
+	trustDomains := meshTrustDomains(&v1alpha1.MeshConfig{TrustDomain: "example.com", TrustDomainAliases: []string{"old.example.com"}})
+	assert.True(matchTrustDomain("example.com", trustDomains))
+	assert.True(matchTrustDomain("old.example.com", trustDomains))
+	assert.True(matchTrustDomain("cluster.local", trustDomains))
+	assert.False(matchTrustDomain("other.com", trustDomains))
+
+	assert.Equal([]string{"cluster.local"}, meshTrustDomains(nil))
+}
+
+func TestTargetPort(t *testing.T) {
+	assert := assert.New(t)
+
+	port, ok := targetPort(v1.ServicePort{Port: 8000})
+	assert.True(ok)
+	assert.Equal(int32(8000), port)
+
+	port, ok = targetPort(v1.ServicePort{Port: 8000, TargetPort: intstr.FromInt(80)})
+	assert.True(ok)
+	assert.Equal(int32(80), port)
+
+	_, ok = targetPort(v1.ServicePort{Port: 8000, TargetPort: intstr.FromString("http")})
+	assert.False(ok)
+}
+
+func TestTCPOnly(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(tcpOnly(nil))
+	assert.True(tcpOnly([]protocol.Instance{protocol.TCP, protocol.MySQL}))
+	assert.False(tcpOnly([]protocol.Instance{protocol.TCP, protocol.HTTP}))
+	assert.False(tcpOnly([]protocol.Instance{protocol.Unsupported}))
+}
@@ -0,0 +1,382 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	k8s_labels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"istio.io/api/mesh/v1alpha1"
+	"istio.io/api/security/v1beta1"
+	"istio.io/istio/galley/pkg/config/analysis"
+	"istio.io/istio/galley/pkg/config/analysis/analyzers/util"
+	"istio.io/istio/galley/pkg/config/analysis/msg"
+	configKube "istio.io/istio/pkg/config/kube"
+	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/config/resource"
+	"istio.io/istio/pkg/config/schema/collections"
+)
+
+const (
+	defaultTrustDomain = "cluster.local"
+	requestAuthPrefix  = "request.auth."
+	requestHeaderKey   = "request.headers"
+)
+
+// workload is an in-mesh pod, with the services selecting it.
+type workload struct {
+	pod      *v1.Pod
+	services []*v1.ServiceSpec
+}
+
+// initWorkloadsMap builds a map indexed by namespace with the in-mesh pods and the services selecting them.
+func initWorkloadsMap(c analysis.Context) map[string][]workload {
+	servicesMap := make(map[string][]*v1.ServiceSpec)
+	c.ForEach(collections.K8SCoreV1Services.Name(), func(r *resource.Instance) bool {
+		ns := r.Metadata.FullName.Namespace.String()
+		servicesMap[ns] = append(servicesMap[ns], r.Message.(*v1.ServiceSpec))
+		return true
+	})
+
+	workloadsMap := make(map[string][]workload)
+	c.ForEach(collections.K8SCoreV1Pods.Name(), func(r *resource.Instance) bool {
+		if !util.PodInMesh(r, c) {
+			return true
+		}
+		p := r.Message.(*v1.Pod)
+		w := workload{pod: p}
+		for _, svc := range servicesMap[p.Namespace] {
+			if len(svc.Selector) > 0 && k8s_labels.SelectorFromSet(svc.Selector).Matches(k8s_labels.Set(p.Labels)) {
+				w.services = append(w.services, svc)
+			}
+		}
+		workloadsMap[p.Namespace] = append(workloadsMap[p.Namespace], w)
+		return true
+	})
+	return workloadsMap
+}
+
+// selectedWorkloads returns the workloads the policy applies to.
+func selectedWorkloads(ap *v1beta1.AuthorizationPolicy, apNs string, meshWide bool, workloadsMap map[string][]workload) []workload {
+	var candidates []workload
+	if meshWide {
+		for _, ws := range workloadsMap {
+			candidates = append(candidates, ws...)
+		}
+	} else {
+		candidates = workloadsMap[apNs]
+	}
+	if ap.Selector == nil {
+		return candidates
+	}
+
+	selector := k8s_labels.SelectorFromSet(ap.Selector.MatchLabels)
+	var selected []workload
+	for _, w := range candidates {
+		if selector.Matches(k8s_labels.Set(w.pod.Labels)) {
+			selected = append(selected, w)
+		}
+	}
+	return selected
+}
+
+// exposedPorts returns the ports the workloads listen on, as declared by their containers and by the
+// target ports of the services selecting them.
+func exposedPorts(workloads []workload) map[int32]struct{} {
+	ports := make(map[int32]struct{})
+	for _, w := range workloads {
+		for _, c := range w.pod.Spec.Containers {
+			for _, p := range c.Ports {
+				ports[p.ContainerPort] = struct{}{}
+			}
+		}
+		for _, svc := range w.services {
+			for _, p := range svc.Ports {
+				if port, ok := targetPort(p); ok {
+					ports[port] = struct{}{}
+				}
+			}
+		}
+	}
+	return ports
+}
+
+// targetPort returns the numeric port the service port forwards to, if known.
+func targetPort(p v1.ServicePort) (int32, bool) {
+	switch {
+	case p.TargetPort.Type == intstr.String:
+		// Named target ports are resolved with the container ports.
+		return 0, false
+	case p.TargetPort.IntVal == 0:
+		return p.Port, true
+	default:
+		return p.TargetPort.IntVal, true
+	}
+}
+
+// portProtocols returns the protocols of the service ports forwarding to port.
+func portProtocols(workloads []workload, port int32) []protocol.Instance {
+	var protocols []protocol.Instance
+	for _, w := range workloads {
+		for _, svc := range w.services {
+			for _, p := range svc.Ports {
+				if tp, ok := targetPort(p); ok && tp == port {
+					protocols = append(protocols, configKube.ConvertProtocol(p.Port, p.Name, p.Protocol, p.AppProtocol))
+				}
+			}
+		}
+	}
+	return protocols
+}
+
+// tcpOnly returns true when all the protocols are known, and none of them is HTTP.
+func tcpOnly(protocols []protocol.Instance) bool {
+	if len(protocols) == 0 {
+		return false
+	}
+	for _, p := range protocols {
+		if !p.IsTCP() {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *AuthorizationPoliciesAnalyzer) analyzeNotExposedPorts(r *resource.Instance, c analysis.Context, workloads []workload) {
+	ap := r.Message.(*v1beta1.AuthorizationPolicy)
+
+	exposed := exposedPorts(workloads)
+	// The ports of workloads which neither declare them nor are selected by a service are unknown.
+	if len(exposed) == 0 {
+		return
+	}
+
+	for i, rule := range ap.Rules {
+		for j, to := range rule.To {
+			if to.Operation == nil {
+				continue
+			}
+			for k, port := range to.Operation.Ports {
+				p, err := strconv.ParseInt(port, 10, 32)
+				if err != nil {
+					continue
+				}
+				if _, ok := exposed[int32(p)]; ok {
+					continue
+				}
+
+				m := msg.NewAuthorizationPolicyPortNotExposed(r, port, i)
+				if line, ok := util.ErrorLine(r, fmt.Sprintf(util.AuthorizationPolicyPort, i, j, k)); ok {
+					m.Line = line
+				}
+				c.Report(collections.IstioSecurityV1Beta1Authorizationpolicies.Name(), m)
+			}
+		}
+	}
+}
+
+func (a *AuthorizationPoliciesAnalyzer) analyzeForeignTrustDomains(r *resource.Instance, c analysis.Context, mConf *v1alpha1.MeshConfig) {
+	ap := r.Message.(*v1beta1.AuthorizationPolicy)
+
+	trustDomains := meshTrustDomains(mConf)
+	for i, rule := range ap.Rules {
+		for j, from := range rule.From {
+			if from.Source == nil {
+				continue
+			}
+			for k, principal := range from.Source.Principals {
+				td, ok := principalTrustDomain(principal)
+				if !ok || matchTrustDomain(td, trustDomains) {
+					continue
+				}
+
+				m := msg.NewAuthorizationPolicyForeignTrustDomain(r, principal, i, td)
+				if line, ok := util.ErrorLine(r, fmt.Sprintf(util.AuthorizationPolicyPrincipal, i, j, k)); ok {
+					m.Line = line
+				}
+				c.Report(collections.IstioSecurityV1Beta1Authorizationpolicies.Name(), m)
+			}
+		}
+	}
+}
+
+// meshTrustDomains returns the trust domain of the mesh and its aliases.
+func meshTrustDomains(mConf *v1alpha1.MeshConfig) []string {
+	td := mConf.GetTrustDomain()
+	if td == "" {
+		td = defaultTrustDomain
+	}
+	return append([]string{td}, mConf.GetTrustDomainAliases()...)
+}
+
+// principalTrustDomain returns the trust domain of a principal of the form <trust domain>/ns/<namespace>/sa/<service account>.
+// Principals of other forms, and principals with a wildcard trust domain, are not checked.
+func principalTrustDomain(principal string) (string, bool) {
+	parts := strings.Split(principal, "/")
+	if len(parts) != 5 || strings.Contains(parts[0], "*") {
+		return "", false
+	}
+	return parts[0], true
+}
+
+// matchTrustDomain returns true if the trust domain is one the principals of the mesh are matched against.
+// The default trust domain is always accepted, as istiod rewrites it to the mesh trust domain.
+func matchTrustDomain(td string, trustDomains []string) bool {
+	if td == defaultTrustDomain {
+		return true
+	}
+	for _, t := range trustDomains {
+		if td == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *AuthorizationPoliciesAnalyzer) analyzeRequestAuthNotConfigured(r *resource.Instance, c analysis.Context,
+	workloads []workload, requestAuthns []*resource.Instance, rootNs string) {
+	ap := r.Message.(*v1beta1.AuthorizationPolicy)
+	apNs := r.Metadata.FullName.Namespace.String()
+
+	if requestAuthnApplies(apNs, rootNs, workloads, requestAuthns) {
+		return
+	}
+
+	for i, rule := range ap.Rules {
+		for j, from := range rule.From {
+			if from.Source == nil {
+				continue
+			}
+			for k, principal := range from.Source.RequestPrincipals {
+				m := msg.NewAuthorizationPolicyRequestAuthNotConfigured(r, fmt.Sprintf("requestPrincipal %q", principal), i)
+				if line, ok := util.ErrorLine(r, fmt.Sprintf(util.AuthorizationPolicyRequestPrincipal, i, j, k)); ok {
+					m.Line = line
+				}
+				c.Report(collections.IstioSecurityV1Beta1Authorizationpolicies.Name(), m)
+			}
+		}
+		for j, when := range rule.When {
+			// Conditions with only notValues match requests without a JWT.
+			if !strings.HasPrefix(when.Key, requestAuthPrefix) || len(when.Values) == 0 {
+				continue
+			}
+			m := msg.NewAuthorizationPolicyRequestAuthNotConfigured(r, fmt.Sprintf("condition %q", when.Key), i)
+			if line, ok := util.ErrorLine(r, fmt.Sprintf(util.AuthorizationPolicyConditionKey, i, j)); ok {
+				m.Line = line
+			}
+			c.Report(collections.IstioSecurityV1Beta1Authorizationpolicies.Name(), m)
+		}
+	}
+}
+
+// requestAuthnApplies returns true if a RequestAuthentication applies to one of the workloads, or to
+// all the workloads of the policy namespace.
+func requestAuthnApplies(apNs, rootNs string, workloads []workload, requestAuthns []*resource.Instance) bool {
+	for _, ra := range requestAuthns {
+		raNs := ra.Metadata.FullName.Namespace.String()
+		if raNs != apNs && raNs != rootNs {
+			continue
+		}
+		spec := ra.Message.(*v1beta1.RequestAuthentication)
+		if spec.Selector == nil {
+			return true
+		}
+		selector := k8s_labels.SelectorFromSet(spec.Selector.MatchLabels)
+		for _, w := range workloads {
+			if (raNs == rootNs || raNs == w.pod.Namespace) && selector.Matches(k8s_labels.Set(w.pod.Labels)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (a *AuthorizationPoliciesAnalyzer) analyzeHTTPFieldsOnTCPPorts(r *resource.Instance, c analysis.Context, workloads []workload) {
+	ap := r.Message.(*v1beta1.AuthorizationPolicy)
+
+	for i, rule := range ap.Rules {
+		ruleFields := ruleHTTPFields(rule)
+		for j, to := range rule.To {
+			if to.Operation == nil {
+				continue
+			}
+			fields := append(operationHTTPFields(to.Operation), ruleFields...)
+			if len(fields) == 0 {
+				continue
+			}
+			for k, port := range to.Operation.Ports {
+				p, err := strconv.ParseInt(port, 10, 32)
+				if err != nil || !tcpOnly(portProtocols(workloads, int32(p))) {
+					continue
+				}
+				for _, field := range fields {
+					m := msg.NewAuthorizationPolicyHTTPFieldOnTCPPort(r, field, i, port)
+					if line, ok := util.ErrorLine(r, fmt.Sprintf(util.AuthorizationPolicyPort, i, j, k)); ok {
+						m.Line = line
+					}
+					c.Report(collections.IstioSecurityV1Beta1Authorizationpolicies.Name(), m)
+				}
+			}
+		}
+	}
+}
+
+// operationHTTPFields returns the names of the HTTP only fields set in the operation.
+func operationHTTPFields(op *v1beta1.Operation) []string {
+	var fields []string
+	for _, f := range []struct {
+		name   string
+		values []string
+	}{
+		{"hosts", op.Hosts},
+		{"notHosts", op.NotHosts},
+		{"methods", op.Methods},
+		{"notMethods", op.NotMethods},
+		{"paths", op.Paths},
+		{"notPaths", op.NotPaths},
+	} {
+		if len(f.values) > 0 {
+			fields = append(fields, f.name)
+		}
+	}
+	return fields
+}
+
+// ruleHTTPFields returns the names of the HTTP only sources and conditions of the rule.
+func ruleHTTPFields(rule *v1beta1.Rule) []string {
+	var fields []string
+	for _, from := range rule.From {
+		if from.Source == nil {
+			continue
+		}
+		if len(from.Source.RequestPrincipals) > 0 {
+			fields = append(fields, "requestPrincipals")
+		}
+		if len(from.Source.NotRequestPrincipals) > 0 {
+			fields = append(fields, "notRequestPrincipals")
+		}
+	}
+	for _, when := range rule.When {
+		if strings.HasPrefix(when.Key, requestAuthPrefix) || strings.HasPrefix(when.Key, requestHeaderKey) {
+			fields = append(fields, fmt.Sprintf("condition %q", when.Key))
+		}
+	}
+	return fields
+}
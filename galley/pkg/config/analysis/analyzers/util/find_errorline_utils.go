@@ -78,6 +78,22 @@ const (
 	// Required parameters: rule index, from index, namespace index.
 	AuthorizationPolicyNameSpace = "{.spec.rules[%d].from[%d].source.namespaces[%d]}"
 
+	// Path for port in authorizationPolicy.
+	// Required parameters: rule index, to index, port index.
+	AuthorizationPolicyPort = "{.spec.rules[%d].to[%d].operation.ports[%d]}"
+
+	// Path for principal in authorizationPolicy.
+	// Required parameters: rule index, from index, principal index.
+	AuthorizationPolicyPrincipal = "{.spec.rules[%d].from[%d].source.principals[%d]}"
+
+	// Path for request principal in authorizationPolicy.
+	// Required parameters: rule index, from index, request principal index.
+	AuthorizationPolicyRequestPrincipal = "{.spec.rules[%d].from[%d].source.requestPrincipals[%d]}"
+
+	// Path for condition key in authorizationPolicy.
+	// Required parameters: rule index, condition index.
+	AuthorizationPolicyConditionKey = "{.spec.rules[%d].when[%d].key}"
+
 	// Path for annotation.
 	// Required parameters: annotation name.
 	Annotation = "{.metadata.annotations.%s}"
@@ -172,6 +172,22 @@ var (
 	// InvalidApplicationUID defines a diag.MessageType for message "InvalidApplicationUID".
 	// Description: Application pods should not run as user ID (UID) 1337
 	InvalidApplicationUID = diag.NewMessageType(diag.Warning, "IST0144", "User ID (UID) 1337 is reserved for the sidecar proxy.")
+
+	// AuthorizationPolicyPortNotExposed defines a diag.MessageType for message "AuthorizationPolicyPortNotExposed".
+	// Description: An AuthorizationPolicy rule references a port not exposed by the selected workloads
+	AuthorizationPolicyPortNotExposed = diag.NewMessageType(diag.Warning, "IST0145", "Port %v in rule %d is not exposed by any workload selected by the policy, the operation can never match.")
+
+	// AuthorizationPolicyForeignTrustDomain defines a diag.MessageType for message "AuthorizationPolicyForeignTrustDomain".
+	// Description: An AuthorizationPolicy principal has a trust domain outside the mesh trust domain and its aliases
+	AuthorizationPolicyForeignTrustDomain = diag.NewMessageType(diag.Warning, "IST0146", "Principal %q in rule %d can never match: trust domain %q is neither the mesh trust domain nor one of its aliases.")
+
+	// AuthorizationPolicyRequestAuthNotConfigured defines a diag.MessageType for message "AuthorizationPolicyRequestAuthNotConfigured".
+	// Description: An AuthorizationPolicy rule uses request authentication attributes, but no RequestAuthentication applies to the selected workloads
+	AuthorizationPolicyRequestAuthNotConfigured = diag.NewMessageType(diag.Warning, "IST0147", "%s in rule %d can never match: no RequestAuthentication applies to the workloads selected by the policy.")
+
+	// AuthorizationPolicyHTTPFieldOnTCPPort defines a diag.MessageType for message "AuthorizationPolicyHTTPFieldOnTCPPort".
+	// Description: An AuthorizationPolicy rule uses HTTP only fields for a TCP port
+	AuthorizationPolicyHTTPFieldOnTCPPort = diag.NewMessageType(diag.Warning, "IST0148", "%s in rule %d only applies to HTTP traffic, but port %v of the selected workloads is TCP.")
 )
 
 // All returns a list of all known message types.
@@ -218,6 +234,10 @@ func All() []*diag.MessageType {
 		UnsupportedKubernetesVersion,
 		LocalhostListener,
 		InvalidApplicationUID,
+		AuthorizationPolicyPortNotExposed,
+		AuthorizationPolicyForeignTrustDomain,
+		AuthorizationPolicyRequestAuthNotConfigured,
+		AuthorizationPolicyHTTPFieldOnTCPPort,
 	}
 }
 
@@ -629,3 +649,45 @@ func NewInvalidApplicationUID(r *resource.Instance) diag.Message {
 		r,
 	)
 }
+
+// NewAuthorizationPolicyPortNotExposed returns a new diag.Message based on AuthorizationPolicyPortNotExposed.
+func NewAuthorizationPolicyPortNotExposed(r *resource.Instance, port string, rule int) diag.Message {
+	return diag.NewMessage(
+		AuthorizationPolicyPortNotExposed,
+		r,
+		port,
+		rule,
+	)
+}
+
+// NewAuthorizationPolicyForeignTrustDomain returns a new diag.Message based on AuthorizationPolicyForeignTrustDomain.
+func NewAuthorizationPolicyForeignTrustDomain(r *resource.Instance, principal string, rule int, trustDomain string) diag.Message {
+	return diag.NewMessage(
+		AuthorizationPolicyForeignTrustDomain,
+		r,
+		principal,
+		rule,
+		trustDomain,
+	)
+}
+
+// NewAuthorizationPolicyRequestAuthNotConfigured returns a new diag.Message based on AuthorizationPolicyRequestAuthNotConfigured.
+func NewAuthorizationPolicyRequestAuthNotConfigured(r *resource.Instance, field string, rule int) diag.Message {
+	return diag.NewMessage(
+		AuthorizationPolicyRequestAuthNotConfigured,
+		r,
+		field,
+		rule,
+	)
+}
+
+// NewAuthorizationPolicyHTTPFieldOnTCPPort returns a new diag.Message based on AuthorizationPolicyHTTPFieldOnTCPPort.
+func NewAuthorizationPolicyHTTPFieldOnTCPPort(r *resource.Instance, field string, rule int, port string) diag.Message {
+	return diag.NewMessage(
+		AuthorizationPolicyHTTPFieldOnTCPPort,
+		r,
+		field,
+		rule,
+		port,
+	)
+}
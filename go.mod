@@ -39,6 +39,7 @@ require (
 	github.com/gogo/protobuf v1.3.2
 	github.com/golang/protobuf v1.5.1
 	github.com/google/go-cmp v0.5.5
+	github.com/google/go-containerregistry v0.5.1
 	github.com/google/gofuzz v1.2.0
 	github.com/google/uuid v1.2.0
 	github.com/gorilla/mux v1.8.0
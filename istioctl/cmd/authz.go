@@ -16,15 +16,23 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
+	authpb "istio.io/api/security/v1beta1"
 	"istio.io/istio/istioctl/pkg/authz"
 	"istio.io/istio/istioctl/pkg/util/configdump"
 	"istio.io/istio/istioctl/pkg/util/handlers"
+	"istio.io/istio/pilot/pkg/config/kube/crd"
+	"istio.io/istio/pilot/pkg/model"
+	configlabels "istio.io/istio/pkg/config/labels"
+	"istio.io/istio/pkg/config/schema/collections"
 	"istio.io/istio/pkg/kube"
 	"istio.io/pkg/log"
 )
@@ -140,6 +148,163 @@ func getConfigDumpFromPod(podName, podNamespace string) (*configdump.Wrapper, er
 	return envoyConfig, nil
 }
 
+type evalArgs struct {
+	policyFiles   []string
+	requestsFile  string
+	workloadLabel []string
+	rootNamespace string
+	outputFormat  string
+}
+
+func evalCmd() *cobra.Command {
+	ea := evalArgs{}
+	cmd := &cobra.Command{
+		Use:   "eval",
+		Short: "Evaluate requests against the AuthorizationPolicy applied to a workload.",
+		Long: `Eval evaluates, offline, a list of requests against the AuthorizationPolicy applied to a workload,
+and reports for each request whether it is allowed, denied or sent to an external authorizer (CUSTOM),
+and the policy and rule which decided it. The requests are evaluated against the same RBAC configuration
+as the one generated for Envoy.
+
+The requests are read from a JSON file with a list of objects with the following fields, all optional:
+name, sourcePrincipal, sourceNamespace, sourceIP, remoteIP, destinationIP, destinationPort, sni, host,
+method, path, headers (a map of header names to values), claims (the claims of the JWT of the request)
+and expect (the expected decision, one of ALLOW, DENY or CUSTOM).
+
+The command fails if the decision of a request is not the expected one, so that it can be used to check
+policy changes against a corpus of requests.`,
+		Example: `  # Evaluate the requests of requests.json against the policies of policies.yaml applied to the
+  # workloads labeled app=httpbin in the foo namespace:
+  istioctl x authz eval -f policies.yaml --requests requests.json -n foo -l app=httpbin`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch ea.outputFormat {
+			case jsonOutput, summaryOutput:
+			default:
+				return fmt.Errorf("output format %q not supported", ea.outputFormat)
+			}
+			if len(ea.policyFiles) == 0 || ea.requestsFile == "" {
+				return fmt.Errorf("eval requires the policy files (-f) and the requests file (--requests)")
+			}
+			policies, err := readAuthorizationPolicies(ea.policyFiles, ea.rootNamespace)
+			if err != nil {
+				return err
+			}
+			data, err := ioutil.ReadFile(ea.requestsFile)
+			if err != nil {
+				return err
+			}
+			var requests []authz.Request
+			if err := json.Unmarshal(data, &requests); err != nil {
+				return fmt.Errorf("failed to parse requests from %s: %v", ea.requestsFile, err)
+			}
+
+			ns := handlers.HandleNamespace(namespace, defaultNamespace)
+			workload := configlabels.Instance(convertToStringMap(ea.workloadLabel))
+			evaluator := authz.NewEvaluator(policies.ListAuthorizationPolicies(ns, configlabels.Collection{workload}))
+			for _, w := range evaluator.Warnings {
+				cmd.PrintErrln("Warning: " + w)
+			}
+			results := make([]authz.Result, 0, len(requests))
+			mismatches := 0
+			for i, r := range requests {
+				if r.Name == "" {
+					r.Name = fmt.Sprintf("request-%d", i)
+				}
+				res, err := evaluator.Evaluate(r)
+				if err != nil {
+					return fmt.Errorf("failed to evaluate %s: %v", r.Name, err)
+				}
+				if res.Mismatch() {
+					mismatches++
+				}
+				results = append(results, res)
+			}
+			if err := printEvalResults(cmd.OutOrStdout(), results, ea.outputFormat); err != nil {
+				return err
+			}
+			if mismatches > 0 {
+				return fmt.Errorf("%d of %d requests did not get the expected decision", mismatches, len(results))
+			}
+			return nil
+		},
+	}
+	cmd.PersistentFlags().StringSliceVarP(&ea.policyFiles, "file", "f", nil,
+		"The YAML files with the AuthorizationPolicy to evaluate")
+	cmd.PersistentFlags().StringVar(&ea.requestsFile, "requests", "",
+		"The JSON file with the requests to evaluate")
+	cmd.PersistentFlags().StringSliceVarP(&ea.workloadLabel, "labels", "l", nil,
+		"The labels of the workload; e.g. -l app=httpbin,version=v1")
+	cmd.PersistentFlags().StringVar(&ea.rootNamespace, "root-namespace", "istio-system",
+		"The root namespace of the mesh, whose policies apply to all the workloads")
+	cmd.PersistentFlags().StringVarP(&ea.outputFormat, "output", "o", summaryOutput,
+		"Output format: one of json|short")
+	return cmd
+}
+
+// readAuthorizationPolicies reads the AuthorizationPolicy from the YAML files, ignoring other resources.
+func readAuthorizationPolicies(files []string, rootNamespace string) (*model.AuthorizationPolicies, error) {
+	policies := &model.AuthorizationPolicies{
+		NamespaceToPolicies: map[string][]model.AuthorizationPolicy{},
+		RootNamespace:       rootNamespace,
+	}
+	gvk := collections.IstioSecurityV1Beta1Authorizationpolicies.Resource().GroupVersionKind()
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		configs, _, err := crd.ParseInputs(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse policies from %s: %v", f, err)
+		}
+		for _, c := range configs {
+			if c.GroupVersionKind != gvk {
+				continue
+			}
+			if c.Namespace == "" {
+				c.Namespace = handlers.HandleNamespace(namespace, defaultNamespace)
+			}
+			policies.NamespaceToPolicies[c.Namespace] = append(policies.NamespaceToPolicies[c.Namespace], model.AuthorizationPolicy{
+				Name:        c.Name,
+				Namespace:   c.Namespace,
+				Annotations: c.Annotations,
+				Spec:        c.Spec.(*authpb.AuthorizationPolicy),
+			})
+		}
+	}
+	return policies, nil
+}
+
+func printEvalResults(out io.Writer, results []authz.Result, outputFormat string) error {
+	if outputFormat == jsonOutput {
+		b, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "REQUEST\tDECISION\tPOLICY\tRULE\tREASON")
+	for _, r := range results {
+		policy, rule := "-", "-"
+		if r.Policy != "" {
+			policy, rule = r.Policy, fmt.Sprint(r.Rule)
+		}
+		reason := r.Reason
+		if r.Provider != "" {
+			reason += " (provider " + r.Provider + ")"
+		}
+		if r.Mismatch() {
+			reason += fmt.Sprintf(", expected %s", r.Expected)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Request, r.Decision, policy, rule, reason)
+	}
+	return w.Flush()
+}
+
 // AuthZ groups commands used for inspecting and interacting the authorization policy.
 // Note: this is still under active development and is not ready for real use.
 func AuthZ() *cobra.Command {
@@ -149,6 +314,7 @@ func AuthZ() *cobra.Command {
 	}
 
 	cmd.AddCommand(checkCmd)
+	cmd.AddCommand(evalCmd())
 	cmd.Long += "\n\n" + ExperimentalMsg
 	return cmd
 }
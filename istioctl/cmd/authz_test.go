@@ -13,3 +13,43 @@
 // limitations under the License.
 
 package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAuthzEval(t *testing.T) {
+	cases := []execTestCase{
+		{
+			args:           strings.Split("x authz eval -f testdata/authz/policies.yaml", " "),
+			expectedString: "eval requires the policy files (-f) and the requests file (--requests)",
+			wantException:  true,
+		},
+		{
+			args: strings.Split("x authz eval -f testdata/authz/policies.yaml --requests testdata/authz/requests.json "+
+				"-n foo -l app=httpbin", " "),
+			expectedOutput: `REQUEST            DECISION   POLICY                    RULE   REASON
+get-from-sleep     ALLOW      allow-sleep.foo           0      matched ALLOW policy
+post-from-sleep    DENY       -                         -      no ALLOW policy matched
+admin-from-sleep   DENY       deny-admin.istio-system   0      matched DENY policy, expected ALLOW
+Error: 1 of 3 requests did not get the expected decision
+`,
+			wantException: true,
+		},
+		{
+			// The ALLOW policy does not apply to other workloads.
+			args: strings.Split("x authz eval -f testdata/authz/policies.yaml --requests testdata/authz/requests.json "+
+				"-n foo -l app=other -o json", " "),
+			expectedString: `"reason": "no ALLOW policy applied"`,
+			wantException:  true,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d %s", i, strings.Join(c.args, " ")), func(t *testing.T) {
+			verifyExecTestOutput(t, c)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"istio.io/istio/security/pkg/pki/ca"
+)
+
+func caCommand() *cobra.Command {
+	caCmd := &cobra.Command{
+		Use:   "ca",
+		Short: "Manage the Istio CA",
+		Long:  `A group of commands used to manage the Istio CA.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.HelpFunc()(cmd, args)
+			if len(args) != 0 {
+				return fmt.Errorf("unknown subcommand %q", args[0])
+			}
+			return nil
+		},
+	}
+	caCmd.AddCommand(caRevokeCommand())
+	caCmd.Long += "\n\n" + ExperimentalMsg
+	return caCmd
+}
+
+func caRevokeCommand() *cobra.Command {
+	var remove bool
+	cmd := &cobra.Command{
+		Use:   "revoke <spiffe-id|serial-number>...",
+		Short: "Revoke identities or certificates issued by the Istio CA",
+		Long: fmt.Sprintf(`Revoke identities or certificates issued by the Istio CA, by adding them to the %s ConfigMap
+of the Istio namespace.
+
+The Istio CA refuses to sign certificates for the revoked identities, and the proxies deny the requests of the
+revoked identities by principal. Istiod pushes a certificate revocation list of the revoked serial numbers to the
+proxies, so they reject these certificates during the TLS handshake.
+
+The proxies need a revocation list for every CA of a peer certificate chain. With a plugged-in CA certificate, or
+with multiple trust anchors, the PEM encoded revocation lists of the other CAs must be provided in the %s key of
+the ConfigMap, otherwise the revoked serial numbers are accepted until the certificates expire.`,
+			ca.RevocationListConfigMap, ca.RevocationListCRLsKey),
+		Example: `  # Revoke an identity
+  istioctl x ca revoke spiffe://cluster.local/ns/default/sa/productpage
+
+  # Revoke a certificate by serial number
+  istioctl x ca revoke 5e:3a:1f:9b:c2:0d:44:71
+
+  # Remove an identity from the revocation list
+  istioctl x ca revoke --remove spiffe://cluster.local/ns/default/sa/productpage`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := interfaceFactory(kubeconfig)
+			if err != nil {
+				return err
+			}
+			cms := client.CoreV1().ConfigMaps(istioNamespace)
+			cm, err := cms.Get(context.TODO(), ca.RevocationListConfigMap, metav1.GetOptions{})
+			create := errors.IsNotFound(err)
+			if err != nil && !create {
+				return fmt.Errorf("failed to get the revocation list: %v", err)
+			}
+			if create {
+				cm = &v1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      ca.RevocationListConfigMap,
+						Namespace: istioNamespace,
+					},
+				}
+			}
+			rl, err := ca.ParseRevocationList(cm.Data[ca.RevocationListKey])
+			if err != nil {
+				return fmt.Errorf("invalid revocation list in ConfigMap %s/%s: %v", istioNamespace, ca.RevocationListConfigMap, err)
+			}
+
+			for _, entry := range args {
+				if remove {
+					removed, err := rl.Remove(entry)
+					if err != nil {
+						return err
+					}
+					if !removed {
+						fmt.Fprintf(cmd.OutOrStdout(), "%s is not revoked\n", entry)
+						continue
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "%s removed from the revocation list\n", entry)
+				} else {
+					if err := rl.Add(entry); err != nil {
+						return err
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "%s revoked\n", entry)
+				}
+			}
+
+			if cm.Data == nil {
+				cm.Data = map[string]string{}
+			}
+			cm.Data[ca.RevocationListKey] = rl.String()
+			if create {
+				_, err = cms.Create(context.TODO(), cm, metav1.CreateOptions{})
+			} else {
+				_, err = cms.Update(context.TODO(), cm, metav1.UpdateOptions{})
+			}
+			if err != nil {
+				return fmt.Errorf("failed to update the revocation list: %v", err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&remove, "remove", false, "Remove the identities or serial numbers from the revocation list")
+	cmd.Long += "\n\n" + ExperimentalMsg
+	return cmd
+}
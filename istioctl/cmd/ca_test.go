@@ -0,0 +1,75 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"istio.io/istio/security/pkg/pki/ca"
+)
+
+func TestCARevoke(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	interfaceFactory = func(_ string) (kubernetes.Interface, error) {
+		return client, nil
+	}
+
+	cases := []struct {
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{
+			args: []string{"spiffe://cluster.local/ns/default/sa/foo", "0A:0B"},
+			want: "spiffe://cluster.local/ns/default/sa/foo\na0b\n",
+		},
+		{
+			args: []string{"spiffe://cluster.local/ns/default/sa/bar"},
+			want: "spiffe://cluster.local/ns/default/sa/bar\nspiffe://cluster.local/ns/default/sa/foo\na0b\n",
+		},
+		{
+			args: []string{"--remove", "spiffe://cluster.local/ns/default/sa/foo", "a0b"},
+			want: "spiffe://cluster.local/ns/default/sa/bar\n",
+		},
+		{
+			args:    []string{"not-an-identity"},
+			want:    "spiffe://cluster.local/ns/default/sa/bar\n",
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		rootCmd := GetRootCmd(append([]string{"x", "ca", "revoke"}, c.args...))
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&out)
+		err := rootCmd.Execute()
+		if c.wantErr != (err != nil) {
+			t.Fatalf("istioctl x ca revoke %v: unexpected error %v: %s", c.args, err, out.String())
+		}
+		cm, err := client.CoreV1().ConfigMaps("istio-system").Get(context.TODO(), ca.RevocationListConfigMap, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := cm.Data[ca.RevocationListKey]; got != c.want {
+			t.Fatalf("istioctl x ca revoke %v: got revocation list %q, want %q", c.args, got, c.want)
+		}
+	}
+}
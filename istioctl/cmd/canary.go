@@ -0,0 +1,258 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"istio.io/istio/operator/cmd/mesh"
+	"istio.io/istio/operator/pkg/canary"
+	"istio.io/istio/operator/pkg/manifest"
+	"istio.io/istio/operator/pkg/util/clog"
+	"istio.io/istio/pkg/kube"
+)
+
+type canaryArgs struct {
+	inFilenames      []string
+	set              []string
+	force            bool
+	readinessTimeout time.Duration
+
+	namespaces      []string
+	batchSize       int
+	tag             string
+	restart         bool
+	gateTimeout     time.Duration
+	gateInterval    time.Duration
+	errorRate       float64
+	errorRateWindow time.Duration
+	prometheusAddr  string
+	rollback        bool
+	skipProxyCheck  bool
+}
+
+func canaryCommand() *cobra.Command {
+	cArgs := &canaryArgs{}
+	cmd := &cobra.Command{
+		Use:   "canary <revision>",
+		Short: "Upgrade the control plane with a revision based canary",
+		Long: `Upgrade the control plane with a revision based canary.
+
+The new control plane is installed as the given revision. The namespaces listed with --namespaces are then moved to it,
+--batch-size at a time, by setting their istio.io/rev label, and the revision tag set with --tag is pointed at it.
+After each step, health gates check that istiod is ready, that the proxies of the moved namespaces are synced and,
+with --error-rate-threshold, that their error rate reported by Prometheus is below the threshold. If a gate does not
+pass within --gate-timeout, the namespaces and the tag are moved back to where they were.
+
+The progress is saved in the istio-canary-<revision> ConfigMap of the Istio namespace after each step: running the
+command again resumes an interrupted upgrade, and --rollback moves back the namespaces and the tag of an upgrade.`,
+		Example: `  # Install revision 1-10, move namespaces one at a time, then point the "prod" tag at it
+  istioctl x revision canary 1-10 -f iop.yaml --namespaces foo,bar --tag prod --restart
+
+  # Roll back the namespaces and the tag moved by the upgrade
+  istioctl x revision canary 1-10 --rollback`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("canary requires a revision")
+			}
+			if errs := validation.IsDNS1123Label(args[0]); len(errs) > 0 {
+				return fmt.Errorf("%s - invalid revision format: %v", args[0], errs)
+			}
+			if cArgs.errorRate < 0 || cArgs.errorRate > 1 {
+				return fmt.Errorf("--error-rate-threshold must be within [0, 1], got %v", cArgs.errorRate)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			l := clog.NewConsoleLogger(cmd.OutOrStdout(), cmd.ErrOrStderr(), scope)
+			return runCanary(args[0], cArgs, l)
+		},
+	}
+	cmd.Flags().StringSliceVarP(&cArgs.inFilenames, "filename", "f", nil,
+		"Path to the IstioOperator files of the new control plane")
+	cmd.Flags().StringArrayVarP(&cArgs.set, "set", "s", nil,
+		"Override an IstioOperator value of the new control plane, e.g. to choose a profile (--set profile=demo)")
+	cmd.Flags().BoolVar(&cArgs.force, "force", false, "Proceed even with validation errors")
+	cmd.Flags().DurationVar(&cArgs.readinessTimeout, "readiness-timeout", 300*time.Second,
+		"Maximum time to wait for the resources of the new control plane to be ready")
+	cmd.Flags().StringSliceVar(&cArgs.namespaces, "namespaces", nil,
+		"Namespaces to move to the revision, in order")
+	cmd.Flags().IntVar(&cArgs.batchSize, "batch-size", 1, "Number of namespaces moved at each step")
+	cmd.Flags().StringVar(&cArgs.tag, "tag", "", "Revision tag to point at the revision once all the namespaces are moved")
+	cmd.Flags().BoolVar(&cArgs.restart, "restart", false,
+		"Restart the deployments of the moved namespaces, and wait for their pods to run the revision")
+	cmd.Flags().DurationVar(&cArgs.gateTimeout, "gate-timeout", 5*time.Minute,
+		"Maximum time to wait for the health gate of a step to pass before rolling back")
+	cmd.Flags().DurationVar(&cArgs.gateInterval, "gate-interval", 10*time.Second, "Interval between health checks")
+	cmd.Flags().Float64Var(&cArgs.errorRate, "error-rate-threshold", 0,
+		"Maximum ratio of 5xx responses of the moved namespaces, checked with Prometheus. 0 disables the check")
+	cmd.Flags().DurationVar(&cArgs.errorRateWindow, "error-rate-window", time.Minute,
+		"Window over which the error rate is computed")
+	cmd.Flags().StringVar(&cArgs.prometheusAddr, "prometheus-address", "",
+		"Address of Prometheus. If unset, a port-forward to the Prometheus pod of the Istio namespace is used")
+	cmd.Flags().BoolVar(&cArgs.skipProxyCheck, "skip-proxy-check", false,
+		"Do not check that the proxies of the moved namespaces are synced")
+	cmd.Flags().BoolVar(&cArgs.rollback, "rollback", false,
+		"Move back the namespaces and the tag of the canary upgrade to the revision")
+	return cmd
+}
+
+func runCanary(revision string, cArgs *canaryArgs, l clog.Logger) error {
+	kubeClient, err := kubeClientWithRevision(kubeconfig, configContext, revision)
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %v", err)
+	}
+	restConfig, _, ctrlClient, err := mesh.K8sConfig(kubeconfig, configContext)
+	if err != nil {
+		return err
+	}
+
+	checks := []canary.HealthCheck{&canary.IstiodReady{Client: kubeClient.Kube(), Namespace: istioNamespace}}
+	if cArgs.restart {
+		checks = append(checks, &canary.WorkloadsMigrated{Client: kubeClient.Kube()})
+	}
+	if !cArgs.skipProxyCheck {
+		checks = append(checks, &canary.ProxyConvergence{DiscoveryDo: kubeClient.AllDiscoveryDo, IstioNamespace: istioNamespace})
+	}
+	if cArgs.errorRate > 0 && !cArgs.rollback {
+		address := cArgs.prometheusAddr
+		if address == "" {
+			fw, err := prometheusPortForward(kubeClient)
+			if err != nil {
+				return err
+			}
+			defer fw.Close()
+			address = fmt.Sprintf("http://%s", fw.Address())
+		}
+		promAPI, err := prometheusAPI(address)
+		if err != nil {
+			return err
+		}
+		checks = append(checks, &canary.ErrorRate{
+			Source:    &canary.PrometheusErrorRate{API: promAPI, Window: cArgs.errorRateWindow},
+			Threshold: cArgs.errorRate,
+		})
+	}
+
+	installer := &canaryInstaller{
+		restConfig: restConfig,
+		client:     ctrlClient,
+		args:       cArgs,
+		l:          l,
+	}
+	upgrader, err := canary.NewUpgrader(kubeClient.Kube(), installer, &revisionTagManager{client: kubeClient}, canary.Options{
+		Revision:         revision,
+		IstioNamespace:   istioNamespace,
+		Namespaces:       cArgs.namespaces,
+		BatchSize:        cArgs.batchSize,
+		Tag:              cArgs.tag,
+		RestartWorkloads: cArgs.restart,
+		Checks:           checks,
+		GateTimeout:      cArgs.gateTimeout,
+		GateInterval:     cArgs.gateInterval,
+	}, l)
+	if err != nil {
+		return err
+	}
+	if cArgs.rollback {
+		return upgrader.Rollback(context.Background())
+	}
+	return upgrader.Run(context.Background())
+}
+
+// prometheusPortForward starts a port-forward to the Prometheus pod of the Istio namespace.
+func prometheusPortForward(client kube.ExtendedClient) (kube.PortForwarder, error) {
+	pl, err := client.PodsForSelector(context.TODO(), istioNamespace, "app=prometheus")
+	if err != nil {
+		return nil, fmt.Errorf("not able to locate Prometheus pod: %v", err)
+	}
+	if len(pl.Items) < 1 {
+		return nil, fmt.Errorf("no Prometheus pods found, set --prometheus-address")
+	}
+	fw, err := client.NewPortForwarder(pl.Items[0].Name, istioNamespace, "", 0, 9090)
+	if err != nil {
+		return nil, fmt.Errorf("could not build port forwarder for prometheus: %v", err)
+	}
+	if err = fw.Start(); err != nil {
+		return nil, fmt.Errorf("failure running port forward process: %v", err)
+	}
+	closePortForwarderOnInterrupt(fw)
+	return fw, nil
+}
+
+// canaryInstaller installs the control plane of the revision from the IstioOperator files of the command.
+type canaryInstaller struct {
+	restConfig *rest.Config
+	client     client.Client
+	args       *canaryArgs
+	l          clog.Logger
+}
+
+func (i *canaryInstaller) Install(_ context.Context, revision string) error {
+	setFlags := append([]string{}, i.args.set...)
+	if revArgs.manifestsPath != "" {
+		setFlags = append(setFlags, fmt.Sprintf("installPackagePath=%s", revArgs.manifestsPath))
+	}
+	setFlags = append(setFlags, fmt.Sprintf("revision=%s", revision))
+	_, iop, err := manifest.GenerateConfig(i.args.inFilenames, setFlags, i.args.force, i.restConfig, i.l)
+	if err != nil {
+		return fmt.Errorf("failed to generate the configuration of revision %s: %v", revision, err)
+	}
+	if _, err := mesh.InstallManifests(iop, i.args.force, false, i.restConfig, i.client, i.args.readinessTimeout, i.l); err != nil {
+		return fmt.Errorf("failed to install revision %s: %v", revision, err)
+	}
+	return nil
+}
+
+// revisionTagManager moves the revision tags implemented by the tag webhooks.
+type revisionTagManager struct {
+	client kube.ExtendedClient
+}
+
+func (m *revisionTagManager) TagRevision(ctx context.Context, tag string) (string, error) {
+	webhooks, err := getWebhooksWithTag(ctx, m.client.Kube(), tag)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve tag %s: %v", tag, err)
+	}
+	if len(webhooks) == 0 {
+		return "", nil
+	}
+	return getWebhookRevision(webhooks[0])
+}
+
+func (m *revisionTagManager) SetTag(ctx context.Context, tag, rev string) error {
+	// The tag is expected to be moved, and the revision was checked by the health gates.
+	return setTag(ctx, m.client, tag, rev, tagSetArgs{
+		overwrite:        true,
+		skipConfirmation: true,
+		manifestsPath:    revArgs.manifestsPath,
+	}, ioutil.Discard)
+}
+
+func (m *revisionTagManager) RemoveTag(ctx context.Context, tag string) error {
+	webhooks, err := getWebhooksWithTag(ctx, m.client.Kube(), tag)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve tag %s: %v", tag, err)
+	}
+	return deleteTagWebhooks(ctx, m.client.Kube(), webhooks)
+}
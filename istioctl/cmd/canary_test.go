@@ -0,0 +1,47 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCanaryCmdArgs(t *testing.T) {
+	cases := []execTestCase{
+		{
+			args:           strings.Split("x revision canary", " "),
+			expectedString: "canary requires a revision",
+			wantException:  true,
+		},
+		{
+			args:           strings.Split("x revision canary 1.10", " "),
+			expectedString: "1.10 - invalid revision format",
+			wantException:  true,
+		},
+		{
+			args:           strings.Split("x revision canary 1-10 --error-rate-threshold 2", " "),
+			expectedString: "--error-rate-threshold must be within [0, 1], got 2",
+			wantException:  true,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d %s", i, strings.Join(c.args, " ")), func(t *testing.T) {
+			verifyExecTestOutput(t, c)
+		})
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"istio.io/istio/istioctl/pkg/clioptions"
+	"istio.io/istio/istioctl/pkg/util/handlers"
+	"istio.io/istio/pilot/pkg/xds"
+	"istio.io/istio/pkg/config"
+)
+
+// proxyConfigStatus is the sync state of a resource on a proxy, as reported by an Istiod instance.
+type proxyConfigStatus struct {
+	xds.ConfigStatus
+	Istiod string `json:"istiod"`
+}
+
+type configStatusArgs struct {
+	proxyNamespace string
+	staleOnly      bool
+	wait           bool
+	threshold      float32
+	timeout        time.Duration
+	outputFormat   string
+}
+
+func configStatusCmd() *cobra.Command {
+	var opts clioptions.ControlPlaneOptions
+	csa := configStatusArgs{}
+	cmd := &cobra.Command{
+		Use:   "config-status <type>/<name>[.<namespace>]",
+		Short: "Lists the sync state of an Istio resource on each proxy [kube only]",
+		Long: `Lists, for each proxy connected to Istiod, the version of an Istio resource it has acknowledged,
+whether it is the latest version, and for how long the proxy has been lagging.
+
+Requires PILOT_ENABLE_CONFIG_DISTRIBUTION_TRACKING to be enabled in Istiod.`,
+		Example: `  # List the proxies which have not yet acknowledged the latest version of the bookinfo virtual service
+  istioctl experimental config-status virtualservice/bookinfo.default --stale
+
+  # Wait until 99% of the proxies in the default namespace acknowledge the bookinfo virtual service
+  istioctl experimental config-status virtualservice/bookinfo.default --proxy-namespace default --wait --threshold .99
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 || !strings.Contains(args[0], "/") {
+				cmd.Println(cmd.UsageString())
+				return fmt.Errorf("config-status requires <type>/<name>[.<namespace>]")
+			}
+			if csa.threshold <= 0 || csa.threshold > 1 {
+				return fmt.Errorf("--threshold must be within (0, 1], got %v", csa.threshold)
+			}
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			switch csa.outputFormat {
+			case jsonOutput, summaryOutput:
+			default:
+				return fmt.Errorf("output format %q not supported", csa.outputFormat)
+			}
+			parts := strings.SplitN(args[0], "/", 2)
+			s, err := schemaForKind(parts[0])
+			if err != nil {
+				return err
+			}
+			name, ns := handlers.InferPodInfo(parts[1], handlers.HandleNamespace(namespace, defaultNamespace))
+			resource := config.Key(s.Resource().Kind(), name, ns)
+
+			ctx, cancel := context.WithTimeout(context.Background(), csa.timeout)
+			defer cancel()
+			statuses, err := getConfigStatus(ctx, resource, csa.proxyNamespace, opts)
+			if err != nil {
+				return err
+			}
+			if csa.wait {
+				t := time.NewTicker(pollInterval)
+				defer t.Stop()
+				for syncedRatio(statuses) < csa.threshold {
+					select {
+					case <-ctx.Done():
+						_ = printConfigStatus(c.OutOrStdout(), statuses, csa)
+						return fmt.Errorf("timeout expired before resource %s was synced on %v of the proxies",
+							resource, csa.threshold)
+					case <-t.C:
+					}
+					if statuses, err = getConfigStatus(ctx, resource, csa.proxyNamespace, opts); err != nil {
+						return err
+					}
+				}
+			}
+			return printConfigStatus(c.OutOrStdout(), statuses, csa)
+		},
+	}
+	cmd.PersistentFlags().StringVar(&csa.proxyNamespace, "proxy-namespace", "",
+		"Only list the proxies of this namespace")
+	cmd.PersistentFlags().BoolVar(&csa.staleOnly, "stale", false,
+		"Only list the proxies which have not acknowledged the latest version of the resource")
+	cmd.PersistentFlags().BoolVar(&csa.wait, "wait", false,
+		"Wait until the ratio of synced proxies reaches --threshold")
+	cmd.PersistentFlags().Float32Var(&csa.threshold, "threshold", 1,
+		"The ratio of synced proxies required by --wait")
+	cmd.PersistentFlags().DurationVar(&csa.timeout, "timeout", 30*time.Second,
+		"The duration to wait before failing")
+	cmd.PersistentFlags().StringVarP(&csa.outputFormat, "output", "o", summaryOutput,
+		"Output format: one of json|short")
+	opts.AttachControlPlaneFlags(cmd)
+	return cmd
+}
+
+// getConfigStatus queries every Istiod instance for the sync state of the resource on its proxies.
+func getConfigStatus(ctx context.Context, resource, proxyNamespace string,
+	opts clioptions.ControlPlaneOptions) ([]proxyConfigStatus, error) {
+	kubeClient, err := kubeClientWithRevision(kubeconfig, configContext, opts.Revision)
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/debug/config_status?resource=%s", resource)
+	if proxyNamespace != "" {
+		path += "&proxy_namespace=" + proxyNamespace
+	}
+	istiodResponses, err := kubeClient.AllDiscoveryDo(ctx, istioNamespace, path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query istiod for config status "+
+			"(is config distribution tracking enabled): %v", err)
+	}
+	var statuses []proxyConfigStatus
+	for istiod, response := range istiodResponses {
+		var configStatuses []xds.ConfigStatus
+		if err := json.Unmarshal(response, &configStatuses); err != nil {
+			return nil, fmt.Errorf("unable to parse config status from %s: %v", istiod, err)
+		}
+		for _, cs := range configStatuses {
+			statuses = append(statuses, proxyConfigStatus{ConfigStatus: cs, Istiod: istiod})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ProxyID < statuses[j].ProxyID
+	})
+	return statuses, nil
+}
+
+// syncedRatio returns the ratio of proxies running the latest version of the resource.
+func syncedRatio(statuses []proxyConfigStatus) float32 {
+	if len(statuses) == 0 {
+		return 1
+	}
+	synced := 0
+	for _, s := range statuses {
+		if s.Synced {
+			synced++
+		}
+	}
+	return float32(synced) / float32(len(statuses))
+}
+
+func printConfigStatus(out io.Writer, statuses []proxyConfigStatus, csa configStatusArgs) error {
+	synced := 0
+	filtered := make([]proxyConfigStatus, 0, len(statuses))
+	for _, s := range statuses {
+		if s.Synced {
+			synced++
+		}
+		if !csa.staleOnly || !s.Synced {
+			filtered = append(filtered, s)
+		}
+	}
+	if csa.outputFormat == jsonOutput {
+		b, err := json.MarshalIndent(filtered, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PROXY\tISTIOD\tACKED\tLATEST\tSYNCED\tLAST ACK\tLAG")
+	for _, s := range filtered {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%v\t%s\t%s\n", s.ProxyID, s.Istiod, versionOrNone(s.AckedVersion),
+			versionOrNone(s.LatestVersion), s.Synced, formatAge(s.LastAck), formatLag(s.Lag))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(out, "%d/%d proxies synced\n", synced, len(statuses))
+	return err
+}
+
+func versionOrNone(version string) string {
+	if version == "" {
+		return "<none>"
+	}
+	return version
+}
+
+func formatAge(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return time.Since(*t).Round(time.Second).String() + " ago"
+}
+
+func formatLag(lag time.Duration) string {
+	if lag == 0 {
+		return "-"
+	}
+	return lag.Round(time.Second).String()
+}
@@ -0,0 +1,113 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"istio.io/istio/pilot/pkg/xds"
+)
+
+func TestConfigStatusCmd(t *testing.T) {
+	cannedResponseObj := []xds.ConfigStatus{
+		{
+			ProxyID:       "foo.default",
+			AckedVersion:  "2",
+			LatestVersion: "2",
+			Synced:        true,
+		},
+		{
+			ProxyID:       "bar.default",
+			AckedVersion:  "1",
+			LatestVersion: "2",
+			Lag:           90 * time.Second,
+		},
+	}
+	cannedResponse, _ := json.Marshal(cannedResponseObj)
+	if strings.Contains(string(cannedResponse), "last_ack") {
+		t.Fatalf("unknown ack times must be omitted, got %s", cannedResponse)
+	}
+	cannedResponseMap := map[string][]byte{"istiod-1": cannedResponse}
+	syncedResponse, _ := json.Marshal(cannedResponseObj[:1])
+	syncedResponseMap := map[string][]byte{"istiod-1": syncedResponse}
+
+	cases := []execTestCase{
+		{
+			args:           strings.Split("x config-status virtualservice", " "),
+			expectedString: "config-status requires <type>/<name>[.<namespace>]",
+			wantException:  true,
+		},
+		{
+			execClientConfig: cannedResponseMap,
+			args:             strings.Split("x config-status not-service/foo.default", " "),
+			expectedString:   "type not-service is not recognized",
+			wantException:    true,
+		},
+		{
+			execClientConfig: cannedResponseMap,
+			args:             strings.Split("x config-status virtualservice/foo.default --threshold 2", " "),
+			expectedString:   "--threshold must be within (0, 1], got 2",
+			wantException:    true,
+		},
+		{
+			execClientConfig: cannedResponseMap,
+			args:             strings.Split("x config-status virtual-service/foo.default", " "),
+			expectedOutput: `PROXY         ISTIOD     ACKED   LATEST   SYNCED   LAST ACK   LAG
+bar.default   istiod-1   1       2        false    -          1m30s
+foo.default   istiod-1   2       2        true     -          -
+1/2 proxies synced
+`,
+		},
+		{
+			execClientConfig: cannedResponseMap,
+			args:             strings.Split("x config-status virtualservice/foo.default --stale", " "),
+			expectedOutput: `PROXY         ISTIOD     ACKED   LATEST   SYNCED   LAST ACK   LAG
+bar.default   istiod-1   1       2        false    -          1m30s
+1/2 proxies synced
+`,
+		},
+		{
+			execClientConfig: cannedResponseMap,
+			args:             strings.Split("x config-status virtualservice/foo.default --stale -o json", " "),
+			expectedString:   `"proxy": "bar.default"`,
+		},
+		{
+			execClientConfig: cannedResponseMap,
+			args:             strings.Split("x config-status virtualservice/foo.default --wait --threshold .5", " "),
+			expectedString:   "1/2 proxies synced",
+		},
+		{
+			execClientConfig: cannedResponseMap,
+			args:             strings.Split("x config-status virtualservice/foo.default --wait --timeout 2s", " "),
+			expectedString:   "timeout expired before resource VirtualService/default/foo was synced on 1 of the proxies",
+			wantException:    true,
+		},
+		{
+			execClientConfig: syncedResponseMap,
+			args:             strings.Split("x config-status virtualservice/foo.default --wait", " "),
+			expectedString:   "1/1 proxies synced",
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("case %d %s", i, strings.Join(c.args, " ")), func(t *testing.T) {
+			verifyExecTestOutput(t, c)
+		})
+	}
+}
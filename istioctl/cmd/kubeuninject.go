@@ -128,29 +128,31 @@ func restoreAppProbes(containers []corev1.Container, probers map[string]*inject.
 		}
 		containerName := matches[1]
 		probeType := matches[2]
+		if prober.HTTPGet == nil && prober.TCPSocket == nil {
+			// The original gRPC exec command is not known, keep the rewritten prober.
+			continue
+		}
+		handler := corev1.Handler{
+			HTTPGet:   prober.HTTPGet,
+			TCPSocket: prober.TCPSocket,
+		}
 		for i, c := range containers {
 			if c.Name == containerName {
 				container := c.DeepCopy()
 				switch probeType {
 				case "readyz":
 					container.ReadinessProbe = &corev1.Probe{
-						Handler: corev1.Handler{
-							HTTPGet: prober.HTTPGet,
-						},
+						Handler:        handler,
 						TimeoutSeconds: prober.TimeoutSeconds,
 					}
 				case "livez":
 					container.LivenessProbe = &corev1.Probe{
-						Handler: corev1.Handler{
-							HTTPGet: prober.HTTPGet,
-						},
+						Handler:        handler,
 						TimeoutSeconds: prober.TimeoutSeconds,
 					}
 				case "startupz":
 					container.StartupProbe = &corev1.Probe{
-						Handler: corev1.Handler{
-							HTTPGet: prober.HTTPGet,
-						},
+						Handler:        handler,
 						TimeoutSeconds: prober.TimeoutSeconds,
 					}
 				}
@@ -111,6 +111,7 @@ func revisionCommand() *cobra.Command {
 	revisionCmd.AddCommand(revisionListCommand())
 	revisionCmd.AddCommand(revisionDescribeCommand())
 	revisionCmd.AddCommand(tagCommand())
+	revisionCmd.AddCommand(canaryCommand())
 	return revisionCmd
 }
 
@@ -0,0 +1,338 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/spf13/cobra"
+
+	"istio.io/istio/istioctl/pkg/util/configdump"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/simulation"
+	"istio.io/istio/pilot/pkg/xds"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
+	"istio.io/istio/pkg/test"
+)
+
+type simulateArgs struct {
+	address  string
+	port     int
+	path     string
+	host     string
+	headers  []string
+	protocol string
+	tls      string
+	alpn     string
+	sni      string
+	mode     string
+	file     string
+	snapshot string
+}
+
+func simulateCmd() *cobra.Command {
+	sa := simulateArgs{}
+	cmd := &cobra.Command{
+		Use:   "simulate [<type>/]<name>[.<namespace>]",
+		Short: "Simulates how the proxy of a pod would handle a request",
+		Long: `Traces a request through the configuration of the proxy of a pod, and reports the listener, filter chain,
+route and cluster which would handle it, as well as the TLS mode of the downstream and upstream connections.
+
+The simulation only uses the configuration of the proxy, it does not send any traffic.`,
+		Example: `  # Simulate an HTTP request from a pod to the reviews service
+  istioctl x simulate productpage-v1-84d9fb6bc8-t4f8d --host reviews --port 9080 --path /reviews/0
+
+  # Simulate an mTLS request received by a pod on port 9080
+  istioctl x simulate reviews-v1-5b7f94f9bc-wp5tb --mode inbound --address 10.0.0.1 --port 9080 --tls mtls
+
+  # Simulate a TLS request through a gateway, without using Kubernetes API
+  ssh <user@hostname> 'curl localhost:15000/config_dump' > envoy-config.json
+  istioctl x simulate --file envoy-config.json --mode gateway --port 443 --tls tls --sni bookinfo.example.com
+
+  # Simulate a request from the workload with address 10.0.0.5, using the configuration generated from a snapshot
+  kubectl exec -n istio-system deploy/istiod -- curl -s localhost:8080/debug/snapshotz > snapshot.tar.gz
+  istioctl x simulate 10.0.0.5 --snapshot snapshot.tar.gz --host reviews --port 9080
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if sa.file != "" && sa.snapshot != "" {
+				cmd.Println(cmd.UsageString())
+				return fmt.Errorf("--file and --snapshot cannot be used together")
+			}
+			if (len(args) == 1) != (sa.file == "") {
+				cmd.Println(cmd.UsageString())
+				return fmt.Errorf("simulate requires pod name or --file parameter")
+			}
+			if sa.port == 0 {
+				cmd.Println(cmd.UsageString())
+				return fmt.Errorf("simulate requires --port")
+			}
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			call, err := sa.call()
+			if err != nil {
+				return err
+			}
+			if sa.snapshot != "" {
+				sim, err := simulationFromSnapshot(sa.snapshot, args[0], call.CallMode == simulation.CallModeGateway)
+				if err != nil {
+					return err
+				}
+				return runSimulation(sim, call, c.OutOrStdout())
+			}
+			var dump []byte
+			if len(args) == 1 {
+				podName, podNamespace, err := getPodName(args[0])
+				if err != nil {
+					return err
+				}
+				if dump, err = extractConfigDump(podName, podNamespace); err != nil {
+					return err
+				}
+			} else if dump, err = readFile(sa.file); err != nil {
+				return err
+			}
+			return simulateConfigDump(dump, call, c.OutOrStdout())
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&sa.address, "address", "",
+		"Destination address of the request, defaults to an address no listener is bound to")
+	cmd.PersistentFlags().IntVar(&sa.port, "port", 0, "Destination port of the request")
+	cmd.PersistentFlags().StringVar(&sa.path, "path", "/", "Path of the HTTP request")
+	cmd.PersistentFlags().StringVar(&sa.host, "host", "", "Host header of the HTTP request, also used as SNI for TLS requests")
+	cmd.PersistentFlags().StringArrayVar(&sa.headers, "header", nil, "Header of the HTTP request, in the form name=value")
+	cmd.PersistentFlags().StringVar(&sa.protocol, "protocol", string(simulation.HTTP),
+		"Protocol of the request: one of http|http2|tcp")
+	cmd.PersistentFlags().StringVar(&sa.tls, "tls", string(simulation.Plaintext),
+		"TLS mode of the connection: one of plaintext|tls|mtls")
+	cmd.PersistentFlags().StringVar(&sa.alpn, "alpn", "", "ALPN negotiated by the TLS connection, defaults to the protocol")
+	cmd.PersistentFlags().StringVar(&sa.sni, "sni", "", "SNI of the TLS connection, defaults to the host")
+	cmd.PersistentFlags().StringVar(&sa.mode, "mode", string(simulation.CallModeOutbound),
+		"How the request reaches the proxy: outbound (from the application), inbound (to the application) or gateway")
+	cmd.PersistentFlags().StringVarP(&sa.file, "file", "f", "", "Envoy config dump JSON file")
+	cmd.PersistentFlags().StringVar(&sa.snapshot, "snapshot", "",
+		"Snapshot archive from the /debug/snapshotz endpoint of istiod, the proxy is then given by its IP address")
+
+	return cmd
+}
+
+func (sa simulateArgs) call() (simulation.Call, error) {
+	call := simulation.Call{
+		Address:    sa.address,
+		Port:       sa.port,
+		Path:       sa.path,
+		Protocol:   simulation.Protocol(sa.protocol),
+		TLS:        simulation.TLSMode(sa.tls),
+		Alpn:       sa.alpn,
+		HostHeader: sa.host,
+		Headers:    http.Header{},
+		Sni:        sa.sni,
+		CallMode:   simulation.CallMode(sa.mode),
+	}
+	switch call.Protocol {
+	case simulation.HTTP, simulation.HTTP2, simulation.TCP:
+	default:
+		return call, fmt.Errorf("protocol %q not supported", sa.protocol)
+	}
+	switch call.TLS {
+	case simulation.Plaintext, simulation.TLS, simulation.MTLS:
+	default:
+		return call, fmt.Errorf("tls mode %q not supported", sa.tls)
+	}
+	switch call.CallMode {
+	case simulation.CallModeOutbound, simulation.CallModeInbound, simulation.CallModeGateway:
+	default:
+		return call, fmt.Errorf("mode %q not supported", sa.mode)
+	}
+	if call.TLS == simulation.MTLS && call.Sni == "" {
+		// Unlike TLS, SNI is not set automatically for mTLS by the simulation.
+		call.Sni = sa.host
+	}
+	for _, h := range sa.headers {
+		kv := strings.SplitN(h, "=", 2)
+		if len(kv) != 2 {
+			return call, fmt.Errorf("invalid header %q, expected name=value", h)
+		}
+		call.Headers.Add(kv[0], kv[1])
+	}
+	return call, nil
+}
+
+// simulateConfigDump runs the call against the configuration of an Envoy config dump, and prints the result.
+func simulateConfigDump(dump []byte, call simulation.Call, out io.Writer) error {
+	sim, err := simulationFromConfigDump(dump)
+	if err != nil {
+		return err
+	}
+	return runSimulation(sim, call, out)
+}
+
+// runSimulation runs the call and prints the result.
+func runSimulation(sim *simulation.Simulation, call simulation.Call, out io.Writer) error {
+	result := sim.Run(call)
+
+	w := tabwriter.NewWriter(out, 0, 8, 1, ' ', 0)
+	printField := func(name, value string) {
+		if value != "" {
+			_, _ = fmt.Fprintf(w, "%s:\t%s\n", name, value)
+		}
+	}
+	printField("Listener", result.ListenerMatched)
+	printField("Filter Chain", result.FilterChainMatched)
+	printField("Route Config", result.RouteConfigMatched)
+	printField("Virtual Host", result.VirtualHostMatched)
+	printField("Route", result.RouteMatched)
+	printField("Cluster", result.ClusterMatched)
+	if result.Error == nil {
+		printField("Downstream TLS", string(result.DownstreamTLSMode()))
+		if result.ClusterMatched != "" {
+			upstream, err := sim.UpstreamTLSMode(result.ClusterMatched)
+			if err != nil {
+				upstream = simulation.TLSMode(fmt.Sprintf("unknown (%v)", err))
+			}
+			printField("Upstream TLS", string(upstream))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if result.Error != nil {
+		return fmt.Errorf("request would fail: %v", result.Error)
+	}
+	return nil
+}
+
+// simulationFromConfigDump extracts the active listeners, clusters and routes of an Envoy config dump.
+func simulationFromConfigDump(dump []byte) (*simulation.Simulation, error) {
+	cd := &configdump.Wrapper{}
+	if err := json.Unmarshal(dump, cd); err != nil {
+		return nil, fmt.Errorf("error unmarshalling config dump response from Envoy: %v", err)
+	}
+	sim := &simulation.Simulation{}
+
+	listenerDump, err := cd.GetListenerConfigDump()
+	if err != nil {
+		return nil, err
+	}
+	for _, dl := range listenerDump.GetDynamicListeners() {
+		if dl.GetActiveState().GetListener() == nil {
+			continue
+		}
+		// Support v2 or v3 in config dump. See ads.go:RequestedTypes for more info.
+		dl.ActiveState.Listener.TypeUrl = v3.ListenerType
+		l := &listener.Listener{}
+		if err := dl.ActiveState.Listener.UnmarshalTo(l); err != nil {
+			return nil, err
+		}
+		sim.Listeners = append(sim.Listeners, l)
+	}
+
+	clusterDump, err := cd.GetClusterConfigDump()
+	if err != nil {
+		return nil, err
+	}
+	for _, dc := range clusterDump.GetDynamicActiveClusters() {
+		dc.Cluster.TypeUrl = v3.ClusterType
+		c := &cluster.Cluster{}
+		if err := dc.Cluster.UnmarshalTo(c); err != nil {
+			return nil, err
+		}
+		sim.Clusters = append(sim.Clusters, c)
+	}
+
+	routeDump, err := cd.GetRouteConfigDump()
+	if err != nil {
+		return nil, err
+	}
+	for _, dr := range routeDump.GetDynamicRouteConfigs() {
+		dr.RouteConfig.TypeUrl = v3.RouteType
+		r := &route.RouteConfiguration{}
+		if err := dr.RouteConfig.UnmarshalTo(r); err != nil {
+			return nil, err
+		}
+		sim.Routes = append(sim.Routes, r)
+	}
+	return sim, nil
+}
+
+// simulationFromSnapshot generates the configuration of the proxy with the given IP address from a snapshot
+// archive of istiod. The namespace, labels, cluster and network of the proxy are taken from its endpoints.
+func simulationFromSnapshot(filename string, ip string, gateway bool) (*simulation.Simulation, error) {
+	b, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := xds.ReadSnapshot(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	proxy := &model.Proxy{
+		Type:        model.SidecarProxy,
+		IPAddresses: []string{ip},
+		Metadata:    &model.NodeMetadata{},
+	}
+	if gateway {
+		proxy.Type = model.Router
+	}
+	ep := snapshotEndpoint(snapshot, ip)
+	if ep == nil {
+		return nil, fmt.Errorf("no workload with address %s found in the snapshot", ip)
+	}
+	proxy.ConfigNamespace = ep.Namespace
+	proxy.Metadata.Labels = ep.Labels
+	proxy.Metadata.ClusterID = ep.Locality.ClusterID
+	proxy.Metadata.Network = ep.Network
+
+	var sim *simulation.Simulation
+	err = test.Wrap(func(t test.Failer) {
+		s := xds.NewFakeDiscoveryServerFromSnapshot(t, snapshot, xds.FakeOptions{})
+		p := s.SetupProxy(proxy)
+		sim = &simulation.Simulation{
+			Listeners: s.Listeners(p),
+			Clusters:  s.Clusters(p),
+			Routes:    s.Routes(p),
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot: %v", err)
+	}
+	return sim, nil
+}
+
+// snapshotEndpoint returns an endpoint with the given address, if any.
+func snapshotEndpoint(snapshot *xds.Snapshot, ip string) *model.IstioEndpoint {
+	for _, byNamespace := range snapshot.EndpointShards {
+		for _, shards := range byNamespace {
+			for _, endpoints := range shards {
+				for _, ep := range endpoints {
+					if ep.Address == ip {
+						return ep
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	adminapi "github.com/envoyproxy/go-control-plane/envoy/admin/v3"
+	any "google.golang.org/protobuf/types/known/anypb"
+
+	"istio.io/istio/istioctl/pkg/util/configdump"
+	"istio.io/istio/pilot/pkg/networking/core/v1alpha3"
+	"istio.io/istio/pilot/pkg/networking/util"
+	"istio.io/istio/pilot/pkg/xds"
+)
+
+const simulateConfig = `
+apiVersion: networking.istio.io/v1alpha3
+kind: ServiceEntry
+metadata:
+  name: se
+  namespace: default
+spec:
+  hosts:
+  - example.com
+  ports:
+  - number: 80
+    name: http
+    protocol: HTTP
+  - number: 9000
+    name: tcp
+    protocol: TCP
+  resolution: DNS
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: vs
+  namespace: default
+spec:
+  hosts:
+  - example.com
+  http:
+  - match:
+    - uri:
+        prefix: /v2
+    route:
+    - destination:
+        host: example.com
+        subset: v2
+  - route:
+    - destination:
+        host: example.com
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: DestinationRule
+metadata:
+  name: dr
+  namespace: default
+spec:
+  host: example.com
+  trafficPolicy:
+    tls:
+      mode: SIMPLE
+  subsets:
+  - name: v2
+    labels:
+      version: v2
+`
+
+// writeSimulateConfigDump writes the config dump of a sidecar generated from simulateConfig.
+func writeSimulateConfigDump(t *testing.T) string {
+	s := v1alpha3.NewConfigGenTest(t, v1alpha3.TestOptions{ConfigString: simulateConfig})
+	proxy := s.SetupProxy(nil)
+
+	listeners := &adminapi.ListenersConfigDump{}
+	for _, l := range s.Listeners(proxy) {
+		listeners.DynamicListeners = append(listeners.DynamicListeners, &adminapi.ListenersConfigDump_DynamicListener{
+			Name:        l.Name,
+			ActiveState: &adminapi.ListenersConfigDump_DynamicListenerState{Listener: util.MessageToAny(l)},
+		})
+	}
+	clusters := &adminapi.ClustersConfigDump{}
+	for _, c := range s.Clusters(proxy) {
+		clusters.DynamicActiveClusters = append(clusters.DynamicActiveClusters, &adminapi.ClustersConfigDump_DynamicCluster{
+			Cluster: util.MessageToAny(c),
+		})
+	}
+	routes := &adminapi.RoutesConfigDump{}
+	for _, r := range s.Routes(proxy) {
+		routes.DynamicRouteConfigs = append(routes.DynamicRouteConfigs, &adminapi.RoutesConfigDump_DynamicRouteConfig{
+			RouteConfig: util.MessageToAny(r),
+		})
+	}
+	dump, err := json.Marshal(&configdump.Wrapper{ConfigDump: &adminapi.ConfigDump{
+		Configs: []*any.Any{util.MessageToAny(listeners), util.MessageToAny(clusters), util.MessageToAny(routes)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "config_dump.json")
+	if err := ioutil.WriteFile(file, dump, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// writeSimulateSnapshot writes a snapshot of istiod with simulateConfig and a workload with address 1.2.3.4.
+func writeSimulateSnapshot(t *testing.T) string {
+	s := xds.NewFakeDiscoveryServer(t, xds.FakeOptions{
+		ConfigString: simulateConfig,
+		KubernetesObjectString: `apiVersion: v1
+kind: Service
+metadata:
+  name: app
+  namespace: default
+spec:
+  clusterIP: 10.0.0.1
+  ports:
+  - name: http
+    port: 8080
+---
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: app
+  namespace: default
+subsets:
+- addresses:
+  - ip: 1.2.3.4
+  ports:
+  - name: http
+    port: 8080
+`,
+	})
+	snapshot, err := s.Discovery.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := snapshot.WriteArchive(&buf); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	if err := ioutil.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestSimulate(t *testing.T) {
+	file := writeSimulateConfigDump(t)
+	snapshot := writeSimulateSnapshot(t)
+	cases := []struct {
+		name          string
+		args          string
+		want          []string
+		wantException bool
+	}{
+		{
+			name:          "missing pod and file",
+			args:          "x simulate --port 80",
+			want:          []string{"simulate requires pod name or --file parameter"},
+			wantException: true,
+		},
+		{
+			name:          "missing port",
+			args:          "x simulate -f " + file,
+			want:          []string{"simulate requires --port"},
+			wantException: true,
+		},
+		{
+			name:          "invalid protocol",
+			args:          "x simulate -f " + file + " --port 80 --protocol udp",
+			want:          []string{`protocol "udp" not supported`},
+			wantException: true,
+		},
+		{
+			name: "http route",
+			args: "x simulate -f " + file + " --port 80 --host example.com --path /v1",
+			want: []string{
+				"Listener:       0.0.0.0_80",
+				"Route Config:   80",
+				"Virtual Host:   example.com:80",
+				"Cluster:        outbound|80||example.com",
+				"Downstream TLS: plaintext",
+				"Upstream TLS:   tls",
+			},
+		},
+		{
+			name: "http route to subset",
+			args: "x simulate -f " + file + " --port 80 --host example.com --path /v2/reviews --header x-user=admin",
+			want: []string{"Cluster:        outbound|80|v2|example.com"},
+		},
+		{
+			name: "tcp",
+			args: "x simulate -f " + file + " --port 9000 --protocol tcp",
+			want: []string{
+				"Listener:       0.0.0.0_9000",
+				"Cluster:        outbound|9000||example.com",
+			},
+		},
+		{
+			name:          "file and snapshot",
+			args:          "x simulate --port 80 -f " + file + " --snapshot " + snapshot,
+			want:          []string{"--file and --snapshot cannot be used together"},
+			wantException: true,
+		},
+		{
+			name: "snapshot",
+			args: "x simulate 1.2.3.4 --snapshot " + snapshot + " --port 80 --host example.com --path /v1",
+			want: []string{
+				"Listener:       0.0.0.0_80",
+				"Cluster:        outbound|80||example.com",
+				"Upstream TLS:   tls",
+			},
+		},
+		{
+			name:          "snapshot unknown workload",
+			args:          "x simulate 1.2.3.5 --snapshot " + snapshot + " --port 80",
+			want:          []string{"no workload with address 1.2.3.5 found in the snapshot"},
+			wantException: true,
+		},
+		{
+			name:          "alpn without tls",
+			args:          "x simulate -f " + file + " --port 80 --host example.com --alpn h2",
+			want:          []string{"Error: request would fail: invalid call, ALPN can only be sent in TLS requests"},
+			wantException: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			rootCmd := GetRootCmd(strings.Split(tt.args, " "))
+			rootCmd.SetOut(&out)
+			rootCmd.SetErr(&out)
+			err := rootCmd.Execute()
+			if (err != nil) != tt.wantException {
+				t.Fatalf("got error %v, want exception %v", err, tt.wantException)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output %q does not contain %q", out.String(), want)
+				}
+			}
+		})
+	}
+}
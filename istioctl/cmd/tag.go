@@ -69,6 +69,27 @@ var (
 	webhookName      = ""
 )
 
+// tagSetArgs are the flags of the commands setting a revision tag.
+type tagSetArgs struct {
+	// generate writes the tag webhook to the output instead of applying it.
+	generate         bool
+	overwrite        bool
+	skipConfirmation bool
+	manifestsPath    string
+	webhookName      string
+}
+
+// tagSetFlags returns the tagSetArgs of the flags set by the user.
+func tagSetFlags(generate bool) tagSetArgs {
+	return tagSetArgs{
+		generate:         generate,
+		overwrite:        overwrite,
+		skipConfirmation: skipConfirmation,
+		manifestsPath:    manifestsPath,
+		webhookName:      webhookName,
+	}
+}
+
 type tagWebhookConfig struct {
 	tag                string
 	revision           string
@@ -145,7 +166,7 @@ injection labels.`,
 				return fmt.Errorf("failed to create Kubernetes client: %v", err)
 			}
 
-			return setTag(context.Background(), client, args[0], revision, false, cmd.OutOrStdout())
+			return setTag(context.Background(), client, args[0], revision, tagSetFlags(false), cmd.OutOrStdout())
 		},
 	}
 
@@ -192,7 +213,7 @@ injection labels.`,
 				return fmt.Errorf("failed to create Kubernetes client: %v", err)
 			}
 
-			return setTag(context.Background(), client, args[0], revision, true, cmd.OutOrStdout())
+			return setTag(context.Background(), client, args[0], revision, tagSetFlags(true), cmd.OutOrStdout())
 		},
 	}
 
@@ -268,9 +289,9 @@ revision tag before removing using the "istioctl x revision tag list" command.
 }
 
 // setTag creates or modifies a revision tag.
-func setTag(ctx context.Context, kubeClient kube.ExtendedClient, tag, revision string, generate bool, w io.Writer) error {
+func setTag(ctx context.Context, kubeClient kube.ExtendedClient, tag, revision string, args tagSetArgs, w io.Writer) error {
 	// ensure that the revision is recent enough to patch tag webhooks
-	if !skipConfirmation {
+	if !args.skipConfirmation {
 		sufficient, version, err := versionCheck(revision)
 		if err != nil {
 			return err
@@ -285,7 +306,7 @@ func setTag(ctx context.Context, kubeClient kube.ExtendedClient, tag, revision s
 	if err != nil {
 		return err
 	}
-	if !generate && !overwrite && len(revWebhookCollisions) > 0 {
+	if !args.generate && !args.overwrite && len(revWebhookCollisions) > 0 {
 		return fmt.Errorf("cannot create revision tag %q: found existing control plane revision with same name", tag)
 	}
 
@@ -305,7 +326,7 @@ func setTag(ctx context.Context, kubeClient kube.ExtendedClient, tag, revision s
 	if err != nil {
 		return err
 	}
-	if len(whs) > 0 && !overwrite {
+	if len(whs) > 0 && !args.overwrite {
 		return fmt.Errorf("revision tag %q already exists, and --overwrite is false", tag)
 	}
 
@@ -313,15 +334,15 @@ func setTag(ctx context.Context, kubeClient kube.ExtendedClient, tag, revision s
 	if err != nil {
 		return fmt.Errorf("failed to create tag webhook config: %v", err)
 	}
-	tagWhYAML, err := tagWebhookYAML(tagWhConfig, manifestsPath)
+	tagWhYAML, err := tagWebhookYAML(tagWhConfig, args.manifestsPath)
 	if err != nil {
 		return fmt.Errorf("failed to create tag webhook: %v", err)
 	}
 	// custom webhook name specified, change the generated tag webhook configuration
-	if webhookName != "" {
-		tagWhYAML = renameTagWebhookConfiguration(tagWhYAML, tag, webhookName)
+	if args.webhookName != "" {
+		tagWhYAML = renameTagWebhookConfiguration(tagWhYAML, tag, args.webhookName)
 	}
-	if generate {
+	if args.generate {
 		_, err := w.Write([]byte(tagWhYAML))
 		if err != nil {
 			return err
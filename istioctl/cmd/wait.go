@@ -152,6 +152,16 @@ func printVerbosef(cmd *cobra.Command, template string, args ...interface{}) {
 }
 
 func validateType(kind string) error {
+	s, err := schemaForKind(kind)
+	if err != nil {
+		return err
+	}
+	targetSchema = s
+	return nil
+}
+
+// schemaForKind returns the schema of a Pilot resource kind, ignoring case and dashes.
+func schemaForKind(kind string) (collection.Schema, error) {
 	originalKind := kind
 
 	// Remove any dashes.
@@ -159,11 +169,10 @@ func validateType(kind string) error {
 
 	for _, s := range collections.Pilot.All() {
 		if strings.EqualFold(kind, s.Resource().Kind()) {
-			targetSchema = s
-			return nil
+			return s, nil
 		}
 	}
-	return fmt.Errorf("type %s is not recognized", originalKind)
+	return nil, fmt.Errorf("type %s is not recognized", originalKind)
 }
 
 func countVersions(versionCount map[string]int, configVersion string) {
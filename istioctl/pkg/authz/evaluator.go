@@ -0,0 +1,535 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	rbacpb "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	routepb "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	matcherpb "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+
+	"istio.io/api/annotation"
+	"istio.io/istio/pilot/pkg/model"
+	authzmodel "istio.io/istio/pilot/pkg/security/authz/model"
+	sm "istio.io/istio/pilot/pkg/security/model"
+	"istio.io/istio/pkg/spiffe"
+)
+
+// Decision is the result of evaluating a request against the authorization policies.
+type Decision string
+
+const (
+	// Allow means the request is allowed.
+	Allow Decision = "ALLOW"
+	// Deny means the request is denied.
+	Deny Decision = "DENY"
+	// Custom means the request is sent to an external authorizer, which decides whether it is allowed.
+	Custom Decision = "CUSTOM"
+)
+
+// Request is a request to evaluate against the authorization policies.
+type Request struct {
+	// Name identifies the request in the results.
+	Name string `json:"name,omitempty"`
+	// SourcePrincipal is the mTLS identity of the client, e.g. "cluster.local/ns/default/sa/sleep".
+	SourcePrincipal string `json:"sourcePrincipal,omitempty"`
+	// SourceNamespace is the namespace of the client, used when SourcePrincipal is not set.
+	SourceNamespace string `json:"sourceNamespace,omitempty"`
+	// SourceIP is the IP address of the peer.
+	SourceIP string `json:"sourceIP,omitempty"`
+	// RemoteIP is the IP address of the original client. Defaults to SourceIP.
+	RemoteIP        string `json:"remoteIP,omitempty"`
+	DestinationIP   string `json:"destinationIP,omitempty"`
+	DestinationPort uint32 `json:"destinationPort,omitempty"`
+	SNI             string `json:"sni,omitempty"`
+	Host            string `json:"host,omitempty"`
+	Method          string `json:"method,omitempty"`
+	Path            string `json:"path,omitempty"`
+	// Headers are the request headers, keyed by name.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Claims are the claims of the validated JWT of the request. The request principal, audiences and
+	// presenter are derived from the "iss", "sub", "aud" and "azp" claims.
+	Claims map[string]interface{} `json:"claims,omitempty"`
+	// Expect is the expected decision, if any.
+	Expect Decision `json:"expect,omitempty"`
+}
+
+// Result is the result of evaluating a request.
+type Result struct {
+	Request  string   `json:"request"`
+	Decision Decision `json:"decision"`
+	// Policy is the <name>.<namespace> of the policy whose rule decided the request, if any.
+	Policy string `json:"policy,omitempty"`
+	// Rule is the index of the rule of Policy which matched the request.
+	Rule int `json:"rule"`
+	// Provider is the extension provider of a CUSTOM decision.
+	Provider string   `json:"provider,omitempty"`
+	Reason   string   `json:"reason"`
+	Expected Decision `json:"expected,omitempty"`
+}
+
+// Mismatch returns true if the request had an expected decision which is not the actual one.
+func (r Result) Mismatch() bool {
+	return r.Expected != "" && r.Expected != r.Decision
+}
+
+type compiledPolicy struct {
+	name      string
+	namespace string
+	provider  string
+	// rules are the RBAC policies generated from the rules of the policy, nil for the rules which are ignored.
+	rules []*rbacpb.Policy
+}
+
+// Evaluator evaluates requests against the authorization policies applied to a workload, using the same
+// RBAC configuration as the one generated for Envoy.
+type Evaluator struct {
+	custom []compiledPolicy
+	deny   []compiledPolicy
+	allow  []compiledPolicy
+
+	// Warnings lists the rules ignored when generating the RBAC configuration.
+	Warnings []string
+}
+
+// NewEvaluator creates an evaluator for the policies applied to a workload.
+func NewEvaluator(policies model.AuthorizationPoliciesResult) *Evaluator {
+	e := &Evaluator{}
+	// CUSTOM policies are generated with the DENY action, as done by the builder.
+	e.custom = e.compile(policies.Custom, rbacpb.RBAC_DENY)
+	e.deny = e.compile(policies.Deny, rbacpb.RBAC_DENY)
+	e.allow = e.compile(policies.Allow, rbacpb.RBAC_ALLOW)
+	return e
+}
+
+func (e *Evaluator) compile(policies []model.AuthorizationPolicy, action rbacpb.RBAC_Action) []compiledPolicy {
+	var out []compiledPolicy
+	for _, policy := range policies {
+		if dryRun, _ := strconv.ParseBool(policy.Annotations[annotation.IoIstioDryRun.Name]); dryRun {
+			e.Warnings = append(e.Warnings, fmt.Sprintf("ignored dry-run policy %s.%s", policy.Name, policy.Namespace))
+			continue
+		}
+		cp := compiledPolicy{
+			name:      policy.Name,
+			namespace: policy.Namespace,
+			provider:  policy.Spec.GetProvider().GetName(),
+		}
+		for i, rule := range policy.Spec.Rules {
+			var generated *rbacpb.Policy
+			m, err := authzmodel.New(rule)
+			if err == nil {
+				generated, err = m.Generate(false, action)
+			}
+			if err != nil {
+				e.Warnings = append(e.Warnings, fmt.Sprintf("ignored rule %d of policy %s.%s: %v", i, policy.Name, policy.Namespace, err))
+			}
+			cp.rules = append(cp.rules, generated)
+		}
+		out = append(out, cp)
+	}
+	return out
+}
+
+// Evaluate returns the decision for the request. CUSTOM policies are evaluated first, then DENY and ALLOW
+// policies, in the same order as the filters in Envoy.
+func (e *Evaluator) Evaluate(r Request) (Result, error) {
+	attrs, err := newAttributes(r)
+	if err != nil {
+		return Result{}, err
+	}
+	res := Result{Request: r.Name, Expected: r.Expect}
+	cp, rule, err := firstMatch(e.custom, attrs)
+	if err != nil {
+		return Result{}, err
+	}
+	if cp != nil {
+		res.Decision, res.Policy, res.Rule, res.Provider = Custom, cp.name+"."+cp.namespace, rule, cp.provider
+		res.Reason = "matched CUSTOM policy"
+		return res, nil
+	}
+	if cp, rule, err = firstMatch(e.deny, attrs); err != nil {
+		return Result{}, err
+	}
+	if cp != nil {
+		res.Decision, res.Policy, res.Rule = Deny, cp.name+"."+cp.namespace, rule
+		res.Reason = "matched DENY policy"
+		return res, nil
+	}
+	if len(e.allow) == 0 {
+		res.Decision, res.Reason = Allow, "no ALLOW policy applied"
+		return res, nil
+	}
+	if cp, rule, err = firstMatch(e.allow, attrs); err != nil {
+		return Result{}, err
+	}
+	if cp != nil {
+		res.Decision, res.Policy, res.Rule = Allow, cp.name+"."+cp.namespace, rule
+		res.Reason = "matched ALLOW policy"
+		return res, nil
+	}
+	res.Decision, res.Reason = Deny, "no ALLOW policy matched"
+	return res, nil
+}
+
+func firstMatch(policies []compiledPolicy, attrs *attributes) (*compiledPolicy, int, error) {
+	for i := range policies {
+		for j, rule := range policies[i].rules {
+			if rule == nil {
+				continue
+			}
+			matched, err := matchPolicy(rule, attrs)
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to evaluate rule %d of policy %s.%s: %v",
+					j, policies[i].name, policies[i].namespace, err)
+			}
+			if matched {
+				return &policies[i], j, nil
+			}
+		}
+	}
+	return nil, 0, nil
+}
+
+// attributes are the properties of a request, as seen by the Envoy RBAC filter.
+type attributes struct {
+	headers         map[string]string
+	path            string
+	sourceIP        net.IP
+	remoteIP        net.IP
+	destinationIP   net.IP
+	destinationPort uint32
+	sni             string
+	// principal is the URI SAN of the peer certificate.
+	principal string
+	// metadata is the dynamic metadata of the authn filter.
+	metadata map[string]interface{}
+}
+
+func newAttributes(r Request) (*attributes, error) {
+	a := &attributes{
+		headers:         map[string]string{},
+		destinationPort: r.DestinationPort,
+		sni:             r.SNI,
+		metadata:        map[string]interface{}{},
+	}
+	for k, v := range r.Headers {
+		a.headers[strings.ToLower(k)] = v
+	}
+	if r.Method != "" {
+		a.headers[":method"] = r.Method
+	}
+	if r.Host != "" {
+		a.headers[":authority"] = r.Host
+	}
+	if r.Path != "" {
+		a.headers[":path"] = r.Path
+		a.path = strings.SplitN(r.Path, "?", 2)[0]
+	}
+
+	var err error
+	if a.sourceIP, err = parseIP(r.SourceIP); err != nil {
+		return nil, err
+	}
+	a.remoteIP = a.sourceIP
+	if r.RemoteIP != "" {
+		if a.remoteIP, err = parseIP(r.RemoteIP); err != nil {
+			return nil, err
+		}
+	}
+	if a.destinationIP, err = parseIP(r.DestinationIP); err != nil {
+		return nil, err
+	}
+
+	principal := strings.TrimPrefix(r.SourcePrincipal, spiffe.URIPrefix)
+	if principal == "" && r.SourceNamespace != "" {
+		// The namespace is only known from the principal, which is matched with ".*/ns/<namespace>/.*".
+		principal = fmt.Sprintf("/ns/%s/", r.SourceNamespace)
+	} else if principal != "" {
+		a.principal = spiffe.URIPrefix + principal
+	}
+	if principal != "" {
+		a.metadata["source.principal"] = principal
+	}
+
+	if len(r.Claims) > 0 {
+		a.metadata["request.auth.claims"] = r.Claims
+		iss, _ := r.Claims["iss"].(string)
+		sub, _ := r.Claims["sub"].(string)
+		if iss != "" && sub != "" {
+			a.metadata["request.auth.principal"] = iss + "/" + sub
+		}
+		if aud, f := r.Claims["aud"]; f {
+			a.metadata["request.auth.audiences"] = aud
+		}
+		if azp, f := r.Claims["azp"]; f {
+			a.metadata["request.auth.presenter"] = azp
+		}
+	}
+	return a, nil
+}
+
+func parseIP(s string) (net.IP, error) {
+	if s == "" {
+		return nil, nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid ip address: %s", s)
+	}
+	return ip, nil
+}
+
+func matchPolicy(p *rbacpb.Policy, a *attributes) (bool, error) {
+	permitted := false
+	for _, perm := range p.Permissions {
+		m, err := matchPermission(perm, a)
+		if err != nil {
+			return false, err
+		}
+		if m {
+			permitted = true
+			break
+		}
+	}
+	if !permitted {
+		return false, nil
+	}
+	for _, id := range p.Principals {
+		m, err := matchPrincipal(id, a)
+		if err != nil || m {
+			return m, err
+		}
+	}
+	return false, nil
+}
+
+func matchPermission(p *rbacpb.Permission, a *attributes) (bool, error) {
+	switch r := p.Rule.(type) {
+	case *rbacpb.Permission_Any:
+		return r.Any, nil
+	case *rbacpb.Permission_AndRules:
+		for _, rule := range r.AndRules.Rules {
+			if m, err := matchPermission(rule, a); err != nil || !m {
+				return false, err
+			}
+		}
+		return true, nil
+	case *rbacpb.Permission_OrRules:
+		for _, rule := range r.OrRules.Rules {
+			if m, err := matchPermission(rule, a); err != nil || m {
+				return m, err
+			}
+		}
+		return false, nil
+	case *rbacpb.Permission_NotRule:
+		m, err := matchPermission(r.NotRule, a)
+		return !m, err
+	case *rbacpb.Permission_Header:
+		return matchHeader(r.Header, a)
+	case *rbacpb.Permission_UrlPath:
+		return matchString(r.UrlPath.GetPath(), a.path, a.path != "")
+	case *rbacpb.Permission_DestinationIp:
+		return matchCidr(r.DestinationIp, a.destinationIP)
+	case *rbacpb.Permission_DestinationPort:
+		return a.destinationPort == r.DestinationPort, nil
+	case *rbacpb.Permission_RequestedServerName:
+		return matchString(r.RequestedServerName, a.sni, true)
+	case *rbacpb.Permission_Metadata:
+		return matchMetadata(r.Metadata, a)
+	default:
+		return false, fmt.Errorf("unsupported permission %T", p.Rule)
+	}
+}
+
+func matchPrincipal(p *rbacpb.Principal, a *attributes) (bool, error) {
+	switch id := p.Identifier.(type) {
+	case *rbacpb.Principal_Any:
+		return id.Any, nil
+	case *rbacpb.Principal_AndIds:
+		for _, i := range id.AndIds.Ids {
+			if m, err := matchPrincipal(i, a); err != nil || !m {
+				return false, err
+			}
+		}
+		return true, nil
+	case *rbacpb.Principal_OrIds:
+		for _, i := range id.OrIds.Ids {
+			if m, err := matchPrincipal(i, a); err != nil || m {
+				return m, err
+			}
+		}
+		return false, nil
+	case *rbacpb.Principal_NotId:
+		m, err := matchPrincipal(id.NotId, a)
+		return !m, err
+	case *rbacpb.Principal_Authenticated_:
+		if a.principal == "" {
+			return false, nil
+		}
+		return matchString(id.Authenticated.GetPrincipalName(), a.principal, true)
+	case *rbacpb.Principal_DirectRemoteIp:
+		return matchCidr(id.DirectRemoteIp, a.sourceIP)
+	case *rbacpb.Principal_RemoteIp:
+		return matchCidr(id.RemoteIp, a.remoteIP)
+	case *rbacpb.Principal_Header:
+		return matchHeader(id.Header, a)
+	case *rbacpb.Principal_Metadata:
+		return matchMetadata(id.Metadata, a)
+	default:
+		return false, fmt.Errorf("unsupported principal %T", p.Identifier)
+	}
+}
+
+func matchHeader(h *routepb.HeaderMatcher, a *attributes) (bool, error) {
+	v, present := a.headers[strings.ToLower(h.Name)]
+	var m bool
+	switch spec := h.HeaderMatchSpecifier.(type) {
+	case *routepb.HeaderMatcher_PresentMatch:
+		m = present == spec.PresentMatch
+	case *routepb.HeaderMatcher_ExactMatch:
+		m = present && v == spec.ExactMatch
+	case *routepb.HeaderMatcher_PrefixMatch:
+		m = present && strings.HasPrefix(v, spec.PrefixMatch)
+	case *routepb.HeaderMatcher_SuffixMatch:
+		m = present && strings.HasSuffix(v, spec.SuffixMatch)
+	case *routepb.HeaderMatcher_ContainsMatch:
+		m = present && strings.Contains(v, spec.ContainsMatch)
+	case *routepb.HeaderMatcher_SafeRegexMatch:
+		var err error
+		if m, err = matchRegex(spec.SafeRegexMatch, v); err != nil {
+			return false, err
+		}
+		m = present && m
+	default:
+		return false, fmt.Errorf("unsupported header matcher %T", h.HeaderMatchSpecifier)
+	}
+	return m != h.InvertMatch, nil
+}
+
+// matchString matches the string matcher against v. If present is false, the attribute is missing and never matches.
+func matchString(sm *matcherpb.StringMatcher, v string, present bool) (bool, error) {
+	if !present {
+		return false, nil
+	}
+	if sm.IgnoreCase {
+		v = strings.ToLower(v)
+	}
+	lower := func(s string) string {
+		if sm.IgnoreCase {
+			return strings.ToLower(s)
+		}
+		return s
+	}
+	switch p := sm.MatchPattern.(type) {
+	case *matcherpb.StringMatcher_Exact:
+		return v == lower(p.Exact), nil
+	case *matcherpb.StringMatcher_Prefix:
+		return strings.HasPrefix(v, lower(p.Prefix)), nil
+	case *matcherpb.StringMatcher_Suffix:
+		return strings.HasSuffix(v, lower(p.Suffix)), nil
+	case *matcherpb.StringMatcher_Contains:
+		return strings.Contains(v, lower(p.Contains)), nil
+	case *matcherpb.StringMatcher_SafeRegex:
+		return matchRegex(p.SafeRegex, v)
+	default:
+		return false, fmt.Errorf("unsupported string matcher %T", sm.MatchPattern)
+	}
+}
+
+func matchRegex(r *matcherpb.RegexMatcher, v string) (bool, error) {
+	// Envoy requires the regex to match the full string.
+	re, err := regexp.Compile("^(?:" + r.Regex + ")$")
+	if err != nil {
+		return false, fmt.Errorf("invalid regex %q: %v", r.Regex, err)
+	}
+	return re.MatchString(v), nil
+}
+
+func matchCidr(cidr *core.CidrRange, ip net.IP) (bool, error) {
+	if ip == nil {
+		return false, nil
+	}
+	_, ipNet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", cidr.AddressPrefix, cidr.PrefixLen.GetValue()))
+	if err != nil {
+		return false, err
+	}
+	return ipNet.Contains(ip), nil
+}
+
+// matchMetadata matches the dynamic metadata of the authn filter. The metadata of other filters is not known offline.
+func matchMetadata(mm *matcherpb.MetadataMatcher, a *attributes) (bool, error) {
+	if mm.Filter != sm.AuthnFilterName {
+		return false, fmt.Errorf("unsupported metadata of filter %s", mm.Filter)
+	}
+	var v interface{} = a.metadata
+	for _, segment := range mm.Path {
+		fields, ok := v.(map[string]interface{})
+		if !ok {
+			return false, nil
+		}
+		if v, ok = fields[segment.GetKey()]; !ok {
+			return false, nil
+		}
+	}
+	return matchValue(mm.Value, v)
+}
+
+func matchValue(vm *matcherpb.ValueMatcher, v interface{}) (bool, error) {
+	switch p := vm.MatchPattern.(type) {
+	case *matcherpb.ValueMatcher_StringMatch:
+		// Audiences may be a list in the claims, match any of them.
+		for _, s := range stringValues(v) {
+			if m, err := matchString(p.StringMatch, s, true); err != nil || m {
+				return m, err
+			}
+		}
+		return false, nil
+	case *matcherpb.ValueMatcher_ListMatch:
+		// The authn filter stores the claims as lists, a single string is a list of one element.
+		for _, s := range stringValues(v) {
+			if m, err := matchValue(p.ListMatch.GetOneOf(), s); err != nil || m {
+				return m, err
+			}
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("unsupported value matcher %T", vm.MatchPattern)
+	}
+}
+
+func stringValues(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var out []string
+		for _, e := range t {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	case []string:
+		return t
+	default:
+		return nil
+	}
+}
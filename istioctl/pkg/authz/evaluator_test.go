@@ -0,0 +1,148 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"testing"
+
+	"istio.io/api/annotation"
+	authpb "istio.io/api/security/v1beta1"
+	typev1beta1 "istio.io/api/type/v1beta1"
+	"istio.io/istio/pilot/pkg/model"
+)
+
+func policy(name string, action authpb.AuthorizationPolicy_Action, rules ...*authpb.Rule) model.AuthorizationPolicy {
+	return model.AuthorizationPolicy{
+		Name:      name,
+		Namespace: "foo",
+		Spec: &authpb.AuthorizationPolicy{
+			Selector: &typev1beta1.WorkloadSelector{MatchLabels: map[string]string{"app": "httpbin"}},
+			Action:   action,
+			Rules:    rules,
+		},
+	}
+}
+
+func TestEvaluator(t *testing.T) {
+	allowGet := policy("allow-get", authpb.AuthorizationPolicy_ALLOW, &authpb.Rule{
+		From: []*authpb.Rule_From{{Source: &authpb.Source{Namespaces: []string{"bar"}}}},
+		To:   []*authpb.Rule_To{{Operation: &authpb.Operation{Methods: []string{"GET"}, Paths: []string{"/public/*"}}}},
+	}, &authpb.Rule{
+		When: []*authpb.Condition{{Key: "request.auth.claims[groups]", Values: []string{"admin"}}},
+	})
+	denyIP := policy("deny-ip", authpb.AuthorizationPolicy_DENY, &authpb.Rule{
+		From: []*authpb.Rule_From{{Source: &authpb.Source{IpBlocks: []string{"10.0.0.0/8"}}}},
+	})
+	denyHeader := policy("deny-header", authpb.AuthorizationPolicy_DENY, &authpb.Rule{
+		When: []*authpb.Condition{{Key: "request.headers[X-Block]", Values: []string{"*"}}},
+	})
+	custom := policy("ext-authz", authpb.AuthorizationPolicy_CUSTOM, &authpb.Rule{
+		To: []*authpb.Rule_To{{Operation: &authpb.Operation{Paths: []string{"/admin"}}}},
+	})
+	custom.Spec.ActionDetail = &authpb.AuthorizationPolicy_Provider{Provider: &authpb.AuthorizationPolicy_ExtensionProvider{Name: "opa"}}
+	dryRun := policy("dry-run", authpb.AuthorizationPolicy_DENY, &authpb.Rule{})
+	dryRun.Annotations = map[string]string{annotation.IoIstioDryRun.Name: "true"}
+
+	evaluator := NewEvaluator(model.AuthorizationPoliciesResult{
+		Custom: []model.AuthorizationPolicy{custom},
+		Deny:   []model.AuthorizationPolicy{dryRun, denyIP, denyHeader},
+		Allow:  []model.AuthorizationPolicy{allowGet},
+	})
+	if len(evaluator.Warnings) != 1 {
+		t.Errorf("expected a warning for the dry-run policy, got %v", evaluator.Warnings)
+	}
+
+	cases := []struct {
+		name     string
+		request  Request
+		decision Decision
+		policy   string
+		rule     int
+	}{
+		{
+			name:     "allowed namespace",
+			request:  Request{SourcePrincipal: "cluster.local/ns/bar/sa/sleep", Method: "GET", Path: "/public/index.html?q=1"},
+			decision: Allow,
+			policy:   "allow-get.foo",
+		},
+		{
+			name:     "namespace without principal",
+			request:  Request{SourceNamespace: "bar", Method: "GET", Path: "/public/index.html"},
+			decision: Allow,
+			policy:   "allow-get.foo",
+		},
+		{
+			name:     "other namespace",
+			request:  Request{SourcePrincipal: "spiffe://cluster.local/ns/baz/sa/sleep", Method: "GET", Path: "/public/index.html"},
+			decision: Deny,
+		},
+		{
+			name:     "other method",
+			request:  Request{SourcePrincipal: "cluster.local/ns/bar/sa/sleep", Method: "POST", Path: "/public/index.html"},
+			decision: Deny,
+		},
+		{
+			name:     "claim",
+			request:  Request{Method: "POST", Path: "/", Claims: map[string]interface{}{"groups": []interface{}{"dev", "admin"}}},
+			decision: Allow,
+			policy:   "allow-get.foo",
+			rule:     1,
+		},
+		{
+			name:     "denied ip",
+			request:  Request{SourceNamespace: "bar", SourceIP: "10.1.2.3", Method: "GET", Path: "/public/index.html"},
+			decision: Deny,
+			policy:   "deny-ip.foo",
+		},
+		{
+			name:     "denied header",
+			request:  Request{SourceNamespace: "bar", Method: "GET", Path: "/public/a", Headers: map[string]string{"x-block": "1"}},
+			decision: Deny,
+			policy:   "deny-header.foo",
+		},
+		{
+			name:     "custom",
+			request:  Request{SourceIP: "10.1.2.3", Path: "/admin"},
+			decision: Custom,
+			policy:   "ext-authz.foo",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evaluator.Evaluate(tt.request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Decision != tt.decision || got.Policy != tt.policy || got.Rule != tt.rule {
+				t.Errorf("got %s by %q rule %d (%s), want %s by %q rule %d",
+					got.Decision, got.Policy, got.Rule, got.Reason, tt.decision, tt.policy, tt.rule)
+			}
+		})
+	}
+}
+
+func TestEvaluatorWithoutAllowPolicy(t *testing.T) {
+	evaluator := NewEvaluator(model.AuthorizationPoliciesResult{})
+	got, err := evaluator.Evaluate(Request{Method: "GET", Path: "/", Expect: Deny})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Decision != Allow || !got.Mismatch() {
+		t.Errorf("got %v, want a mismatching ALLOW decision", got)
+	}
+	if _, err := evaluator.Evaluate(Request{SourceIP: "invalid"}); err == nil {
+		t.Errorf("expected error for invalid source ip")
+	}
+}
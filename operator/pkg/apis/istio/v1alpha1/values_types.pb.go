@@ -413,15 +413,21 @@ type CNIRepairConfig struct {
 	Tag     interface{}      `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`
 	Image   string              `protobuf:"bytes,4,opt,name=image,proto3" json:"image,omitempty"`
 	// Controls whether various repair behaviors are enabled.
-	LabelPods            bool     `protobuf:"varint,5,opt,name=labelPods,proto3" json:"labelPods,omitempty"`
-	CreateEvents         string   `protobuf:"bytes,6,opt,name=createEvents,proto3" json:"createEvents,omitempty"` // Deprecated: Do not use.
-	DeletePods           bool     `protobuf:"varint,7,opt,name=deletePods,proto3" json:"deletePods,omitempty"`
-	BrokenPodLabelKey    string   `protobuf:"bytes,8,opt,name=brokenPodLabelKey,proto3" json:"brokenPodLabelKey,omitempty"`
-	BrokenPodLabelValue  string   `protobuf:"bytes,9,opt,name=brokenPodLabelValue,proto3" json:"brokenPodLabelValue,omitempty"`
-	InitContainerName    string   `protobuf:"bytes,10,opt,name=initContainerName,proto3" json:"initContainerName,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	LabelPods           bool   `protobuf:"varint,5,opt,name=labelPods,proto3" json:"labelPods,omitempty"`
+	CreateEvents        string `protobuf:"bytes,6,opt,name=createEvents,proto3" json:"createEvents,omitempty"` // Deprecated: Do not use.
+	DeletePods          bool   `protobuf:"varint,7,opt,name=deletePods,proto3" json:"deletePods,omitempty"`
+	BrokenPodLabelKey   string `protobuf:"bytes,8,opt,name=brokenPodLabelKey,proto3" json:"brokenPodLabelKey,omitempty"`
+	BrokenPodLabelValue string `protobuf:"bytes,9,opt,name=brokenPodLabelValue,proto3" json:"brokenPodLabelValue,omitempty"`
+	InitContainerName   string `protobuf:"bytes,10,opt,name=initContainerName,proto3" json:"initContainerName,omitempty"`
+	// The maximum number of broken pods deleted per minute, 0 means no limit.
+	MaxDeletionsPerMinute uint32 `protobuf:"varint,11,opt,name=maxDeletionsPerMinute,proto3" json:"maxDeletionsPerMinute,omitempty"`
+	// The initial delay before retrying to repair a pod, doubled on each failure.
+	BackoffBaseDelay *types.Duration `protobuf:"bytes,12,opt,name=backoffBaseDelay,proto3" json:"backoffBaseDelay,omitempty"`
+	// The maximum delay before retrying to repair a pod.
+	BackoffMaxDelay      *types.Duration `protobuf:"bytes,13,opt,name=backoffMaxDelay,proto3" json:"backoffMaxDelay,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
 func (m *CNIRepairConfig) Reset()         { *m = CNIRepairConfig{} }
@@ -519,6 +525,27 @@ func (m *CNIRepairConfig) GetInitContainerName() string {
 	return ""
 }
 
+func (m *CNIRepairConfig) GetMaxDeletionsPerMinute() uint32 {
+	if m != nil {
+		return m.MaxDeletionsPerMinute
+	}
+	return 0
+}
+
+func (m *CNIRepairConfig) GetBackoffBaseDelay() *types.Duration {
+	if m != nil {
+		return m.BackoffBaseDelay
+	}
+	return nil
+}
+
+func (m *CNIRepairConfig) GetBackoffMaxDelay() *types.Duration {
+	if m != nil {
+		return m.BackoffMaxDelay
+	}
+	return nil
+}
+
 // Configuration for CPU target utilization for HorizontalPodAutoscaler target.
 type CPUTargetUtilizationConfig struct {
 	// K8s utilization setting for HorizontalPodAutoscaler target.
@@ -4234,293 +4261,294 @@ func init() {
 }
 
 var fileDescriptor_261260e22432516f = []byte{
-	// 4606 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x7c, 0x49, 0x73, 0x1c, 0x47,
-	0x76, 0x30, 0x1b, 0x7b, 0xbf, 0x46, 0x03, 0x8d, 0xc4, 0xc2, 0x22, 0x08, 0x91, 0x50, 0x49, 0xa2,
-	0x28, 0x52, 0x03, 0x52, 0x10, 0x86, 0xa4, 0x38, 0x92, 0x3e, 0x61, 0x95, 0xc0, 0x01, 0xc0, 0xfe,
-	0xaa, 0x9b, 0xd4, 0x32, 0x9e, 0xa1, 0x13, 0x55, 0x89, 0x42, 0x8a, 0xd5, 0x95, 0xe5, 0xaa, 0xea,
-	0x06, 0x5b, 0x37, 0x9f, 0x1c, 0x76, 0x84, 0x7d, 0xf0, 0x0f, 0xf0, 0x1c, 0xfd, 0x13, 0xfc, 0x03,
-	0x7c, 0xf1, 0x71, 0xc2, 0x11, 0xbe, 0x3b, 0x74, 0xb2, 0x8f, 0x3e, 0x38, 0xe6, 0xe0, 0x8b, 0x23,
-	0x97, 0x5a, 0xbb, 0x1a, 0xdd, 0x00, 0xa4, 0xb0, 0xc3, 0x27, 0xa2, 0xde, 0x96, 0xdb, 0xcb, 0xb7,
-	0xe5, 0x6b, 0xc2, 0x3d, 0xef, 0xb5, 0xfd, 0x00, 0x7b, 0x34, 0x78, 0x40, 0x83, 0x90, 0xb2, 0x07,
-	0x9d, 0x8f, 0xb0, 0xe3, 0x9d, 0xe2, 0x8f, 0x1e, 0x74, 0xb0, 0xd3, 0x26, 0xc1, 0xab, 0xb0, 0xeb,
-	0x91, 0x60, 0xcd, 0xf3, 0x59, 0xc8, 0xd0, 0x54, 0x84, 0x5c, 0xbe, 0x65, 0x33, 0x66, 0x3b, 0xe4,
-	0x81, 0x80, 0x1f, 0xb7, 0x4f, 0x1e, 0x58, 0x6d, 0x1f, 0x87, 0x94, 0xb9, 0x92, 0x72, 0xf9, 0x0b,
-	0x9b, 0x86, 0xa7, 0xed, 0xe3, 0x35, 0x93, 0xb5, 0x1e, 0xd8, 0xcc, 0x66, 0x09, 0x61, 0xfc, 0x47,
-	0x5e, 0xc2, 0x99, 0x8f, 0x3d, 0x8f, 0xf8, 0x6a, 0xac, 0xe5, 0x05, 0xce, 0x26, 0xfe, 0x14, 0x02,
-	0x24, 0x54, 0x37, 0x00, 0x36, 0x7d, 0xf3, 0x74, 0x9b, 0xb9, 0x27, 0xd4, 0x46, 0x0b, 0x30, 0x8e,
-	0x5b, 0xd6, 0xa3, 0x0d, 0xad, 0xb4, 0x5a, 0xba, 0x5b, 0x35, 0xe4, 0x07, 0xd2, 0x60, 0xd2, 0xf3,
-	0xcc, 0x47, 0x1b, 0x0e, 0xd1, 0x46, 0x04, 0x3c, 0xfa, 0xe4, 0xf4, 0xc1, 0xc7, 0x9f, 0x3c, 0x7c,
-	0xa3, 0x8d, 0x4a, 0x7a, 0xf1, 0xa1, 0xff, 0x71, 0x0c, 0xca, 0xdb, 0x47, 0xfb, 0x4a, 0xe6, 0x06,
-	0x4c, 0x12, 0x17, 0x1f, 0x3b, 0xc4, 0x12, 0x52, 0x2b, 0xeb, 0xcb, 0x6b, 0x72, 0xa6, 0x6b, 0xd1,
-	0x4c, 0xd7, 0xb6, 0x18, 0x73, 0x5e, 0xf2, 0xdd, 0x31, 0x22, 0x52, 0x54, 0x83, 0xd1, 0xd3, 0xf6,
-	0xb1, 0x18, 0xaf, 0x6c, 0xf0, 0x3f, 0xd1, 0x07, 0x30, 0x1a, 0x62, 0x5b, 0x8c, 0x54, 0x59, 0xbf,
-	0xbe, 0x16, 0xed, 0xdc, 0x5a, 0xb3, 0xeb, 0x91, 0x7d, 0x37, 0x24, 0xfe, 0x09, 0x36, 0x89, 0xc1,
-	0x69, 0xf8, 0xb4, 0x68, 0x0b, 0xdb, 0x44, 0x1b, 0x13, 0xec, 0xf2, 0x03, 0xdd, 0x02, 0xf0, 0xda,
-	0x8e, 0x53, 0x67, 0x0e, 0x35, 0xbb, 0xda, 0xb8, 0x40, 0xa5, 0x20, 0x68, 0x05, 0xca, 0xa6, 0x4b,
-	0xb7, 0xa8, 0xbb, 0x43, 0x7d, 0x6d, 0x42, 0xa0, 0x13, 0x00, 0xe7, 0x36, 0x5d, 0xca, 0xd7, 0xc4,
-	0xd1, 0x93, 0x92, 0x3b, 0x81, 0xa0, 0xbb, 0x30, 0xab, 0xbe, 0xf6, 0xa8, 0x43, 0x8e, 0x70, 0x8b,
-	0x68, 0x53, 0x82, 0x28, 0x0f, 0x46, 0x1f, 0xc2, 0x1c, 0x79, 0x63, 0x3a, 0x6d, 0x4b, 0x7c, 0x06,
-	0x1e, 0x36, 0x49, 0xa0, 0x95, 0x57, 0x47, 0xef, 0x96, 0x8d, 0x5e, 0x04, 0x3a, 0x80, 0x19, 0x8f,
-	0x59, 0x9b, 0xae, 0xcb, 0x42, 0xa1, 0x0f, 0x81, 0x06, 0x62, 0x07, 0x56, 0xb3, 0x3b, 0x70, 0x88,
-	0xbd, 0x46, 0xe8, 0x53, 0xd7, 0x8e, 0xb7, 0x62, 0x6b, 0x44, 0x2b, 0x19, 0x39, 0x5e, 0x74, 0x17,
-	0x6a, 0x5e, 0xe0, 0xbd, 0x32, 0x9d, 0x76, 0x10, 0x12, 0xff, 0x95, 0xcf, 0x1c, 0xa2, 0x55, 0xc4,
-	0x34, 0x67, 0xbc, 0xc0, 0xdb, 0x96, 0x60, 0x83, 0x39, 0x04, 0x2d, 0xc3, 0x94, 0xc3, 0xec, 0x03,
-	0xd2, 0x21, 0x8e, 0x36, 0x2d, 0x28, 0xe2, 0x6f, 0xf4, 0x11, 0x4c, 0xf8, 0xc4, 0xc3, 0xd4, 0xd7,
-	0xaa, 0x62, 0x2e, 0x37, 0x92, 0xb9, 0x6c, 0x1f, 0xed, 0x1b, 0x02, 0x25, 0x4f, 0xdf, 0x50, 0x84,
-	0x5c, 0x0b, 0xcc, 0x53, 0x4c, 0x5d, 0x62, 0x69, 0x33, 0x83, 0xb5, 0x40, 0x91, 0xa2, 0x35, 0x18,
-	0x0f, 0x31, 0x75, 0x43, 0x6d, 0x56, 0xf0, 0x68, 0x99, 0x71, 0x9a, 0x1c, 0xa3, 0x86, 0x91, 0x64,
-	0xfa, 0x1e, 0xcc, 0x64, 0x11, 0x97, 0xd3, 0x3e, 0xfd, 0x6f, 0x46, 0x61, 0x36, 0xb7, 0x92, 0xff,
-	0x3d, 0x7a, 0xbc, 0x02, 0x65, 0x07, 0x1f, 0x13, 0xa7, 0xce, 0xac, 0x40, 0xa8, 0xf1, 0x94, 0x91,
-	0x00, 0xd0, 0x1d, 0x98, 0x36, 0x7d, 0x82, 0x43, 0xb2, 0xdb, 0x21, 0x6e, 0x18, 0x48, 0x45, 0x16,
-	0xba, 0x90, 0x81, 0x73, 0x7d, 0xb6, 0x88, 0x43, 0x42, 0x22, 0xc4, 0x4c, 0x0a, 0x31, 0x29, 0x08,
-	0xd7, 0xd2, 0x63, 0x9f, 0xbd, 0x26, 0x6e, 0x9d, 0x59, 0x07, 0x5c, 0xfa, 0xaf, 0x49, 0x57, 0x69,
-	0x74, 0x2f, 0x02, 0x3d, 0x84, 0xf9, 0x2c, 0x50, 0x6c, 0x83, 0x56, 0x16, 0xf4, 0x45, 0x28, 0x2e,
-	0x9f, 0xba, 0x94, 0x1f, 0x13, 0x3f, 0x3a, 0xe2, 0x8b, 0x1b, 0x03, 0x52, 0x7e, 0x0f, 0x42, 0xff,
-	0x06, 0x96, 0xb7, 0xeb, 0x2f, 0x9a, 0xd8, 0xb7, 0x49, 0xf8, 0x22, 0xa4, 0x0e, 0xfd, 0x41, 0x28,
-	0xb4, 0x3a, 0x9a, 0xa7, 0xa0, 0x85, 0x02, 0xb5, 0xd9, 0x21, 0x3e, 0xb6, 0x49, 0x8a, 0x42, 0x9c,
-	0xd5, 0xb8, 0xd1, 0x17, 0xaf, 0xff, 0x57, 0x09, 0xca, 0x06, 0x09, 0x58, 0xdb, 0xe7, 0xb7, 0xed,
-	0x31, 0x4c, 0x38, 0xb4, 0x45, 0xc3, 0x40, 0x2b, 0xad, 0x8e, 0xde, 0xad, 0xac, 0xdf, 0x4e, 0xce,
-	0x27, 0x26, 0x5a, 0x3b, 0x10, 0x14, 0xbb, 0x6e, 0xe8, 0x77, 0x0d, 0x45, 0x8e, 0x3e, 0x83, 0x29,
-	0x9f, 0xfc, 0x59, 0x9b, 0x04, 0x61, 0xa0, 0x8d, 0x08, 0xd6, 0xb7, 0x8b, 0x58, 0x0d, 0x45, 0x23,
-	0x99, 0x63, 0x96, 0xe5, 0x4f, 0xa0, 0x92, 0x92, 0xca, 0xb5, 0xe6, 0x35, 0xe9, 0x8a, 0xb9, 0x97,
-	0x0d, 0xfe, 0x27, 0x57, 0x05, 0xe1, 0x3f, 0x94, 0x26, 0xc9, 0x8f, 0xa7, 0x23, 0x4f, 0x4a, 0xcb,
-	0xbf, 0x82, 0x6a, 0x46, 0xea, 0x45, 0x98, 0xf5, 0x6f, 0x60, 0x75, 0x87, 0x9c, 0xe0, 0xb6, 0x13,
-	0xd6, 0x99, 0xb5, 0x43, 0x03, 0xbf, 0xed, 0xf1, 0x5d, 0xd9, 0x6a, 0x5b, 0x36, 0xb9, 0xda, 0x15,
-	0xfa, 0x1a, 0x96, 0x94, 0xe4, 0x78, 0xf5, 0x4a, 0x5e, 0x7a, 0xab, 0xa4, 0xc0, 0xa2, 0xad, 0x8a,
-	0xd6, 0xa4, 0x2e, 0x78, 0xcc, 0xa2, 0xff, 0xbe, 0x0a, 0xf3, 0xbb, 0xb6, 0x4f, 0x82, 0xe0, 0x4b,
-	0x1c, 0x92, 0x33, 0xdc, 0x55, 0x62, 0xf7, 0xa0, 0x86, 0xdb, 0x21, 0x0b, 0x4c, 0xec, 0x90, 0xdd,
-	0xa1, 0xe7, 0xdb, 0xc3, 0x83, 0x74, 0x98, 0x8e, 0x61, 0x87, 0xf8, 0x8d, 0x72, 0x79, 0x19, 0x58,
-	0x96, 0x86, 0xba, 0xca, 0xfd, 0x65, 0x60, 0xe8, 0x29, 0x8c, 0x9a, 0x5e, 0x5b, 0x5c, 0xd0, 0xca,
-	0xfa, 0xbb, 0x29, 0xcb, 0xd5, 0x57, 0x8f, 0xc5, 0x2d, 0xe5, 0x4c, 0xe9, 0x2d, 0x9f, 0x1c, 0xde,
-	0xd6, 0xac, 0xc3, 0x28, 0x71, 0x3b, 0xe2, 0x92, 0x0e, 0xe1, 0x1f, 0x0c, 0x4e, 0x8c, 0x36, 0x61,
-	0x42, 0xd8, 0x0e, 0xe9, 0x81, 0x2a, 0xeb, 0x1f, 0x24, 0x6c, 0x05, 0x9b, 0xbc, 0x26, 0x2e, 0x70,
-	0xac, 0xfa, 0xe2, 0x03, 0x21, 0x18, 0x73, 0xf9, 0xe5, 0xbd, 0x21, 0x94, 0x4b, 0xfc, 0x8d, 0xbe,
-	0x82, 0x69, 0x97, 0x59, 0xa4, 0x41, 0x1c, 0x62, 0x86, 0xcc, 0xbf, 0x90, 0xcf, 0xca, 0x70, 0x16,
-	0xf8, 0xbf, 0xca, 0x15, 0xfc, 0x1f, 0x83, 0x15, 0x01, 0x09, 0xe9, 0xe6, 0xc9, 0x09, 0x37, 0x33,
-	0x5d, 0xb1, 0xa2, 0x78, 0x9e, 0xd3, 0x42, 0xf6, 0xfb, 0x59, 0xd9, 0x0d, 0x87, 0x9a, 0xe4, 0xf9,
-	0x49, 0x9f, 0x21, 0xce, 0x15, 0x88, 0xce, 0x60, 0x35, 0x87, 0x6f, 0x12, 0xbf, 0x95, 0x1d, 0xb4,
-	0x7a, 0xf1, 0x41, 0x07, 0x0a, 0x45, 0xf7, 0x61, 0xdc, 0x63, 0x7e, 0x18, 0x68, 0x33, 0xe2, 0x5c,
-	0x17, 0x13, 0xe9, 0x75, 0x0e, 0x8e, 0xfc, 0xa6, 0xa0, 0x41, 0xbf, 0x84, 0xb2, 0x1f, 0x5d, 0x3c,
-	0xe5, 0x6b, 0xe7, 0x0b, 0xee, 0xa4, 0x18, 0x3a, 0xa1, 0x44, 0x9f, 0x42, 0x35, 0x20, 0xa6, 0x4f,
-	0xc2, 0x97, 0xcc, 0x69, 0xb7, 0x48, 0xa0, 0xd5, 0xc4, 0x58, 0x4b, 0x09, 0x6b, 0x23, 0x85, 0x36,
-	0xb2, 0xc4, 0xa8, 0x0e, 0x28, 0x20, 0x7e, 0x87, 0x9a, 0x24, 0x7d, 0xba, 0x73, 0x43, 0x6a, 0x6f,
-	0x01, 0x2f, 0xd7, 0x44, 0x1e, 0x5d, 0x6b, 0x48, 0x6a, 0x22, 0xff, 0x1b, 0xdd, 0x87, 0xb1, 0x1f,
-	0x3a, 0x9e, 0xab, 0xcd, 0xe7, 0xfd, 0xed, 0x77, 0xc4, 0x67, 0x2f, 0xeb, 0x47, 0x6a, 0x23, 0x04,
-	0x11, 0x3a, 0x84, 0x4a, 0xc8, 0x1c, 0xe2, 0xab, 0xb9, 0x2c, 0x5c, 0xfc, 0x60, 0xd2, 0xfc, 0xe8,
-	0x00, 0x66, 0x7d, 0xe6, 0x38, 0xd4, 0xb5, 0x0f, 0xf1, 0x9b, 0x46, 0xdb, 0xb7, 0x89, 0xb6, 0x28,
-	0x44, 0xde, 0xea, 0x71, 0xfb, 0xcf, 0x7d, 0x29, 0x6d, 0x8f, 0xf9, 0xf5, 0x2d, 0x21, 0x29, 0xcf,
-	0x8a, 0xbe, 0x81, 0xc5, 0x04, 0xf4, 0xc2, 0xc5, 0x1d, 0x4c, 0x1d, 0x7e, 0xf1, 0xb5, 0xa5, 0xa1,
-	0x65, 0x16, 0x0b, 0x40, 0x87, 0x50, 0x35, 0xc5, 0x36, 0x44, 0xe7, 0x78, 0xfd, 0x42, 0x0b, 0x37,
-	0xb2, 0xdc, 0xe8, 0x37, 0xb0, 0x80, 0x2d, 0x8b, 0xf2, 0x3d, 0xc0, 0x4e, 0xec, 0xc7, 0x03, 0x4d,
-	0xbb, 0x98, 0xd4, 0x42, 0x21, 0xe8, 0x09, 0x94, 0xfd, 0xb6, 0xbb, 0x19, 0x18, 0x8c, 0x85, 0xda,
-	0xf2, 0x40, 0xe3, 0x98, 0x10, 0xcb, 0x88, 0xe3, 0x7b, 0x62, 0x72, 0x91, 0x4d, 0xd2, 0xf2, 0x1c,
-	0x1c, 0x12, 0xed, 0x66, 0x14, 0x71, 0xe4, 0x10, 0xc2, 0x23, 0x27, 0xc6, 0xee, 0x42, 0x4e, 0xf5,
-	0xdf, 0x4b, 0x30, 0xa3, 0xcc, 0x66, 0xe4, 0xf3, 0x8e, 0x60, 0x5e, 0x64, 0x83, 0xaf, 0x88, 0x30,
-	0xaa, 0xb6, 0xc4, 0x2a, 0xff, 0xf4, 0xd6, 0xb9, 0x36, 0xd7, 0x40, 0x82, 0x73, 0x37, 0xcd, 0x98,
-	0x76, 0x10, 0x23, 0xc3, 0x3b, 0x88, 0xff, 0x0f, 0x0b, 0x72, 0x16, 0xd4, 0xcd, 0x4c, 0x63, 0x2c,
-	0xaf, 0x40, 0xfb, 0x6e, 0xc1, 0x3c, 0xe4, 0x0a, 0xf6, 0x33, 0xac, 0xfa, 0x3f, 0xd6, 0x60, 0xfa,
-	0x4b, 0x87, 0x1d, 0x8b, 0x33, 0xe2, 0x2b, 0xfd, 0x10, 0xc6, 0xb0, 0x6f, 0x9e, 0xaa, 0xa5, 0x2d,
-	0x24, 0x32, 0x93, 0x34, 0x53, 0xa8, 0xa2, 0xa0, 0xe2, 0x71, 0xa3, 0xd4, 0x1d, 0x7e, 0x42, 0x71,
-	0xd6, 0xa3, 0xad, 0xcb, 0xb8, 0xb1, 0x00, 0xc5, 0xdd, 0xbc, 0xd2, 0x36, 0xec, 0x50, 0x4b, 0xc6,
-	0x78, 0xa3, 0x83, 0xdd, 0x7c, 0x9e, 0x07, 0x7d, 0x05, 0xb7, 0x2d, 0x19, 0x9f, 0xc8, 0x49, 0xbd,
-	0xa4, 0x01, 0x3d, 0xa6, 0x0e, 0x0d, 0xbb, 0x0d, 0x12, 0x86, 0xd4, 0xb5, 0x03, 0x6d, 0x43, 0xe4,
-	0x64, 0x83, 0xc8, 0xd0, 0x4b, 0x98, 0x57, 0x24, 0x47, 0x69, 0x97, 0x37, 0x71, 0x01, 0x37, 0x55,
-	0x24, 0x00, 0xb9, 0xb0, 0x6c, 0xf5, 0x8d, 0xcd, 0x54, 0x5c, 0x70, 0x2f, 0x11, 0x3f, 0x28, 0x8e,
-	0x13, 0x03, 0x9d, 0x23, 0x11, 0xd5, 0xa1, 0x66, 0xe5, 0x22, 0x36, 0x11, 0xc0, 0x67, 0x16, 0x51,
-	0x1c, 0xd3, 0x09, 0xd9, 0x3d, 0xdc, 0xe8, 0x37, 0x80, 0x14, 0xac, 0x99, 0xb2, 0xaa, 0x8f, 0x2f,
-	0x6e, 0x55, 0x0b, 0xc4, 0x44, 0x99, 0xd5, 0x74, 0x92, 0x59, 0xdd, 0x85, 0x59, 0x91, 0x21, 0xd5,
-	0x93, 0x2c, 0xbf, 0x2a, 0x53, 0xf0, 0x1c, 0x18, 0xdd, 0x83, 0x5a, 0x0c, 0x92, 0x2e, 0x2a, 0xd0,
-	0xde, 0x13, 0xa7, 0xdd, 0x03, 0x47, 0x77, 0x60, 0x46, 0x28, 0x7e, 0xa2, 0x9d, 0x33, 0x32, 0x61,
-	0xce, 0x42, 0xb9, 0x61, 0x72, 0x98, 0xbd, 0x19, 0x3c, 0x0b, 0x98, 0xab, 0xbd, 0x3b, 0xd8, 0x30,
-	0xc5, 0xc4, 0xe8, 0x31, 0x4c, 0x3a, 0xcc, 0xb6, 0xa9, 0x6b, 0x2b, 0xef, 0x97, 0x32, 0x08, 0xf2,
-	0x6e, 0x1d, 0x48, 0xb4, 0xba, 0x88, 0x11, 0x35, 0x5a, 0x82, 0x89, 0x16, 0x09, 0x4e, 0xf7, 0x77,
-	0xb4, 0x5f, 0x8a, 0x29, 0xa9, 0x2f, 0xb4, 0x03, 0xd3, 0xfc, 0xaf, 0x23, 0x12, 0x9e, 0x31, 0xff,
-	0x75, 0xa0, 0x7c, 0xdf, 0x60, 0x9f, 0x9a, 0xe1, 0x42, 0x5f, 0xc0, 0x74, 0xab, 0xed, 0x84, 0x54,
-	0x55, 0x05, 0x94, 0x9b, 0x59, 0x49, 0xa4, 0x1c, 0xa6, 0xb0, 0x6a, 0x6a, 0x19, 0x0e, 0xa4, 0xc1,
-	0xa4, 0x2b, 0xa5, 0x69, 0xef, 0x8b, 0x09, 0x46, 0x9f, 0xe8, 0x11, 0x2c, 0x79, 0xcc, 0xda, 0x39,
-	0x6a, 0x34, 0x08, 0xb7, 0x03, 0xa9, 0x42, 0xc8, 0x7d, 0x71, 0x0c, 0x7d, 0xb0, 0xe8, 0x77, 0xb0,
-	0xc2, 0x5a, 0x34, 0x6c, 0x50, 0x8b, 0x98, 0xd8, 0xdf, 0x17, 0x56, 0x9b, 0xa9, 0xc1, 0x0f, 0xb1,
-	0xa7, 0xdd, 0x19, 0xb8, 0xef, 0xe7, 0xf2, 0xa3, 0xcf, 0x61, 0x9a, 0xb9, 0x49, 0xf9, 0x45, 0x39,
-	0xc2, 0xf3, 0xe4, 0x65, 0xe8, 0x91, 0x01, 0x4b, 0xcc, 0xe3, 0x2a, 0xca, 0xfc, 0x43, 0xec, 0x62,
-	0x9b, 0x7c, 0x4d, 0x8e, 0x4f, 0x19, 0x7b, 0x1d, 0x68, 0x1f, 0x0c, 0x94, 0xd4, 0x87, 0x13, 0x3d,
-	0x84, 0x39, 0xcf, 0xa7, 0xcc, 0xa7, 0x61, 0x77, 0xdb, 0xc1, 0x41, 0x20, 0x32, 0xe5, 0x9b, 0x71,
-	0x5a, 0xdf, 0x8b, 0x14, 0xb1, 0x9f, 0xcf, 0xde, 0x74, 0xb5, 0x15, 0x31, 0x68, 0x3a, 0xf6, 0xe3,
-	0xe0, 0x38, 0xf6, 0xe3, 0x1f, 0xe8, 0x31, 0x94, 0xc5, 0x1f, 0xfb, 0x2e, 0x0d, 0xb5, 0xb7, 0xf2,
-	0xf5, 0x9c, 0x7a, 0x84, 0x52, 0x4c, 0x09, 0x2d, 0x7a, 0x0f, 0x46, 0x03, 0x2b, 0xd0, 0x6e, 0xe5,
-	0xc3, 0xc5, 0xc6, 0x4e, 0x43, 0x11, 0x73, 0x7c, 0x54, 0xef, 0xb8, 0x3d, 0x44, 0xbd, 0x63, 0x0d,
-	0x26, 0x42, 0x1f, 0x9b, 0xc4, 0xd7, 0xde, 0x16, 0xd4, 0xa9, 0x40, 0xb2, 0x29, 0xe0, 0x51, 0x51,
-	0x49, 0x52, 0xa1, 0x75, 0x98, 0x68, 0x07, 0xe4, 0x70, 0xbb, 0xae, 0xbd, 0x33, 0x70, 0x77, 0x15,
-	0x25, 0x5a, 0x03, 0xe4, 0x93, 0x16, 0x0b, 0x49, 0x9d, 0x3a, 0x2c, 0xdc, 0xb4, 0x2c, 0xee, 0xcd,
-	0xb4, 0x87, 0x42, 0x3d, 0x0b, 0x30, 0x7c, 0x4e, 0xe2, 0xa2, 0x5b, 0xda, 0xa3, 0xfc, 0x9c, 0xf6,
-	0x05, 0x3c, 0x9a, 0x93, 0xa4, 0xe2, 0x51, 0x86, 0xc7, 0xf9, 0xb7, 0x89, 0x1f, 0xd6, 0x7d, 0xd6,
-	0xa1, 0x16, 0xf1, 0xb5, 0x27, 0x32, 0xca, 0xe8, 0x41, 0xa0, 0x15, 0x28, 0x7f, 0x7f, 0x16, 0x2a,
-	0x63, 0xf5, 0x89, 0xac, 0x39, 0xc6, 0x00, 0xb1, 0xc3, 0x61, 0xa0, 0x3d, 0xed, 0xd9, 0xe1, 0x66,
-	0xb2, 0xc3, 0x61, 0x80, 0x96, 0x79, 0x42, 0xdd, 0xa1, 0x01, 0x77, 0x85, 0xbf, 0x92, 0xa5, 0xba,
-	0xe8, 0x1b, 0x6d, 0xc1, 0x4c, 0x8b, 0xb5, 0xdd, 0xf0, 0x30, 0x74, 0x02, 0x3e, 0x72, 0xa0, 0x7d,
-	0x3a, 0x70, 0xab, 0x72, 0x1c, 0xa2, 0x30, 0x8a, 0xa3, 0x9d, 0xfa, 0x4c, 0x15, 0x46, 0x23, 0x00,
-	0x1f, 0x81, 0xbc, 0x09, 0x89, 0xef, 0x62, 0x47, 0x6e, 0x88, 0xf6, 0xf9, 0xe0, 0x11, 0xb2, 0x1c,
-	0xfa, 0x2f, 0xa0, 0x1c, 0xaf, 0x09, 0xad, 0x42, 0x45, 0xc5, 0xf6, 0x3c, 0x53, 0x51, 0xa5, 0xe8,
-	0x34, 0x48, 0x37, 0x60, 0x3a, 0xbd, 0xf7, 0x62, 0x0a, 0x22, 0xc4, 0xd9, 0x74, 0xb1, 0xd3, 0x0d,
-	0x68, 0x30, 0x44, 0x50, 0x94, 0xe3, 0xd0, 0xef, 0xc3, 0x7c, 0x81, 0xad, 0xe5, 0x51, 0x9e, 0x23,
-	0x6a, 0xa0, 0x32, 0xf2, 0x93, 0x1f, 0xfa, 0x5f, 0xd5, 0x60, 0xa1, 0x28, 0x46, 0xfa, 0x3f, 0x55,
-	0x84, 0xf8, 0x02, 0xaa, 0x66, 0x3b, 0x08, 0x59, 0xab, 0x21, 0xb7, 0x5e, 0x45, 0x34, 0xe7, 0x2d,
-	0x24, 0xcb, 0x90, 0x8e, 0x52, 0xe1, 0xc2, 0x65, 0x8c, 0xca, 0x45, 0xca, 0x18, 0x5b, 0x71, 0x19,
-	0x63, 0x56, 0xa4, 0xa0, 0xf7, 0xce, 0x8f, 0x65, 0x0b, 0xeb, 0x18, 0x77, 0x60, 0xc6, 0x61, 0xd8,
-	0xda, 0xc2, 0x0e, 0x76, 0x4d, 0xe2, 0xef, 0xd7, 0xb5, 0x9a, 0x74, 0xf4, 0x59, 0x28, 0x7a, 0x0a,
-	0x5a, 0x1a, 0xd2, 0x10, 0xc1, 0x8e, 0x81, 0x5d, 0x9b, 0xf0, 0xec, 0x95, 0x7b, 0xaf, 0xbe, 0xf8,
-	0xb8, 0x56, 0xf2, 0xe1, 0x39, 0xb5, 0x92, 0xf9, 0x9f, 0xb0, 0x56, 0xb2, 0xf0, 0x33, 0xd6, 0x4a,
-	0x16, 0xff, 0x27, 0x6a, 0x25, 0x4b, 0x3f, 0x6b, 0xad, 0xe4, 0xfa, 0x10, 0xb5, 0x92, 0x3b, 0x30,
-	0xed, 0x13, 0xcf, 0xa1, 0x26, 0xde, 0xe6, 0x66, 0x52, 0x64, 0xb5, 0x55, 0x79, 0x18, 0x69, 0x38,
-	0xda, 0x4a, 0xd7, 0x54, 0x6e, 0x5c, 0xe0, 0x1c, 0xce, 0x2b, 0xb0, 0xdc, 0xbc, 0x7a, 0x81, 0x65,
-	0xe5, 0x27, 0x28, 0xb0, 0xbc, 0x95, 0x2a, 0xb0, 0x3c, 0x52, 0x05, 0x16, 0x19, 0x07, 0xe8, 0xfd,
-	0x2e, 0xde, 0x77, 0x1d, 0xcf, 0xcd, 0xd4, 0x5a, 0x0a, 0x8a, 0x23, 0xb7, 0x7f, 0x86, 0xe2, 0xc8,
-	0xea, 0x55, 0x8b, 0x23, 0x1b, 0xb0, 0x18, 0x79, 0xab, 0xa6, 0x8f, 0x4f, 0x4e, 0xa8, 0xa9, 0xdc,
-	0xb5, 0x2e, 0x36, 0xa1, 0x18, 0x99, 0xaf, 0x24, 0xbd, 0x73, 0xc5, 0x4a, 0xd2, 0xaf, 0x61, 0x5a,
-	0xe5, 0xec, 0x42, 0x23, 0x55, 0x7e, 0x31, 0x74, 0x29, 0x25, 0xc3, 0xdc, 0xb7, 0x3e, 0xf3, 0xde,
-	0x4f, 0x51, 0x9f, 0xe9, 0xa9, 0x25, 0xdd, 0xb9, 0x52, 0x2d, 0x29, 0x53, 0xee, 0xf9, 0xc5, 0x95,
-	0xcb, 0x3d, 0x6b, 0x3f, 0x43, 0xb9, 0xe7, 0x14, 0xb4, 0x7e, 0xaa, 0x7e, 0xc9, 0x47, 0xc3, 0x25,
-	0x98, 0x08, 0xda, 0x27, 0x27, 0xf4, 0x8d, 0x1a, 0x4c, 0x7d, 0xe9, 0xff, 0x56, 0x02, 0xd4, 0x9b,
-	0x74, 0x5d, 0x72, 0x90, 0x55, 0xa8, 0xa8, 0x67, 0x60, 0x91, 0x50, 0xc8, 0x91, 0xd2, 0x20, 0x1e,
-	0x2a, 0xdb, 0x22, 0x24, 0xda, 0x61, 0x2d, 0x4c, 0xdd, 0x86, 0x9c, 0xd2, 0xa8, 0x0c, 0x95, 0x7b,
-	0x31, 0xe8, 0x19, 0x20, 0xea, 0x8a, 0xf7, 0xeb, 0x5d, 0xb7, 0xc3, 0xba, 0x7b, 0xd4, 0xe1, 0x69,
-	0xe3, 0xd8, 0xc0, 0x29, 0x15, 0x70, 0xe9, 0x7f, 0x51, 0x82, 0x9b, 0xcf, 0xdb, 0xe1, 0x31, 0x6b,
-	0xbb, 0x56, 0xe6, 0x66, 0xa9, 0x35, 0x7f, 0x0e, 0x63, 0x2d, 0x66, 0xc9, 0x69, 0xcf, 0xa4, 0xdd,
-	0xfd, 0x39, 0x4c, 0x6b, 0x87, 0xcc, 0x22, 0x86, 0xe0, 0xd3, 0xef, 0xc2, 0x18, 0xff, 0x42, 0x55,
-	0x28, 0x6f, 0x1e, 0x1c, 0x3c, 0xff, 0xfa, 0xd5, 0xe6, 0xd1, 0xb7, 0xb5, 0x6b, 0x68, 0x0e, 0xaa,
-	0xc6, 0xee, 0x97, 0xfb, 0x8d, 0xa6, 0xf1, 0xed, 0xab, 0xe7, 0x47, 0x07, 0xdf, 0xd6, 0x4a, 0xfa,
-	0x1f, 0xa7, 0xa1, 0x22, 0x32, 0x82, 0x2b, 0xed, 0x76, 0x51, 0x60, 0x38, 0x72, 0xd5, 0xc0, 0xb0,
-	0x4f, 0xd0, 0x97, 0x0f, 0x1e, 0xc7, 0x0a, 0x82, 0xc7, 0xbc, 0x17, 0x1b, 0xef, 0xe3, 0xc5, 0xe2,
-	0x27, 0xe8, 0x89, 0xf4, 0x13, 0xf4, 0xbb, 0x50, 0x15, 0x29, 0x58, 0x03, 0xb7, 0x3c, 0x6e, 0x32,
-	0xc5, 0x9b, 0x53, 0xc9, 0xc8, 0x02, 0xb3, 0xaf, 0x0a, 0xe5, 0xa1, 0x5f, 0x15, 0xee, 0xc2, 0xac,
-	0xb4, 0x01, 0x49, 0x1a, 0x0e, 0xaa, 0x93, 0x22, 0x0b, 0x8e, 0xa2, 0xdb, 0xca, 0x65, 0xa2, 0xdb,
-	0x7c, 0xd4, 0x35, 0x7d, 0xe9, 0xa8, 0xcb, 0x84, 0xdb, 0xaf, 0x09, 0xf1, 0xb0, 0x43, 0x3b, 0x7c,
-	0x6b, 0x79, 0xf0, 0x2b, 0xae, 0xa6, 0x2b, 0x4d, 0xcc, 0xa6, 0x4d, 0xe2, 0x36, 0x89, 0xfc, 0x49,
-	0xef, 0xa8, 0x26, 0x1f, 0x63, 0x90, 0x04, 0x74, 0x00, 0x35, 0x8b, 0x78, 0x0e, 0xeb, 0xb6, 0x88,
-	0x1b, 0x4a, 0x4b, 0xa5, 0x1a, 0x29, 0x06, 0x7b, 0xf2, 0x1e, 0x4e, 0x6e, 0x55, 0xcd, 0xb8, 0x66,
-	0x82, 0x06, 0x5b, 0xd5, 0x98, 0x38, 0x95, 0x72, 0x2f, 0x0c, 0x9d, 0x72, 0xab, 0x80, 0x7e, 0xf1,
-	0x22, 0x01, 0x7d, 0x41, 0x74, 0xa0, 0xfd, 0x0c, 0xd1, 0xc1, 0x8d, 0xab, 0x3f, 0x9d, 0x64, 0xfc,
-	0xfc, 0xf2, 0x15, 0xfd, 0xfc, 0x29, 0xbc, 0x2d, 0x2d, 0x46, 0x9d, 0x6f, 0xa7, 0xc9, 0x9c, 0x86,
-	0x4b, 0x79, 0xd8, 0xca, 0x27, 0x12, 0x59, 0x36, 0x15, 0xc1, 0x9d, 0xb7, 0xf3, 0x83, 0x85, 0xa0,
-	0x13, 0x58, 0xed, 0x4b, 0xb4, 0xef, 0xca, 0x81, 0xde, 0x1a, 0x38, 0xd0, 0x40, 0x19, 0x05, 0x39,
-	0xc9, 0xad, 0x2b, 0xe4, 0x24, 0xff, 0x0f, 0xa6, 0xa5, 0x2e, 0xca, 0xac, 0x4a, 0x45, 0x8c, 0x37,
-	0x53, 0x01, 0x7b, 0x62, 0xa9, 0x55, 0xe2, 0x95, 0x61, 0x40, 0x4f, 0xe0, 0xfa, 0xf7, 0x67, 0xaf,
-	0x03, 0x6e, 0x7c, 0x9c, 0x0e, 0xf1, 0x77, 0xdf, 0x84, 0x3e, 0xe6, 0xe1, 0xc2, 0xf6, 0xa6, 0x88,
-	0x14, 0xcb, 0x46, 0x3f, 0x34, 0xfa, 0x18, 0x26, 0x3d, 0xa7, 0x6d, 0x53, 0x37, 0x50, 0xd5, 0xa9,
-	0x1b, 0x05, 0xa7, 0x2c, 0xd7, 0x60, 0x44, 0x94, 0x51, 0x91, 0x5a, 0xef, 0x69, 0xff, 0x79, 0x67,
-	0x70, 0x39, 0x4c, 0xff, 0x87, 0x12, 0x20, 0xb1, 0x1e, 0x15, 0x5e, 0x28, 0x07, 0x74, 0x07, 0x66,
-	0x54, 0x38, 0x17, 0x25, 0xe6, 0x25, 0x55, 0x90, 0xce, 0x40, 0xd1, 0x0b, 0x58, 0xa4, 0x31, 0x63,
-	0xc8, 0xd5, 0x97, 0xf8, 0x87, 0x89, 0xcf, 0x4c, 0xb5, 0xb6, 0x14, 0x92, 0x19, 0xc5, 0xdc, 0xdc,
-	0xbb, 0x44, 0x08, 0x07, 0x07, 0x81, 0x8a, 0x07, 0x32, 0x30, 0x7d, 0x1f, 0xe6, 0xc4, 0xc4, 0x33,
-	0x2e, 0xfb, 0x72, 0x7d, 0x24, 0x21, 0xcc, 0x36, 0x89, 0x43, 0x5a, 0x24, 0xf4, 0xaf, 0x24, 0x08,
-	0xdd, 0x87, 0x91, 0xce, 0xba, 0x7a, 0x2a, 0x4a, 0x29, 0x4c, 0x2c, 0xfc, 0xe5, 0xba, 0x4a, 0x4f,
-	0x46, 0x3a, 0xeb, 0xfa, 0xdf, 0x8e, 0xc2, 0x5c, 0x0f, 0xe6, 0x92, 0x03, 0x7f, 0x03, 0x73, 0x2d,
-	0x12, 0x62, 0x0b, 0x87, 0xf8, 0x15, 0x79, 0x63, 0x9e, 0x62, 0x57, 0x75, 0x74, 0x55, 0xd6, 0xef,
-	0x17, 0xce, 0xe3, 0x50, 0x51, 0xef, 0x2a, 0x62, 0x35, 0xaf, 0x5a, 0x2b, 0x07, 0x47, 0xbb, 0x00,
-	0x9e, 0xcf, 0x5a, 0x24, 0x3c, 0x25, 0xed, 0xa8, 0xe6, 0xf5, 0x5e, 0xa1, 0xc8, 0x7a, 0x4c, 0xa6,
-	0x84, 0xa5, 0x18, 0xd1, 0x57, 0x50, 0x09, 0x42, 0x6c, 0xbe, 0xb6, 0x7c, 0xda, 0x21, 0xbe, 0xda,
-	0xa2, 0x3b, 0x85, 0x72, 0x1a, 0x9c, 0x6e, 0x47, 0xd0, 0x29, 0x41, 0x69, 0x56, 0xf4, 0x27, 0x30,
-	0x87, 0x4d, 0x93, 0x04, 0xc1, 0x2b, 0x87, 0xd9, 0xaf, 0xbc, 0xa4, 0xd3, 0xb2, 0xb2, 0xfe, 0xb0,
-	0x50, 0xde, 0xa6, 0xa0, 0x3e, 0x60, 0xb6, 0xd4, 0x14, 0x19, 0xfc, 0x29, 0xc9, 0xb3, 0x38, 0x8b,
-	0xd4, 0x31, 0xbc, 0x3d, 0x70, 0x97, 0xd0, 0xa7, 0x50, 0x39, 0xc3, 0x41, 0x6b, 0xf8, 0x18, 0x2b,
-	0x4d, 0xae, 0xff, 0xcb, 0x28, 0xdc, 0x3c, 0x67, 0xdb, 0x2e, 0xa9, 0x01, 0x57, 0x9a, 0x13, 0xfa,
-	0x6d, 0x14, 0x0f, 0xbd, 0x62, 0x1d, 0xe2, 0xfb, 0xd4, 0x22, 0xea, 0x88, 0x36, 0x86, 0x3a, 0xea,
-	0x35, 0xf9, 0xcf, 0x73, 0xc5, 0x6b, 0xcc, 0x98, 0x99, 0xef, 0xe5, 0x1f, 0x4b, 0x30, 0x93, 0x25,
-	0x41, 0x4f, 0x61, 0x32, 0xfb, 0x42, 0x3d, 0xd8, 0x69, 0x47, 0x0c, 0xe8, 0x2b, 0x6e, 0x9d, 0x84,
-	0xe9, 0x57, 0x8f, 0x2c, 0x6a, 0xb9, 0x83, 0x45, 0xe4, 0xf8, 0xd0, 0x33, 0x98, 0x65, 0xca, 0x5b,
-	0x45, 0xa2, 0x46, 0x87, 0x14, 0x95, 0x67, 0xd4, 0xff, 0x6e, 0x1c, 0x56, 0xce, 0x53, 0xe3, 0x4b,
-	0x1e, 0xec, 0x93, 0xe4, 0xe5, 0x6e, 0xe0, 0xa1, 0x0a, 0x7f, 0x16, 0x3f, 0xdd, 0x3d, 0x05, 0x68,
-	0x31, 0x97, 0x86, 0x8c, 0x4f, 0x7c, 0x88, 0x07, 0xec, 0x14, 0x35, 0x7a, 0x04, 0x53, 0x21, 0xf3,
-	0x98, 0xc3, 0xec, 0xee, 0x10, 0xd9, 0x55, 0x4c, 0x8b, 0x76, 0x60, 0xd6, 0xa2, 0x01, 0x9f, 0x79,
-	0x1c, 0x4a, 0x0c, 0x2e, 0xe9, 0xe6, 0x59, 0xf8, 0x01, 0x67, 0x35, 0x48, 0x5d, 0xf0, 0x21, 0x0e,
-	0x38, 0xcb, 0x87, 0xbe, 0x87, 0xc5, 0xe8, 0x9c, 0x62, 0x3b, 0x20, 0xf6, 0x72, 0x52, 0x38, 0xa8,
-	0x8d, 0xe1, 0x2c, 0xd0, 0x5a, 0x86, 0xd7, 0x28, 0x16, 0x89, 0x4e, 0x61, 0x41, 0xa9, 0x57, 0x76,
-	0xa8, 0xa9, 0x2b, 0x0c, 0x55, 0x28, 0x51, 0xdf, 0x80, 0x6a, 0x76, 0xe8, 0x29, 0x18, 0x3b, 0x7a,
-	0x7e, 0xb4, 0x5b, 0xbb, 0xc6, 0xff, 0xda, 0x7b, 0x71, 0x70, 0x50, 0x2b, 0xa1, 0x59, 0xa8, 0xec,
-	0x1a, 0xc6, 0x73, 0xa3, 0x21, 0xb3, 0xcc, 0x11, 0xfd, 0xef, 0x4b, 0x70, 0x67, 0x38, 0xbb, 0x78,
-	0x49, 0x55, 0xfd, 0x12, 0xe6, 0x1c, 0x66, 0x7f, 0x4d, 0x5d, 0x8b, 0x9d, 0x45, 0x69, 0x87, 0x52,
-	0xda, 0x73, 0xf2, 0x92, 0x5e, 0x1e, 0x7d, 0x57, 0xf9, 0xf6, 0x74, 0x90, 0x85, 0x1e, 0xc2, 0x7c,
-	0xd0, 0x3e, 0x0e, 0x4c, 0x9f, 0x1e, 0x13, 0x2b, 0x69, 0x1f, 0x28, 0x89, 0x72, 0x78, 0x11, 0x4a,
-	0xff, 0xeb, 0x12, 0x54, 0x52, 0xd5, 0xd5, 0xb8, 0x32, 0x5e, 0x4a, 0x55, 0xc6, 0x11, 0x8c, 0x79,
-	0xcc, 0x0f, 0xc5, 0x34, 0xc7, 0x0d, 0xf1, 0x37, 0x5a, 0x86, 0x29, 0x9e, 0x7d, 0x89, 0xa7, 0xa1,
-	0x51, 0x01, 0x8f, 0xbf, 0xd1, 0x2d, 0x00, 0xd9, 0xe7, 0x2b, 0xb0, 0x63, 0x02, 0x9b, 0x82, 0x70,
-	0x5e, 0x4f, 0x45, 0xaa, 0xaa, 0xff, 0x3f, 0xfe, 0xd6, 0xff, 0x79, 0x12, 0x2a, 0xa9, 0xd7, 0x51,
-	0x2e, 0x8b, 0x27, 0xcc, 0xf2, 0x89, 0x58, 0x35, 0x60, 0xa7, 0x20, 0x3c, 0x05, 0x56, 0xb5, 0x12,
-	0x59, 0x03, 0x51, 0x02, 0xb3, 0x40, 0xf4, 0x21, 0xcc, 0x99, 0xac, 0xe5, 0x31, 0x97, 0xe7, 0x5e,
-	0x51, 0x3b, 0xbd, 0x4c, 0xa5, 0x7b, 0x11, 0xc9, 0x3b, 0xd6, 0x36, 0xf3, 0xc9, 0x4e, 0xbb, 0xe5,
-	0xa9, 0xac, 0x79, 0x88, 0x77, 0xac, 0x88, 0x83, 0x9f, 0x84, 0xfa, 0x11, 0x81, 0x8a, 0xc0, 0x65,
-	0xc1, 0x50, 0xb6, 0x49, 0x14, 0xa1, 0x78, 0xbe, 0x1d, 0x81, 0xeb, 0xea, 0x19, 0x43, 0xb5, 0x4d,
-	0xe4, 0xc0, 0x49, 0x31, 0x60, 0x26, 0x5d, 0x0c, 0xb8, 0x0b, 0xb3, 0xaa, 0x80, 0x13, 0xf3, 0xd7,
-	0x54, 0xdb, 0x45, 0x16, 0x9c, 0xf9, 0x4d, 0x01, 0xca, 0xfd, 0xa6, 0xe0, 0x29, 0x8f, 0x65, 0x68,
-	0x87, 0x3a, 0xc4, 0x26, 0x96, 0x7a, 0x03, 0x39, 0xd7, 0x20, 0x26, 0xd4, 0x68, 0x0b, 0x56, 0x7c,
-	0x82, 0x2d, 0xea, 0x92, 0x20, 0xd8, 0x77, 0x69, 0x48, 0xb1, 0xb3, 0x43, 0x1c, 0xdc, 0x6d, 0x10,
-	0x93, 0xb9, 0x96, 0x7c, 0x05, 0xa9, 0x1a, 0xe7, 0xd2, 0xa0, 0x47, 0xb0, 0x14, 0xe3, 0xeb, 0xc4,
-	0xa7, 0xcc, 0x8a, 0xb8, 0x17, 0x05, 0x77, 0x1f, 0x2c, 0xfa, 0x14, 0x6e, 0xc4, 0x98, 0x3d, 0x4c,
-	0x9d, 0xb6, 0x4f, 0x9a, 0xa7, 0x3e, 0x09, 0x4e, 0x99, 0x63, 0x89, 0xd7, 0x8a, 0xaa, 0xd1, 0x9f,
-	0x80, 0x6b, 0x59, 0x10, 0xe2, 0xb0, 0x2d, 0x2a, 0xb3, 0xa2, 0xdb, 0xa0, 0x6a, 0xa4, 0x20, 0xd9,
-	0x12, 0x8a, 0x76, 0x81, 0x12, 0x4a, 0xf4, 0x90, 0x7e, 0x43, 0xd8, 0xb7, 0x5a, 0xc2, 0x23, 0xe1,
-	0xa9, 0x27, 0xf4, 0x05, 0x75, 0xca, 0x91, 0x81, 0x97, 0xfa, 0xb2, 0x22, 0x8e, 0xa7, 0x10, 0x87,
-	0x3e, 0x87, 0xb2, 0x43, 0x4f, 0x88, 0xd9, 0x35, 0x1d, 0xa2, 0x2a, 0xd1, 0x83, 0x8d, 0x7f, 0xc2,
-	0x82, 0x4e, 0xe1, 0x36, 0x5f, 0xfc, 0xa6, 0x27, 0xea, 0x4c, 0xdc, 0xa8, 0xbc, 0x70, 0x43, 0xea,
-	0x88, 0xdb, 0xd7, 0x08, 0xb1, 0x1f, 0x46, 0xa5, 0xe8, 0x41, 0xde, 0x74, 0x90, 0x18, 0xfd, 0x77,
-	0x30, 0x9b, 0x6b, 0x60, 0x48, 0x74, 0xb8, 0x94, 0xd6, 0xe1, 0xcc, 0x3e, 0x8f, 0x0f, 0xbb, 0xcf,
-	0xfa, 0x36, 0x5c, 0xef, 0xd3, 0xb0, 0xce, 0x33, 0x3f, 0xd3, 0x6b, 0x47, 0x15, 0x64, 0xd3, 0x6b,
-	0xcb, 0x6e, 0x9d, 0x16, 0xf3, 0xbb, 0x51, 0x55, 0x57, 0x7e, 0xe9, 0x5f, 0x42, 0x39, 0x6e, 0x99,
-	0x40, 0x4f, 0x61, 0x3c, 0x64, 0xaf, 0x89, 0x3b, 0xac, 0x53, 0x15, 0x33, 0x92, 0x2c, 0xfa, 0x9f,
-	0xc2, 0x74, 0xfa, 0x39, 0x08, 0xad, 0x40, 0x59, 0xbc, 0xe4, 0xd7, 0x71, 0x78, 0xaa, 0x26, 0x92,
-	0x00, 0x62, 0x83, 0x3b, 0x92, 0x32, 0xb8, 0x5c, 0x1d, 0x85, 0x04, 0x51, 0x12, 0x96, 0x99, 0x5d,
-	0x0a, 0xa2, 0xff, 0xbe, 0x04, 0x55, 0x95, 0x5e, 0xc6, 0x4f, 0xef, 0x15, 0x9c, 0xca, 0xed, 0x87,
-	0x0d, 0x17, 0xd3, 0x4c, 0x3c, 0xa3, 0x8c, 0x1e, 0x51, 0xea, 0x91, 0xb9, 0xaf, 0x1a, 0x19, 0x58,
-	0x3c, 0xdb, 0xd1, 0xac, 0x7b, 0xc8, 0xb7, 0xfb, 0xea, 0xff, 0x31, 0x0e, 0x8b, 0x85, 0xdd, 0x3d,
-	0xe8, 0x1b, 0xb8, 0x21, 0x4d, 0x65, 0xd2, 0x4e, 0xb4, 0xd5, 0x55, 0xed, 0x6c, 0x43, 0x84, 0xe4,
-	0xfd, 0x99, 0xd1, 0xb7, 0x30, 0xef, 0x92, 0x0e, 0x51, 0x03, 0xc6, 0x15, 0xc5, 0xca, 0xc5, 0x1e,
-	0x3e, 0x8a, 0x64, 0x88, 0xa7, 0x1a, 0xe7, 0x0c, 0x77, 0x83, 0x9c, 0xec, 0xe9, 0x8b, 0x3e, 0xd5,
-	0x14, 0x08, 0x41, 0x07, 0x30, 0xef, 0x93, 0x33, 0x9f, 0x86, 0x64, 0xd3, 0xf3, 0xbe, 0x6a, 0x36,
-	0xeb, 0x75, 0x9f, 0x1d, 0x13, 0x61, 0xbc, 0xcf, 0xdf, 0x8b, 0x22, 0x36, 0x64, 0xc0, 0xbc, 0x7c,
-	0x56, 0x21, 0x99, 0x6a, 0xcf, 0xb0, 0xbd, 0x67, 0x45, 0xcc, 0x3c, 0xd6, 0x64, 0xc7, 0x99, 0x85,
-	0x0f, 0x5b, 0x44, 0xcc, 0xf1, 0xc9, 0xaa, 0x85, 0x7a, 0xf4, 0x79, 0x61, 0x1c, 0x08, 0xcb, 0x2c,
-	0xaa, 0x16, 0x09, 0x8c, 0xdb, 0xb5, 0x50, 0xbd, 0x07, 0x45, 0x2d, 0xd0, 0x43, 0xd8, 0xb5, 0x98,
-	0x05, 0xdd, 0x8b, 0x1b, 0x28, 0x9b, 0xb1, 0x18, 0x4d, 0x76, 0x15, 0xe6, 0xe1, 0xe8, 0x19, 0xa0,
-	0x76, 0x40, 0x0e, 0x88, 0x8d, 0xcd, 0x6e, 0x34, 0xc9, 0x60, 0x98, 0xb7, 0x92, 0x5e, 0x2e, 0xfd,
-	0xcf, 0x47, 0x60, 0x3a, 0xdd, 0x1f, 0x85, 0x1e, 0xc3, 0x24, 0xcf, 0x8c, 0x2d, 0x66, 0xf7, 0x76,
-	0x18, 0x4b, 0xc2, 0x1d, 0x89, 0x8e, 0x1a, 0x0a, 0x15, 0x35, 0xfa, 0x8c, 0x5b, 0x76, 0xfb, 0x34,
-	0x0c, 0x42, 0xe2, 0xa9, 0x3b, 0x71, 0x3b, 0xcf, 0x7a, 0xc0, 0x09, 0x1a, 0x21, 0xf1, 0xa2, 0x8e,
-	0xb0, 0x98, 0x03, 0x6d, 0xc0, 0xc4, 0x0f, 0xd4, 0x7b, 0x4d, 0xa3, 0x8e, 0xdc, 0x95, 0x3c, 0xef,
-	0x77, 0x02, 0x1b, 0x75, 0x4c, 0x49, 0x5a, 0xb4, 0x9d, 0x2d, 0x3f, 0x8c, 0xe5, 0x7f, 0x12, 0x24,
-	0x59, 0x1b, 0x09, 0x49, 0x41, 0xe5, 0x41, 0x7f, 0x00, 0xf3, 0x05, 0x2b, 0x43, 0x1a, 0x4c, 0x62,
-	0xd5, 0xb8, 0x24, 0x0d, 0x60, 0xf4, 0xa9, 0x37, 0x60, 0xb1, 0x70, 0x3d, 0xfd, 0x59, 0xd0, 0x2a,
-	0x54, 0x64, 0x49, 0xa2, 0x29, 0x2c, 0xb4, 0x7a, 0x31, 0x4b, 0x81, 0xf4, 0x35, 0x40, 0xbd, 0x0b,
-	0x3d, 0x67, 0x12, 0xff, 0x59, 0x82, 0xeb, 0x7d, 0x96, 0x87, 0x1e, 0xc2, 0xb8, 0x45, 0x8e, 0xdb,
-	0xf6, 0x10, 0x41, 0xbe, 0x24, 0x44, 0x1b, 0xb0, 0xd8, 0xc2, 0x6f, 0x8e, 0xda, 0xad, 0x63, 0xe2,
-	0x3f, 0x3f, 0xd9, 0x0c, 0x43, 0x9f, 0x1e, 0xb7, 0xb9, 0x12, 0x4a, 0x83, 0x5a, 0x8c, 0xe4, 0x81,
-	0x4f, 0x1a, 0x91, 0xba, 0xba, 0xf2, 0x6d, 0xa9, 0x0f, 0x16, 0x3d, 0x05, 0x2d, 0x85, 0x39, 0x24,
-	0x41, 0x80, 0xed, 0xe8, 0x47, 0x87, 0xf2, 0xc5, 0xa9, 0x2f, 0x5e, 0xff, 0x43, 0x09, 0x60, 0x0b,
-	0x07, 0x91, 0x13, 0x79, 0x06, 0x48, 0x45, 0xb1, 0xc6, 0x4e, 0x72, 0x75, 0x06, 0xaf, 0xbb, 0x80,
-	0x8b, 0xc7, 0xe5, 0x9d, 0xb8, 0xcb, 0x9b, 0xdf, 0x74, 0x79, 0x4c, 0x59, 0x20, 0xaa, 0xc3, 0xa2,
-	0xe4, 0x15, 0x7d, 0x64, 0x72, 0x1a, 0xdb, 0xc6, 0x4e, 0x30, 0x44, 0x26, 0x5e, 0xcc, 0xa8, 0x3f,
-	0x01, 0x24, 0x7b, 0xd2, 0x0c, 0xd1, 0x43, 0xa8, 0x56, 0x96, 0x37, 0x3b, 0xa5, 0x5e, 0xb3, 0xa3,
-	0xff, 0xe5, 0x38, 0x4c, 0x08, 0xd1, 0x01, 0x7a, 0x0f, 0x46, 0x4d, 0x97, 0xaa, 0x9b, 0x37, 0x9f,
-	0xf9, 0xb5, 0x6b, 0xd4, 0xf0, 0x67, 0xba, 0x14, 0x6d, 0xc0, 0x94, 0x2a, 0xb7, 0x44, 0xc1, 0x4a,
-	0xea, 0x97, 0xb1, 0xd9, 0x5f, 0x1e, 0x18, 0x31, 0x25, 0x5a, 0x83, 0x09, 0xf9, 0x68, 0xab, 0xb2,
-	0xfe, 0xa5, 0x7c, 0x97, 0x71, 0x74, 0x2f, 0x25, 0x95, 0xe8, 0x8a, 0xe1, 0x89, 0x9e, 0xea, 0xdd,
-	0x5a, 0x2c, 0x2c, 0xb2, 0x1b, 0x92, 0x06, 0x3d, 0xe6, 0xb6, 0x53, 0xa5, 0xaf, 0xca, 0x76, 0xde,
-	0x28, 0x48, 0xaa, 0x23, 0x9b, 0x11, 0xd3, 0xa2, 0xaf, 0x61, 0x29, 0xc8, 0xfa, 0x6b, 0xd5, 0xf8,
-	0xaa, 0x1e, 0xcd, 0x52, 0xf6, 0xa7, 0xd0, 0xaf, 0x1b, 0x7d, 0xd8, 0xd1, 0x43, 0x28, 0xcb, 0x1f,
-	0x3b, 0xf0, 0x1d, 0x9d, 0xef, 0xbf, 0xa3, 0x53, 0x82, 0x6a, 0xdb, 0xa5, 0x99, 0x3e, 0xca, 0xc5,
-	0x5c, 0x1f, 0xe5, 0x0a, 0x94, 0xd9, 0x59, 0xf4, 0x33, 0x55, 0xe9, 0x3c, 0x12, 0x00, 0x7a, 0x0c,
-	0xd0, 0x22, 0x81, 0xfa, 0x79, 0x83, 0x0a, 0x89, 0xfb, 0xd6, 0xf6, 0x53, 0xa4, 0xe8, 0x2e, 0x8c,
-	0x1d, 0xe3, 0x80, 0xa8, 0x78, 0x37, 0xf5, 0x6b, 0x89, 0xe4, 0x76, 0x18, 0x82, 0x02, 0x7d, 0x01,
-	0xd3, 0x34, 0xa5, 0x5f, 0xaa, 0xad, 0x62, 0x25, 0xdf, 0x8d, 0x9a, 0xd6, 0x3e, 0x23, 0xc3, 0xc1,
-	0x75, 0x31, 0x5a, 0x4e, 0x13, 0xdb, 0x81, 0xf6, 0xbe, 0x70, 0x4d, 0x19, 0x98, 0xae, 0xc1, 0x52,
-	0xb1, 0x9f, 0xd3, 0x6f, 0xc3, 0x5b, 0xe7, 0xc6, 0x18, 0xfa, 0x12, 0x2c, 0x14, 0x3d, 0x9e, 0xe9,
-	0x73, 0x30, 0x9b, 0x7b, 0x1e, 0xd1, 0x7f, 0x0b, 0xd5, 0xcc, 0xaf, 0xaf, 0x7e, 0xe2, 0x36, 0x89,
-	0x59, 0xa8, 0x66, 0x76, 0xfc, 0xde, 0xb3, 0x3e, 0x2f, 0x21, 0x68, 0x16, 0x2a, 0x2f, 0x8e, 0x1a,
-	0xf5, 0xdd, 0xed, 0xfd, 0xbd, 0xfd, 0xdd, 0x9d, 0xda, 0x35, 0x54, 0x81, 0xc9, 0x9d, 0xdd, 0xbd,
-	0xcd, 0x17, 0x07, 0xcd, 0x5a, 0x09, 0x01, 0x4c, 0x34, 0x9a, 0xc6, 0xfe, 0x76, 0xb3, 0x36, 0x82,
-	0x26, 0x61, 0xf4, 0xf9, 0xde, 0x5e, 0x6d, 0xf4, 0xde, 0xcb, 0x28, 0xb5, 0xe2, 0x68, 0xe9, 0xc1,
-	0x6a, 0xd7, 0x50, 0x35, 0xe5, 0x38, 0x6b, 0x25, 0x2e, 0x46, 0xb9, 0xd4, 0xda, 0x08, 0x1f, 0x24,
-	0xe5, 0xa9, 0x6a, 0xa3, 0x68, 0x1e, 0x66, 0x99, 0x47, 0xdc, 0x6d, 0xe2, 0x06, 0xed, 0x60, 0xd3,
-	0x26, 0x6e, 0x58, 0x1b, 0xdb, 0x5a, 0xfa, 0xa7, 0x1f, 0x6f, 0x5d, 0xfb, 0xc3, 0x8f, 0xb7, 0xae,
-	0xfd, 0xeb, 0x8f, 0xb7, 0xae, 0x7d, 0x17, 0xff, 0xc7, 0x10, 0xc7, 0x13, 0x62, 0x07, 0x3e, 0xfe,
-	0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0x98, 0xa0, 0x41, 0x87, 0x57, 0x42, 0x00, 0x00,
+	// 4623 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x3c, 0x49, 0x73, 0x1b, 0x57,
+	0x7a, 0x02, 0x77, 0x7c, 0x20, 0x48, 0xf0, 0x71, 0xd1, 0x13, 0x45, 0x4b, 0x74, 0x8f, 0x2c, 0xcb,
+	0x96, 0x87, 0x92, 0x69, 0x8e, 0x2c, 0x6b, 0x6c, 0xc7, 0x5c, 0x6d, 0x7a, 0x48, 0x0a, 0x69, 0x50,
+	0xf2, 0x32, 0x99, 0x51, 0x1e, 0xbb, 0x1f, 0xc1, 0x67, 0x35, 0xfa, 0x75, 0xba, 0x1b, 0x90, 0xe0,
+	0x5b, 0x4e, 0xa9, 0xa4, 0x2a, 0x97, 0xfc, 0x80, 0xcc, 0x31, 0x3f, 0x21, 0x3f, 0x20, 0x97, 0x1c,
+	0x5d, 0xa9, 0xca, 0x3d, 0xe5, 0x53, 0x72, 0xcc, 0x21, 0x35, 0x87, 0x5c, 0x52, 0x6f, 0xe9, 0x15,
+	0x0d, 0x02, 0x24, 0xed, 0x4a, 0x2a, 0x27, 0xa2, 0xbf, 0xed, 0x6d, 0x5f, 0x7f, 0xdb, 0xfb, 0x9a,
+	0xf0, 0xae, 0xf7, 0xb2, 0xf9, 0x80, 0x78, 0x2c, 0x78, 0xc0, 0x82, 0x90, 0xf1, 0x07, 0x9d, 0xf7,
+	0x89, 0xe3, 0x9d, 0x91, 0xf7, 0x1f, 0x74, 0x88, 0xd3, 0xa6, 0xc1, 0x8b, 0xb0, 0xeb, 0xd1, 0x60,
+	0xcd, 0xf3, 0x79, 0xc8, 0xd1, 0x54, 0x84, 0x5c, 0xbe, 0xd5, 0xe4, 0xbc, 0xe9, 0xd0, 0x07, 0x12,
+	0x7e, 0xd2, 0x3e, 0x7d, 0x60, 0xb7, 0x7d, 0x12, 0x32, 0xee, 0x2a, 0xca, 0xe5, 0xcf, 0x9a, 0x2c,
+	0x3c, 0x6b, 0x9f, 0xac, 0x59, 0xbc, 0xf5, 0xa0, 0xc9, 0x9b, 0x3c, 0x21, 0x8c, 0x7f, 0xe4, 0x25,
+	0xbc, 0xf2, 0x89, 0xe7, 0x51, 0x5f, 0x8f, 0xb5, 0xbc, 0x20, 0xd8, 0xe4, 0x4f, 0x29, 0x40, 0x41,
+	0x0d, 0x13, 0x60, 0xd3, 0xb7, 0xce, 0xb6, 0xb9, 0x7b, 0xca, 0x9a, 0x68, 0x01, 0xc6, 0x49, 0xcb,
+	0x7e, 0xb4, 0x81, 0x4b, 0xab, 0xa5, 0x7b, 0x55, 0x53, 0x3d, 0x20, 0x0c, 0x93, 0x9e, 0x67, 0x3d,
+	0xda, 0x70, 0x28, 0x1e, 0x91, 0xf0, 0xe8, 0x51, 0xd0, 0x07, 0x1f, 0x7c, 0xf4, 0xf0, 0x35, 0x1e,
+	0x55, 0xf4, 0xf2, 0xc1, 0xf8, 0xe3, 0x18, 0x94, 0xb7, 0x8f, 0xf6, 0xb5, 0xcc, 0x0d, 0x98, 0xa4,
+	0x2e, 0x39, 0x71, 0xa8, 0x2d, 0xa5, 0x56, 0xd6, 0x97, 0xd7, 0xd4, 0x4c, 0xd7, 0xa2, 0x99, 0xae,
+	0x6d, 0x71, 0xee, 0x3c, 0x17, 0xbb, 0x63, 0x46, 0xa4, 0xa8, 0x06, 0xa3, 0x67, 0xed, 0x13, 0x39,
+	0x5e, 0xd9, 0x14, 0x3f, 0xd1, 0x3b, 0x30, 0x1a, 0x92, 0xa6, 0x1c, 0xa9, 0xb2, 0x7e, 0x7d, 0x2d,
+	0xda, 0xb9, 0xb5, 0xe3, 0xae, 0x47, 0xf7, 0xdd, 0x90, 0xfa, 0xa7, 0xc4, 0xa2, 0xa6, 0xa0, 0x11,
+	0xd3, 0x62, 0x2d, 0xd2, 0xa4, 0x78, 0x4c, 0xb2, 0xab, 0x07, 0x74, 0x0b, 0xc0, 0x6b, 0x3b, 0x4e,
+	0x9d, 0x3b, 0xcc, 0xea, 0xe2, 0x71, 0x89, 0x4a, 0x41, 0xd0, 0x0a, 0x94, 0x2d, 0x97, 0x6d, 0x31,
+	0x77, 0x87, 0xf9, 0x78, 0x42, 0xa2, 0x13, 0x80, 0xe0, 0xb6, 0x5c, 0x26, 0xd6, 0x24, 0xd0, 0x93,
+	0x8a, 0x3b, 0x81, 0xa0, 0x7b, 0x30, 0xab, 0x9f, 0xf6, 0x98, 0x43, 0x8f, 0x48, 0x8b, 0xe2, 0x29,
+	0x49, 0x94, 0x07, 0xa3, 0xf7, 0x60, 0x8e, 0xbe, 0xb6, 0x9c, 0xb6, 0x2d, 0x1f, 0x03, 0x8f, 0x58,
+	0x34, 0xc0, 0xe5, 0xd5, 0xd1, 0x7b, 0x65, 0xb3, 0x17, 0x81, 0x0e, 0x60, 0xc6, 0xe3, 0xf6, 0xa6,
+	0xeb, 0xf2, 0x50, 0xea, 0x43, 0x80, 0x41, 0xee, 0xc0, 0x6a, 0x76, 0x07, 0x0e, 0x89, 0xd7, 0x08,
+	0x7d, 0xe6, 0x36, 0xe3, 0xad, 0xd8, 0x1a, 0xc1, 0x25, 0x33, 0xc7, 0x8b, 0xee, 0x41, 0xcd, 0x0b,
+	0xbc, 0x17, 0x96, 0xd3, 0x0e, 0x42, 0xea, 0xbf, 0xf0, 0xb9, 0x43, 0x71, 0x45, 0x4e, 0x73, 0xc6,
+	0x0b, 0xbc, 0x6d, 0x05, 0x36, 0xb9, 0x43, 0xd1, 0x32, 0x4c, 0x39, 0xbc, 0x79, 0x40, 0x3b, 0xd4,
+	0xc1, 0xd3, 0x92, 0x22, 0x7e, 0x46, 0xef, 0xc3, 0x84, 0x4f, 0x3d, 0xc2, 0x7c, 0x5c, 0x95, 0x73,
+	0xb9, 0x91, 0xcc, 0x65, 0xfb, 0x68, 0xdf, 0x94, 0x28, 0x75, 0xfa, 0xa6, 0x26, 0x14, 0x5a, 0x60,
+	0x9d, 0x11, 0xe6, 0x52, 0x1b, 0xcf, 0x0c, 0xd6, 0x02, 0x4d, 0x8a, 0xd6, 0x60, 0x3c, 0x24, 0xcc,
+	0x0d, 0xf1, 0xac, 0xe4, 0xc1, 0x99, 0x71, 0x8e, 0x05, 0x46, 0x0f, 0xa3, 0xc8, 0x8c, 0x3d, 0x98,
+	0xc9, 0x22, 0x2e, 0xa7, 0x7d, 0xc6, 0x0f, 0x63, 0x30, 0x9b, 0x5b, 0xc9, 0xff, 0x1d, 0x3d, 0x5e,
+	0x81, 0xb2, 0x43, 0x4e, 0xa8, 0x53, 0xe7, 0x76, 0x20, 0xd5, 0x78, 0xca, 0x4c, 0x00, 0xe8, 0x2e,
+	0x4c, 0x5b, 0x3e, 0x25, 0x21, 0xdd, 0xed, 0x50, 0x37, 0x0c, 0x94, 0x22, 0x4b, 0x5d, 0xc8, 0xc0,
+	0x85, 0x3e, 0xdb, 0xd4, 0xa1, 0x21, 0x95, 0x62, 0x26, 0xa5, 0x98, 0x14, 0x44, 0x68, 0xe9, 0x89,
+	0xcf, 0x5f, 0x52, 0xb7, 0xce, 0xed, 0x03, 0x21, 0xfd, 0x37, 0xb4, 0xab, 0x35, 0xba, 0x17, 0x81,
+	0x1e, 0xc2, 0x7c, 0x16, 0x28, 0xb7, 0x01, 0x97, 0x25, 0x7d, 0x11, 0x4a, 0xc8, 0x67, 0x2e, 0x13,
+	0xc7, 0x24, 0x8e, 0x8e, 0xfa, 0xf2, 0x8d, 0x01, 0x25, 0xbf, 0x07, 0x81, 0x36, 0x60, 0xb1, 0x45,
+	0x5e, 0xef, 0x88, 0xe9, 0x09, 0x3d, 0xae, 0x53, 0xff, 0x90, 0xb9, 0xed, 0x50, 0x29, 0x6f, 0xd5,
+	0x2c, 0x46, 0xa2, 0x5d, 0xa8, 0x9d, 0x10, 0xeb, 0x25, 0x3f, 0x3d, 0xdd, 0x22, 0x01, 0xdd, 0xa1,
+	0x0e, 0xe9, 0xe2, 0x69, 0xad, 0xb1, 0xf9, 0xb3, 0xdb, 0xd1, 0xf6, 0xd6, 0xec, 0x61, 0x41, 0xdb,
+	0x30, 0xab, 0x61, 0x87, 0x72, 0x18, 0xd2, 0xc5, 0xd5, 0x41, 0x52, 0xf2, 0x1c, 0xc6, 0xd7, 0xb0,
+	0xbc, 0x5d, 0x7f, 0x76, 0x4c, 0xfc, 0x26, 0x0d, 0x9f, 0x85, 0xcc, 0x61, 0xdf, 0x4b, 0x42, 0xad,
+	0x5c, 0x4f, 0x00, 0x87, 0x12, 0xb5, 0xd9, 0xa1, 0x3e, 0x69, 0xd2, 0x14, 0x85, 0xd4, 0xb6, 0x71,
+	0xb3, 0x2f, 0xde, 0xf8, 0xef, 0x12, 0x94, 0x4d, 0x1a, 0xf0, 0xb6, 0x2f, 0xec, 0xc5, 0x87, 0x30,
+	0xe1, 0xb0, 0x16, 0x0b, 0x03, 0x5c, 0x5a, 0x1d, 0xbd, 0x57, 0x59, 0xbf, 0x9d, 0x68, 0x58, 0x4c,
+	0xb4, 0x76, 0x20, 0x29, 0x76, 0xdd, 0xd0, 0xef, 0x9a, 0x9a, 0x1c, 0x7d, 0x02, 0x53, 0x3e, 0xfd,
+	0x8b, 0x36, 0x0d, 0xc2, 0x00, 0x8f, 0x48, 0xd6, 0x37, 0x8b, 0x58, 0x4d, 0x4d, 0xa3, 0x98, 0x63,
+	0x96, 0xe5, 0x8f, 0xa0, 0x92, 0x92, 0x2a, 0xf4, 0xfe, 0x25, 0xed, 0xca, 0xb9, 0x97, 0x4d, 0xf1,
+	0x53, 0x28, 0xb3, 0xf4, 0x80, 0xfa, 0x5d, 0x50, 0x0f, 0x4f, 0x46, 0x1e, 0x97, 0x96, 0x7f, 0x0d,
+	0xd5, 0x8c, 0xd4, 0x8b, 0x30, 0x1b, 0x5f, 0xc3, 0xea, 0x0e, 0x3d, 0x25, 0x6d, 0x27, 0xac, 0x73,
+	0x7b, 0x87, 0x05, 0x7e, 0xdb, 0x13, 0xbb, 0xb2, 0xd5, 0xb6, 0x9b, 0xf4, 0x6a, 0x46, 0xe0, 0x2b,
+	0x58, 0xd2, 0x92, 0xe3, 0xd5, 0x6b, 0x79, 0xe9, 0xad, 0x52, 0x02, 0x8b, 0xb6, 0x2a, 0x5a, 0x93,
+	0x36, 0x51, 0x31, 0x8b, 0xf1, 0x87, 0x2a, 0xcc, 0xef, 0x36, 0x7d, 0x1a, 0x04, 0x9f, 0x93, 0x90,
+	0xbe, 0x22, 0x5d, 0x2d, 0x76, 0x0f, 0x6a, 0xa4, 0x1d, 0xf2, 0xc0, 0x22, 0x0e, 0xdd, 0x1d, 0x7a,
+	0xbe, 0x3d, 0x3c, 0xc8, 0x80, 0xe9, 0x18, 0x76, 0x48, 0x5e, 0x6b, 0xa7, 0x9d, 0x81, 0x65, 0x69,
+	0x98, 0xab, 0x1d, 0x78, 0x06, 0x86, 0x9e, 0xc0, 0xa8, 0xe5, 0xb5, 0xa5, 0x89, 0xa9, 0xac, 0xdf,
+	0x49, 0xd9, 0xde, 0xbe, 0x7a, 0x2c, 0xed, 0x8c, 0x60, 0x4a, 0x6f, 0xf9, 0xe4, 0xf0, 0xd6, 0x72,
+	0x1d, 0x46, 0xa9, 0xdb, 0xc1, 0x53, 0xc3, 0x79, 0x38, 0x53, 0x10, 0xa3, 0x4d, 0x98, 0x90, 0xd6,
+	0x4f, 0xf9, 0xd0, 0xca, 0xfa, 0x3b, 0x09, 0x5b, 0xc1, 0x26, 0xaf, 0x49, 0x13, 0x14, 0xab, 0xbe,
+	0x7c, 0x40, 0x08, 0xc6, 0x5c, 0x61, 0x7e, 0x6e, 0x48, 0xe5, 0x92, 0xbf, 0xd1, 0x17, 0x30, 0xed,
+	0x72, 0x9b, 0x36, 0xa8, 0x43, 0xad, 0x90, 0xfb, 0x17, 0xf2, 0xba, 0x19, 0xce, 0x02, 0x0f, 0x5e,
+	0xb9, 0x82, 0x07, 0xe7, 0xb0, 0x22, 0x21, 0x21, 0xdb, 0x3c, 0x3d, 0x15, 0x86, 0xb2, 0x2b, 0x57,
+	0x14, 0xcf, 0x53, 0xd9, 0xb7, 0xb7, 0xb3, 0xb2, 0x1b, 0x0e, 0xb3, 0xe8, 0xd3, 0xd3, 0x3e, 0x43,
+	0x9c, 0x2b, 0x10, 0xbd, 0x82, 0xd5, 0x1c, 0xfe, 0x98, 0xfa, 0xad, 0xec, 0xa0, 0xd5, 0x8b, 0x0f,
+	0x3a, 0x50, 0x28, 0xba, 0x0f, 0xe3, 0x1e, 0xf7, 0xc3, 0x00, 0xcf, 0xc8, 0x73, 0x5d, 0x4c, 0xa4,
+	0xd7, 0x05, 0x38, 0xf2, 0xfc, 0x92, 0x06, 0xfd, 0x0a, 0xca, 0x7e, 0xf4, 0xe2, 0xe9, 0x68, 0x61,
+	0xbe, 0xe0, 0x9d, 0x94, 0x43, 0x27, 0x94, 0xe8, 0x63, 0xa8, 0x06, 0xd4, 0xf2, 0x69, 0xf8, 0x9c,
+	0x3b, 0xed, 0x16, 0x0d, 0x70, 0x4d, 0x8e, 0xb5, 0x94, 0xb0, 0x36, 0x52, 0x68, 0x33, 0x4b, 0x8c,
+	0xea, 0x80, 0x02, 0xea, 0x77, 0x98, 0x45, 0xd3, 0xa7, 0x3b, 0x37, 0xa4, 0xf6, 0x16, 0xf0, 0x0a,
+	0x4d, 0x14, 0xf9, 0x01, 0x46, 0x4a, 0x13, 0xc5, 0x6f, 0x74, 0x1f, 0xc6, 0xbe, 0xef, 0x78, 0x2e,
+	0x9e, 0xcf, 0x47, 0x0c, 0xdf, 0x52, 0x9f, 0x3f, 0xaf, 0x1f, 0xe9, 0x8d, 0x90, 0x44, 0xe8, 0x10,
+	0x2a, 0x21, 0x77, 0xa8, 0xaf, 0xe7, 0xb2, 0x70, 0xf1, 0x83, 0x49, 0xf3, 0xa3, 0x03, 0x98, 0xf5,
+	0xb9, 0xe3, 0x30, 0xb7, 0x79, 0x48, 0x5e, 0x37, 0xda, 0x7e, 0x93, 0xe2, 0x45, 0x29, 0xf2, 0x56,
+	0x4f, 0xe0, 0xf2, 0xd4, 0x57, 0xd2, 0xf6, 0xb8, 0x5f, 0xdf, 0x92, 0x92, 0xf2, 0xac, 0xe8, 0x6b,
+	0x58, 0x4c, 0x40, 0xcf, 0x5c, 0xd2, 0x21, 0xcc, 0x11, 0x2f, 0x3e, 0x5e, 0x1a, 0x5a, 0x66, 0xb1,
+	0x00, 0x74, 0x08, 0x55, 0x4b, 0x6e, 0x43, 0x74, 0x8e, 0xd7, 0x2f, 0xb4, 0x70, 0x33, 0xcb, 0x8d,
+	0x7e, 0x0b, 0x0b, 0xc4, 0xb6, 0x99, 0xd8, 0x03, 0xe2, 0xc4, 0x91, 0x48, 0x80, 0xf1, 0xc5, 0xa4,
+	0x16, 0x0a, 0x41, 0x8f, 0xa1, 0xec, 0xb7, 0xdd, 0xcd, 0xc0, 0xe4, 0x3c, 0xc4, 0xcb, 0x03, 0x8d,
+	0x63, 0x42, 0xac, 0x62, 0xa6, 0xef, 0xa8, 0x25, 0x44, 0x1e, 0xd3, 0x96, 0xe7, 0x90, 0x90, 0xe2,
+	0x9b, 0x51, 0xcc, 0x94, 0x43, 0x48, 0x8f, 0x9c, 0x18, 0xbb, 0x0b, 0x39, 0xd5, 0xff, 0x28, 0xc1,
+	0x8c, 0x36, 0x9b, 0x91, 0xcf, 0x3b, 0x82, 0x79, 0x99, 0xcf, 0xbe, 0xa0, 0xd2, 0xa8, 0x36, 0x15,
+	0x56, 0xfb, 0xa7, 0x37, 0xce, 0xb5, 0xb9, 0x26, 0x92, 0x9c, 0xbb, 0x69, 0xc6, 0xb4, 0x83, 0x18,
+	0x19, 0xde, 0x41, 0xfc, 0x29, 0x2c, 0xa8, 0x59, 0x30, 0x37, 0x33, 0x8d, 0xb1, 0xbc, 0x02, 0xed,
+	0xbb, 0x05, 0xf3, 0x50, 0x2b, 0xd8, 0xcf, 0xb0, 0x1a, 0xff, 0x54, 0x83, 0xe9, 0xcf, 0x1d, 0x7e,
+	0x42, 0x1c, 0xbd, 0xd2, 0xf7, 0x60, 0x8c, 0xf8, 0xd6, 0x99, 0x5e, 0xda, 0x42, 0x22, 0x33, 0x49,
+	0x94, 0xa5, 0x2a, 0x4a, 0x2a, 0x11, 0xf9, 0x2a, 0xdd, 0x11, 0x27, 0x14, 0xe7, 0x6d, 0x78, 0x5d,
+	0x45, 0xbe, 0x05, 0x28, 0xe1, 0xe6, 0xb5, 0xb6, 0x11, 0x87, 0xd9, 0x2a, 0xc6, 0x1b, 0x1d, 0xec,
+	0xe6, 0xf3, 0x3c, 0xe8, 0x0b, 0xb8, 0x6d, 0xab, 0xf8, 0x44, 0x4d, 0xea, 0x39, 0x0b, 0xd8, 0x09,
+	0x73, 0x58, 0xd8, 0x6d, 0xd0, 0x30, 0x64, 0x6e, 0x33, 0xc0, 0x1b, 0x32, 0xab, 0x1c, 0x44, 0x86,
+	0x9e, 0xc3, 0xbc, 0x26, 0x39, 0x4a, 0xbb, 0xbc, 0x89, 0x0b, 0xb8, 0xa9, 0x22, 0x01, 0xc8, 0x85,
+	0x65, 0xbb, 0x6f, 0x6c, 0xa6, 0xe3, 0x82, 0x77, 0x13, 0xf1, 0x83, 0xe2, 0x38, 0x39, 0xd0, 0x39,
+	0x12, 0x51, 0x1d, 0x6a, 0x76, 0x2e, 0x62, 0xc3, 0xe5, 0xfc, 0x22, 0x8a, 0x63, 0x3a, 0x29, 0xbb,
+	0x87, 0x1b, 0xfd, 0x16, 0x90, 0x86, 0x1d, 0xa7, 0xac, 0xea, 0x87, 0x17, 0xb7, 0xaa, 0x05, 0x62,
+	0xa2, 0xdc, 0x70, 0x3a, 0xc9, 0x0d, 0xef, 0xc1, 0xac, 0xcc, 0xf1, 0xea, 0x49, 0x9d, 0xa2, 0xaa,
+	0x8a, 0x08, 0x39, 0x30, 0x7a, 0x17, 0x6a, 0x31, 0x48, 0xb9, 0xa8, 0x00, 0xbf, 0x25, 0x4f, 0xbb,
+	0x07, 0x8e, 0xee, 0xc2, 0x8c, 0x54, 0xfc, 0x44, 0x3b, 0x67, 0x54, 0xca, 0x9f, 0x85, 0x0a, 0xc3,
+	0xe4, 0xf0, 0xe6, 0x66, 0xf0, 0x65, 0xc0, 0x5d, 0x7c, 0x67, 0xb0, 0x61, 0x8a, 0x89, 0xd1, 0x87,
+	0x30, 0xe9, 0xf0, 0x66, 0x93, 0xb9, 0x4d, 0x3c, 0x97, 0x37, 0x08, 0xea, 0xdd, 0x3a, 0x50, 0x68,
+	0xfd, 0x22, 0x46, 0xd4, 0x68, 0x09, 0x26, 0x5a, 0x34, 0x38, 0xdb, 0xdf, 0xc1, 0xbf, 0x92, 0x53,
+	0xd2, 0x4f, 0x68, 0x07, 0xa6, 0xc5, 0xaf, 0x23, 0x1a, 0xbe, 0xe2, 0xfe, 0xcb, 0x00, 0xcf, 0xe7,
+	0x4f, 0xb1, 0x8f, 0x4f, 0xcd, 0x70, 0xa1, 0xcf, 0x60, 0xba, 0xd5, 0x76, 0x42, 0xa6, 0xeb, 0x1a,
+	0xda, 0xcd, 0xac, 0x24, 0x52, 0x0e, 0x53, 0x58, 0x3d, 0xb5, 0x0c, 0x87, 0x28, 0x7d, 0xb9, 0x4a,
+	0x1a, 0x7e, 0x5b, 0x4e, 0x30, 0x7a, 0x44, 0x8f, 0x60, 0xc9, 0xe3, 0xf6, 0xce, 0x51, 0xa3, 0x41,
+	0x85, 0x1d, 0x48, 0x95, 0x72, 0xee, 0xcb, 0x63, 0xe8, 0x83, 0x45, 0xbf, 0x87, 0x15, 0xde, 0x62,
+	0x61, 0x83, 0xd9, 0xd4, 0x22, 0xfe, 0xbe, 0xb4, 0xda, 0x5c, 0x0f, 0x7e, 0x48, 0x3c, 0x7c, 0x77,
+	0xe0, 0xbe, 0x9f, 0xcb, 0x8f, 0x3e, 0x85, 0x69, 0xee, 0x26, 0x05, 0x24, 0x7c, 0x7d, 0xa0, 0xbc,
+	0x0c, 0x3d, 0x32, 0x61, 0x89, 0x7b, 0x42, 0x45, 0xb9, 0x7f, 0x48, 0x5c, 0xd2, 0xa4, 0x5f, 0xd1,
+	0x93, 0x33, 0xce, 0x5f, 0x06, 0xf8, 0x9d, 0x81, 0x92, 0xfa, 0x70, 0xa2, 0x87, 0x30, 0xe7, 0xf9,
+	0x8c, 0xfb, 0x2c, 0xec, 0x6e, 0x3b, 0x24, 0x08, 0x64, 0xae, 0x7f, 0x33, 0x2e, 0x4c, 0xf4, 0x22,
+	0x65, 0xec, 0xe7, 0xf3, 0xd7, 0x5d, 0xbc, 0xb2, 0x5a, 0xca, 0xc5, 0x7e, 0x02, 0x1c, 0xc7, 0x7e,
+	0xe2, 0x01, 0x7d, 0x08, 0x65, 0xf9, 0x63, 0xdf, 0x65, 0x21, 0x7e, 0x23, 0x5f, 0x91, 0xaa, 0x47,
+	0x28, 0xcd, 0x94, 0xd0, 0xa2, 0xb7, 0x60, 0x34, 0xb0, 0x03, 0x7c, 0x2b, 0x1f, 0x2e, 0x36, 0x76,
+	0x1a, 0x9a, 0x58, 0xe0, 0xa3, 0x8a, 0xcd, 0xed, 0x21, 0x2a, 0x36, 0x6b, 0x30, 0x11, 0xfa, 0xc4,
+	0xa2, 0x3e, 0x7e, 0x73, 0xb5, 0x94, 0x0d, 0x24, 0x8f, 0x25, 0x3c, 0x2a, 0x8b, 0x29, 0x2a, 0xb4,
+	0x0e, 0x13, 0xed, 0x80, 0x1e, 0x6e, 0xd7, 0xf1, 0x2f, 0x06, 0xee, 0xae, 0xa6, 0x44, 0x6b, 0x80,
+	0x7c, 0xda, 0xe2, 0x21, 0xad, 0x33, 0x87, 0x87, 0x9b, 0xb6, 0x2d, 0xbc, 0x19, 0x7e, 0x28, 0xd5,
+	0xb3, 0x00, 0x23, 0xe6, 0x24, 0x5f, 0x74, 0x1b, 0x3f, 0xca, 0xcf, 0x69, 0x5f, 0xc2, 0xa3, 0x39,
+	0x29, 0x2a, 0x11, 0x65, 0x78, 0x82, 0x7f, 0x9b, 0xfa, 0x61, 0xdd, 0xe7, 0x1d, 0x66, 0x53, 0x1f,
+	0x3f, 0x56, 0x51, 0x46, 0x0f, 0x42, 0x54, 0xa3, 0xbe, 0x7b, 0x15, 0x6a, 0x63, 0xf5, 0x91, 0xa4,
+	0x4a, 0x00, 0x72, 0x87, 0xc3, 0x00, 0x3f, 0xe9, 0xd9, 0xe1, 0xe3, 0x64, 0x87, 0xc3, 0x40, 0x14,
+	0x1b, 0x7d, 0xda, 0x61, 0x81, 0x70, 0x85, 0xbf, 0x56, 0xc5, 0xc6, 0xe8, 0x19, 0x6d, 0xc1, 0x4c,
+	0x8b, 0xb7, 0xdd, 0xf0, 0x30, 0x74, 0x02, 0x31, 0x72, 0x80, 0x3f, 0x1e, 0xb8, 0x55, 0x39, 0x0e,
+	0x31, 0x49, 0x8b, 0x44, 0x3b, 0xf5, 0x89, 0x9a, 0x64, 0x0c, 0x10, 0x23, 0xd0, 0xd7, 0x21, 0xf5,
+	0x5d, 0xe2, 0xa8, 0x0d, 0xc1, 0x9f, 0x0e, 0x1e, 0x21, 0xcb, 0x61, 0xfc, 0x12, 0xca, 0xf1, 0x9a,
+	0xd0, 0x2a, 0x54, 0x74, 0x6c, 0x2f, 0x32, 0x15, 0x5d, 0x4c, 0x4f, 0x83, 0x0c, 0x13, 0xa6, 0xd3,
+	0x7b, 0x2f, 0xa7, 0x20, 0x43, 0x9c, 0x4d, 0x97, 0x38, 0xdd, 0x80, 0x05, 0x43, 0x04, 0x45, 0x39,
+	0x0e, 0xe3, 0x3e, 0xcc, 0x17, 0xd8, 0x5a, 0x11, 0xe5, 0x39, 0xb2, 0x8a, 0xab, 0x22, 0x3f, 0xf5,
+	0x60, 0xfc, 0x4d, 0x0d, 0x16, 0x8a, 0x62, 0xa4, 0xff, 0x57, 0x45, 0x88, 0xcf, 0xa0, 0x6a, 0xb5,
+	0x83, 0x90, 0xb7, 0x1a, 0x6a, 0xeb, 0xf1, 0xc4, 0xc0, 0x85, 0x64, 0x19, 0xd2, 0x51, 0x2a, 0x5c,
+	0xb8, 0x8c, 0x51, 0xb9, 0x48, 0x19, 0x63, 0x2b, 0x2e, 0x63, 0xcc, 0xae, 0x8e, 0x66, 0xe3, 0xa2,
+	0x7d, 0x77, 0xc8, 0x3a, 0xc6, 0x5d, 0x98, 0x71, 0x38, 0xb1, 0xb7, 0x88, 0x43, 0x5c, 0x8b, 0xfa,
+	0xfb, 0x75, 0x5c, 0x53, 0x8e, 0x3e, 0x0b, 0x15, 0xd5, 0xc6, 0x34, 0xa4, 0x21, 0x83, 0x1d, 0x93,
+	0xb8, 0x4d, 0x2a, 0xb2, 0x57, 0xe1, 0xbd, 0xfa, 0xe2, 0xe3, 0x5a, 0xc9, 0x7b, 0xe7, 0xd4, 0x4a,
+	0xe6, 0x7f, 0xc2, 0x5a, 0xc9, 0xc2, 0xcf, 0x58, 0x2b, 0x59, 0xfc, 0xdf, 0xa8, 0x95, 0x2c, 0xfd,
+	0xac, 0xb5, 0x92, 0xeb, 0x43, 0xd4, 0x4a, 0xee, 0xc2, 0xb4, 0x4f, 0x3d, 0x87, 0x59, 0x64, 0x5b,
+	0x98, 0x49, 0x99, 0xd5, 0x56, 0xd5, 0x61, 0xa4, 0xe1, 0x68, 0x2b, 0x5d, 0x53, 0xb9, 0x71, 0x81,
+	0x73, 0x38, 0xaf, 0xc0, 0x72, 0xf3, 0xea, 0x05, 0x96, 0x95, 0x9f, 0xa0, 0xc0, 0xf2, 0x46, 0xaa,
+	0xc0, 0xf2, 0x48, 0x17, 0x58, 0x54, 0x1c, 0x60, 0xf4, 0x7b, 0xf1, 0xbe, 0xed, 0x78, 0x6e, 0xa6,
+	0xd6, 0x52, 0x50, 0x1c, 0xb9, 0xfd, 0x33, 0x14, 0x47, 0x56, 0xaf, 0x5a, 0x1c, 0xd9, 0x80, 0xc5,
+	0xc8, 0x5b, 0x1d, 0xfb, 0xe4, 0xf4, 0x94, 0x59, 0xda, 0x5d, 0x1b, 0x72, 0x13, 0x8a, 0x91, 0xf9,
+	0x4a, 0xd2, 0x2f, 0xae, 0x58, 0x49, 0xfa, 0x0d, 0x4c, 0xeb, 0x9c, 0x5d, 0x6a, 0x24, 0xbe, 0x73,
+	0x21, 0x79, 0x66, 0x86, 0xb9, 0x6f, 0x7d, 0xe6, 0xad, 0x9f, 0xa2, 0x3e, 0xd3, 0x53, 0x4b, 0xba,
+	0x7b, 0xa5, 0x5a, 0x52, 0xa6, 0xdc, 0xf3, 0xcb, 0x2b, 0x97, 0x7b, 0xd6, 0x7e, 0x86, 0x72, 0xcf,
+	0x19, 0xe0, 0x7e, 0xaa, 0x7e, 0xc9, 0x6b, 0xcf, 0x25, 0x98, 0x08, 0xda, 0xa7, 0xa7, 0xec, 0xb5,
+	0x1e, 0x4c, 0x3f, 0x19, 0xff, 0x5e, 0x02, 0xd4, 0x9b, 0x74, 0x5d, 0x72, 0x90, 0x55, 0xa8, 0xe8,
+	0x8b, 0x6c, 0x99, 0x50, 0xa8, 0x91, 0xd2, 0x20, 0x11, 0x2a, 0x37, 0x65, 0x48, 0xb4, 0xc3, 0x5b,
+	0x84, 0xb9, 0x0d, 0x35, 0xa5, 0x51, 0x49, 0x58, 0x80, 0x41, 0x5f, 0x02, 0x62, 0xae, 0xbc, 0x81,
+	0xdf, 0x75, 0x3b, 0xbc, 0xbb, 0xc7, 0x1c, 0x91, 0x36, 0x8e, 0x0d, 0x9c, 0x52, 0x01, 0x97, 0xf1,
+	0x57, 0x25, 0xb8, 0xf9, 0xb4, 0x1d, 0x9e, 0xf0, 0xb6, 0x6b, 0x67, 0xde, 0x2c, 0xbd, 0xe6, 0x4f,
+	0x61, 0xac, 0xc5, 0x6d, 0x35, 0xed, 0x99, 0xb4, 0xbb, 0x3f, 0x87, 0x69, 0xed, 0x90, 0xdb, 0xd4,
+	0x94, 0x7c, 0xc6, 0x3d, 0x18, 0x13, 0x4f, 0xa8, 0x0a, 0xe5, 0xcd, 0x83, 0x83, 0xa7, 0x5f, 0xbd,
+	0xd8, 0x3c, 0xfa, 0xa6, 0x76, 0x0d, 0xcd, 0x41, 0xd5, 0xdc, 0xfd, 0x7c, 0xbf, 0x71, 0x6c, 0x7e,
+	0xf3, 0xe2, 0xe9, 0xd1, 0xc1, 0x37, 0xb5, 0x92, 0xf1, 0xc7, 0x69, 0xa8, 0xc8, 0x8c, 0xe0, 0x4a,
+	0xbb, 0x5d, 0x14, 0x18, 0x8e, 0x5c, 0x35, 0x30, 0xec, 0x13, 0xf4, 0xe5, 0x83, 0xc7, 0xb1, 0x82,
+	0xe0, 0x31, 0xef, 0xc5, 0xc6, 0xfb, 0x78, 0xb1, 0xf8, 0x12, 0x7d, 0x22, 0x7d, 0x89, 0x7e, 0x07,
+	0xaa, 0x32, 0x05, 0x6b, 0x90, 0x96, 0x27, 0x4c, 0xa6, 0xbc, 0x73, 0x2a, 0x99, 0x59, 0x60, 0xf6,
+	0x56, 0xa1, 0x3c, 0xf4, 0xad, 0x82, 0xe8, 0x05, 0x91, 0x5b, 0x9d, 0xa4, 0xe1, 0xa0, 0x7b, 0x41,
+	0xb2, 0xe0, 0x28, 0xba, 0xad, 0x5c, 0x26, 0xba, 0xcd, 0x47, 0x5d, 0xd3, 0x97, 0x8e, 0xba, 0x2c,
+	0xb8, 0xfd, 0x92, 0x52, 0x8f, 0x38, 0xac, 0x23, 0xb6, 0x56, 0x04, 0xbf, 0xf2, 0xd5, 0x74, 0x95,
+	0x89, 0xd9, 0x6c, 0xd2, 0xc1, 0x17, 0xde, 0x83, 0x24, 0xa0, 0x03, 0x51, 0x9c, 0xf3, 0x1c, 0xde,
+	0x6d, 0x51, 0x37, 0x54, 0x96, 0x0a, 0xcf, 0x0c, 0x37, 0x65, 0xb3, 0x87, 0x53, 0x58, 0x55, 0x2b,
+	0xae, 0x99, 0xa0, 0xc1, 0x56, 0x35, 0x26, 0x4e, 0xa5, 0xdc, 0x0b, 0x43, 0xa7, 0xdc, 0x3a, 0xa0,
+	0x5f, 0xbc, 0x48, 0x40, 0x5f, 0x10, 0x1d, 0xe0, 0x9f, 0x21, 0x3a, 0xb8, 0x71, 0xf5, 0xab, 0x93,
+	0x8c, 0x9f, 0x5f, 0xbe, 0xa2, 0x9f, 0x3f, 0x83, 0x37, 0x95, 0xc5, 0xa8, 0x8b, 0xed, 0xb4, 0xb8,
+	0xd3, 0x70, 0xd9, 0xe9, 0xa9, 0x9a, 0x48, 0x64, 0xd9, 0xf0, 0xca, 0xc0, 0x9d, 0x1f, 0x2c, 0x04,
+	0x9d, 0xc2, 0x6a, 0x5f, 0xa2, 0x7d, 0x57, 0x0d, 0xf4, 0xc6, 0xc0, 0x81, 0x06, 0xca, 0x28, 0xc8,
+	0x49, 0x6e, 0x5d, 0x21, 0x27, 0xf9, 0x13, 0x98, 0x56, 0xba, 0xa8, 0xb2, 0x2a, 0x1d, 0x31, 0xde,
+	0x4c, 0x05, 0xec, 0x89, 0xa5, 0x56, 0x24, 0x66, 0x86, 0x01, 0x3d, 0x86, 0xeb, 0xdf, 0xbd, 0x7a,
+	0x19, 0x08, 0xe3, 0xe3, 0x74, 0xa8, 0xbf, 0xfb, 0x3a, 0xf4, 0x89, 0x08, 0x17, 0xb6, 0x37, 0x65,
+	0xa4, 0x58, 0x36, 0xfb, 0xa1, 0xd1, 0x07, 0x30, 0xe9, 0x39, 0xed, 0x26, 0x73, 0x03, 0xfc, 0x66,
+	0xbe, 0x4a, 0x16, 0x9f, 0xb2, 0x5a, 0x83, 0x19, 0x51, 0x46, 0x45, 0x6a, 0xa3, 0xa7, 0x81, 0xe9,
+	0x17, 0x83, 0xcb, 0x61, 0xc6, 0x3f, 0x96, 0x00, 0xc9, 0xf5, 0xe8, 0xf0, 0x42, 0x3b, 0x20, 0x51,
+	0x90, 0x56, 0x80, 0x28, 0x31, 0x2f, 0xe9, 0x82, 0x74, 0x06, 0x8a, 0x9e, 0xc1, 0x22, 0x8b, 0x19,
+	0x43, 0xa1, 0xbe, 0xd4, 0x3f, 0x4c, 0x7c, 0x66, 0xaa, 0xb5, 0xa5, 0x90, 0xcc, 0x2c, 0xe6, 0x16,
+	0xde, 0x25, 0x42, 0x38, 0x24, 0x08, 0x74, 0x3c, 0x90, 0x81, 0x19, 0xfb, 0x30, 0x27, 0x27, 0x9e,
+	0x71, 0xd9, 0x97, 0xeb, 0x23, 0x09, 0x61, 0xf6, 0x98, 0x3a, 0xb4, 0x45, 0x43, 0xff, 0x4a, 0x82,
+	0xd0, 0x7d, 0x18, 0xe9, 0xac, 0xe3, 0xd1, 0xbc, 0xc2, 0xc4, 0xc2, 0x9f, 0xaf, 0xeb, 0xf4, 0x64,
+	0xa4, 0xb3, 0x6e, 0xfc, 0xdd, 0x28, 0xcc, 0xf5, 0x60, 0x2e, 0x39, 0xf0, 0xd7, 0x30, 0xd7, 0xa2,
+	0x21, 0xb1, 0x49, 0x48, 0x5e, 0xd0, 0xd7, 0xd6, 0x19, 0x71, 0x75, 0x4f, 0x5a, 0x65, 0xfd, 0x7e,
+	0xe1, 0x3c, 0x0e, 0x35, 0xf5, 0xae, 0x26, 0xd6, 0xf3, 0xaa, 0xb5, 0x72, 0x70, 0xb4, 0x0b, 0xe0,
+	0xf9, 0xbc, 0x45, 0xc3, 0x33, 0xda, 0x8e, 0x6a, 0x5e, 0x6f, 0x15, 0x8a, 0xac, 0xc7, 0x64, 0x5a,
+	0x58, 0x8a, 0x11, 0x7d, 0x01, 0x95, 0x20, 0x24, 0xd6, 0x4b, 0xdb, 0x67, 0x1d, 0xea, 0xeb, 0x2d,
+	0xba, 0x5b, 0x28, 0xa7, 0x21, 0xe8, 0x76, 0x24, 0x9d, 0x16, 0x94, 0x66, 0x45, 0x7f, 0x06, 0x73,
+	0xc4, 0xb2, 0x68, 0x10, 0xbc, 0x70, 0x78, 0xf3, 0x85, 0x97, 0xf4, 0x8a, 0x56, 0xd6, 0x1f, 0x16,
+	0xca, 0xdb, 0x94, 0xd4, 0x07, 0xbc, 0xa9, 0x34, 0x45, 0x05, 0x7f, 0x5a, 0xf2, 0x2c, 0xc9, 0x22,
+	0x0d, 0x02, 0x6f, 0x0e, 0xdc, 0x25, 0xf4, 0x31, 0x54, 0x5e, 0x91, 0xa0, 0x35, 0x7c, 0x8c, 0x95,
+	0x26, 0x37, 0xfe, 0x75, 0x14, 0x6e, 0x9e, 0xb3, 0x6d, 0x97, 0xd4, 0x80, 0x2b, 0xcd, 0x09, 0xfd,
+	0x2e, 0x8a, 0x87, 0x5e, 0xf0, 0x0e, 0xf5, 0x7d, 0x66, 0x53, 0x7d, 0x44, 0x1b, 0x43, 0x1d, 0xf5,
+	0x9a, 0xfa, 0xf3, 0x54, 0xf3, 0x9a, 0x33, 0x56, 0xe6, 0x79, 0xf9, 0xc7, 0x12, 0xcc, 0x64, 0x49,
+	0xd0, 0x13, 0x98, 0xcc, 0xde, 0x50, 0x0f, 0x76, 0xda, 0x11, 0x03, 0xfa, 0x42, 0x58, 0x27, 0x69,
+	0xfa, 0xf5, 0x25, 0x0b, 0x1e, 0x19, 0x52, 0x44, 0x8e, 0x0f, 0x7d, 0x09, 0xb3, 0xbc, 0x1d, 0xa6,
+	0x41, 0x78, 0x74, 0x48, 0x51, 0x79, 0x46, 0xe3, 0xef, 0xc7, 0x61, 0xe5, 0x3c, 0x35, 0xbe, 0xe4,
+	0xc1, 0x3e, 0x4e, 0x6e, 0xee, 0x06, 0x1e, 0xaa, 0xf4, 0x67, 0x11, 0x39, 0x7a, 0x02, 0xd0, 0xe2,
+	0x2e, 0x0b, 0xb9, 0x98, 0xf8, 0x10, 0x17, 0xd8, 0x29, 0x6a, 0xf4, 0x08, 0xa6, 0x42, 0xee, 0x71,
+	0x87, 0x37, 0xbb, 0x43, 0x64, 0x57, 0x31, 0x2d, 0xda, 0x81, 0x59, 0x9b, 0x05, 0x62, 0xe6, 0x71,
+	0x28, 0x31, 0xb8, 0xa4, 0x9b, 0x67, 0x11, 0x07, 0x9c, 0xd5, 0x20, 0x3c, 0x3e, 0xe4, 0xa9, 0xe4,
+	0xf8, 0xd0, 0x77, 0xb0, 0x18, 0x9d, 0x53, 0x6c, 0x07, 0xe4, 0x5e, 0x4e, 0x4a, 0x07, 0xb5, 0x31,
+	0x9c, 0x05, 0x5a, 0xcb, 0xf0, 0x9a, 0xc5, 0x22, 0xd1, 0x19, 0x2c, 0x30, 0xb7, 0x17, 0x8e, 0xa7,
+	0xae, 0x30, 0x54, 0xa1, 0x44, 0x63, 0x03, 0xaa, 0xd9, 0xa1, 0xa7, 0x60, 0xec, 0xe8, 0xe9, 0xd1,
+	0x6e, 0xed, 0x9a, 0xf8, 0xb5, 0xf7, 0xec, 0xe0, 0xa0, 0x56, 0x42, 0xb3, 0x50, 0xd9, 0x35, 0xcd,
+	0xa7, 0x66, 0x43, 0x65, 0x99, 0x23, 0xc6, 0x3f, 0x94, 0xe0, 0xee, 0x70, 0x76, 0xf1, 0x92, 0xaa,
+	0xfa, 0x39, 0xcc, 0x39, 0xbc, 0xf9, 0x15, 0x73, 0x6d, 0xfe, 0x2a, 0x4a, 0x3b, 0xf0, 0xc8, 0xa0,
+	0xbc, 0xa4, 0x97, 0xc7, 0xd8, 0xd5, 0xbe, 0x3d, 0x1d, 0x64, 0x89, 0x3e, 0x8e, 0xa0, 0x7d, 0x12,
+	0x58, 0x3e, 0x3b, 0xa1, 0x76, 0xd2, 0x3e, 0x50, 0x92, 0xe5, 0xf0, 0x22, 0x94, 0xf1, 0xb7, 0x25,
+	0xa8, 0xa4, 0xaa, 0xab, 0x71, 0x65, 0xbc, 0x94, 0xaa, 0x8c, 0x23, 0x18, 0x13, 0x35, 0x57, 0x39,
+	0xcd, 0x71, 0x53, 0xfe, 0x16, 0x97, 0x5d, 0x22, 0xfb, 0x12, 0xac, 0xf2, 0xb5, 0x19, 0x37, 0xe3,
+	0x67, 0xd1, 0x95, 0xad, 0xfa, 0x7c, 0x25, 0x76, 0x4c, 0x62, 0x53, 0x10, 0xc1, 0xeb, 0xe9, 0x48,
+	0x55, 0x7f, 0xc1, 0x10, 0x3f, 0x1b, 0xff, 0x32, 0x09, 0x95, 0xd4, 0xed, 0xa8, 0x90, 0x25, 0x12,
+	0x66, 0x75, 0x45, 0xac, 0x5b, 0xc8, 0x53, 0x10, 0x91, 0x02, 0xeb, 0x5a, 0x89, 0xaa, 0x81, 0x68,
+	0x81, 0x59, 0xa0, 0x28, 0x42, 0x59, 0xbc, 0xe5, 0x71, 0x57, 0xe4, 0x5e, 0xd1, 0x07, 0x01, 0x2a,
+	0x95, 0xee, 0x45, 0x24, 0xf7, 0x58, 0xdb, 0xdc, 0xa7, 0x3b, 0xed, 0x96, 0x87, 0xcb, 0x03, 0x0f,
+	0x38, 0xc7, 0x21, 0x4e, 0x42, 0x7f, 0x06, 0xa1, 0x23, 0x70, 0x55, 0x30, 0x54, 0x6d, 0x12, 0x45,
+	0x28, 0x91, 0x6f, 0x47, 0xe0, 0xba, 0xbe, 0xc6, 0xd0, 0x6d, 0x13, 0x39, 0x70, 0x52, 0x0c, 0x98,
+	0x49, 0x17, 0x03, 0x44, 0xdb, 0x85, 0x9b, 0xe5, 0x57, 0x17, 0x27, 0x79, 0x70, 0xe6, 0xab, 0x08,
+	0x94, 0xfb, 0x2a, 0xe2, 0x89, 0x88, 0x65, 0x58, 0x87, 0x39, 0xb4, 0x49, 0x6d, 0x3c, 0x3f, 0x70,
+	0xdd, 0x29, 0x6a, 0xb4, 0x05, 0x2b, 0x3e, 0x25, 0x36, 0x73, 0x69, 0x10, 0x88, 0xab, 0x69, 0x46,
+	0x1c, 0xd9, 0x36, 0xde, 0xa0, 0x16, 0x77, 0x6d, 0x75, 0x0b, 0x52, 0x35, 0xcf, 0xa5, 0x11, 0x1d,
+	0x09, 0x31, 0xbe, 0x4e, 0x7d, 0xc6, 0xed, 0x88, 0x7b, 0x51, 0x72, 0xf7, 0xc1, 0xa2, 0x8f, 0xe1,
+	0x46, 0x8c, 0xd9, 0x23, 0xcc, 0x69, 0xfb, 0xf4, 0xf8, 0xcc, 0xa7, 0xc1, 0x19, 0x77, 0x6c, 0x79,
+	0x5b, 0x51, 0x35, 0xfb, 0x13, 0x08, 0x2d, 0x0b, 0x42, 0x12, 0xb6, 0x65, 0x65, 0x56, 0x76, 0x1b,
+	0x54, 0xcd, 0x14, 0x24, 0x5b, 0x42, 0xc1, 0x17, 0x28, 0xa1, 0x44, 0x17, 0xe9, 0x37, 0xa4, 0x7d,
+	0xab, 0x25, 0x3c, 0x0a, 0x9e, 0xba, 0x42, 0x5f, 0xd0, 0xa7, 0x1c, 0x19, 0x78, 0xa5, 0x2f, 0x2b,
+	0xf2, 0x78, 0x0a, 0x71, 0xe8, 0x53, 0x28, 0x3b, 0xec, 0x94, 0x5a, 0x5d, 0xcb, 0xa1, 0xf8, 0xce,
+	0x90, 0xc6, 0x3f, 0x61, 0x41, 0x67, 0x70, 0x5b, 0x2c, 0x7e, 0xd3, 0x93, 0x75, 0x26, 0x61, 0x54,
+	0x9e, 0xb9, 0x21, 0x73, 0xe4, 0xdb, 0xd7, 0x08, 0x89, 0x1f, 0x46, 0xa5, 0xe8, 0x41, 0xde, 0x74,
+	0x90, 0x18, 0xe3, 0xf7, 0x30, 0x9b, 0x6b, 0x60, 0x48, 0x74, 0xb8, 0x94, 0xd6, 0xe1, 0xcc, 0x3e,
+	0x8f, 0x0f, 0xbb, 0xcf, 0xc6, 0x36, 0x5c, 0xef, 0xd3, 0xb0, 0x8e, 0x6a, 0xaa, 0x36, 0xa5, 0x2b,
+	0xc8, 0xa2, 0xe2, 0x24, 0xbb, 0x75, 0x5a, 0xdc, 0xef, 0x46, 0x55, 0x5d, 0xf5, 0x64, 0x7c, 0x0e,
+	0xe5, 0xb8, 0x65, 0x02, 0x3d, 0x81, 0xf1, 0x50, 0x7c, 0xee, 0x31, 0xac, 0x53, 0x95, 0x33, 0x52,
+	0x2c, 0xc6, 0x9f, 0xc3, 0x74, 0xfa, 0x3a, 0x48, 0xdc, 0xdb, 0xcb, 0x9b, 0xfc, 0x3a, 0x09, 0xcf,
+	0xf4, 0x44, 0x12, 0x40, 0x6c, 0x70, 0x47, 0x52, 0x06, 0x57, 0xa8, 0xa3, 0x94, 0x20, 0x4b, 0xc2,
+	0x2a, 0xb3, 0x4b, 0x41, 0x8c, 0x3f, 0x94, 0xa0, 0xaa, 0xd3, 0xcb, 0xf8, 0xea, 0xbd, 0x42, 0x52,
+	0xb9, 0xfd, 0xb0, 0xe1, 0x62, 0x9a, 0x49, 0x64, 0x94, 0xd1, 0x25, 0x4a, 0x3d, 0x32, 0xf7, 0x55,
+	0x33, 0x03, 0x8b, 0x67, 0x3b, 0x9a, 0x75, 0x0f, 0xf9, 0x76, 0x5f, 0xe3, 0x3f, 0xc7, 0x61, 0xb1,
+	0xb0, 0xbb, 0x07, 0x7d, 0x0d, 0x37, 0x94, 0xa9, 0x4c, 0xda, 0x89, 0xb6, 0xba, 0xba, 0x9d, 0x6d,
+	0x88, 0x90, 0xbc, 0x3f, 0x33, 0xfa, 0x06, 0xe6, 0x5d, 0xda, 0xa1, 0x7a, 0xc0, 0xb8, 0xa2, 0x58,
+	0xb9, 0xd8, 0xc5, 0x47, 0x91, 0x0c, 0x79, 0x55, 0xe3, 0x88, 0x3e, 0xd2, 0x9c, 0xec, 0xe9, 0x8b,
+	0x5e, 0xd5, 0x14, 0x08, 0x41, 0x07, 0x30, 0xef, 0xd3, 0x57, 0x3e, 0x0b, 0xe9, 0xa6, 0xe7, 0x7d,
+	0x71, 0x7c, 0x5c, 0xaf, 0xfb, 0xfc, 0x84, 0xe2, 0xda, 0xc0, 0xbd, 0x28, 0x62, 0x43, 0x26, 0xcc,
+	0xab, 0x6b, 0x15, 0x9a, 0xa9, 0xf6, 0x0c, 0xdb, 0x7b, 0x56, 0xc4, 0x2c, 0x62, 0x4d, 0x7e, 0x92,
+	0x59, 0xf8, 0xb0, 0x45, 0xc4, 0x1c, 0x9f, 0xaa, 0x5a, 0xe8, 0x4b, 0x9f, 0x67, 0xe6, 0x01, 0x5e,
+	0x8a, 0xaa, 0x16, 0x09, 0x4c, 0xd8, 0xb5, 0x50, 0xdf, 0x07, 0x45, 0x2d, 0xd0, 0x43, 0xd8, 0xb5,
+	0x98, 0x45, 0x74, 0x15, 0x46, 0x7d, 0x8a, 0xb1, 0x18, 0xac, 0xba, 0x0a, 0xf3, 0x70, 0x71, 0x57,
+	0xd2, 0x0e, 0xe8, 0x01, 0x6d, 0x12, 0xab, 0x1b, 0x4d, 0x32, 0x18, 0xe6, 0xae, 0xa4, 0x97, 0xcb,
+	0xf8, 0xcb, 0x11, 0x98, 0x4e, 0xf7, 0x47, 0x89, 0x86, 0x42, 0x91, 0x19, 0xdb, 0xbc, 0xd9, 0xdb,
+	0x61, 0xac, 0x08, 0x77, 0x14, 0x3a, 0x6a, 0x28, 0xd4, 0xd4, 0xe8, 0x13, 0x61, 0xd9, 0x9b, 0x67,
+	0x61, 0x10, 0x52, 0x4f, 0xbf, 0x13, 0xb7, 0xf3, 0xac, 0x07, 0x82, 0xa0, 0x11, 0x52, 0x4f, 0x33,
+	0x27, 0x1c, 0x68, 0x03, 0x26, 0xbe, 0x67, 0xde, 0x4b, 0x16, 0x75, 0xe4, 0xae, 0xe4, 0x79, 0xbf,
+	0x95, 0xd8, 0xa8, 0x63, 0x4a, 0xd1, 0xa2, 0xed, 0x6c, 0xf9, 0x61, 0x2c, 0xff, 0x49, 0x90, 0x62,
+	0x6d, 0x24, 0x24, 0x05, 0x95, 0x07, 0xe3, 0x01, 0xcc, 0x17, 0xac, 0x4c, 0x74, 0x20, 0x12, 0xdd,
+	0xb8, 0xa4, 0x0c, 0x60, 0xf4, 0x68, 0x34, 0x60, 0xb1, 0x70, 0x3d, 0xfd, 0x59, 0xc4, 0x8d, 0x99,
+	0x2a, 0x49, 0x1c, 0x4b, 0x0b, 0xad, 0x6f, 0xcc, 0x52, 0x20, 0x63, 0x0d, 0x50, 0xef, 0x42, 0xcf,
+	0x99, 0xc4, 0x7f, 0x95, 0xe0, 0x7a, 0x9f, 0xe5, 0xa1, 0x87, 0x30, 0x6e, 0xd3, 0x93, 0x76, 0x73,
+	0x88, 0x20, 0x5f, 0x11, 0xea, 0xcf, 0xfc, 0x8e, 0xda, 0xad, 0x13, 0xea, 0x3f, 0x3d, 0xdd, 0x0c,
+	0x43, 0x9f, 0x9d, 0xb4, 0x85, 0x12, 0x8e, 0xc4, 0x9f, 0xf9, 0xf5, 0x22, 0x45, 0xe0, 0x93, 0x46,
+	0xa4, 0x5e, 0x5d, 0x75, 0xb7, 0xd4, 0x07, 0x2b, 0xda, 0x60, 0x52, 0x98, 0x43, 0x1a, 0x04, 0xa4,
+	0x19, 0x7d, 0x36, 0xa9, 0x6e, 0x9c, 0xfa, 0xe2, 0x8d, 0x1f, 0x4a, 0x00, 0xe2, 0x0b, 0x41, 0xbd,
+	0xd4, 0x2f, 0x01, 0xe9, 0x28, 0xd6, 0xdc, 0x49, 0x5e, 0x9d, 0xc1, 0xeb, 0x2e, 0xe0, 0x12, 0x71,
+	0x79, 0x27, 0xee, 0xf2, 0x16, 0x6f, 0xba, 0x3a, 0xa6, 0x2c, 0x10, 0xd5, 0x61, 0x51, 0xf1, 0xca,
+	0x3e, 0x32, 0x35, 0x8d, 0x6d, 0x73, 0x27, 0x18, 0x22, 0x13, 0x2f, 0x66, 0x34, 0x1e, 0x03, 0x92,
+	0x20, 0xdb, 0x94, 0x3d, 0x84, 0x7a, 0x65, 0x79, 0xb3, 0x53, 0xea, 0x35, 0x3b, 0xc6, 0x5f, 0x8f,
+	0xc3, 0x84, 0x14, 0x1d, 0x88, 0x86, 0x3f, 0xcb, 0x65, 0x78, 0x24, 0x1f, 0x80, 0xc4, 0xdf, 0x83,
+	0x9b, 0x02, 0x8f, 0x36, 0x60, 0x4a, 0x97, 0x5b, 0xa2, 0x60, 0x25, 0xf5, 0x6d, 0x6f, 0xf6, 0xcb,
+	0x03, 0x33, 0xa6, 0x14, 0x9d, 0x8c, 0xea, 0xd2, 0x56, 0x67, 0xfd, 0x4b, 0xf9, 0x2e, 0xe3, 0xe8,
+	0xbd, 0x54, 0x54, 0xb2, 0x2b, 0x46, 0x24, 0x7a, 0xba, 0x77, 0x6b, 0xb1, 0xb0, 0xc8, 0x6e, 0x2a,
+	0x1a, 0xd1, 0x45, 0x1a, 0x46, 0xe9, 0x2b, 0xbe, 0xde, 0x53, 0x1f, 0xcf, 0x56, 0x70, 0xcd, 0x84,
+	0x16, 0x7d, 0x05, 0x4b, 0x41, 0xd6, 0x5f, 0xeb, 0xc6, 0x57, 0x5c, 0xcd, 0xdb, 0x9f, 0x42, 0xbf,
+	0x6e, 0xf6, 0x61, 0x47, 0x0f, 0xa1, 0xac, 0x3e, 0x76, 0x10, 0x3b, 0x3a, 0xdf, 0x7f, 0x47, 0xa7,
+	0x24, 0xd5, 0xb6, 0xcb, 0x32, 0x7d, 0x94, 0x8b, 0xb9, 0x3e, 0xca, 0x15, 0x28, 0xf3, 0x57, 0xd1,
+	0x87, 0xb6, 0xca, 0x79, 0x24, 0x00, 0xf4, 0x21, 0x80, 0x68, 0x9d, 0x56, 0x12, 0xf1, 0x9d, 0xf3,
+	0x6b, 0xfb, 0x29, 0x52, 0x74, 0x0f, 0xc6, 0x4e, 0x48, 0x40, 0xf1, 0x5b, 0xf9, 0xaf, 0x25, 0x92,
+	0xb7, 0xc3, 0x94, 0x14, 0xa2, 0x1b, 0x9b, 0xa5, 0xf4, 0x0b, 0xdf, 0xcd, 0x5b, 0xd8, 0x5e, 0xed,
+	0x33, 0x33, 0x1c, 0x42, 0x17, 0xa3, 0xe5, 0x1c, 0x93, 0x66, 0x80, 0xdf, 0x96, 0xae, 0x29, 0x03,
+	0x33, 0x30, 0x2c, 0x15, 0xfb, 0x39, 0xe3, 0x36, 0xbc, 0x71, 0x6e, 0x8c, 0x61, 0x2c, 0xc1, 0x42,
+	0xd1, 0xe5, 0x99, 0x31, 0x07, 0xb3, 0xb9, 0xeb, 0x11, 0xe3, 0x77, 0x50, 0xcd, 0x7c, 0x7d, 0xf5,
+	0x13, 0xb7, 0x49, 0xcc, 0x42, 0x35, 0xb3, 0xe3, 0xef, 0x7e, 0xd9, 0xe7, 0x26, 0x44, 0x94, 0x61,
+	0x9e, 0x1d, 0x35, 0xea, 0xbb, 0xdb, 0xfb, 0x7b, 0xfb, 0xbb, 0x3b, 0xb5, 0x6b, 0xa8, 0x02, 0x93,
+	0x3b, 0xbb, 0x7b, 0x9b, 0xcf, 0x0e, 0x8e, 0x6b, 0x25, 0x04, 0x30, 0xd1, 0x38, 0x36, 0xf7, 0xb7,
+	0x8f, 0x6b, 0x23, 0x68, 0x12, 0x46, 0x9f, 0xee, 0xed, 0xd5, 0x46, 0xdf, 0x7d, 0x1e, 0xa5, 0x56,
+	0x02, 0xad, 0x3c, 0x58, 0xed, 0x9a, 0x68, 0x23, 0x88, 0xdd, 0x60, 0xad, 0x24, 0xc4, 0x68, 0x97,
+	0x5a, 0x1b, 0x11, 0x83, 0xa4, 0x3c, 0x55, 0x6d, 0x14, 0xcd, 0xc3, 0x2c, 0xf7, 0xa8, 0xbb, 0x4d,
+	0xdd, 0xa0, 0x1d, 0x6c, 0x36, 0xa9, 0x1b, 0xd6, 0xc6, 0xb6, 0x96, 0xfe, 0xf9, 0xc7, 0x5b, 0xd7,
+	0x7e, 0xf8, 0xf1, 0xd6, 0xb5, 0x7f, 0xfb, 0xf1, 0xd6, 0xb5, 0x6f, 0xe3, 0x7f, 0x6d, 0x71, 0x32,
+	0x21, 0x77, 0xe0, 0x83, 0xff, 0x19, 0x00, 0x1c, 0xab, 0xa5, 0xa1, 0x19, 0x43, 0x00, 0x00,
 }
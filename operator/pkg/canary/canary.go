@@ -0,0 +1,432 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package canary orchestrates revision based canary upgrades of the control plane: the new control plane
+// is installed as a revision, namespaces are moved to it in batches and the revision tag is pointed at it,
+// with health gates after each step. When a gate fails, the namespaces and the tag are moved back.
+package canary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"istio.io/api/label"
+	"istio.io/istio/operator/pkg/util/clog"
+)
+
+const (
+	installStep = "install"
+	tagStep     = "tag"
+
+	// restartedAtAnnotation is the annotation set by "kubectl rollout restart".
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+	// istioInjectionLabel is the legacy injection label, which takes precedence over istio.io/rev.
+	istioInjectionLabel = "istio-injection"
+)
+
+// injectionLabels are the namespace labels selecting the control plane which injects the sidecars.
+var injectionLabels = []string{label.IoIstioRev.Name, istioInjectionLabel}
+
+// Installer installs the control plane of a revision, waiting for its resources to be applied.
+type Installer interface {
+	Install(ctx context.Context, revision string) error
+}
+
+// TagManager reads and moves revision tags.
+type TagManager interface {
+	// TagRevision returns the revision the tag points at, or an empty string if the tag does not exist.
+	TagRevision(ctx context.Context, tag string) (string, error)
+	// SetTag points the tag at the revision.
+	SetTag(ctx context.Context, tag, revision string) error
+	// RemoveTag deletes the tag, if it exists.
+	RemoveTag(ctx context.Context, tag string) error
+}
+
+// Options are the options of a canary upgrade.
+type Options struct {
+	// Revision is the revision of the new control plane.
+	Revision string
+	// IstioNamespace is the namespace of the control plane, where the upgrade state is saved.
+	IstioNamespace string
+	// Namespaces are moved to the revision in order, BatchSize at a time, by setting their istio.io/rev label.
+	Namespaces []string
+	BatchSize  int
+	// Tag, if set, is pointed at the revision once all the namespaces are moved.
+	Tag string
+	// RestartWorkloads restarts the deployments of the moved namespaces, for their sidecars to be injected
+	// by the revision.
+	RestartWorkloads bool
+	// Checks are the health gates run after each step, retried every GateInterval until GateTimeout.
+	Checks       []HealthCheck
+	GateTimeout  time.Duration
+	GateInterval time.Duration
+}
+
+// Upgrader runs canary upgrades. Each step is saved once its health gate passes, so that an interrupted
+// upgrade resumes from the first step which did not complete.
+type Upgrader struct {
+	client    kubernetes.Interface
+	installer Installer
+	tags      TagManager
+	store     stateStore
+	opts      Options
+	l         clog.Logger
+}
+
+// NewUpgrader creates an Upgrader. tags may be nil if no tag is moved.
+func NewUpgrader(client kubernetes.Interface, installer Installer, tags TagManager, opts Options, l clog.Logger) (*Upgrader, error) {
+	if opts.Revision == "" {
+		return nil, fmt.Errorf("revision must be set")
+	}
+	if opts.Tag != "" && tags == nil {
+		return nil, fmt.Errorf("a tag manager is required to move tag %s", opts.Tag)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1
+	}
+	if opts.GateInterval <= 0 {
+		opts.GateInterval = 10 * time.Second
+	}
+	return &Upgrader{
+		client:    client,
+		installer: installer,
+		tags:      tags,
+		store:     stateStore{client: client, namespace: opts.IstioNamespace},
+		opts:      opts,
+		l:         l,
+	}, nil
+}
+
+// step is a step of the upgrade. moved are the namespaces moved to the revision once the step is applied.
+type step struct {
+	name  string
+	apply func(ctx context.Context, state *State) error
+	moved []string
+}
+
+func (u *Upgrader) steps() []step {
+	steps := []step{{name: installStep, apply: u.install}}
+	var moved []string
+	for i := 0; i < len(u.opts.Namespaces); i += u.opts.BatchSize {
+		end := i + u.opts.BatchSize
+		if end > len(u.opts.Namespaces) {
+			end = len(u.opts.Namespaces)
+		}
+		batch := u.opts.Namespaces[i:end]
+		moved = append(moved, batch...)
+		steps = append(steps, step{
+			name:  fmt.Sprintf("namespaces-%d", i/u.opts.BatchSize),
+			apply: func(ctx context.Context, state *State) error { return u.moveNamespaces(ctx, state, batch) },
+			moved: append([]string{}, moved...),
+		})
+	}
+	if u.opts.Tag != "" {
+		steps = append(steps, step{name: tagStep, apply: u.moveTag, moved: moved})
+	}
+	return steps
+}
+
+// Run runs the upgrade, resuming it if it was interrupted. When a health gate fails, the upgrade is
+// rolled back and the failure is returned.
+func (u *Upgrader) Run(ctx context.Context) error {
+	state, err := u.store.load(ctx, u.opts.Revision)
+	if err != nil {
+		return err
+	}
+	switch {
+	case state == nil:
+		state = u.newState()
+	case state.Phase == Completed:
+		u.l.LogAndPrintf("Canary upgrade to revision %s is already completed.\n", u.opts.Revision)
+		return nil
+	case state.Phase == RolledBack:
+		u.l.LogAndPrintf("Canary upgrade to revision %s was rolled back (%s), restarting it.\n", u.opts.Revision, state.Message)
+		state = u.newState()
+	case state.Tag != u.opts.Tag || !reflect.DeepEqual(state.Namespaces, u.opts.Namespaces):
+		return fmt.Errorf("a canary upgrade to revision %s with tag %q and namespaces %v is in progress, "+
+			"roll it back before starting another one", state.Revision, state.Tag, state.Namespaces)
+	default:
+		u.l.LogAndPrintf("Resuming canary upgrade to revision %s after %d completed steps.\n",
+			u.opts.Revision, len(state.CompletedSteps))
+	}
+
+	for _, s := range u.steps() {
+		if state.completed(s.name) {
+			continue
+		}
+		u.l.LogAndPrintf("Step %s: applying.\n", s.name)
+		if err := s.apply(ctx, state); err != nil {
+			return fmt.Errorf("step %s failed: %v", s.name, err)
+		}
+		target := Target{Revision: u.opts.Revision, Namespaces: s.moved}
+		if s.name == tagStep {
+			tagged, err := u.taggedNamespaces(ctx, u.opts.Tag)
+			if err != nil {
+				return err
+			}
+			target.Namespaces = append(append([]string{}, s.moved...), tagged...)
+		}
+		if err := u.gate(ctx, target); err != nil {
+			message := fmt.Sprintf("health gate of step %s failed: %v", s.name, err)
+			u.l.LogAndErrorf("%s, rolling back.", message)
+			if rerr := u.rollback(ctx, state, message); rerr != nil {
+				return fmt.Errorf("%s, and rollback failed: %v", message, rerr)
+			}
+			return fmt.Errorf("%s, the upgrade was rolled back", message)
+		}
+		state.CompletedSteps = append(state.CompletedSteps, s.name)
+		if err := u.store.save(ctx, state); err != nil {
+			return err
+		}
+		u.l.LogAndPrintf("Step %s: completed.\n", s.name)
+	}
+	state.Phase = Completed
+	if err := u.store.save(ctx, state); err != nil {
+		return err
+	}
+	u.l.LogAndPrintf("Canary upgrade to revision %s completed.\n", u.opts.Revision)
+	return nil
+}
+
+// Rollback moves the namespaces and the tag of an upgrade in progress back to where they were.
+func (u *Upgrader) Rollback(ctx context.Context) error {
+	state, err := u.store.load(ctx, u.opts.Revision)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return fmt.Errorf("no canary upgrade to revision %s found", u.opts.Revision)
+	}
+	if state.Phase == RolledBack {
+		u.l.LogAndPrintf("Canary upgrade to revision %s is already rolled back.\n", u.opts.Revision)
+		return nil
+	}
+	return u.rollback(ctx, state, "rolled back on request")
+}
+
+func (u *Upgrader) newState() *State {
+	return &State{
+		Revision:   u.opts.Revision,
+		Tag:        u.opts.Tag,
+		Namespaces: u.opts.Namespaces,
+		Phase:      InProgress,
+	}
+}
+
+func (u *Upgrader) install(ctx context.Context, state *State) error {
+	if err := u.store.save(ctx, state); err != nil {
+		return err
+	}
+	return u.installer.Install(ctx, u.opts.Revision)
+}
+
+// moveNamespaces sets the istio.io/rev label of the namespaces to the revision, saving their previous
+// injection labels first.
+func (u *Upgrader) moveNamespaces(ctx context.Context, state *State, namespaces []string) error {
+	if state.PreviousLabels == nil {
+		state.PreviousLabels = map[string]map[string]string{}
+	}
+	for _, name := range namespaces {
+		if _, f := state.PreviousLabels[name]; f {
+			// Already moved by an interrupted run.
+			continue
+		}
+		ns, err := u.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		previous := map[string]string{}
+		for _, l := range injectionLabels {
+			if v, f := ns.Labels[l]; f {
+				previous[l] = v
+			}
+		}
+		state.PreviousLabels[name] = previous
+	}
+	// Save the previous labels before changing them, for an interrupted run to be rolled back.
+	if err := u.store.save(ctx, state); err != nil {
+		return err
+	}
+	for _, name := range namespaces {
+		if err := u.setInjectionLabels(ctx, name, map[string]string{label.IoIstioRev.Name: u.opts.Revision}); err != nil {
+			return err
+		}
+	}
+	return u.restartWorkloads(ctx, namespaces)
+}
+
+func (u *Upgrader) moveTag(ctx context.Context, state *State) error {
+	if !state.TagMoved {
+		previous, err := u.tags.TagRevision(ctx, u.opts.Tag)
+		if err != nil {
+			return err
+		}
+		state.PreviousTagRevision = previous
+		state.TagMoved = true
+		if err := u.store.save(ctx, state); err != nil {
+			return err
+		}
+	}
+	if err := u.tags.SetTag(ctx, u.opts.Tag, u.opts.Revision); err != nil {
+		return err
+	}
+	tagged, err := u.taggedNamespaces(ctx, u.opts.Tag)
+	if err != nil {
+		return err
+	}
+	return u.restartWorkloads(ctx, tagged)
+}
+
+// taggedNamespaces returns the namespaces whose sidecars are injected by the revision the tag points at.
+func (u *Upgrader) taggedNamespaces(ctx context.Context, tag string) ([]string, error) {
+	namespaces, err := u.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", label.IoIstioRev.Name, tag),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the namespaces using tag %s: %v", tag, err)
+	}
+	var names []string
+	for _, ns := range namespaces.Items {
+		names = append(names, ns.Name)
+	}
+	return names, nil
+}
+
+// gate runs the health checks until they all pass, or until the gate times out.
+func (u *Upgrader) gate(ctx context.Context, target Target) error {
+	deadline := time.Now().Add(u.opts.GateTimeout)
+	for {
+		err := u.runChecks(ctx, target)
+		if err == nil {
+			return nil
+		}
+		if time.Now().Add(u.opts.GateInterval).After(deadline) {
+			return err
+		}
+		u.l.LogAndPrintf("Waiting for health checks: %v\n", err)
+		select {
+		case <-time.After(u.opts.GateInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (u *Upgrader) runChecks(ctx context.Context, target Target) error {
+	for _, c := range u.opts.Checks {
+		if err := c.Check(ctx, target); err != nil {
+			return fmt.Errorf("%s check failed: %v", c.Name(), err)
+		}
+	}
+	return nil
+}
+
+// rollback moves the tag back to its previous revision, or removes it if it did not exist, and restores the labels
+// of the moved namespaces.
+func (u *Upgrader) rollback(ctx context.Context, state *State, message string) error {
+	if state.TagMoved {
+		if state.PreviousTagRevision == "" {
+			// The namespaces using the tag were not injected before the upgrade, their workloads are not restarted.
+			if err := u.tags.RemoveTag(ctx, state.Tag); err != nil {
+				return fmt.Errorf("failed to remove tag %s: %v", state.Tag, err)
+			}
+			u.l.LogAndPrintf("Tag %s did not exist before the upgrade and was removed.\n", state.Tag)
+		} else {
+			if err := u.tags.SetTag(ctx, state.Tag, state.PreviousTagRevision); err != nil {
+				return fmt.Errorf("failed to move tag %s back to revision %s: %v", state.Tag, state.PreviousTagRevision, err)
+			}
+			u.l.LogAndPrintf("Tag %s moved back to revision %s.\n", state.Tag, state.PreviousTagRevision)
+			tagged, err := u.taggedNamespaces(ctx, state.Tag)
+			if err != nil {
+				return err
+			}
+			if err := u.restartWorkloads(ctx, tagged); err != nil {
+				return err
+			}
+		}
+		state.TagMoved = false
+	}
+	for _, name := range state.Namespaces {
+		previous, f := state.PreviousLabels[name]
+		if !f {
+			continue
+		}
+		if err := u.setInjectionLabels(ctx, name, previous); err != nil {
+			return fmt.Errorf("failed to restore the labels of namespace %s: %v", name, err)
+		}
+		if err := u.restartWorkloads(ctx, []string{name}); err != nil {
+			return err
+		}
+		delete(state.PreviousLabels, name)
+		u.l.LogAndPrintf("Namespace %s moved back.\n", name)
+	}
+	state.Phase = RolledBack
+	state.Message = message
+	state.CompletedSteps = nil
+	if err := u.store.save(ctx, state); err != nil {
+		return err
+	}
+	u.l.LogAndPrintf("Canary upgrade to revision %s rolled back. The control plane of the revision is still installed.\n",
+		state.Revision)
+	return nil
+}
+
+// setInjectionLabels replaces the injection labels of the namespace with labels.
+func (u *Upgrader) setInjectionLabels(ctx context.Context, name string, labels map[string]string) error {
+	patch := map[string]interface{}{}
+	for _, l := range injectionLabels {
+		if v, f := labels[l]; f {
+			patch[l] = v
+		} else {
+			// A null value removes the label.
+			patch[l] = nil
+		}
+	}
+	b, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"labels": patch}})
+	if err != nil {
+		return err
+	}
+	_, err = u.client.CoreV1().Namespaces().Patch(ctx, name, types.MergePatchType, b, metav1.PatchOptions{})
+	return err
+}
+
+// restartWorkloads restarts the deployments of the namespaces the way "kubectl rollout restart" does,
+// if RestartWorkloads is set.
+func (u *Upgrader) restartWorkloads(ctx context.Context, namespaces []string) error {
+	if !u.opts.RestartWorkloads {
+		return nil
+	}
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		restartedAtAnnotation, time.Now().Format(time.RFC3339))
+	for _, ns := range namespaces {
+		deployments, err := u.client.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, d := range deployments.Items {
+			if _, err := u.client.AppsV1().Deployments(ns).Patch(ctx, d.Name, types.StrategicMergePatchType,
+				[]byte(patch), metav1.PatchOptions{}); err != nil {
+				return fmt.Errorf("failed to restart deployment %s/%s: %v", ns, d.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package canary
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"istio.io/istio/operator/pkg/util/clog"
+)
+
+type fakeInstaller struct {
+	installed []string
+}
+
+func (f *fakeInstaller) Install(_ context.Context, revision string) error {
+	f.installed = append(f.installed, revision)
+	return nil
+}
+
+type fakeTags map[string]string
+
+func (f fakeTags) TagRevision(_ context.Context, tag string) (string, error) {
+	return f[tag], nil
+}
+
+func (f fakeTags) SetTag(_ context.Context, tag, revision string) error {
+	f[tag] = revision
+	return nil
+}
+
+func (f fakeTags) RemoveTag(_ context.Context, tag string) error {
+	delete(f, tag)
+	return nil
+}
+
+// recordingCheck records the targets it is run with, and fails for the targets fail returns true for.
+type recordingCheck struct {
+	targets [][]string
+	fail    func(target Target) bool
+}
+
+func (c *recordingCheck) Name() string {
+	return "recording"
+}
+
+func (c *recordingCheck) Check(_ context.Context, target Target) error {
+	c.targets = append(c.targets, target.Namespaces)
+	if c.fail != nil && c.fail(target) {
+		return fmt.Errorf("unhealthy")
+	}
+	return nil
+}
+
+func namespace(name string, labels map[string]string) *v1.Namespace {
+	return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newTestClient() kubernetes.Interface {
+	return fake.NewSimpleClientset(
+		namespace("istio-system", nil),
+		namespace("a", map[string]string{"istio-injection": "enabled"}),
+		namespace("b", map[string]string{"istio.io/rev": "1-9"}),
+		namespace("c", nil),
+		namespace("tagged", map[string]string{"istio.io/rev": "prod"}),
+	)
+}
+
+func namespaceLabels(t *testing.T, client kubernetes.Interface) map[string]map[string]string {
+	t.Helper()
+	out := map[string]map[string]string{}
+	for _, name := range []string{"a", "b", "c"} {
+		ns, err := client.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[name] = ns.Labels
+		if out[name] == nil {
+			out[name] = map[string]string{}
+		}
+	}
+	return out
+}
+
+func newTestUpgrader(t *testing.T, client kubernetes.Interface, installer Installer, tags fakeTags, check HealthCheck) *Upgrader {
+	t.Helper()
+	u, err := NewUpgrader(client, installer, tags, Options{
+		Revision:       "1-10",
+		IstioNamespace: "istio-system",
+		Namespaces:     []string{"a", "b", "c"},
+		BatchSize:      2,
+		Tag:            "prod",
+		Checks:         []HealthCheck{check},
+	}, clog.NewDefaultLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestRun(t *testing.T) {
+	client := newTestClient()
+	installer := &fakeInstaller{}
+	tags := fakeTags{"prod": "1-9"}
+	check := &recordingCheck{}
+	u := newTestUpgrader(t, client, installer, tags, check)
+
+	if err := u.Run(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	moved := map[string]string{"istio.io/rev": "1-10"}
+	if got := namespaceLabels(t, client); !reflect.DeepEqual(got, map[string]map[string]string{"a": moved, "b": moved, "c": moved}) {
+		t.Errorf("got namespace labels %v", got)
+	}
+	if tags["prod"] != "1-10" {
+		t.Errorf("got tag pointing at %s, want 1-10", tags["prod"])
+	}
+	wantTargets := [][]string{nil, {"a", "b"}, {"a", "b", "c"}, {"a", "b", "c", "tagged"}}
+	if !reflect.DeepEqual(check.targets, wantTargets) {
+		t.Errorf("got health gates on %v, want %v", check.targets, wantTargets)
+	}
+	state, err := u.store.load(context.TODO(), "1-10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Phase != Completed || len(state.CompletedSteps) != 4 {
+		t.Errorf("got state %+v", state)
+	}
+
+	// A completed upgrade is not run again.
+	if err := u.Run(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if len(installer.installed) != 1 {
+		t.Errorf("got %d installs, want 1", len(installer.installed))
+	}
+}
+
+func TestRunRollback(t *testing.T) {
+	cases := []struct {
+		name string
+		fail func(target Target) bool
+		// tag is the revision of the tag before the upgrade, the tag does not exist if empty.
+		tag string
+	}{
+		{
+			name: "namespace gate",
+			fail: func(target Target) bool { return len(target.Namespaces) == 3 },
+			tag:  "1-9",
+		},
+		{
+			name: "tag gate",
+			fail: func(target Target) bool { return len(target.Namespaces) == 4 },
+			tag:  "1-9",
+		},
+		{
+			name: "new tag",
+			fail: func(target Target) bool { return len(target.Namespaces) == 4 },
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient()
+			before := namespaceLabels(t, client)
+			tags := fakeTags{}
+			if tt.tag != "" {
+				tags["prod"] = tt.tag
+			}
+			u := newTestUpgrader(t, client, &fakeInstaller{}, tags, &recordingCheck{fail: tt.fail})
+
+			err := u.Run(context.TODO())
+			if err == nil || !strings.Contains(err.Error(), "the upgrade was rolled back") {
+				t.Fatalf("got error %v, want a rollback", err)
+			}
+			if got := namespaceLabels(t, client); !reflect.DeepEqual(got, before) {
+				t.Errorf("got namespace labels %v, want %v", got, before)
+			}
+			if got, f := tags["prod"]; got != tt.tag || f != (tt.tag != "") {
+				t.Errorf("got tag pointing at %q, want %q", got, tt.tag)
+			}
+			state, err := u.store.load(context.TODO(), "1-10")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if state.Phase != RolledBack || state.TagMoved || len(state.PreviousLabels) != 0 {
+				t.Errorf("got state %+v", state)
+			}
+		})
+	}
+}
+
+func TestRunResume(t *testing.T) {
+	client := newTestClient()
+	installer := &fakeInstaller{}
+	tags := fakeTags{"prod": "1-9"}
+	check := &recordingCheck{}
+	u := newTestUpgrader(t, client, installer, tags, check)
+
+	// The first batch was moved by an interrupted run, which did not record the second batch as completed.
+	if err := u.store.save(context.TODO(), &State{
+		Revision:       "1-10",
+		Tag:            "prod",
+		Namespaces:     []string{"a", "b", "c"},
+		Phase:          InProgress,
+		CompletedSteps: []string{installStep},
+		PreviousLabels: map[string]map[string]string{"a": {"istio-injection": "enabled"}, "b": {"istio.io/rev": "1-9"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, ns := range []string{"a", "b"} {
+		if err := u.setInjectionLabels(context.TODO(), ns, map[string]string{"istio.io/rev": "1-10"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// An upgrade with another plan is rejected.
+	other, err := NewUpgrader(client, installer, tags, Options{Revision: "1-10", IstioNamespace: "istio-system"}, clog.NewDefaultLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Run(context.TODO()); err == nil || !strings.Contains(err.Error(), "is in progress") {
+		t.Errorf("got error %v, want an upgrade in progress", err)
+	}
+
+	// Rolling back restores the labels saved by the interrupted run.
+	before := map[string]map[string]string{"a": {"istio-injection": "enabled"}, "b": {"istio.io/rev": "1-9"}, "c": {}}
+	if err := u.Rollback(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if got := namespaceLabels(t, client); !reflect.DeepEqual(got, before) {
+		t.Errorf("got namespace labels %v, want %v", got, before)
+	}
+}
+
+func TestRunResumeSkipsCompletedSteps(t *testing.T) {
+	client := newTestClient()
+	installer := &fakeInstaller{}
+	check := &recordingCheck{}
+	u := newTestUpgrader(t, client, installer, fakeTags{"prod": "1-9"}, check)
+	if err := u.store.save(context.TODO(), &State{
+		Revision:       "1-10",
+		Tag:            "prod",
+		Namespaces:     []string{"a", "b", "c"},
+		Phase:          InProgress,
+		CompletedSteps: []string{installStep, "namespaces-0"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Run(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if len(installer.installed) != 0 {
+		t.Errorf("got %d installs, want the install step to be skipped", len(installer.installed))
+	}
+	wantTargets := [][]string{{"a", "b", "c"}, {"a", "b", "c", "tagged"}}
+	if !reflect.DeepEqual(check.targets, wantTargets) {
+		t.Errorf("got health gates on %v, want %v", check.targets, wantTargets)
+	}
+}
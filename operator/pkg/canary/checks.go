@@ -0,0 +1,249 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package canary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"istio.io/api/label"
+	"istio.io/istio/pilot/pkg/xds"
+)
+
+// Target is what a health check verifies: the revision of the upgrade, and the namespaces moved to it so far.
+type Target struct {
+	Revision   string
+	Namespaces []string
+}
+
+// HealthCheck is a health gate of the canary upgrade, run after each step. Failing checks are retried
+// until the gate times out, at which point the upgrade is rolled back.
+type HealthCheck interface {
+	Name() string
+	Check(ctx context.Context, target Target) error
+}
+
+// IstiodReady checks that the istiod deployments of the revision are fully rolled out and ready.
+type IstiodReady struct {
+	Client    kubernetes.Interface
+	Namespace string
+}
+
+var _ HealthCheck = &IstiodReady{}
+
+func (c *IstiodReady) Name() string {
+	return "istiod readiness"
+}
+
+func (c *IstiodReady) Check(ctx context.Context, target Target) error {
+	deployments, err := c.Client.AppsV1().Deployments(c.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=istiod,%s=%s", label.IoIstioRev.Name, target.Revision),
+	})
+	if err != nil {
+		return err
+	}
+	if len(deployments.Items) == 0 {
+		return fmt.Errorf("no istiod deployment of revision %s found in namespace %s", target.Revision, c.Namespace)
+	}
+	for _, d := range deployments.Items {
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		if d.Status.UpdatedReplicas < replicas || d.Status.ReadyReplicas < replicas {
+			return fmt.Errorf("deployment %s has %d/%d updated and %d/%d ready replicas",
+				d.Name, d.Status.UpdatedReplicas, replicas, d.Status.ReadyReplicas, replicas)
+		}
+	}
+	return nil
+}
+
+// WorkloadsMigrated checks that the injected pods of the target namespaces run the proxy of the
+// revision and are ready. It is only meaningful when the workloads are restarted after being moved.
+type WorkloadsMigrated struct {
+	Client kubernetes.Interface
+}
+
+var _ HealthCheck = &WorkloadsMigrated{}
+
+func (c *WorkloadsMigrated) Name() string {
+	return "workload migration"
+}
+
+func (c *WorkloadsMigrated) Check(ctx context.Context, target Target) error {
+	var pending []string
+	for _, ns := range target.Namespaces {
+		pods, err := c.Client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
+			// Pods injected by any revision have the label.
+			LabelSelector: label.IoIstioRev.Name,
+			FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+		})
+		if err != nil {
+			return err
+		}
+		for _, pod := range pods.Items {
+			if pod.DeletionTimestamp != nil {
+				// Terminating pods of the previous rollout are ignored.
+				continue
+			}
+			if pod.Labels[label.IoIstioRev.Name] != target.Revision || !podReady(pod) {
+				pending = append(pending, pod.Name+"."+pod.Namespace)
+			}
+		}
+	}
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		return fmt.Errorf("%d pods are not running ready on revision %s: %s",
+			len(pending), target.Revision, strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+func podReady(pod v1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == v1.PodReady {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// DiscoveryDoFunc makes an HTTP request to each istiod of the revision, returning the responses by istiod name.
+type DiscoveryDoFunc func(ctx context.Context, namespace, path string) (map[string][]byte, error)
+
+// ProxyConvergence checks that the proxies of the target namespaces connected to the istiods of the
+// revision acked all the configuration they were sent, the way "istioctl proxy-status" reports SYNCED.
+type ProxyConvergence struct {
+	DiscoveryDo    DiscoveryDoFunc
+	IstioNamespace string
+}
+
+var _ HealthCheck = &ProxyConvergence{}
+
+func (c *ProxyConvergence) Name() string {
+	return "proxy convergence"
+}
+
+func (c *ProxyConvergence) Check(ctx context.Context, target Target) error {
+	if len(target.Namespaces) == 0 {
+		return nil
+	}
+	namespaces := map[string]struct{}{}
+	for _, ns := range target.Namespaces {
+		namespaces[ns] = struct{}{}
+	}
+	responses, err := c.DiscoveryDo(ctx, c.IstioNamespace, "/debug/syncz")
+	if err != nil {
+		return err
+	}
+	var stale []string
+	for istiod, resp := range responses {
+		statuses := []xds.SyncStatus{}
+		if err := json.Unmarshal(resp, &statuses); err != nil {
+			return fmt.Errorf("failed to parse sync status of %s: %v", istiod, err)
+		}
+		for _, s := range statuses {
+			if _, f := namespaces[proxyNamespace(s.ProxyID)]; !f {
+				continue
+			}
+			if s.ClusterSent != s.ClusterAcked || s.ListenerSent != s.ListenerAcked ||
+				s.RouteSent != s.RouteAcked || s.EndpointSent != s.EndpointAcked {
+				stale = append(stale, s.ProxyID)
+			}
+		}
+	}
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		return fmt.Errorf("%d proxies are not synced: %s", len(stale), strings.Join(stale, ", "))
+	}
+	return nil
+}
+
+// proxyNamespace returns the namespace of a proxy ID of the form <pod>.<namespace>.
+func proxyNamespace(proxyID string) string {
+	return proxyID[strings.LastIndex(proxyID, ".")+1:]
+}
+
+// ErrorRateSource returns the ratio of failed requests received by the workloads of namespaces, in [0, 1].
+type ErrorRateSource interface {
+	ErrorRate(ctx context.Context, namespaces []string) (float64, error)
+}
+
+// ErrorRate checks that the error rate of the target namespaces, as reported by Source, does not
+// exceed Threshold.
+type ErrorRate struct {
+	Source    ErrorRateSource
+	Threshold float64
+}
+
+var _ HealthCheck = &ErrorRate{}
+
+func (c *ErrorRate) Name() string {
+	return "error rate"
+}
+
+func (c *ErrorRate) Check(ctx context.Context, target Target) error {
+	if len(target.Namespaces) == 0 {
+		return nil
+	}
+	rate, err := c.Source.ErrorRate(ctx, target.Namespaces)
+	if err != nil {
+		return err
+	}
+	if rate > c.Threshold {
+		return fmt.Errorf("error rate %.4f exceeds threshold %.4f", rate, c.Threshold)
+	}
+	return nil
+}
+
+// PrometheusErrorRate computes the error rate from the Istio standard metrics in Prometheus, as the ratio
+// of 5xx responses reported by the destination workloads over Window.
+type PrometheusErrorRate struct {
+	API    promv1.API
+	Window time.Duration
+}
+
+var _ ErrorRateSource = &PrometheusErrorRate{}
+
+func (p *PrometheusErrorRate) ErrorRate(ctx context.Context, namespaces []string) (float64, error) {
+	selector := fmt.Sprintf(`reporter="destination",destination_workload_namespace=~"%s"`, strings.Join(namespaces, "|"))
+	window := model.Duration(p.Window).String()
+	query := fmt.Sprintf(`sum(rate(istio_requests_total{%s,response_code=~"5.."}[%s])) / sum(rate(istio_requests_total{%s}[%s]))`,
+		selector, window, selector, window)
+	val, _, err := p.API.Query(ctx, query, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to query Prometheus: %v", err)
+	}
+	vec, ok := val.(model.Vector)
+	if !ok {
+		return 0, fmt.Errorf("unexpected Prometheus result type %s", val.Type())
+	}
+	// No sample, or a NaN ratio, means there was no traffic.
+	if len(vec) == 0 || math.IsNaN(float64(vec[0].Value)) {
+		return 0, nil
+	}
+	return float64(vec[0].Value), nil
+}
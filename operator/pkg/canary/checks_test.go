@@ -0,0 +1,135 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package canary
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"istio.io/istio/pilot/pkg/xds"
+)
+
+func checkError(t *testing.T, err error, want string) {
+	t.Helper()
+	if want == "" {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("got error %v, want %q", err, want)
+	}
+}
+
+func TestIstiodReady(t *testing.T) {
+	istiod := func(ready int32) *appsv1.Deployment {
+		replicas := int32(2)
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "istiod-1-10",
+				Namespace: "istio-system",
+				Labels:    map[string]string{"app": "istiod", "istio.io/rev": "1-10"},
+			},
+			Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{UpdatedReplicas: 2, ReadyReplicas: ready},
+		}
+	}
+	cases := []struct {
+		name     string
+		revision string
+		ready    int32
+		err      string
+	}{
+		{name: "ready", revision: "1-10", ready: 2},
+		{name: "not ready", revision: "1-10", ready: 1, err: "1/2 ready replicas"},
+		{name: "not found", revision: "1-11", ready: 2, err: "no istiod deployment of revision 1-11"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &IstiodReady{Client: fake.NewSimpleClientset(istiod(tt.ready)), Namespace: "istio-system"}
+			checkError(t, c.Check(context.TODO(), Target{Revision: tt.revision}), tt.err)
+		})
+	}
+}
+
+func TestWorkloadsMigrated(t *testing.T) {
+	pod := func(name, revision string, ready v1.ConditionStatus) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "a", Labels: map[string]string{"istio.io/rev": revision}},
+			Status: v1.PodStatus{
+				Phase:      v1.PodRunning,
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: ready}},
+			},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		pod("migrated", "1-10", v1.ConditionTrue),
+		pod("not-ready", "1-10", v1.ConditionFalse),
+		pod("old", "1-9", v1.ConditionTrue),
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "not-injected", Namespace: "a"}},
+	)
+	c := &WorkloadsMigrated{Client: client}
+	checkError(t, c.Check(context.TODO(), Target{Revision: "1-10", Namespaces: []string{"a"}}),
+		"2 pods are not running ready on revision 1-10: not-ready.a, old.a")
+	checkError(t, c.Check(context.TODO(), Target{Revision: "1-10", Namespaces: []string{"b"}}), "")
+}
+
+func TestProxyConvergence(t *testing.T) {
+	syncz := func(statuses ...xds.SyncStatus) []byte {
+		b, _ := json.Marshal(statuses)
+		return b
+	}
+	responses := map[string][]byte{
+		"istiod-1": syncz(
+			xds.SyncStatus{ProxyID: "synced.a", ClusterSent: "1", ClusterAcked: "1", ListenerSent: "1", ListenerAcked: "1"},
+			xds.SyncStatus{ProxyID: "stale.b", ClusterSent: "2", ClusterAcked: "1"},
+		),
+		"istiod-2": syncz(xds.SyncStatus{ProxyID: "other.c", RouteSent: "2"}),
+	}
+	c := &ProxyConvergence{
+		DiscoveryDo: func(_ context.Context, namespace, path string) (map[string][]byte, error) {
+			if namespace != "istio-system" || path != "/debug/syncz" {
+				t.Errorf("unexpected request %s %s", namespace, path)
+			}
+			return responses, nil
+		},
+		IstioNamespace: "istio-system",
+	}
+	checkError(t, c.Check(context.TODO(), Target{Namespaces: []string{"a"}}), "")
+	checkError(t, c.Check(context.TODO(), Target{Namespaces: []string{"a", "b", "c"}}), "2 proxies are not synced: other.c, stale.b")
+}
+
+type fakeErrorRate float64
+
+func (f fakeErrorRate) ErrorRate(context.Context, []string) (float64, error) {
+	return float64(f), nil
+}
+
+func TestErrorRate(t *testing.T) {
+	target := Target{Namespaces: []string{"a"}}
+	checkError(t, (&ErrorRate{Source: fakeErrorRate(0.01), Threshold: 0.05}).Check(context.TODO(), target), "")
+	checkError(t, (&ErrorRate{Source: fakeErrorRate(0.1), Threshold: 0.05}).Check(context.TODO(), target),
+		"error rate 0.1000 exceeds threshold 0.0500")
+	// Nothing is checked before namespaces are moved.
+	checkError(t, (&ErrorRate{Source: fakeErrorRate(0.1), Threshold: 0.05}).Check(context.TODO(), Target{}), "")
+}
@@ -0,0 +1,121 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package canary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"istio.io/api/label"
+)
+
+// Phase is the phase of a canary upgrade.
+type Phase string
+
+const (
+	// InProgress is the phase of a canary upgrade which has not completed all its steps yet.
+	InProgress Phase = "InProgress"
+	// Completed is the phase of a canary upgrade which completed all its steps.
+	Completed Phase = "Completed"
+	// RolledBack is the phase of a canary upgrade whose namespaces and tag were moved back.
+	RolledBack Phase = "RolledBack"
+)
+
+const (
+	stateConfigMapPrefix = "istio-canary-"
+	stateKey             = "state"
+)
+
+// State is the progress of a canary upgrade, saved in a ConfigMap of the Istio namespace after each
+// step so that an interrupted upgrade can be resumed or rolled back.
+type State struct {
+	Revision   string   `json:"revision"`
+	Tag        string   `json:"tag,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	Phase      Phase    `json:"phase"`
+	// CompletedSteps are the steps whose health gate passed.
+	CompletedSteps []string `json:"completedSteps,omitempty"`
+	// PreviousLabels holds, per moved namespace, its injection labels before it was moved.
+	PreviousLabels map[string]map[string]string `json:"previousLabels,omitempty"`
+	// TagMoved is set once the tag is pointed at the revision, PreviousTagRevision is where it pointed before.
+	TagMoved            bool   `json:"tagMoved,omitempty"`
+	PreviousTagRevision string `json:"previousTagRevision,omitempty"`
+	// Message describes why the upgrade was rolled back.
+	Message string `json:"message,omitempty"`
+}
+
+func (s *State) completed(step string) bool {
+	for _, c := range s.CompletedSteps {
+		if c == step {
+			return true
+		}
+	}
+	return false
+}
+
+// stateStore saves the state of the canary upgrades in ConfigMaps.
+type stateStore struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+func stateConfigMapName(revision string) string {
+	return stateConfigMapPrefix + revision
+}
+
+// load returns the saved state of the canary upgrade to revision, or nil if there is none.
+func (s stateStore) load(ctx context.Context, revision string) (*State, error) {
+	cm, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, stateConfigMapName(revision), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read canary upgrade state: %v", err)
+	}
+	state := &State{}
+	if err := json.Unmarshal([]byte(cm.Data[stateKey]), state); err != nil {
+		return nil, fmt.Errorf("failed to parse canary upgrade state in ConfigMap %s/%s: %v", cm.Namespace, cm.Name, err)
+	}
+	return state, nil
+}
+
+func (s stateStore) save(ctx context.Context, state *State) error {
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      stateConfigMapName(state.Revision),
+			Namespace: s.namespace,
+			Labels:    map[string]string{label.IoIstioRev.Name: state.Revision},
+		},
+		Data: map[string]string{stateKey: string(b)},
+	}
+	configMaps := s.client.CoreV1().ConfigMaps(s.namespace)
+	if _, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{}); errors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save canary upgrade state: %v", err)
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"os"
 	"strings"
 
+	"istio.io/istio/pilot/pkg/dns"
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pkg/config/constants"
 	istioagent "istio.io/istio/pkg/istio-agent"
@@ -42,6 +43,13 @@ func NewAgentOptions(proxy *model.Proxy) *istioagent.AgentOptions {
 		o.ProxyXDSViaAgent = true
 		o.ProxyXDSDebugViaAgent = proxyXDSDebugViaAgent
 		o.DNSCapture = dnsCaptureByAgent
+		o.DNSOptions = dns.Options{
+			TTL:               dnsTTL,
+			UpstreamCacheSize: dnsUpstreamCacheSize,
+			NegativeCacheTTL:  dnsNegativeCacheTTL,
+		}
+		o.XdsSnapshotDir = xdsSnapshotDir
+		o.XdsSnapshotMaxAge = xdsSnapshotMaxAge
 		o.ProxyNamespace = PodNamespaceVar.Get()
 		o.ProxyDomain = proxy.DNSDomain
 	}
@@ -75,7 +75,11 @@ var (
 	eccSigAlgEnv        = env.RegisterStringVar("ECC_SIGNATURE_ALGORITHM", "", "The type of ECC signature algorithm to use when generating private keys").Get()
 	fileMountedCertsEnv = env.RegisterBoolVar("FILE_MOUNTED_CERTS", false, "").Get()
 	credFetcherTypeEnv  = env.RegisterStringVar("CREDENTIAL_FETCHER_TYPE", "",
-		"The type of the credential fetcher. Currently supported types include GoogleComputeEngine").Get()
+		"The type of the credential fetcher. Currently supported types include GoogleComputeEngine, Exec, UnixSocket and File").Get()
+	credFetcherSourceEnv = env.RegisterStringVar("CREDENTIAL_FETCHER_SOURCE", "",
+		"Where the credential fetcher gets the credential from: the path of an executable, or a JSON list of the "+
+			"executable and its arguments, for Exec, "+
+			"<socket path>[:<HTTP path>] for UnixSocket, and the token file for File").Get()
 	credIdentityProvider = env.RegisterStringVar("CREDENTIAL_IDENTITY_PROVIDER", "GoogleComputeEngine",
 		"The identity provider for credential. Currently default supported identity provider is GoogleComputeEngine").Get()
 	proxyXDSViaAgent = env.RegisterBoolVar("PROXY_XDS_VIA_AGENT", true,
@@ -86,6 +90,22 @@ var (
 	// This is a copy of the env var in the init code.
 	dnsCaptureByAgent = env.RegisterBoolVar("ISTIO_META_DNS_CAPTURE", false,
 		"If set to true, enable the capture of outgoing DNS packets on port 53, redirecting to istio-agent on :15053").Get()
+	// The DNS proxy settings are usually set through the proxyMetadata of the ProxyConfig.
+	dnsTTL = env.RegisterDurationVar("DNS_PROXY_TTL", 30*time.Second,
+		"The TTL of the answers of the agent DNS proxy for hosts of the service registry.").Get()
+	dnsUpstreamCacheSize = env.RegisterIntVar("DNS_PROXY_UPSTREAM_CACHE_SIZE", 1024,
+		"The maximum number of upstream DNS responses cached by the agent DNS proxy, honoring the TTL of the "+
+			"responses. Set to 0 to disable the cache.").Get()
+	dnsNegativeCacheTTL = env.RegisterDurationVar("DNS_PROXY_NEGATIVE_CACHE_TTL", 30*time.Second,
+		"The maximum duration the agent DNS proxy caches negative (NXDOMAIN or empty) upstream responses for. "+
+			"Set to 0 to disable negative caching.").Get()
+
+	xdsSnapshotDir = env.RegisterStringVar("XDS_SNAPSHOT_DIR", "",
+		"If set, the agent XDS proxy saves the last configuration acknowledged by Envoy in this directory, and "+
+			"serves it to Envoy when Istiod cannot be reached at startup.").Get()
+	xdsSnapshotMaxAge = env.RegisterDurationVar("XDS_SNAPSHOT_MAX_AGE", 24*time.Hour,
+		"The maximum age of the configuration saved in XDS_SNAPSHOT_DIR that is served to Envoy. "+
+			"Set to 0 to disable the limit.").Get()
 
 	// Ability of istio-agent to retrieve proxyConfig via XDS for dynamic configuration updates
 	enableProxyConfigXdsEnv = env.RegisterBoolVar("PROXY_CONFIG_XDS_AGENT", false,
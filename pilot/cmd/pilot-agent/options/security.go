@@ -50,7 +50,7 @@ func NewSecurityOptions(proxyConfig *meshconfig.ProxyConfig, stsPort int, tokenM
 	}
 
 	o, err := SetupSecurityOptions(proxyConfig, o, jwtPolicy.Get(),
-		credFetcherTypeEnv, credIdentityProvider)
+		credFetcherTypeEnv, credIdentityProvider, credFetcherSourceEnv)
 	if err != nil {
 		return o, err
 	}
@@ -67,7 +67,7 @@ func NewSecurityOptions(proxyConfig *meshconfig.ProxyConfig, stsPort int, tokenM
 }
 
 func SetupSecurityOptions(proxyConfig *meshconfig.ProxyConfig, secOpt *security.Options, jwtPolicy,
-	credFetcherTypeEnv, credIdentityProvider string) (*security.Options, error) {
+	credFetcherTypeEnv, credIdentityProvider, credFetcherSource string) (*security.Options, error) {
 	var jwtPath string
 	if jwtPolicy == jwt.PolicyThirdParty {
 		log.Info("JWT policy is third-party-jwt")
@@ -87,11 +87,11 @@ func SetupSecurityOptions(proxyConfig *meshconfig.ProxyConfig, secOpt *security.
 		o.CAEndpoint = proxyConfig.DiscoveryAddress
 	}
 
-	// TODO (liminw): CredFetcher is a general interface. In 1.7, we limit the use on GCE only because
-	// GCE is the only supported plugin at the moment.
-	if credFetcherTypeEnv == security.GCE {
+	switch credFetcherTypeEnv {
+	case security.GCE, security.Exec, security.UnixSocket, security.File:
 		o.CredIdentityProvider = credIdentityProvider
-		credFetcher, err := credentialfetcher.NewCredFetcher(credFetcherTypeEnv, o.TrustDomain, jwtPath, o.CredIdentityProvider)
+		credFetcher, err := credentialfetcher.NewCredFetcher(credFetcherTypeEnv, o.TrustDomain, jwtPath,
+			o.CredIdentityProvider, credFetcherSource)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create credential fetcher: %v", err)
 		}
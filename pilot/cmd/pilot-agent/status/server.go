@@ -38,6 +38,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/expfmt"
 	"go.opencensus.io/stats/view"
+	"google.golang.org/grpc"
+	ghc "google.golang.org/grpc/health/grpc_health_v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"istio.io/istio/pilot/cmd/pilot-agent/metrics"
@@ -88,8 +90,10 @@ type KubeAppProbers map[string]*Prober
 
 // Prober represents a single container prober
 type Prober struct {
-	HTTPGet        *apimirror.HTTPGetAction `json:"httpGet"`
-	TimeoutSeconds int32                    `json:"timeoutSeconds,omitempty"`
+	HTTPGet        *apimirror.HTTPGetAction   `json:"httpGet,omitempty"`
+	TCPSocket      *apimirror.TCPSocketAction `json:"tcpSocket,omitempty"`
+	GRPC           *apimirror.GRPCAction      `json:"grpc,omitempty"`
+	TimeoutSeconds int32                      `json:"timeoutSeconds,omitempty"`
 }
 
 // Options for the status server.
@@ -114,6 +118,7 @@ type Server struct {
 	appProbersDestination string
 	appKubeProbers        KubeAppProbers
 	appProbeClient        map[string]*http.Client
+	appProbeDialer        *net.Dialer
 	statusPort            uint16
 	lastProbeSuccessful   bool
 	envoyStatsPort        int
@@ -189,33 +194,43 @@ func NewServer(config Options) (*Server, error) {
 	}
 
 	s.appProbeClient = make(map[string]*http.Client, len(s.appKubeProbers))
+	localAddr := UpstreamLocalAddressIPv4
+	if config.IPv6 {
+		localAddr = UpstreamLocalAddressIPv6
+	}
+	s.appProbeDialer = &net.Dialer{
+		LocalAddr: localAddr,
+	}
 	// Validate the map key matching the regex pattern.
 	for path, prober := range s.appKubeProbers {
 		if !appProberPattern.Match([]byte(path)) {
 			return nil, fmt.Errorf(`invalid key, must be in form of regex pattern ^/app-health/[^\/]+/(livez|readyz)$`)
 		}
-		if prober.HTTPGet == nil {
-			return nil, fmt.Errorf(`invalid prober type, must be of type httpGet`)
-		}
-		if prober.HTTPGet.Port.Type != intstr.Int {
-			return nil, fmt.Errorf("invalid prober config for %v, the port must be int type", path)
-		}
-		localAddr := UpstreamLocalAddressIPv4
-		if config.IPv6 {
-			localAddr = UpstreamLocalAddressIPv6
-		}
-		d := &net.Dialer{
-			LocalAddr: localAddr,
-		}
-		// Construct a http client and cache it in order to reuse the connection.
-		s.appProbeClient[path] = &http.Client{
-			Timeout: time.Duration(prober.TimeoutSeconds) * time.Second,
-			// We skip the verification since kubelet skips the verification for HTTPS prober as well
-			// https://kubernetes.io/docs/tasks/configure-pod-container/configure-liveness-readiness-probes/#configure-probes
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-				DialContext:     d.DialContext,
-			},
+		switch {
+		case prober.HTTPGet != nil:
+			if prober.HTTPGet.Port.Type != intstr.Int {
+				return nil, fmt.Errorf("invalid prober config for %v, the port must be int type", path)
+			}
+			// Construct a http client and cache it in order to reuse the connection.
+			s.appProbeClient[path] = &http.Client{
+				Timeout: time.Duration(prober.TimeoutSeconds) * time.Second,
+				// We skip the verification since kubelet skips the verification for HTTPS prober as well
+				// https://kubernetes.io/docs/tasks/configure-pod-container/configure-liveness-readiness-probes/#configure-probes
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+					DialContext:     s.appProbeDialer.DialContext,
+				},
+			}
+		case prober.TCPSocket != nil:
+			if prober.TCPSocket.Port.Type != intstr.Int {
+				return nil, fmt.Errorf("invalid prober config for %v, the port must be int type", path)
+			}
+		case prober.GRPC != nil:
+			if prober.GRPC.Port <= 0 || prober.GRPC.Port > 65535 {
+				return nil, fmt.Errorf("invalid prober config for %v, the port must be in the range 1 to 65535", path)
+			}
+		default:
+			return nil, fmt.Errorf(`invalid prober type, must be of type httpGet, tcpSocket or grpc`)
 		}
 	}
 
@@ -519,6 +534,17 @@ func (s *Server) handleAppProbe(w http.ResponseWriter, req *http.Request) {
 		_, _ = w.Write([]byte(fmt.Sprintf("app prober config does not exists for %v", path)))
 		return
 	}
+	switch {
+	case prober.TCPSocket != nil:
+		s.handleAppProbeTCPSocket(w, prober)
+	case prober.GRPC != nil:
+		s.handleAppProbeGRPC(w, req, prober)
+	default:
+		s.handleAppProbeHTTPGet(w, req, prober, path)
+	}
+}
+
+func (s *Server) handleAppProbeHTTPGet(w http.ResponseWriter, req *http.Request, prober *Prober, path string) {
 	// get the http client must exist because
 	httpClient := s.appProbeClient[path]
 
@@ -579,6 +605,67 @@ func (s *Server) handleAppProbe(w http.ResponseWriter, req *http.Request) {
 	w.WriteHeader(response.StatusCode)
 }
 
+// probeTimeout returns the timeout of a prober, which defaults to 1 second as in Kubernetes.
+func probeTimeout(prober *Prober) time.Duration {
+	if prober.TimeoutSeconds <= 0 {
+		return time.Second
+	}
+	return time.Duration(prober.TimeoutSeconds) * time.Second
+}
+
+func (s *Server) handleAppProbeTCPSocket(w http.ResponseWriter, prober *Prober) {
+	port := prober.TCPSocket.Port.IntValue()
+	timeout := probeTimeout(prober)
+	d := &net.Dialer{
+		LocalAddr: s.appProbeDialer.LocalAddr,
+		Timeout:   timeout,
+	}
+	conn, err := d.Dial("tcp", net.JoinHostPort(s.appProbersDestination, strconv.Itoa(port)))
+	if err != nil {
+		log.Errorf("Failed to connect to app port %d for TCP probe: %v", port, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = conn.Close()
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleAppProbeGRPC(w http.ResponseWriter, req *http.Request, prober *Prober) {
+	timeout := probeTimeout(prober)
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	defer cancel()
+	addr := net.JoinHostPort(s.appProbersDestination, strconv.Itoa(int(prober.GRPC.Port)))
+	conn, err := grpc.DialContext(ctx, addr,
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return s.appProbeDialer.DialContext(ctx, "tcp", addr)
+		}))
+	if err != nil {
+		log.Errorf("Failed to connect to app port %d for gRPC probe: %v", prober.GRPC.Port, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer conn.Close()
+
+	var service string
+	if prober.GRPC.Service != nil {
+		service = *prober.GRPC.Service
+	}
+	resp, err := ghc.NewHealthClient(conn).Check(ctx, &ghc.HealthCheckRequest{Service: service})
+	if err != nil {
+		log.Errorf("gRPC health check of app port %d failed: %v", prober.GRPC.Port, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if resp.GetStatus() != ghc.HealthCheckResponse_SERVING {
+		log.Debugf("gRPC health check of app port %d returned %v", prober.GRPC.Port, resp.GetStatus())
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
 // notifyExit sends SIGTERM to itself
 func notifyExit() {
 	p, err := os.FindProcess(os.Getpid())
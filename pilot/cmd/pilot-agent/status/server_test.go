@@ -32,6 +32,9 @@ import (
 	"time"
 
 	"github.com/prometheus/common/expfmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	ghc "google.golang.org/grpc/health/grpc_health_v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"istio.io/istio/pilot/cmd/pilot-agent/status/ready"
@@ -86,9 +89,19 @@ func TestNewServer(t *testing.T) {
 		},
 		// invalid probe type
 		{
-			probe: `{"/app-health/hello-world/readyz": {"tcpSocket": {"port": "8888"}}}`,
+			probe: `{"/app-health/hello-world/readyz": {"exec": {"command": ["cat", "/tmp/healthy"]}}}`,
 			err:   "invalid prober type",
 		},
+		// TCP port is not Int typed.
+		{
+			probe: `{"/app-health/hello-world/readyz": {"tcpSocket": {"port": "8888"}}}`,
+			err:   "must be int type",
+		},
+		// gRPC port is out of range.
+		{
+			probe: `{"/app-health/hello-world/readyz": {"grpc": {"port": 0}}}`,
+			err:   "must be in the range",
+		},
 		// Port is not Int typed.
 		{
 			probe: `{"/app-health/hello-world/readyz": {"httpGet": {"path": "/hello/sunnyvale", "port": "container-port-dontknow"}}}`,
@@ -109,6 +122,11 @@ func TestNewServer(t *testing.T) {
 			probe: `{"/app-health/hello-world/readyz": {"httpGet": {"path": "/hello/sunnyvale", "port": 8080}},
 "/app-health/business/livez": {"httpGet": {"port": 9090}}}`,
 		},
+		// A valid input with TCP and gRPC probers.
+		{
+			probe: `{"/app-health/hello-world/readyz": {"tcpSocket": {"port": 8080}},` +
+				`"/app-health/business/livez": {"grpc": {"port": 9090, "service": "business"}}}`,
+		},
 		// A valid input without any prober info.
 		{
 			probe: `{}`,
@@ -484,6 +502,112 @@ func TestAppProbe(t *testing.T) {
 	}
 }
 
+func TestTCPAndGRPCAppProbe(t *testing.T) {
+	// Starts the applications first.
+	tcpListener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("failed to allocate unused port %v", err)
+	}
+	defer tcpListener.Close()
+	go http.Serve(tcpListener, &handler{})
+	tcpPort := tcpListener.Addr().(*net.TCPAddr).Port
+
+	// Allocate a port and release it, so that nothing listens on it.
+	closedListener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("failed to allocate unused port %v", err)
+	}
+	closedPort := closedListener.Addr().(*net.TCPAddr).Port
+	closedListener.Close()
+
+	grpcListener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("failed to allocate unused port %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("not-serving", ghc.HealthCheckResponse_NOT_SERVING)
+	ghc.RegisterHealthServer(grpcServer, healthServer)
+	go grpcServer.Serve(grpcListener)
+	defer grpcServer.Stop()
+	grpcPort := int32(grpcListener.Addr().(*net.TCPAddr).Port)
+
+	service := func(s string) *string { return &s }
+	testCases := []struct {
+		name       string
+		prober     *Prober
+		statusCode int
+	}{
+		{
+			name:       "tcp",
+			prober:     &Prober{TCPSocket: &apimirror.TCPSocketAction{Port: intstr.FromInt(tcpPort)}},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "tcp closed port",
+			prober:     &Prober{TCPSocket: &apimirror.TCPSocketAction{Port: intstr.FromInt(closedPort)}},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "grpc",
+			prober:     &Prober{GRPC: &apimirror.GRPCAction{Port: grpcPort}},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "grpc not serving",
+			prober:     &Prober{GRPC: &apimirror.GRPCAction{Port: grpcPort, Service: service("not-serving")}},
+			statusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "grpc unknown service",
+			prober:     &Prober{GRPC: &apimirror.GRPCAction{Port: grpcPort, Service: service("unknown")}},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "grpc closed port",
+			prober:     &Prober{GRPC: &apimirror.GRPCAction{Port: int32(closedPort)}},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			appProber, err := json.Marshal(KubeAppProbers{"/app-health/hello-world/readyz": tc.prober})
+			if err != nil {
+				t.Fatalf("invalid app probers")
+			}
+			server, err := NewServer(Options{KubeAppProbers: string(appProber)})
+			if err != nil {
+				t.Fatalf("failed to create status server %v", err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go server.Run(ctx)
+
+			var statusPort uint16
+			if err := retry.UntilSuccess(func() error {
+				server.mutex.RLock()
+				statusPort = server.statusPort
+				server.mutex.RUnlock()
+				if statusPort == 0 {
+					return fmt.Errorf("no port allocated")
+				}
+				return nil
+			}); err != nil {
+				t.Fatalf("failed to getport: %v", err)
+			}
+
+			resp, err := http.Get(fmt.Sprintf("http://localhost:%v/app-health/hello-world/readyz", statusPort))
+			if err != nil {
+				t.Fatal("request failed: ", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tc.statusCode {
+				t.Errorf("unexpected status code, want = %v, got = %v", tc.statusCode, resp.StatusCode)
+			}
+		})
+	}
+}
+
 func TestHttpsAppProbe(t *testing.T) {
 	// Starts the application first.
 	listener, err := net.Listen("tcp", ":0")
@@ -54,27 +54,28 @@ const (
 	// k8s:// - load in-cluster k8s controller
 	// example k8s://
 	Kubernetes ConfigSourceAddressScheme = "k8s"
+	// consul://ADDRESS - watch a Consul-compatible catalog as a service registry
+	// example consul://127.0.0.1:8500?dc=dc1
+	// This is not a config store, the registry is created with the other service registries.
+	Consul ConfigSourceAddressScheme = "consul"
+	// peer://ADDRESS - watch the services exported by the istiod of another cluster as a service registry
+	// example peer://istiod.istio-system.cluster2.example.com:15012?cluster=cluster2
+	// This is not a config store, the registry is created with the other service registries.
+	Peer ConfigSourceAddressScheme = "peer"
 )
 
 // initConfigController creates the config controller in the pilotConfig.
 func (s *Server) initConfigController(args *PilotArgs) error {
 	s.initStatusController(args, features.EnableStatus)
 	meshConfig := s.environment.Mesh()
-	if len(meshConfig.ConfigSources) > 0 {
+	if hasConfigStoreSources(meshConfig) {
 		// Using MCP for config.
 		if err := s.initConfigSources(args); err != nil {
 			return err
 		}
 	} else if args.RegistryOptions.FileDir != "" {
 		// Local files - should be added even if other options are specified
-		store := memory.Make(collections.Pilot)
-		configController := memory.NewController(store)
-
-		err := s.makeFileMonitor(args.RegistryOptions.FileDir, args.RegistryOptions.KubeOptions.DomainSuffix, configController)
-		if err != nil {
-			return err
-		}
-		s.ConfigStores = append(s.ConfigStores, configController)
+		s.initFileConfigSource(args.RegistryOptions.FileDir, args.RegistryOptions.KubeOptions.DomainSuffix)
 	} else {
 		err2 := s.initK8SConfigStore(args)
 		if err2 != nil {
@@ -188,14 +189,7 @@ func (s *Server) initConfigSources(args *PilotArgs) (err error) {
 			if srcAddress.Path == "" {
 				return fmt.Errorf("invalid fs config URL %s, contains no file path", configSource.Address)
 			}
-			store := memory.Make(collections.Pilot)
-			configController := memory.NewController(store)
-
-			err := s.makeFileMonitor(srcAddress.Path, args.RegistryOptions.KubeOptions.DomainSuffix, configController)
-			if err != nil {
-				return err
-			}
-			s.ConfigStores = append(s.ConfigStores, configController)
+			s.initFileConfigSource(srcAddress.Path, args.RegistryOptions.KubeOptions.DomainSuffix)
 		case XDS:
 			xdsMCP, err := adsc.New(srcAddress.Host, &adsc.Config{
 				Meta: model.NodeMetadata{
@@ -228,6 +222,8 @@ func (s *Server) initConfigSources(args *PilotArgs) (err error) {
 				// TODO: handle k8s:// scheme for remote cluster. Use same mechanism as service registry,
 				// using the cluster name as key to match a secret.
 			}
+		case Consul, Peer:
+			// Service registry, initialized by initServiceControllers.
 		default:
 			log.Warnf("Ignoring unsupported config source: %v", configSource.Address)
 		}
@@ -235,6 +231,20 @@ func (s *Server) initConfigSources(args *PilotArgs) (err error) {
 	return nil
 }
 
+// hasConfigStoreSources returns true if any of the mesh config 'configSources' is a config store,
+// as opposed to a service registry.
+func hasConfigStoreSources(meshConfig *meshconfig.MeshConfig) bool {
+	for _, configSource := range meshConfig.ConfigSources {
+		if srcAddress, err := url.Parse(configSource.Address); err == nil {
+			if scheme := ConfigSourceAddressScheme(srcAddress.Scheme); scheme == Consul || scheme == Peer {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
 // initInprocessAnalysisController spins up an instance of Galley which serves no purpose other than
 // running Analyzers for status updates.  The Status Updater will eventually need to allow input from istiod
 // to support config distribution status as well.
@@ -301,6 +311,17 @@ func (s *Server) initStatusController(args *PilotArgs, writeStatus bool) {
 	if writeStatus {
 		s.addTerminatingStartFunc(func(stop <-chan struct{}) error {
 			controller := status.NewController(*s.kubeRestConfig, args.Namespace, s.RWConfigStore)
+			if features.EnableStatusSharding {
+				// every replica writes the status of its share of the resources, no leader is needed.
+				controller.EnableSharding(args.PodName)
+				s.statusReporter.SetController(controller)
+				controller.Start(stop)
+				leaderelection.
+					NewReplicas(args.Namespace, args.PodName, leaderelection.StatusShards, s.kubeClient).
+					AddHandler(controller.SetReplicas).
+					Run(stop)
+				return nil
+			}
 			leaderelection.
 				NewLeaderElection(args.Namespace, args.PodName, leaderelection.StatusController, s.kubeClient).
 				AddRunFunction(func(stop <-chan struct{}) {
@@ -320,9 +341,16 @@ func (s *Server) makeKubeConfigController(args *PilotArgs) (model.ConfigStoreCac
 	return c, nil
 }
 
-func (s *Server) makeFileMonitor(fileDir string, domainSuffix string, configController model.ConfigStore) error {
+// initFileConfigSource adds a config store holding the configs of the files in fileDir, watched recursively.
+// The errors found in the files are exposed by the /debug/config_filez endpoint.
+func (s *Server) initFileConfigSource(fileDir string, domainSuffix string) {
+	store := memory.Make(collections.Pilot)
+	configController := memory.NewController(store)
+
 	fileSnapshot := configmonitor.NewFileSnapshot(fileDir, collections.Pilot, domainSuffix)
 	fileMonitor := configmonitor.NewMonitor("file-monitor", configController, fileSnapshot.ReadConfigFiles, fileDir)
+	s.fileSnapshots = append(s.fileSnapshots, fileSnapshot)
+	s.XDSServer.ConfigFileErrors = s.configFileErrors
 
 	// Defer starting the file monitor until after the service is created.
 	s.addStartFunc(func(stop <-chan struct{}) error {
@@ -330,5 +358,16 @@ func (s *Server) makeFileMonitor(fileDir string, domainSuffix string, configCont
 		return nil
 	})
 
-	return nil
+	s.ConfigStores = append(s.ConfigStores, configController)
+}
+
+// configFileErrors returns the errors found in the files of all the file config sources, keyed by path.
+func (s *Server) configFileErrors() map[string][]string {
+	out := map[string][]string{}
+	for _, snapshot := range s.fileSnapshots {
+		for path, errs := range snapshot.Errors() {
+			out[path] = append(out[path], errs...)
+		}
+	}
+	return out
 }
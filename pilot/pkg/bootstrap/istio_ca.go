@@ -16,6 +16,7 @@ package bootstrap
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -26,20 +27,25 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
 
 	"istio.io/api/security/v1beta1"
 	"istio.io/istio/pilot/pkg/features"
+	"istio.io/istio/pilot/pkg/model"
 	securityModel "istio.io/istio/pilot/pkg/security/model"
 	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/jwt"
 	kubelib "istio.io/istio/pkg/kube"
+	"istio.io/istio/pkg/kube/configmapwatcher"
 	"istio.io/istio/pkg/security"
 	"istio.io/istio/security/pkg/cmd"
 	"istio.io/istio/security/pkg/pki/ca"
 	"istio.io/istio/security/pkg/pki/ra"
+	"istio.io/istio/security/pkg/pki/util"
 	caserver "istio.io/istio/security/pkg/server/ca"
 	"istio.io/istio/security/pkg/server/ca/authenticate"
 	"istio.io/pkg/env"
@@ -137,6 +143,17 @@ var (
 	// TODO: Likely to be removed and added to mesh config
 	k8sSigner = env.RegisterStringVar("K8S_SIGNER", "",
 		"Kubernates CA Signer type. Valid from Kubernates 1.18").Get()
+
+	// TODO: Likely to be removed and added to mesh config
+	externalCaAddress = env.RegisterStringVar("EXTERNAL_CA_ADDRESS", "",
+		"Address of the external signer, required when EXTERNAL_CA is ISTIOD_RA_ISTIO_API").Get()
+
+	externalCaTimeout = env.RegisterDurationVar("EXTERNAL_CA_TIMEOUT", ra.DefaultSignerTimeout,
+		"Timeout of each request to the external signer when EXTERNAL_CA is ISTIOD_RA_ISTIO_API").Get()
+
+	externalCaRetries = env.RegisterIntVar("EXTERNAL_CA_RETRIES", 3,
+		"Number of times a failed request to the external signer is retried when EXTERNAL_CA is "+
+			"ISTIOD_RA_ISTIO_API").Get()
 )
 
 // EnableCA returns whether CA functionality is enabled in istiod.
@@ -368,12 +385,107 @@ func (s *Server) createIstioRA(client kubelib.Client,
 		CaSigner:       opts.ExternalCASigner,
 		CaCertFile:     caCertFile,
 		VerifyAppendCA: true,
-		K8sClient:      client.CertificatesV1beta1(),
 		TrustDomain:    opts.TrustDomain,
 	}
+	if client != nil {
+		raOpts.K8sClient = client.CertificatesV1beta1()
+	}
+	if opts.ExternalCAType == ra.ExtCAGrpc {
+		// The client certificate is expected to be mounted from a kubernetes.io/tls secret.
+		raOpts.SignerAddr = externalCaAddress
+		raOpts.SignerClientCertFile = path.Join(ra.DefaultExtCAClientCertDir, "tls.crt")
+		raOpts.SignerClientKeyFile = path.Join(ra.DefaultExtCAClientCertDir, "tls.key")
+		raOpts.SignerTimeout = externalCaTimeout
+		raOpts.SignerRetries = externalCaRetries
+	}
 	return ra.NewIstioRA(raOpts)
 }
 
+// initCARevocationList watches the revocation list of the Istio CA, stored in a ConfigMap of the CA namespace.
+// The CA refuses to sign certificates for the revoked identities, and the proxies deny their requests by principal.
+// The CRL of the revoked serial numbers is pushed to the proxies so they reject the revoked peer certificates.
+func (s *Server) initCARevocationList(namespace string) {
+	if s.CA == nil || s.RA != nil || s.kubeClient == nil {
+		return
+	}
+	s.environment.CertificateRevocationList = &model.CertificateRevocationList{}
+	s.environment.RevokedIdentities = &model.RevokedIdentities{}
+	c := configmapwatcher.NewController(s.kubeClient, namespace, ca.RevocationListConfigMap, func(cm *v1.ConfigMap) {
+		rl := ca.NewRevocationList()
+		if cm != nil {
+			var err error
+			if rl, err = ca.ParseRevocationList(cm.Data[ca.RevocationListKey]); err == nil {
+				rl.CRLs, err = ca.ParseCRLs(cm.Data[ca.RevocationListCRLsKey])
+			}
+			if err != nil {
+				// Keep the last known list in case there's a misconfiguration issue.
+				log.Errorf("failed to read the CA revocation list from ConfigMap %s/%s: %v",
+					namespace, ca.RevocationListConfigMap, err)
+				return
+			}
+		}
+		log.Infof("CA revocation list updated: %d identities and %d serial numbers revoked", len(rl.Identities), len(rl.Serials))
+		s.CA.UpdateRevocationList(rl)
+		identitiesChanged := s.environment.RevokedIdentities.Set(rl.RevokedIdentities())
+		if s.updateCertificateRevocationList() || identitiesChanged {
+			s.pushRevocationList()
+		}
+	})
+	s.addStartFunc(func(stop <-chan struct{}) error {
+		go c.Run(stop)
+		// The CRL is signed again when the CA cert is rotated.
+		if interval := selfSignedRootCertCheckInterval.Get(); interval > 0 {
+			go func() {
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ticker.C:
+						if s.updateCertificateRevocationList() {
+							s.pushRevocationList()
+						}
+					case <-stop:
+						return
+					}
+				}
+			}()
+		}
+		// Do not sign certificates before the revoked identities are known.
+		cache.WaitForCacheSync(stop, c.HasSynced)
+		return nil
+	})
+}
+
+// updateCertificateRevocationList updates the CRLs pushed to the proxies, and returns true if they changed.
+// With multiple trust anchors, the proxies also need a CRL for each of the other trust anchors.
+func (s *Server) updateCertificateRevocationList() bool {
+	var trustAnchors []*x509.Certificate
+	if features.MultiRootMesh.Get() {
+		for _, anchor := range s.workloadTrustBundle.GetTrustBundle() {
+			cert, err := util.ParsePemEncodedCertificate([]byte(anchor))
+			if err != nil {
+				log.Warnf("failed to parse trust anchor: %v", err)
+				continue
+			}
+			trustAnchors = append(trustAnchors, cert)
+		}
+	}
+	crl, err := s.CA.CertificateRevocationList(trustAnchors)
+	if err != nil {
+		log.Warnf("failed to generate the CA revocation list, revoked certificates are accepted by the proxies "+
+			"until they expire: %v", err)
+	}
+	return s.environment.CertificateRevocationList.Set(crl)
+}
+
+// pushRevocationList pushes the revoked identities and the CRLs to the proxies.
+func (s *Server) pushRevocationList() {
+	s.XDSServer.ConfigUpdate(&model.PushRequest{
+		Full:   true,
+		Reason: []model.TriggerReason{model.GlobalUpdate},
+	})
+}
+
 // getJwtPath returns jwt path.
 func getJwtPath() string {
 	log.Info("JWT policy is ", features.JwtPolicy.Get())
@@ -41,6 +41,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 
 	"istio.io/api/security/v1beta1"
+	configmonitor "istio.io/istio/pilot/pkg/config/monitor"
 	"istio.io/istio/pilot/pkg/features"
 	istiogrpc "istio.io/istio/pilot/pkg/grpc"
 	"istio.io/istio/pilot/pkg/keycertbundle"
@@ -121,6 +122,8 @@ type Server struct {
 	configController  model.ConfigStoreCache
 	ConfigStores      []model.ConfigStoreCache
 	serviceEntryStore *serviceentry.ServiceEntryStore
+	// fileSnapshots read the files of the file config sources.
+	fileSnapshots []*configmonitor.FileSnapshot
 
 	httpServer       *http.Server // debug, monitoring and readiness Server.
 	httpsServer      *http.Server // webhooks HTTPS Server.
@@ -265,6 +268,9 @@ func NewServer(args *PilotArgs, initFuncs ...func(*Server)) (*Server, error) {
 		return nil, err
 	}
 
+	// The revocation list of the CA is pushed to the proxies, so the XDS server must be initialized.
+	s.initCARevocationList(args.Namespace)
+
 	// Parse and validate Istiod Address.
 	istiodHost, _, err := e.GetDiscoveryAddress()
 	if err != nil {
@@ -528,7 +534,7 @@ func (s *Server) initKubeClient(args *PilotArgs) error {
 			if err != nil {
 				return fmt.Errorf("failed reading mesh config: %v", err)
 			}
-			if len(meshConfig.ConfigSources) == 0 && args.RegistryOptions.KubeConfig != "" {
+			if !hasConfigStoreSources(meshConfig) && args.RegistryOptions.KubeConfig != "" {
 				hasK8SConfigStore = true
 			}
 			for _, cs := range meshConfig.ConfigSources {
@@ -1114,6 +1120,10 @@ func (s *Server) initWorkloadTrustBundle(args *PilotArgs) error {
 	}
 
 	s.workloadTrustBundle.UpdateCb(func() {
+		if s.environment.CertificateRevocationList != nil {
+			// The proxies need a CRL for each trust anchor.
+			s.updateCertificateRevocationList()
+		}
 		pushReq := &model.PushRequest{
 			Full:   true,
 			Reason: []model.TriggerReason{model.GlobalUpdate},
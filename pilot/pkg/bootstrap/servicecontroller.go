@@ -15,13 +15,28 @@
 package bootstrap
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/serviceregistry"
 	"istio.io/istio/pilot/pkg/serviceregistry/aggregate"
+	"istio.io/istio/pilot/pkg/serviceregistry/consul"
 	kubecontroller "istio.io/istio/pilot/pkg/serviceregistry/kube/controller"
 	"istio.io/istio/pilot/pkg/serviceregistry/mock"
+	"istio.io/istio/pilot/pkg/serviceregistry/peer"
 	"istio.io/istio/pilot/pkg/serviceregistry/serviceentry"
 	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/kube/secretcontroller"
@@ -63,6 +78,13 @@ func (s *Server) initServiceControllers(args *PilotArgs) error {
 		}
 	}
 
+	if err := s.initConsulRegistries(); err != nil {
+		return err
+	}
+	if err := s.initPeerRegistries(args); err != nil {
+		return err
+	}
+
 	// Defer running of the service controllers.
 	s.addStartFunc(func(stop <-chan struct{}) error {
 		go serviceControllers.Run(stop)
@@ -135,3 +157,145 @@ func (s *Server) initMockRegistry() {
 
 	s.ServiceController().AddRegistry(registry)
 }
+
+// initConsulRegistries creates a service registry for each catalog listed in the mesh config 'configSources',
+// with an address like consul://127.0.0.1:8500?dc=dc1. TLS is used if the config source has TLS settings.
+func (s *Server) initConsulRegistries() error {
+	for _, configSource := range s.environment.Mesh().ConfigSources {
+		srcAddress, err := url.Parse(configSource.Address)
+		if err != nil || ConfigSourceAddressScheme(srcAddress.Scheme) != Consul {
+			continue
+		}
+		if srcAddress.Host == "" {
+			return fmt.Errorf("invalid consul config URL %s, contains no host", configSource.Address)
+		}
+		httpClient, scheme, err := consulHTTPClient(configSource.TlsSettings)
+		if err != nil {
+			return fmt.Errorf("invalid TLS settings of %s: %v", configSource.Address, err)
+		}
+		log.Infof("Adding %s registry adapter for %s", serviceregistry.Consul, srcAddress.Host)
+		s.ServiceController().AddRegistry(consul.NewController(consul.Options{
+			Address:    scheme + "://" + srcAddress.Host,
+			Datacenter: srcAddress.Query().Get("dc"),
+			Token:      features.ConsulToken,
+			ClusterID:  srcAddress.Host,
+			HTTPClient: httpClient,
+			XDSUpdater: s.XDSServer,
+		}))
+	}
+	return nil
+}
+
+// initPeerRegistries creates a service registry for each peer istiod listed in the mesh config 'configSources',
+// with an address like peer://istiod.istio-system.cluster2.example.com:15012?cluster=cluster2, and registers the
+// generator exporting the services of this cluster to the peers allowed by PILOT_PEER_DISCOVERY_IDENTITIES.
+func (s *Server) initPeerRegistries(args *PilotArgs) error {
+	var identities []string
+	for _, id := range strings.Split(features.PeerDiscoveryIdentities, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			identities = append(identities, id)
+		}
+	}
+	s.XDSServer.Generators[peer.GeneratorName] = peer.NewGenerator(s.ServiceController(), s.clusterID, identities)
+
+	for _, configSource := range s.environment.Mesh().ConfigSources {
+		srcAddress, err := url.Parse(configSource.Address)
+		if err != nil || ConfigSourceAddressScheme(srcAddress.Scheme) != Peer {
+			continue
+		}
+		if srcAddress.Host == "" {
+			return fmt.Errorf("invalid peer config URL %s, contains no host", configSource.Address)
+		}
+		clusterID := srcAddress.Query().Get("cluster")
+		if clusterID == "" {
+			clusterID = srcAddress.Host
+		}
+		if clusterID == s.clusterID {
+			return fmt.Errorf("invalid peer config URL %s, the peer cluster is the local cluster %s", configSource.Address, clusterID)
+		}
+		tlsConfig, err := clientTLSConfig(configSource.TlsSettings)
+		if err != nil {
+			return fmt.Errorf("invalid TLS settings of %s: %v", configSource.Address, err)
+		}
+		var dialOptions []grpc.DialOption
+		if tlsConfig != nil {
+			dialOptions = append(dialOptions, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+		} else {
+			log.Warnf("peer %s has no TLS settings, the connection is not authenticated", configSource.Address)
+		}
+		log.Infof("Adding peer registry adapter for %s in cluster %s", srcAddress.Host, clusterID)
+		s.ServiceController().AddRegistry(peer.NewController(peer.Options{
+			Address:        srcAddress.Host,
+			ClusterID:      clusterID,
+			LocalClusterID: s.clusterID,
+			Network:        localNetwork(s.environment.NetworksWatcher.Networks(), s.clusterID),
+			Namespace:      args.Namespace,
+			DialOptions:    dialOptions,
+			XDSUpdater:     s.XDSServer,
+		}))
+	}
+	return nil
+}
+
+// localNetwork returns the network whose endpoints are from the registry of the cluster in the mesh networks, or
+// an empty string if there is none.
+func localNetwork(meshNetworks *meshconfig.MeshNetworks, clusterID string) string {
+	if meshNetworks == nil {
+		return ""
+	}
+	networks := make([]string, 0, len(meshNetworks.Networks))
+	for n := range meshNetworks.Networks {
+		networks = append(networks, n)
+	}
+	// Sorted so that the result is stable if several networks list the cluster.
+	sort.Strings(networks)
+	for _, n := range networks {
+		for _, ep := range meshNetworks.Networks[n].Endpoints {
+			if ep.GetFromRegistry() == clusterID {
+				return n
+			}
+		}
+	}
+	return ""
+}
+
+// consulHTTPClient returns the client and URL scheme used to query a catalog with the given TLS settings.
+func consulHTTPClient(settings *networking.ClientTLSSettings) (*http.Client, string, error) {
+	tlsConfig, err := clientTLSConfig(settings)
+	if err != nil {
+		return nil, "", err
+	}
+	if tlsConfig == nil {
+		return http.DefaultClient, "http", nil
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}, "https", nil
+}
+
+// clientTLSConfig returns the TLS config of a config source, or nil if TLS is disabled.
+func clientTLSConfig(settings *networking.ClientTLSSettings) (*tls.Config, error) {
+	if settings == nil || settings.Mode == networking.ClientTLSSettings_DISABLE {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		ServerName: settings.Sni,
+		MinVersion: tls.VersionTLS12,
+	}
+	if settings.CaCertificates != "" {
+		caCert, err := ioutil.ReadFile(settings.CaCertificates)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA certificates %s", settings.CaCertificates)
+		}
+	}
+	if settings.Mode == networking.ClientTLSSettings_MUTUAL {
+		cert, err := tls.LoadX509KeyPair(settings.ClientCertificate, settings.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -215,3 +215,8 @@ func parseInputsImpl(inputs string, withValidate bool) ([]config.Config, []Istio
 func ParseInputs(inputs string) ([]config.Config, []IstioKind, error) {
 	return parseInputsImpl(inputs, true)
 }
+
+// ParseInputsWithoutValidation is the same as ParseInputs, without validating the configs against their schema.
+func ParseInputsWithoutValidation(inputs string) ([]config.Config, []IstioKind, error) {
+	return parseInputsImpl(inputs, false)
+}
@@ -302,38 +302,31 @@ func buildHTTPVirtualServices(obj config.Config, gateways []string, domain strin
 	result := []config.Config{}
 
 	route := obj.Spec.(*k8s.HTTPRouteSpec)
-	obj.Status.(*kstatus.WrappedStatus).Mutate(func(s config.Status) config.Status {
-		rs := s.(*k8s.HTTPRouteStatus)
-		// TODO report skipped routes
-		rs.Gateways = createRouteStatus(gateways, obj)
-		return rs
-	})
 
 	name := fmt.Sprintf("%s-%s", obj.Name, constants.KubernetesGatewayName)
 
 	httproutes := []*istio.HTTPRoute{}
 	hosts := hostnameToStringList(route.Hostnames)
-	for _, r := range route.Rules {
-		// TODO: implement redirect, rewrite, timeout, mirror, corspolicy, retries
-		vs := &istio.HTTPRoute{}
-		for _, match := range r.Matches {
-			vs.Match = append(vs.Match, &istio.HTTPMatchRequest{
-				Uri:     createURIMatch(match),
-				Headers: createHeadersMatch(match),
-			})
-		}
-		for _, filter := range r.Filters {
-			switch filter.Type {
-			case k8s.HTTPRouteFilterRequestHeaderModifier:
-				vs.Headers = createHeadersFilter(filter.RequestHeaderModifier)
-			default:
-				log.Warnf("unsupported filter type %q", filter.Type)
-			}
+	// Rules using a field we cannot translate are skipped as a whole and reported in the route status,
+	// as partially applying them could route requests they do not match.
+	ignored := []string{}
+	for i, r := range route.Rules {
+		vs, err := buildHTTPRoute(r, obj.Namespace, domain)
+		if err != nil {
+			log.Warnf("ignoring rule %d of HTTPRoute %s/%s: %v", i, obj.Namespace, obj.Name, err)
+			ignored = append(ignored, fmt.Sprintf("rule %d: %v", i, err))
+			continue
 		}
-
-		vs.Route = buildHTTPDestination(r.ForwardTo, obj.Namespace, domain)
 		httproutes = append(httproutes, vs)
 	}
+	obj.Status.(*kstatus.WrappedStatus).Mutate(func(s config.Status) config.Status {
+		rs := s.(*k8s.HTTPRouteStatus)
+		rs.Gateways = createRouteStatus(gateways, obj, ignored, len(httproutes) == 0)
+		return rs
+	})
+	if len(httproutes) == 0 && len(route.Rules) > 0 {
+		return result
+	}
 	vsConfig := config.Config{
 		Meta: config.Meta{
 			CreationTimestamp: obj.CreationTimestamp,
@@ -352,7 +345,70 @@ func buildHTTPVirtualServices(obj config.Config, gateways []string, domain strin
 	return result
 }
 
-func createRouteStatus(gateways []string, obj config.Config) []k8s.RouteGatewayStatus {
+// buildHTTPRoute converts a rule of an HTTPRoute. An error is returned if the rule uses a field which is not supported.
+func buildHTTPRoute(r k8s.HTTPRouteRule, ns string, domain string) (*istio.HTTPRoute, error) {
+	// TODO: implement timeout, corspolicy, retries
+	vs := &istio.HTTPRoute{}
+	for _, match := range r.Matches {
+		if match.ExtensionRef != nil {
+			return nil, fmt.Errorf("unsupported match extensionRef %s/%s", match.ExtensionRef.Kind, match.ExtensionRef.Name)
+		}
+		uri, err := createURIMatch(match)
+		if err != nil {
+			return nil, err
+		}
+		headers, err := createHeadersMatch(match)
+		if err != nil {
+			return nil, err
+		}
+		vs.Match = append(vs.Match, &istio.HTTPMatchRequest{
+			Uri:     uri,
+			Headers: headers,
+		})
+	}
+	for _, filter := range r.Filters {
+		switch filter.Type {
+		case k8s.HTTPRouteFilterRequestHeaderModifier:
+			vs.Headers = createHeadersFilter(filter.RequestHeaderModifier)
+		case k8s.HTTPRouteFilterRequestMirror:
+			mirror, err := createMirrorFilter(filter.RequestMirror, ns, domain)
+			if err != nil {
+				return nil, err
+			}
+			vs.Mirror = mirror
+		default:
+			return nil, fmt.Errorf("unsupported filter type %q", filter.Type)
+		}
+	}
+
+	route, err := buildHTTPDestination(r.ForwardTo, ns, domain)
+	if err != nil {
+		return nil, err
+	}
+	vs.Route = route
+	return vs, nil
+}
+
+// createRouteStatus reports the route as admitted by each gateway. ignored lists the rules which could not be
+// converted; the route is rejected if none of its rules could.
+func createRouteStatus(gateways []string, obj config.Config, ignored []string, rejected bool) []k8s.RouteGatewayStatus {
+	cond := metav1.Condition{
+		Type:               string(k8s.ConditionRouteAdmitted),
+		Status:             kstatus.StatusTrue,
+		ObservedGeneration: obj.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             "RouteAdmitted",
+		Message:            "Route admitted",
+	}
+	if len(ignored) > 0 {
+		cond.Reason = "UnsupportedValue"
+		if rejected {
+			cond.Status = kstatus.StatusFalse
+			cond.Message = "Route rejected, no rule is supported: " + strings.Join(ignored, "; ")
+		} else {
+			cond.Message = "Route partially admitted, ignored unsupported rules: " + strings.Join(ignored, "; ")
+		}
+	}
 	gws := make([]k8s.RouteGatewayStatus, 0, len(gateways))
 	// TODO(https://github.com/kubernetes-sigs/gateway-api/issues/591) this assumes full ownership of route
 	for _, gw := range gateways {
@@ -368,14 +424,7 @@ func createRouteStatus(gateways []string, obj config.Config) []k8s.RouteGatewayS
 		}
 		gws = append(gws, k8s.RouteGatewayStatus{
 			GatewayRef: ref,
-			Conditions: []metav1.Condition{{
-				Type:               string(k8s.ConditionRouteAdmitted),
-				Status:             kstatus.StatusTrue,
-				ObservedGeneration: obj.Generation,
-				LastTransitionTime: metav1.Now(),
-				Reason:             "RouteAdmitted",
-				Message:            "Route admitted",
-			}},
+			Conditions: []metav1.Condition{cond},
 		})
 	}
 	return gws
@@ -395,7 +444,7 @@ func buildTCPVirtualService(obj config.Config, gateways []string, domain string)
 	obj.Status.(*kstatus.WrappedStatus).Mutate(func(s config.Status) config.Status {
 		rs := s.(*k8s.TCPRouteStatus)
 		// TODO report skipped routes
-		rs.Gateways = createRouteStatus(gateways, obj)
+		rs.Gateways = createRouteStatus(gateways, obj, nil, false)
 		return rs
 	})
 
@@ -432,7 +481,7 @@ func buildTLSVirtualService(obj config.Config, gateways []string, domain string)
 	obj.Status.(*kstatus.WrappedStatus).Mutate(func(s config.Status) config.Status {
 		rs := s.(*k8s.TLSRouteStatus)
 		// TODO report skipped routes
-		rs.Gateways = createRouteStatus(gateways, obj)
+		rs.Gateways = createRouteStatus(gateways, obj, nil, false)
 		return rs
 	})
 
@@ -521,9 +570,9 @@ func intSum(n []int) int {
 	return r
 }
 
-func buildHTTPDestination(action []k8s.HTTPRouteForwardTo, ns string, domain string) []*istio.HTTPRouteDestination {
+func buildHTTPDestination(action []k8s.HTTPRouteForwardTo, ns string, domain string) ([]*istio.HTTPRouteDestination, error) {
 	if action == nil {
-		return nil
+		return nil, nil
 	}
 
 	weights := []int{}
@@ -533,7 +582,10 @@ func buildHTTPDestination(action []k8s.HTTPRouteForwardTo, ns string, domain str
 	weights = standardizeWeights(weights)
 	res := []*istio.HTTPRouteDestination{}
 	for i, fwd := range action {
-		dst := buildDestination(fwd, ns, domain)
+		dst, err := buildDestination(fwd.ServiceName, fwd.BackendRef, fwd.Port, ns, domain)
+		if err != nil {
+			return nil, err
+		}
 		rd := &istio.HTTPRouteDestination{
 			Destination: dst,
 			Weight:      int32(weights[i]),
@@ -543,29 +595,30 @@ func buildHTTPDestination(action []k8s.HTTPRouteForwardTo, ns string, domain str
 			case k8s.HTTPRouteFilterRequestHeaderModifier:
 				rd.Headers = createHeadersFilter(filter.RequestHeaderModifier)
 			default:
-				log.Warnf("unsupported filter type %q", filter.Type)
+				// Mirroring is configured per route, not per destination.
+				return nil, fmt.Errorf("unsupported filter type %q in forwardTo", filter.Type)
 			}
 		}
 		res = append(res, rd)
 	}
-	return res
+	return res, nil
 }
 
-func buildDestination(to k8s.HTTPRouteForwardTo, ns, domain string) *istio.Destination {
+func buildDestination(serviceName *string, backendRef *k8s.LocalObjectReference, port *k8s.PortNumber,
+	ns, domain string) (*istio.Destination, error) {
 	res := &istio.Destination{}
-	if to.Port != nil {
+	if port != nil {
 		// TODO: "If unspecified, the destination port in the request is used when forwarding to a backendRef or serviceName."
 		// We need to link up with the gateway and construct a per gateway virtual service. This is not actually
 		// possible with targetPort in some scenarios; need to reconsider the API.
-		res.Port = &istio.PortSelector{Number: uint32(*to.Port)}
+		res.Port = &istio.PortSelector{Number: uint32(*port)}
 	}
-	if to.ServiceName != nil {
-		res.Host = fmt.Sprintf("%s.%s.svc.%s", *to.ServiceName, ns, domain)
-	} else if to.BackendRef != nil {
-		// TODO support this
-		log.Errorf("referencing unsupported destination; backendRef is not supported")
+	if serviceName != nil {
+		res.Host = fmt.Sprintf("%s.%s.svc.%s", *serviceName, ns, domain)
+	} else if backendRef != nil {
+		return nil, fmt.Errorf("unsupported backendRef %s/%s", backendRef.Kind, backendRef.Name)
 	}
-	return res
+	return res, nil
 }
 
 func buildGenericDestination(to k8s.RouteForwardTo, ns, domain string) *istio.Destination {
@@ -653,42 +706,51 @@ func createHeadersFilter(filter *k8s.HTTPRequestHeaderFilter) *istio.Headers {
 	}
 }
 
-func createHeadersMatch(match k8s.HTTPRouteMatch) map[string]*istio.StringMatch {
+func createMirrorFilter(filter *k8s.HTTPRequestMirrorFilter, ns, domain string) (*istio.Destination, error) {
+	if filter == nil {
+		return nil, nil
+	}
+	return buildDestination(filter.ServiceName, filter.BackendRef, filter.Port, ns, domain)
+}
+
+func createHeadersMatch(match k8s.HTTPRouteMatch) (map[string]*istio.StringMatch, error) {
 	if match.Headers == nil {
-		return nil
+		return nil, nil
 	}
 	res := map[string]*istio.StringMatch{}
-	if match.Headers.Type == "" ||
-		match.Headers.Type == k8s.HeaderMatchExact ||
-		match.Headers.Type == k8s.HeaderMatchImplementationSpecific {
-		for k, v := range match.Headers.Values {
+	for k, v := range match.Headers.Values {
+		switch match.Headers.Type {
+		case "", k8s.HeaderMatchExact, k8s.HeaderMatchImplementationSpecific:
 			res[k] = &istio.StringMatch{
 				MatchType: &istio.StringMatch_Exact{Exact: v},
 			}
+		case k8s.HeaderMatchRegularExpression:
+			res[k] = &istio.StringMatch{
+				MatchType: &istio.StringMatch_Regex{Regex: v},
+			}
+		default:
+			return nil, fmt.Errorf("unsupported header match type %q", match.Headers.Type)
 		}
-	} else {
-		log.Warnf("unknown type: %v is not supported Header type", match.Headers.Type)
-		return nil
 	}
-	return res
+	return res, nil
 }
 
-func createURIMatch(match k8s.HTTPRouteMatch) *istio.StringMatch {
-	if match.Path.Type == "" || match.Path.Type == k8s.PathMatchImplementationSpecific || match.Path.Type == k8s.PathMatchPrefix {
+func createURIMatch(match k8s.HTTPRouteMatch) (*istio.StringMatch, error) {
+	switch match.Path.Type {
+	case "", k8s.PathMatchImplementationSpecific, k8s.PathMatchPrefix:
 		return &istio.StringMatch{
 			MatchType: &istio.StringMatch_Prefix{Prefix: match.Path.Value},
-		}
-	} else if match.Path.Type == k8s.PathMatchExact {
+		}, nil
+	case k8s.PathMatchExact:
 		return &istio.StringMatch{
 			MatchType: &istio.StringMatch_Exact{Exact: match.Path.Value},
-		}
-	} else if match.Path.Type == k8s.PathMatchRegularExpression {
+		}, nil
+	case k8s.PathMatchRegularExpression:
 		return &istio.StringMatch{
 			MatchType: &istio.StringMatch_Regex{Regex: match.Path.Value},
-		}
-	} else {
-		log.Warnf("unknown type: %s is not supported Path match type", match.Path.Type)
-		return nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported path match type %q", match.Path.Type)
 	}
 }
 
@@ -46,6 +46,7 @@ func TestConvertResources(t *testing.T) {
 		"weighted",
 		"backendpolicy",
 		"mesh",
+		"unsupported",
 	}
 	for _, tt := range cases {
 		t.Run(tt, func(t *testing.T) {
@@ -15,10 +15,12 @@
 package monitor
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
+	"sync"
 
 	"istio.io/istio/pilot/pkg/config/kube/crd"
 	"istio.io/istio/pkg/config"
@@ -38,6 +40,13 @@ type FileSnapshot struct {
 	root             string
 	domainSuffix     string
 	configTypeFilter map[config.GroupVersionKind]bool
+
+	mutex sync.RWMutex
+	// files holds the configs read from each file. They are kept while the file can't be read or parsed,
+	// so that an incomplete edit does not delete the configs of the file.
+	files map[string][]*config.Config
+	// errors holds the errors of each file found by the last read.
+	errors map[string][]string
 }
 
 // NewFileSnapshot returns a snapshotter.
@@ -47,6 +56,8 @@ func NewFileSnapshot(root string, schemas collection.Schemas, domainSuffix strin
 		root:             root,
 		domainSuffix:     domainSuffix,
 		configTypeFilter: make(map[config.GroupVersionKind]bool),
+		files:            map[string][]*config.Config{},
+		errors:           map[string][]string{},
 	}
 
 	ss := schemas.All()
@@ -65,47 +76,121 @@ func NewFileSnapshot(root string, schemas collection.Schemas, domainSuffix strin
 
 // ReadConfigFiles parses files in the root directory and returns a sorted slice of
 // eligible model.Config. This can be used as a configFunc when creating a Monitor.
+// The root directory is read recursively. The configs failing validation are skipped, and the configs of the
+// files that can't be read or parsed are the ones from the previous read; the errors are reported by Errors.
 func (f *FileSnapshot) ReadConfigFiles() ([]*config.Config, error) {
-	var result []*config.Config
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	configFileReloads.Increment()
 
+	files := map[string][]*config.Config{}
+	errs := map[string][]string{}
 	err := filepath.Walk(f.root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		} else if !supportedExtensions[filepath.Ext(path)] || (info.Mode()&os.ModeType) != 0 {
 			return nil
 		}
-		data, err := ioutil.ReadFile(path)
-		if err != nil {
-			log.Warnf("Failed to read %s: %v", path, err)
-			return err
+		configs, fileErrs := f.readConfigFile(path)
+		if configs == nil && len(fileErrs) > 0 {
+			// Keep the configs from the previous read.
+			configs = f.files[path]
 		}
-		configs, err := parseInputs(data, f.domainSuffix)
-		if err != nil {
-			log.Warnf("Failed to parse %s: %v", path, err)
-			return err
+		files[path] = configs
+		if len(fileErrs) > 0 {
+			errs[path] = fileErrs
 		}
+		return nil
+	})
+	if err != nil {
+		log.Warnf("failure during filepath.Walk: %v", err)
+		errs[f.root] = append(errs[f.root], err.Error())
+	}
 
-		// Filter any unsupported types before appending to the result.
-		for _, cfg := range configs {
-			if !f.configTypeFilter[cfg.GroupVersionKind] {
+	// The same config may be defined in several files, only keep the first one.
+	var result []*config.Config
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	seen := map[string]string{}
+	for _, path := range paths {
+		for _, cfg := range files[path] {
+			if other, found := seen[cfg.Key()]; found {
+				errs[path] = append(errs[path], fmt.Sprintf("%s %s/%s is already defined in %s",
+					cfg.GroupVersionKind.Kind, cfg.Namespace, cfg.Name, other))
 				continue
 			}
+			seen[cfg.Key()] = path
 			result = append(result, cfg)
 		}
-		return nil
-	})
-	if err != nil {
-		log.Warnf("failure during filepath.Walk: %v", err)
 	}
 
+	recordFileErrors(f.errors, errs)
+	f.files = files
+	f.errors = errs
+
 	// Sort by the config IDs.
 	sort.Sort(byKey(result))
 	return result, err
 }
 
-// parseInputs is identical to crd.ParseInputs, except that it returns an array of config pointers.
+// readConfigFile returns the valid configs of a file, and the errors found while reading it. The configs are nil
+// if the file can't be read or parsed.
+func (f *FileSnapshot) readConfigFile(path string) ([]*config.Config, []string) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Warnf("Failed to read %s: %v", path, err)
+		return nil, []string{err.Error()}
+	}
+	configs, err := parseInputs(data, f.domainSuffix)
+	if err != nil {
+		log.Warnf("Failed to parse %s: %v", path, err)
+		return nil, []string{err.Error()}
+	}
+
+	result := []*config.Config{}
+	var errs []string
+	for _, cfg := range configs {
+		// Filter any unsupported types before appending to the result.
+		if !f.configTypeFilter[cfg.GroupVersionKind] {
+			continue
+		}
+		if err := validateConfig(cfg); err != nil {
+			log.Warnf("Invalid config %s %s/%s in %s: %v", cfg.GroupVersionKind.Kind, cfg.Namespace, cfg.Name, path, err)
+			errs = append(errs, fmt.Sprintf("%s %s/%s is invalid: %v", cfg.GroupVersionKind.Kind, cfg.Namespace, cfg.Name, err))
+			continue
+		}
+		result = append(result, cfg)
+	}
+	return result, errs
+}
+
+// Errors returns the errors found by the last read, keyed by file path.
+func (f *FileSnapshot) Errors() map[string][]string {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	out := make(map[string][]string, len(f.errors))
+	for path, errs := range f.errors {
+		out[path] = append([]string{}, errs...)
+	}
+	return out
+}
+
+// validateConfig validates a config with the validation of its schema, from pkg/config/validation.
+func validateConfig(cfg *config.Config) error {
+	s, f := collections.Pilot.FindByGroupVersionKind(cfg.GroupVersionKind)
+	if !f {
+		return fmt.Errorf("unknown type %v", cfg.GroupVersionKind)
+	}
+	_, err := s.Resource().ValidateConfig(*cfg)
+	return err
+}
+
+// parseInputs is identical to crd.ParseInputsWithoutValidation, except that it returns an array of config pointers.
 func parseInputs(data []byte, domainSuffix string) ([]*config.Config, error) {
-	configs, _, err := crd.ParseInputs(string(data))
+	configs, _, err := crd.ParseInputsWithoutValidation(string(data))
 
 	// Convert to an array of pointers.
 	refs := make([]*config.Config, len(configs))
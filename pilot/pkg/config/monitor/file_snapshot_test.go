@@ -126,6 +126,71 @@ func TestFileSnapshotSorting(t *testing.T) {
 	g.Expect(configs[1].Spec).To(gomega.BeAssignableToTypeOf(&networking.VirtualService{}))
 }
 
+var invalidVirtualServiceYAML = `
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: invalid
+spec:
+  hosts:
+  - invalid.example.com
+  http:
+  - route:
+    - destination:
+        host: some.example.internal
+      weight: 10
+    - destination:
+        host: other.example.internal
+      weight: 10
+`
+
+func TestFileSnapshotErrors(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	ts := &testState{
+		ConfigFiles: map[string][]byte{
+			"nested/gateway.yml": []byte(gatewayYAML),
+			"routes.yml":         []byte(virtualServiceYAML + "---" + invalidVirtualServiceYAML),
+			"z-duplicate.yml":    []byte(virtualServiceYAML),
+			"broken.yml":         []byte("kind: [Gateway"),
+		},
+	}
+
+	ts.testSetup(t)
+	defer ts.testTeardown(t)
+
+	fileWatcher := monitor.NewFileSnapshot(ts.rootPath, collection.SchemasFor(), "")
+	configs, err := fileWatcher.ReadConfigFiles()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	// The nested gateway and the valid virtual service are read, the invalid and duplicated configs are skipped.
+	g.Expect(configs).To(gomega.HaveLen(2))
+	g.Expect(configs[0].Name).To(gomega.Equal("some-ingress"))
+	g.Expect(configs[1].Name).To(gomega.Equal("route-for-myapp"))
+
+	errs := fileWatcher.Errors()
+	g.Expect(errs).To(gomega.HaveLen(3))
+	g.Expect(errs[filepath.Join(ts.rootPath, "routes.yml")]).To(gomega.HaveLen(1))
+	g.Expect(errs[filepath.Join(ts.rootPath, "broken.yml")]).To(gomega.HaveLen(1))
+	g.Expect(errs[filepath.Join(ts.rootPath, "z-duplicate.yml")]).To(gomega.HaveLen(1))
+
+	// An unparsable file keeps the configs it had, until it is fixed.
+	gatewayPath := filepath.Join(ts.rootPath, "nested/gateway.yml")
+	g.Expect(ioutil.WriteFile(gatewayPath, []byte("kind: [Gateway"), 0600)).To(gomega.Succeed())
+	g.Expect(os.Remove(filepath.Join(ts.rootPath, "broken.yml"))).To(gomega.Succeed())
+	configs, err = fileWatcher.ReadConfigFiles()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(configs).To(gomega.HaveLen(2))
+	g.Expect(configs[0].Name).To(gomega.Equal("some-ingress"))
+	errs = fileWatcher.Errors()
+	g.Expect(errs).To(gomega.HaveLen(3))
+	g.Expect(errs[gatewayPath]).To(gomega.HaveLen(1))
+
+	g.Expect(os.Remove(gatewayPath)).To(gomega.Succeed())
+	configs, err = fileWatcher.ReadConfigFiles()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(configs).To(gomega.HaveLen(1))
+}
+
 type testState struct {
 	ConfigFiles map[string][]byte
 	rootPath    string
@@ -140,6 +205,9 @@ func (ts *testState) testSetup(t *testing.T) {
 	}
 
 	for name, content := range ts.ConfigFiles {
+		if err = os.MkdirAll(filepath.Dir(filepath.Join(ts.rootPath, name)), 0700); err != nil {
+			t.Fatal(err)
+		}
 		err = ioutil.WriteFile(filepath.Join(ts.rootPath, name), content, 0600)
 		if err != nil {
 			t.Fatal(err)
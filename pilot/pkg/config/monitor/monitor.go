@@ -15,6 +15,8 @@
 package monitor
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"time"
@@ -54,13 +56,15 @@ func NewMonitor(name string, delegateStore model.ConfigStore, getSnapshotFunc fu
 
 const watchDebounceDelay = 50 * time.Millisecond
 
-// Trigger notifications when a file is mutated
+// Trigger notifications when a file is mutated. Directories are watched recursively, including the
+// directories created after the watch starts.
 func fileTrigger(path string, ch chan struct{}, stop <-chan struct{}) error {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return err
 	}
-	if err = watcher.Add(path); err != nil {
+	if err = watchRecursive(watcher, path); err != nil {
+		_ = watcher.Close()
 		return err
 	}
 	go func() {
@@ -71,7 +75,14 @@ func fileTrigger(path string, ch chan struct{}, stop <-chan struct{}) error {
 			case <-debounceC:
 				debounceC = nil
 				ch <- struct{}{}
-			case <-watcher.Events:
+			case e := <-watcher.Events:
+				if e.Op&fsnotify.Create != 0 {
+					if info, err := os.Stat(e.Name); err == nil && info.IsDir() {
+						if err := watchRecursive(watcher, e.Name); err != nil {
+							log.Warnf("Error watching directory %v: %v", e.Name, err)
+						}
+					}
+				}
 				if debounceC == nil {
 					debounceC = time.After(watchDebounceDelay)
 				}
@@ -87,6 +98,19 @@ func fileTrigger(path string, ch chan struct{}, stop <-chan struct{}) error {
 	return nil
 }
 
+// watchRecursive adds the path to the watcher and, if it is a directory, all its sub directories.
+func watchRecursive(watcher *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != root && !info.IsDir() {
+			return nil
+		}
+		return watcher.Add(path)
+	})
+}
+
 // Start starts a new Monitor. Immediately checks the Monitor getSnapshotFunc
 // and updates the controller. It then kicks off an asynchronous event loop that
 // periodically polls the getSnapshotFunc for changes until a close event is sent.
@@ -16,6 +16,9 @@ package monitor
 
 import (
 	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -194,3 +197,28 @@ func TestMonitorForError(t *testing.T) {
 		return nil
 	}).Should(gomega.Succeed())
 }
+
+func TestFileTriggerRecursive(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	root := t.TempDir()
+	ch := make(chan struct{}, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+	g.Expect(fileTrigger(root, ch, stop)).To(gomega.Succeed())
+
+	nested := filepath.Join(root, "nested", "dir")
+	g.Expect(os.MkdirAll(nested, 0700)).To(gomega.Succeed())
+	g.Eventually(ch).Should(gomega.Receive())
+
+	// Files in the directories created after the watch started trigger notifications.
+	g.Eventually(func() bool {
+		g.Expect(ioutil.WriteFile(filepath.Join(nested, "config.yaml"), []byte("# config"), 0600)).To(gomega.Succeed())
+		select {
+		case <-ch:
+			return true
+		case <-time.After(200 * time.Millisecond):
+			return false
+		}
+	}).Should(gomega.BeTrue())
+}
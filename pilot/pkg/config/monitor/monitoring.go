@@ -0,0 +1,80 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"sort"
+
+	"istio.io/pkg/monitoring"
+)
+
+const (
+	// maxErrorFiles bounds the number of files reported with their own path by configFileErrors. The errors of
+	// the other files are reported with the otherFiles path.
+	maxErrorFiles = 20
+	otherFiles    = "other"
+)
+
+var (
+	pathTag = monitoring.MustCreateLabel("path")
+
+	configFileErrors = monitoring.NewGauge(
+		"pilot_config_file_errors",
+		"Number of errors found in a config file by the last read of the file config sources. "+
+			"Only the first files with errors are reported with their own path, the others are reported as 'other'.",
+		monitoring.WithLabels(pathTag),
+	)
+
+	configFileReloads = monitoring.NewSum(
+		"pilot_config_file_reloads",
+		"Total number of reloads of the file config sources.",
+	)
+)
+
+func init() {
+	monitoring.MustRegister(configFileErrors, configFileReloads)
+}
+
+// recordFileErrors records the number of errors of each file, resetting the files which no longer have errors.
+func recordFileErrors(previous, current map[string][]string) {
+	counts := fileErrorCounts(current)
+	for path := range fileErrorCounts(previous) {
+		if _, f := counts[path]; !f {
+			configFileErrors.With(pathTag.Value(path)).Record(0)
+		}
+	}
+	for path, count := range counts {
+		configFileErrors.With(pathTag.Value(path)).Record(float64(count))
+	}
+}
+
+// fileErrorCounts returns the number of errors by path label. Only the first maxErrorFiles paths are used as
+// label, the errors of the other files are summed with the otherFiles label.
+func fileErrorCounts(errs map[string][]string) map[string]int {
+	paths := make([]string, 0, len(errs))
+	for path := range errs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	counts := make(map[string]int, len(paths))
+	for i, path := range paths {
+		label := path
+		if i >= maxErrorFiles {
+			label = otherFiles
+		}
+		counts[label] += len(errs[path])
+	}
+	return counts
+}
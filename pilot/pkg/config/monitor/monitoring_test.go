@@ -0,0 +1,42 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFileErrorCounts(t *testing.T) {
+	errs := map[string][]string{}
+	want := map[string]int{}
+	for i := 0; i < maxErrorFiles; i++ {
+		path := fmt.Sprintf("/etc/config/%02d.yaml", i)
+		errs[path] = []string{"invalid"}
+		want[path] = 1
+	}
+	if got := fileErrorCounts(errs); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// The files after the first maxErrorFiles are reported together.
+	errs["/etc/config/x.yaml"] = []string{"invalid", "duplicate"}
+	errs["/etc/config/y.yaml"] = []string{"invalid"}
+	want[otherFiles] = 3
+	if got := fileErrorCounts(errs); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"istio.io/pkg/cache"
+)
+
+// responseCache is a bounded cache of upstream responses. Entries expire after the smallest TTL of their
+// records. Negative responses (NXDOMAIN, or NOERROR without answers) are cached for the SOA minimum TTL
+// as described in RFC 2308, bounded by the negative TTL of the cache.
+type responseCache struct {
+	entries     cache.ExpiringCache
+	negativeTTL time.Duration
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+type responseCacheKey struct {
+	name   string
+	qtype  uint16
+	qclass uint16
+}
+
+type responseCacheEntry struct {
+	response *dns.Msg
+	stored   time.Time
+	expiry   time.Time
+}
+
+func newResponseCache(size int, negativeTTL time.Duration) *responseCache {
+	return &responseCache{
+		// Expired entries are skipped on lookup, the eviction only reclaims their memory.
+		entries:     cache.NewLRU(negativeTTL, time.Minute, int32(size)),
+		negativeTTL: negativeTTL,
+		now:         time.Now,
+	}
+}
+
+func newResponseCacheKey(req *dns.Msg) responseCacheKey {
+	q := req.Question[0]
+	return responseCacheKey{name: strings.ToLower(q.Name), qtype: q.Qtype, qclass: q.Qclass}
+}
+
+// get returns a copy of the cached response to the request, with the TTLs of its records decreased by
+// the time spent in the cache, or nil if there is no fresh response in the cache.
+func (c *responseCache) get(req *dns.Msg) *dns.Msg {
+	v, f := c.entries.Get(newResponseCacheKey(req))
+	if !f {
+		return nil
+	}
+	entry := v.(*responseCacheEntry)
+	now := c.now()
+	if !now.Before(entry.expiry) {
+		return nil
+	}
+
+	response := entry.response.Copy()
+	response.Id = req.Id
+	elapsed := uint32(now.Sub(entry.stored) / time.Second)
+	for _, rrs := range [][]dns.RR{response.Answer, response.Ns, response.Extra} {
+		for _, rr := range rrs {
+			hdr := rr.Header()
+			if hdr.Rrtype == dns.TypeOPT {
+				continue
+			}
+			if hdr.Ttl > elapsed {
+				hdr.Ttl -= elapsed
+			} else {
+				hdr.Ttl = 0
+			}
+		}
+	}
+	return response
+}
+
+// add caches the upstream response to the request, if it is cacheable.
+func (c *responseCache) add(req *dns.Msg, response *dns.Msg) {
+	if response.Truncated {
+		// A truncated response is incomplete, the client is expected to retry over TCP.
+		return
+	}
+	var ttl time.Duration
+	switch {
+	case response.Rcode == dns.RcodeSuccess && len(response.Answer) > 0:
+		ttl = time.Duration(minTTL(response)) * time.Second
+	case response.Rcode == dns.RcodeSuccess || response.Rcode == dns.RcodeNameError:
+		ttl = c.negativeTTL
+		if soaTTL, f := negativeTTL(response); f && soaTTL < ttl {
+			ttl = soaTTL
+		}
+	default:
+		// Server failures and refusals are not cached, the next request retries the upstream resolvers.
+		return
+	}
+	if ttl <= 0 {
+		return
+	}
+	now := c.now()
+	c.entries.SetWithExpiration(newResponseCacheKey(req), &responseCacheEntry{
+		response: response.Copy(),
+		stored:   now,
+		expiry:   now.Add(ttl),
+	}, ttl)
+}
+
+// minTTL returns the smallest TTL of the records of the response.
+func minTTL(response *dns.Msg) uint32 {
+	var ttl uint32
+	found := false
+	for _, rrs := range [][]dns.RR{response.Answer, response.Ns, response.Extra} {
+		for _, rr := range rrs {
+			hdr := rr.Header()
+			if hdr.Rrtype == dns.TypeOPT {
+				continue
+			}
+			if !found || hdr.Ttl < ttl {
+				ttl = hdr.Ttl
+				found = true
+			}
+		}
+	}
+	return ttl
+}
+
+// negativeTTL returns the TTL of a negative response, which is the minimum of the SOA record TTL
+// and its MINIMUM field.
+func negativeTTL(response *dns.Msg) (time.Duration, bool) {
+	for _, rr := range response.Ns {
+		if soa, ok := rr.(*dns.SOA); ok {
+			ttl := soa.Hdr.Ttl
+			if soa.Minttl < ttl {
+				ttl = soa.Minttl
+			}
+			return time.Duration(ttl) * time.Second, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,207 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+func upstreamResponse(req *dns.Msg, rcode int, answer []dns.RR, ns []dns.RR) *dns.Msg {
+	response := new(dns.Msg)
+	response.SetReply(req)
+	response.Rcode = rcode
+	response.Answer = answer
+	response.Ns = ns
+	return response
+}
+
+func soa(ttl, minttl uint32) []dns.RR {
+	return []dns.RR{&dns.SOA{
+		Hdr:    dns.RR_Header{Name: "example.", Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: ttl},
+		Ns:     "ns.example.",
+		Mbox:   "admin.example.",
+		Minttl: minttl,
+	}}
+}
+
+func TestResponseCache(t *testing.T) {
+	question := func(host string) *dns.Msg {
+		req := new(dns.Msg)
+		req.SetQuestion(host, dns.TypeA)
+		return req
+	}
+	answer := withTTL(a("www.example.com.", []net.IP{net.ParseIP("1.1.1.1").To4()}), 60)
+
+	cases := []struct {
+		name     string
+		response *dns.Msg
+		// wantTTL is the duration the response is cached for, zero if it is not cached.
+		wantTTL time.Duration
+	}{
+		{
+			name:     "answer cached for its TTL",
+			response: upstreamResponse(question("www.example.com."), dns.RcodeSuccess, answer, nil),
+			wantTTL:  60 * time.Second,
+		},
+		{
+			name: "answer cached for the smallest TTL",
+			response: upstreamResponse(question("www.example.com."), dns.RcodeSuccess,
+				append(withTTL(cname("www.example.com.", "alias.example.com."), 10), answer...), nil),
+			wantTTL: 10 * time.Second,
+		},
+		{
+			name: "answer with zero TTL not cached",
+			response: upstreamResponse(question("www.example.com."), dns.RcodeSuccess,
+				withTTL(a("www.example.com.", []net.IP{net.ParseIP("1.1.1.1").To4()}), 0), nil),
+		},
+		{
+			name:     "NXDOMAIN cached for the SOA minimum",
+			response: upstreamResponse(question("www.example.com."), dns.RcodeNameError, nil, soa(20, 5)),
+			wantTTL:  5 * time.Second,
+		},
+		{
+			name:     "NXDOMAIN cached for at most the negative TTL",
+			response: upstreamResponse(question("www.example.com."), dns.RcodeNameError, nil, soa(3600, 3600)),
+			wantTTL:  30 * time.Second,
+		},
+		{
+			name:     "NXDOMAIN without SOA cached for the negative TTL",
+			response: upstreamResponse(question("www.example.com."), dns.RcodeNameError, nil, nil),
+			wantTTL:  30 * time.Second,
+		},
+		{
+			name:     "empty answer cached negatively",
+			response: upstreamResponse(question("www.example.com."), dns.RcodeSuccess, nil, soa(20, 20)),
+			wantTTL:  20 * time.Second,
+		},
+		{
+			name:     "server failure not cached",
+			response: upstreamResponse(question("www.example.com."), dns.RcodeServerFailure, nil, nil),
+		},
+		{
+			name: "truncated response not cached",
+			response: func() *dns.Msg {
+				r := upstreamResponse(question("www.example.com."), dns.RcodeSuccess, answer, nil)
+				r.Truncated = true
+				return r
+			}(),
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			c := newResponseCache(10, 30*time.Second)
+			c.now = func() time.Time { return now }
+
+			c.add(question("www.example.com."), tt.response)
+			// Lookups are case insensitive, and the response gets the ID of the request.
+			req := question("WWW.example.com.")
+			got := c.get(req)
+			if tt.wantTTL == 0 {
+				if got != nil {
+					t.Fatalf("expected response not to be cached, got %v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("expected response to be cached")
+			}
+			if got.Id != req.Id || got.Rcode != tt.response.Rcode {
+				t.Errorf("got response %v, want %v", got, tt.response)
+			}
+
+			now = now.Add(tt.wantTTL - time.Second)
+			got = c.get(req)
+			if got == nil {
+				t.Fatalf("expected response to be cached one second before expiry")
+			}
+			for i, rr := range got.Answer {
+				// The TTL is decreased by the time spent in the cache.
+				if want := tt.response.Answer[i].Header().Ttl - uint32(tt.wantTTL/time.Second) + 1; rr.Header().Ttl != want {
+					t.Errorf("got TTL %d, want %d", rr.Header().Ttl, want)
+				}
+			}
+
+			now = now.Add(time.Second)
+			if got := c.get(req); got != nil {
+				t.Errorf("expected response to expire, got %v", got)
+			}
+		})
+	}
+}
+
+func TestResponseCacheSize(t *testing.T) {
+	c := newResponseCache(2, 30*time.Second)
+	for _, host := range []string{"a.example.", "b.example.", "c.example."} {
+		req := new(dns.Msg)
+		req.SetQuestion(host, dns.TypeA)
+		c.add(req, upstreamResponse(req, dns.RcodeSuccess, withTTL(a(host, []net.IP{net.ParseIP("1.1.1.1").To4()}), 60), nil))
+	}
+	req := new(dns.Msg)
+	req.SetQuestion("a.example.", dns.TypeA)
+	if got := c.get(req); got != nil {
+		t.Errorf("expected least recently used response to be evicted, got %v", got)
+	}
+	req.SetQuestion("c.example.", dns.TypeA)
+	if got := c.get(req); got == nil {
+		t.Errorf("expected most recent response to be cached")
+	}
+}
+
+func TestDNSUpstreamCache(t *testing.T) {
+	agent := initDNSWithOptions(t, Options{TTL: 5 * time.Second, UpstreamCacheSize: 10, NegativeCacheTTL: time.Minute})
+	c := dns.Client{Timeout: time.Second}
+
+	// The name table answers use the configured TTL.
+	m := new(dns.Msg)
+	m.SetQuestion("www.google.com.", dns.TypeA)
+	res, _, err := c.Exchange(m, testAgentDNSAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Answer) != 1 || res.Answer[0].Header().Ttl != 5 {
+		t.Errorf("got answer %v, want a single answer with TTL 5", res.Answer)
+	}
+
+	for _, tt := range []struct {
+		host  string
+		rcode int
+	}{
+		{"www.bing.com.", dns.RcodeSuccess},
+		{"unknown.example.", dns.RcodeNameError},
+	} {
+		m := new(dns.Msg)
+		m.SetQuestion(tt.host, dns.TypeA)
+		if cached := agent.upstreamCache.get(m); cached != nil {
+			t.Fatalf("got cached response for %s before querying it: %v", tt.host, cached)
+		}
+		for i := 0; i < 2; i++ {
+			res, _, err := c.Exchange(m, testAgentDNSAddr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Rcode != tt.rcode {
+				t.Errorf("got rcode %v for %s, want %v", res.Rcode, tt.host, tt.rcode)
+			}
+		}
+		if cached := agent.upstreamCache.get(m); cached == nil || cached.Rcode != tt.rcode {
+			t.Errorf("got cached response %v for %s, want rcode %v", cached, tt.host, tt.rcode)
+		}
+	}
+}
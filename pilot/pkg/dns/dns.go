@@ -18,6 +18,7 @@ import (
 	"net"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/miekg/dns"
@@ -38,6 +39,10 @@ type LocalDNSServer struct {
 
 	resolvConfServers []string
 	searchNamespaces  []string
+	// The cache of upstream responses, nil if disabled.
+	upstreamCache *responseCache
+	// The TTL of the answers built from the name table.
+	ttl uint32
 	// The namespace where the proxy resides
 	// determines the hosts used for shortname resolution
 	proxyNamespace string
@@ -68,13 +73,30 @@ type LookupTable struct {
 const (
 	// In case the client decides to honor the TTL, keep it low so that we can always serve
 	// the latest IP for a host.
-	// TODO: make it configurable
 	defaultTTLInSeconds = 30
 )
 
-func NewLocalDNSServer(proxyNamespace, proxyDomain string) (*LocalDNSServer, error) {
+// Options holds the tunable settings of the DNS proxy.
+type Options struct {
+	// TTL of the answers built from the name table. Defaults to 30s.
+	TTL time.Duration
+	// UpstreamCacheSize is the maximum number of upstream responses cached. The cache is disabled if zero.
+	UpstreamCacheSize int
+	// NegativeCacheTTL is the maximum duration negative upstream responses are cached for.
+	// Negative responses are not cached if zero.
+	NegativeCacheTTL time.Duration
+}
+
+func NewLocalDNSServer(proxyNamespace, proxyDomain string, opts Options) (*LocalDNSServer, error) {
 	h := &LocalDNSServer{
 		proxyNamespace: proxyNamespace,
+		ttl:            defaultTTLInSeconds,
+	}
+	if opts.TTL > 0 {
+		h.ttl = uint32(opts.TTL / time.Second)
+	}
+	if opts.UpstreamCacheSize > 0 {
+		h.upstreamCache = newResponseCache(opts.UpstreamCacheSize, opts.NegativeCacheTTL)
 	}
 
 	// proxyDomain could contain the namespace making it redundant.
@@ -109,7 +131,8 @@ func NewLocalDNSServer(proxyNamespace, proxyDomain string) (*LocalDNSServer, err
 		h.searchNamespaces = dnsConfig.Search
 	}
 
-	log.WithLabels("search", h.searchNamespaces, "servers", h.resolvConfServers).Debugf("initialized DNS")
+	log.WithLabels("search", h.searchNamespaces, "servers", h.resolvConfServers, "ttl", h.ttl,
+		"cacheSize", opts.UpstreamCacheSize).Debugf("initialized DNS")
 
 	if h.udpDNSProxy, err = newDNSProxy("udp", h); err != nil {
 		return nil, err
@@ -150,7 +173,7 @@ func (h *LocalDNSServer) UpdateLookupTable(nt *nds.NameTable) {
 			// malformed ips
 			continue
 		}
-		lookupTable.buildDNSAnswers(altHosts, ipv4, ipv6, h.searchNamespaces)
+		lookupTable.buildDNSAnswers(altHosts, ipv4, ipv6, h.searchNamespaces, h.ttl)
 	}
 	h.lookupTable.Store(lookupTable)
 	log.Debugf("updated lookup table with %d hosts", len(lookupTable.allHosts))
@@ -212,13 +235,26 @@ func (h *LocalDNSServer) ServeDNS(proxy *dnsProxy, w dns.ResponseWriter, req *dn
 		// In other cases, this is a NOP.
 		response.Truncate(size(proxy.protocol, req))
 		log.Debugf("response for hostname %q (found=true): %v", hostname, response)
+		nameTableHits.Increment()
 		_ = w.WriteMsg(response)
 		return
 	}
 
 	// We did not find the host in our internal cache. Query upstream and return the response as is.
-	log.Debugf("response for hostname %q (found=false): %v", hostname, response)
-	response = h.queryUpstream(proxy.upstreamClient, req, log)
+	if h.upstreamCache != nil {
+		response = h.upstreamCache.get(req)
+	}
+	if response != nil {
+		log.Debugf("response for hostname %q (cached=true): %v", hostname, response)
+		upstreamCacheHits.Increment()
+	} else {
+		cacheMisses.Increment()
+		response = h.queryUpstream(proxy.upstreamClient, req, log)
+		log.Debugf("response for hostname %q (found=false): %v", hostname, response)
+		if h.upstreamCache != nil {
+			h.upstreamCache.add(req, response)
+		}
+	}
 	// Compress the response - we don't know if the incoming response was compressed or not. If it was,
 	// but we don't compress on the outbound, we will run into issues. For example, if the compressed
 	// size is 450 bytes but uncompressed 1000 bytes now we are outside of the non-eDNS UDP size limits
@@ -301,6 +337,7 @@ func (h *LocalDNSServer) Close() {
 // TODO: Figure out how to send parallel queries to all nameservers
 func (h *LocalDNSServer) queryUpstream(upstreamClient *dns.Client, req *dns.Msg, scope *istiolog.Scope) *dns.Msg {
 	var response *dns.Msg
+	start := time.Now()
 	for _, upstream := range h.resolvConfServers {
 		cResponse, _, err := upstreamClient.Exchange(req, upstream)
 		if err == nil {
@@ -311,9 +348,12 @@ func (h *LocalDNSServer) queryUpstream(upstreamClient *dns.Client, req *dns.Msg,
 		}
 	}
 	if response == nil {
+		upstreamFailures.Increment()
 		response = new(dns.Msg)
 		response.SetReply(req)
 		response.Rcode = dns.RcodeServerFailure
+	} else {
+		upstreamRequestDuration.Record(time.Since(start).Seconds())
 	}
 	return response
 }
@@ -412,15 +452,16 @@ func (table *LookupTable) lookupHost(qtype uint16, hostname string) ([]dns.RR, b
 // in the lookup table with a CNAME record as the DNS response. This technique eliminates the need
 // to do string parsing, memory allocations, etc. at query time at the cost of Nx number of entries (i.e. memory) to store
 // the lookup table, where N is number of search namespaces.
-func (table *LookupTable) buildDNSAnswers(altHosts map[string]struct{}, ipv4 []net.IP, ipv6 []net.IP, searchNamespaces []string) {
+func (table *LookupTable) buildDNSAnswers(altHosts map[string]struct{}, ipv4 []net.IP, ipv6 []net.IP, searchNamespaces []string,
+	ttl uint32) {
 	for h := range altHosts {
 		h = strings.ToLower(h)
 		table.allHosts[h] = struct{}{}
 		if len(ipv4) > 0 {
-			table.name4[h] = a(h, ipv4)
+			table.name4[h] = withTTL(a(h, ipv4), ttl)
 		}
 		if len(ipv6) > 0 {
-			table.name6[h] = aaaa(h, ipv6)
+			table.name6[h] = withTTL(aaaa(h, ipv6), ttl)
 		}
 		if len(searchNamespaces) > 0 {
 			// NOTE: Right now, rather than storing one expanded host for each one of the search namespace
@@ -438,7 +479,7 @@ func (table *LookupTable) buildDNSAnswers(altHosts map[string]struct{}, ipv4 []n
 			// then the expanded host productpage.ns1.svc.cluster.local is a valid hostname
 			// that is likely to be already present in the altHosts
 			if _, exists := altHosts[expandedHost]; !exists {
-				table.cname[expandedHost] = cname(expandedHost, h)
+				table.cname[expandedHost] = withTTL(cname(expandedHost, h), ttl)
 				table.allHosts[expandedHost] = struct{}{}
 			}
 		}
@@ -482,6 +523,14 @@ func cname(host string, targetHost string) []dns.RR {
 	return []dns.RR{answer}
 }
 
+// withTTL sets the TTL of the records and returns them.
+func withTTL(answers []dns.RR, ttl uint32) []dns.RR {
+	for _, r := range answers {
+		r.Header().Ttl = ttl
+	}
+	return answers
+}
+
 // Size returns if buffer size *advertised* in the requests OPT record.
 // Or when the request was over TCP, we return the maximum allowed size of 64K.
 func size(proto string, r *dns.Msg) int {
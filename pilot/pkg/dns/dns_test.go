@@ -398,8 +398,12 @@ func makeUpstream(t test.Failer, responses map[string]string) string {
 }
 
 func initDNS(t test.Failer) *LocalDNSServer {
+	return initDNSWithOptions(t, Options{})
+}
+
+func initDNSWithOptions(t test.Failer, opts Options) *LocalDNSServer {
 	srv := makeUpstream(t, map[string]string{"www.bing.com.": "1.1.1.1"})
-	testAgentDNS, err := NewLocalDNSServer("ns1", "ns1.svc.cluster.local")
+	testAgentDNS, err := NewLocalDNSServer("ns1", "ns1.svc.cluster.local", opts)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,60 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"istio.io/pkg/monitoring"
+)
+
+var (
+	sourceTag = monitoring.MustCreateLabel("source")
+
+	// cacheHits records the number of requests answered locally, either from the name table or from
+	// the cache of upstream responses.
+	cacheHits = monitoring.NewSum(
+		"dns_cache_hits",
+		"Total number of DNS requests answered by the agent from the name table or the upstream response cache.",
+		monitoring.WithLabels(sourceTag),
+	)
+
+	// cacheMisses records the number of requests forwarded to the upstream resolvers.
+	cacheMisses = monitoring.NewSum(
+		"dns_cache_misses",
+		"Total number of DNS requests forwarded by the agent to the upstream resolvers.",
+	)
+
+	upstreamFailures = monitoring.NewSum(
+		"dns_upstream_failures",
+		"Total number of DNS requests which could not be resolved by any of the upstream resolvers.",
+	)
+
+	upstreamRequestDuration = monitoring.NewDistribution(
+		"dns_upstream_request_duration_seconds",
+		"Time in seconds the agent takes to resolve a DNS request through the upstream resolvers.",
+		[]float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 5},
+	)
+
+	nameTableHits     = cacheHits.With(sourceTag.Value("name_table"))
+	upstreamCacheHits = cacheHits.With(sourceTag.Value("upstream_cache"))
+)
+
+func init() {
+	monitoring.MustRegister(
+		cacheHits,
+		cacheMisses,
+		upstreamFailures,
+		upstreamRequestDuration,
+	)
+}
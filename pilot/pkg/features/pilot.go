@@ -63,6 +63,35 @@ var (
 		"Limits the number of concurrent pushes allowed. On larger machines this can be increased for faster pushes",
 	).Get()
 
+	PushQueueFairness = env.RegisterStringVar(
+		"PILOT_PUSH_QUEUE_FAIRNESS",
+		"",
+		"If set, the proxies waiting for a push are grouped in queues served in proportion to their weight, "+
+			"so that a group of churning proxies does not delay the pushes to the others. The queues are keyed "+
+			"by proxy type with \"proxy-type\", or by proxy type and namespace with \"namespace\". "+
+			"If empty, a single FIFO queue is used.",
+	).Get()
+
+	PushQueueWeights = env.RegisterStringVar(
+		"PILOT_PUSH_QUEUE_WEIGHTS",
+		"router=4",
+		"Comma separated list of <key>=<weight> weights of the push queues, where the key is a proxy type or "+
+			"<proxy type>/<namespace>. The queues without weight use the weight of their proxy type, or 1. "+
+			"The default prioritizes gateways over sidecars.",
+	).Get()
+
+	PushQueueQPS = env.RegisterFloatVar(
+		"PILOT_PUSH_QUEUE_QPS",
+		0,
+		"If set, limits the rate of pushes of each push queue, or of all pushes if PILOT_PUSH_QUEUE_FAIRNESS is empty.",
+	).Get()
+
+	PushQueueBurst = env.RegisterIntVar(
+		"PILOT_PUSH_QUEUE_BURST",
+		100,
+		"The number of pushes of each push queue allowed above PILOT_PUSH_QUEUE_QPS.",
+	).Get()
+
 	// MaxRecvMsgSize The max receive buffer size of gRPC received channel of Pilot in bytes.
 	MaxRecvMsgSize = env.RegisterIntVar(
 		"ISTIO_GPRC_MAXRECVMSGSIZE",
@@ -339,6 +368,12 @@ var (
 	XDSCacheMaxSize = env.RegisterIntVar("PILOT_XDS_CACHE_SIZE", 20000,
 		"The maximum number of cache entries for the XDS cache.").Get()
 
+	XDSCacheMaxBytes = env.RegisterStringVar("PILOT_XDS_CACHE_MAX_BYTES", "",
+		"If set, the XDS cache entries of each resource type are also bounded by their size in bytes. "+
+			"Comma separated list of <type>=<size> budgets, where type is eds or sds and size is a "+
+			"quantity, for example eds=256Mi,sds=16Mi. The resource types without budget are only bounded by "+
+			"PILOT_XDS_CACHE_SIZE, which also applies to each resource type with a budget.").Get()
+
 	// EnableLegacyFSGroupInjection has first-party-jwt as allowed because we only
 	// need the fsGroup configuration for the projected service account volume mount,
 	// which is only used by first-party-jwt. The installer will automatically
@@ -401,6 +436,13 @@ var (
 		" Pilot will use to keep configuration status up to date.  Smaller numbers will result in higher status latency, "+
 		"but larger numbers may impact CPU in high scale environments.")
 
+	EnableStatusSharding = env.RegisterBoolVar(
+		"PILOT_ENABLE_STATUS_SHARDING",
+		false,
+		"If enabled, the status writes are sharded across all istiod replicas instead of being done by the elected leader. "+
+			"The replicas discover each other through a coordination Lease per replica.",
+	).Get()
+
 	WasmRemoteLoadConversion = env.RegisterBoolVar("ISTIO_AGENT_ENABLE_WASM_REMOTE_LOAD_CONVERSION", true,
 		"If enabled, Istio agent will intercept ECDS resource update, downloads Wasm module, "+
 			"and replaces Wasm module remote load with downloaded local module file.").Get()
@@ -438,6 +480,13 @@ var (
 		"If enabled, pilot will only send the delta configs as opposed to the state of the world on a "+
 			"Resource Request")
 
+	ConsulToken = env.RegisterStringVar("CONSUL_HTTP_TOKEN", "",
+		"The ACL token used to query the Consul catalogs configured as service registries in the mesh config configSources.").Get()
+
+	PeerDiscoveryIdentities = env.RegisterStringVar("PILOT_PEER_DISCOVERY_IDENTITIES", "",
+		"Comma separated list of the SPIFFE identities of the peer istiods allowed to discover the services "+
+			"and endpoints of this cluster. If empty, the services of this cluster are not exported to peers.").Get()
+
 	SharedMeshConfig = env.RegisterStringVar("SHARED_MESH_CONFIG", "",
 		"Additional config map to load for shared MeshConfig settings. The standard mesh config will take precedence.").Get()
 
@@ -0,0 +1,255 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leaderelection
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	listerv1 "k8s.io/client-go/listers/coordination/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	"istio.io/pkg/log"
+)
+
+// Sets of replicas sharing some work instead of electing a leader
+const (
+	StatusShards = "istio-status-shards"
+)
+
+// staleLeaseDurations is the number of Lease durations after which the lowest-named live replica deletes an expired
+// Lease, so that the Leases of replicas which never released them, for example on crash, do not accumulate.
+const staleLeaseDurations = 3
+
+// ReplicaSetLabel is the label of the Leases held by the replicas, set to the ID of the replica set.
+const ReplicaSetLabel = "istio.io/replica-set"
+
+// Replicas tracks the live istiod replicas sharing some work. Unlike LeaderElection, all replicas run the work;
+// they use the live replicas to decide which part of the work they own.
+// Each replica holds a coordination Lease, named after the replica set and the replica, that it renews periodically.
+// Replicas whose Lease has expired are considered gone, and their Lease is eventually deleted by the lowest-named
+// live replica.
+type Replicas struct {
+	namespace string
+	name      string
+	client    kubernetes.Interface
+	ttl       time.Duration
+	clock     clock.Clock
+	handlers  []func(replicas []string)
+
+	mu   sync.Mutex
+	live []string
+
+	setID string
+}
+
+func NewReplicas(namespace, name, setID string, client kubernetes.Interface) *Replicas {
+	if name == "" {
+		name = "unknown"
+	}
+	return &Replicas{
+		namespace: namespace,
+		name:      name,
+		setID:     setID,
+		client:    client,
+		// Default to a 30s ttl. Overridable for tests
+		ttl:   time.Second * 30,
+		clock: clock.RealClock{},
+	}
+}
+
+// AddHandler registers a function called with the sorted names of the live replicas, each time they change.
+// The handlers are called synchronously and should neither block nor call Live.
+func (r *Replicas) AddHandler(f func(replicas []string)) *Replicas {
+	r.handlers = append(r.handlers, f)
+	return r
+}
+
+// Live returns the sorted names of the live replicas, including this replica.
+func (r *Replicas) Live() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.live...)
+}
+
+// Run holds the Lease of this replica and watches the Leases of the other replicas until stop is closed.
+// The Lease is deleted on exit, so that the other replicas take over the work immediately.
+func (r *Replicas) Run(stop <-chan struct{}) {
+	factory := informers.NewSharedInformerFactoryWithOptions(r.client, 0,
+		informers.WithNamespace(r.namespace),
+		informers.WithTweakListOptions(func(listOptions *metaV1.ListOptions) {
+			listOptions.LabelSelector = labels.Set(map[string]string{ReplicaSetLabel: r.setID}).AsSelector().String()
+		}))
+	leases := factory.Coordination().V1().Leases()
+	leases.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { r.update(leases.Lister()) },
+		UpdateFunc: func(interface{}, interface{}) { r.update(leases.Lister()) },
+		DeleteFunc: func(interface{}) { r.update(leases.Lister()) },
+	})
+	factory.Start(stop)
+
+	r.renew()
+	r.update(leases.Lister())
+	// Leases expire without any event, the live replicas are also checked on each renewal.
+	t := time.NewTicker(r.ttl / 3)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			r.release()
+			return
+		case <-t.C:
+			r.renew()
+			r.update(leases.Lister())
+		}
+	}
+}
+
+func (r *Replicas) leaseName() string {
+	return r.setID + "-" + r.name
+}
+
+// renew creates or renews the Lease of this replica.
+func (r *Replicas) renew() {
+	now := metaV1.NewMicroTime(r.clock.Now())
+	duration := int32(r.ttl / time.Second)
+	if duration < 1 {
+		duration = 1
+	}
+	leases := r.client.CoordinationV1().Leases(r.namespace)
+	lease, err := leases.Get(context.TODO(), r.leaseName(), metaV1.GetOptions{})
+	if errors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      r.leaseName(),
+				Namespace: r.namespace,
+				Labels:    map[string]string{ReplicaSetLabel: r.setID},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &r.name,
+				LeaseDurationSeconds: &duration,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		if _, err := leases.Create(context.TODO(), lease, metaV1.CreateOptions{}); err != nil {
+			log.Errorf("failed to create lease %s/%s: %v", r.namespace, r.leaseName(), err)
+		}
+		return
+	}
+	if err != nil {
+		log.Errorf("failed to get lease %s/%s: %v", r.namespace, r.leaseName(), err)
+		return
+	}
+	lease = lease.DeepCopy()
+	lease.Spec.HolderIdentity = &r.name
+	lease.Spec.LeaseDurationSeconds = &duration
+	lease.Spec.RenewTime = &now
+	if _, err := leases.Update(context.TODO(), lease, metaV1.UpdateOptions{}); err != nil {
+		log.Errorf("failed to renew lease %s/%s: %v", r.namespace, r.leaseName(), err)
+	}
+}
+
+// release deletes the Lease of this replica.
+func (r *Replicas) release() {
+	err := r.client.CoordinationV1().Leases(r.namespace).Delete(context.TODO(), r.leaseName(), metaV1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		log.Warnf("failed to release lease %s/%s: %v", r.namespace, r.leaseName(), err)
+	}
+}
+
+// update recomputes the live replicas from the Leases, calling the handlers if they changed.
+// This replica is always live: if it cannot hold its Lease, the work is done twice rather than not at all.
+func (r *Replicas) update(lister listerv1.LeaseLister) {
+	// Updates are serialized, so that the handlers are called with the replicas in order.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	leases, err := lister.Leases(r.namespace).List(labels.Everything())
+	if err != nil {
+		log.Errorf("failed to list leases of %s: %v", r.setID, err)
+		return
+	}
+	live := []string{r.name}
+	var stale []*coordinationv1.Lease
+	for _, lease := range leases {
+		if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == r.name {
+			continue
+		}
+		if r.expired(lease) {
+			if r.stale(lease) {
+				stale = append(stale, lease)
+			}
+			continue
+		}
+		live = append(live, *lease.Spec.HolderIdentity)
+	}
+	sort.Strings(live)
+
+	// Only one replica deletes the stale Leases, to avoid concurrent deletions.
+	if live[0] == r.name {
+		for _, lease := range stale {
+			r.delete(lease)
+		}
+	}
+
+	if reflect.DeepEqual(live, r.live) {
+		return
+	}
+	r.live = live
+
+	log.Infof("live replicas of %s: %v", r.setID, live)
+	for _, f := range r.handlers {
+		f(append([]string(nil), live...))
+	}
+}
+
+func (r *Replicas) expired(lease *coordinationv1.Lease) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return r.clock.Now().After(expiry)
+}
+
+// stale returns true if the Lease expired more than staleLeaseDurations Lease durations ago.
+func (r *Replicas) stale(lease *coordinationv1.Lease) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return false
+	}
+	duration := time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	return r.clock.Now().After(lease.Spec.RenewTime.Add(duration * (1 + staleLeaseDurations)))
+}
+
+// delete deletes a stale Lease of another replica, unless it was renewed in the meantime.
+func (r *Replicas) delete(lease *coordinationv1.Lease) {
+	err := r.client.CoordinationV1().Leases(r.namespace).Delete(context.TODO(), lease.Name, metaV1.DeleteOptions{
+		Preconditions: &metaV1.Preconditions{UID: &lease.UID, ResourceVersion: &lease.ResourceVersion},
+	})
+	if err == nil {
+		log.Infof("deleted stale lease %s/%s of %s", r.namespace, lease.Name, *lease.Spec.HolderIdentity)
+	} else if !errors.IsNotFound(err) && !errors.IsConflict(err) {
+		log.Warnf("failed to delete stale lease %s/%s: %v", r.namespace, lease.Name, err)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leaderelection
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/atomic"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"istio.io/istio/pkg/test/util/retry"
+)
+
+const testReplicaSet = "test-replicas"
+
+func runReplicas(name string, client kubernetes.Interface,
+	handlers ...func(replicas []string)) (*Replicas, chan struct{}, chan struct{}) {
+	r := NewReplicas("ns", name, testReplicaSet, client)
+	r.ttl = time.Second * 3
+	for _, h := range handlers {
+		r.AddHandler(h)
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		r.Run(stop)
+		close(done)
+	}()
+	return r, stop, done
+}
+
+func expectReplicas(t *testing.T, r *Replicas, expected ...string) {
+	t.Helper()
+	retry.UntilSuccessOrFail(t, func() error {
+		if got := r.Live(); !reflect.DeepEqual(got, expected) {
+			return fmt.Errorf("unexpected replicas: %v, want %v", got, expected)
+		}
+		return nil
+	}, retry.Timeout(time.Second*5))
+}
+
+func TestReplicas(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	changes := atomic.NewInt32(0)
+	r1, stop1, done1 := runReplicas("pod1", client, func(replicas []string) {
+		changes.Inc()
+	})
+	expectReplicas(t, r1, "pod1")
+
+	// A new replica is seen by both replicas
+	r2, stop2, done2 := runReplicas("pod2", client)
+	expectReplicas(t, r1, "pod1", "pod2")
+	expectReplicas(t, r2, "pod1", "pod2")
+
+	// A stopped replica releases its lease
+	close(stop2)
+	<-done2
+	expectReplicas(t, r1, "pod1")
+	if _, err := client.CoordinationV1().Leases("ns").Get(context.TODO(), testReplicaSet+"-pod2", v1.GetOptions{}); err == nil {
+		t.Fatal("expected the lease of pod2 to be released")
+	}
+
+	close(stop1)
+	<-done1
+	// pod1, then pod1 and pod2, then pod1
+	if got := changes.Load(); got != 3 {
+		t.Fatalf("expected the handler to be called on 3 changes, got %d", got)
+	}
+}
+
+func createLease(t *testing.T, client kubernetes.Interface, holder string, renew time.Time) *coordinationv1.Lease {
+	t.Helper()
+	duration := int32(30)
+	renewTime := v1.NewMicroTime(renew)
+	lease := &coordinationv1.Lease{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      testReplicaSet + "-" + holder,
+			Namespace: "ns",
+			Labels:    map[string]string{ReplicaSetLabel: testReplicaSet},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: &duration,
+			RenewTime:            &renewTime,
+		},
+	}
+	if _, err := client.CoordinationV1().Leases("ns").Create(context.TODO(), lease, v1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	return lease
+}
+
+func TestReplicasExpiredLease(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	lease := createLease(t, client, "pod2", time.Now().Add(-time.Minute))
+	r, stop, _ := runReplicas("pod1", client)
+	defer close(stop)
+	expectReplicas(t, r, "pod1")
+
+	// The replica renews its lease and comes back
+	renew := v1.NewMicroTime(time.Now())
+	lease.Spec.RenewTime = &renew
+	if _, err := client.CoordinationV1().Leases("ns").Update(context.TODO(), lease, v1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	expectReplicas(t, r, "pod1", "pod2")
+}
+
+func TestReplicasStaleLease(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	createLease(t, client, "pod3", time.Now().Add(-time.Hour))
+	r, stop, _ := runReplicas("pod1", client)
+	defer close(stop)
+	expectReplicas(t, r, "pod1")
+
+	// The lowest-named live replica deletes the stale lease
+	retry.UntilSuccessOrFail(t, func() error {
+		if _, err := client.CoordinationV1().Leases("ns").Get(context.TODO(), testReplicaSet+"-pod3", v1.GetOptions{}); err == nil {
+			return fmt.Errorf("expected the stale lease of pod3 to be deleted")
+		}
+		return nil
+	}, retry.Timeout(time.Second*5))
+}
+
+func TestReplicasStaleLeaseNotLowest(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	createLease(t, client, "pod0", time.Now())
+	createLease(t, client, "pod3", time.Now().Add(-time.Hour))
+	r, stop, _ := runReplicas("pod1", client)
+	defer close(stop)
+	expectReplicas(t, r, "pod0", "pod1")
+
+	// Only the lowest-named live replica deletes the stale leases
+	if _, err := client.CoordinationV1().Leases("ns").Get(context.TODO(), testReplicaSet+"-pod3", v1.GetOptions{}); err != nil {
+		t.Fatalf("expected the stale lease of pod3 to be kept: %v", err)
+	}
+}
@@ -42,6 +42,10 @@ type ConfigKey struct {
 	Namespace string
 }
 
+func (key ConfigKey) String() string {
+	return key.Kind.Kind + "/" + key.Namespace + "/" + key.Name
+}
+
 func (key ConfigKey) HashCode() uint32 {
 	var result uint32
 	result = 31*result + crc32.ChecksumIEEE([]byte(key.Kind.Kind))
@@ -65,6 +69,19 @@ func ConfigsOfKind(configs map[ConfigKey]struct{}, kind config.GroupVersionKind)
 	return ret
 }
 
+// HasConfigsOnlyOfKind returns true if configs is not empty and all the configs are of the specified kind.
+func HasConfigsOnlyOfKind(configs map[ConfigKey]struct{}, kind config.GroupVersionKind) bool {
+	if len(configs) == 0 {
+		return false
+	}
+	for conf := range configs {
+		if conf.Kind != kind {
+			return false
+		}
+	}
+	return true
+}
+
 // ConfigNamesOfKind extracts config names of the specified kind.
 func ConfigNamesOfKind(configs map[ConfigKey]struct{}, kind config.GroupVersionKind) map[string]struct{} {
 	ret := make(map[string]struct{})
@@ -83,6 +83,12 @@ type Environment struct {
 	// TrustBundle: List of Mesh TrustAnchors
 	TrustBundle *trustbundle.TrustBundle
 
+	// CertificateRevocationList is the CRL of the mesh CA, pushed to the proxies.
+	CertificateRevocationList *CertificateRevocationList
+
+	// RevokedIdentities are the identities revoked by the mesh CA, denied by the proxies.
+	RevokedIdentities *RevokedIdentities
+
 	clusterLocalServices ClusterLocalProvider
 }
 
@@ -181,6 +187,16 @@ type XdsResourceGenerator interface {
 	Generate(proxy *Proxy, push *PushContext, w *WatchedResource, updates *PushRequest) (Resources, error)
 }
 
+// XdsDeltaResourceGenerator creates the response for a typeURL DeltaDiscoveryRequest. Unlike XdsResourceGenerator,
+// it only generates the resources impacted by the PushRequest ConfigsUpdated, along with the names of the resources
+// that were removed.
+// If the generator cannot compute the deltas of a push, it returns the full set of resources and usedDelta is false.
+type XdsDeltaResourceGenerator interface {
+	XdsResourceGenerator
+	GenerateDeltas(proxy *Proxy, push *PushContext, w *WatchedResource,
+		updates *PushRequest) (res Resources, removed []string, usedDelta bool, err error)
+}
+
 // Proxy contains information about an specific instance of a proxy (envoy sidecar, gateway,
 // etc). The Proxy is initialized when a sidecar connects to Pilot, and populated from
 // 'node' info in the protocol as well as data extracted from registries.
@@ -258,6 +274,9 @@ type Proxy struct {
 
 	// WatchedResources contains the list of watched resources for the proxy, keyed by the DiscoveryRequest TypeUrl.
 	WatchedResources map[string]*WatchedResource
+
+	// EnvoyFilterStatus records the EnvoyFilter patches applied to the configuration generated for the proxy.
+	EnvoyFilterStatus EnvoyFilterStatus
 }
 
 // WatchedResource tracks an active DiscoveryRequest subscription.
@@ -300,6 +319,10 @@ type WatchedResource struct {
 	// LastSize tracks the size of the last update
 	LastSize int
 
+	// LastFullSize tracks the size of the last update which contained the full set of resources.
+	// It is used to estimate the size of a full push when only deltas are sent.
+	LastFullSize int
+
 	// Last request contains the last DiscoveryRequest received for
 	// this type. Generators are called immediately after each request,
 	// and may use the information in DiscoveryRequest.
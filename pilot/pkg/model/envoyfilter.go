@@ -15,12 +15,17 @@ package model
 
 import (
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/gogo/protobuf/proto"
+	"go.uber.org/atomic"
 
 	networking "istio.io/api/networking/v1alpha3"
 	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
 	"istio.io/istio/pkg/config/labels"
 	"istio.io/istio/pkg/config/xds"
 )
@@ -29,8 +34,11 @@ import (
 type EnvoyFilterWrapper struct {
 	Name             string
 	Namespace        string
+	Priority         int32
 	workloadSelector labels.Instance
 	Patches          map[networking.EnvoyFilter_ApplyTo][]*EnvoyFilterConfigPatchWrapper
+	// status records the patches applied to the configuration of the proxy the wrapper was merged for.
+	status *EnvoyFilterStatus
 }
 
 // EnvoyFilterConfigPatchWrapper is a wrapper over the EnvoyFilter ConfigPatch api object
@@ -46,6 +54,14 @@ type EnvoyFilterConfigPatchWrapper struct {
 	// regex match, but as an optimization we can reduce this to a prefix match for common cases.
 	// If this is set, ProxyVersionRegex is ignored.
 	ProxyPrefixMatch string
+	// Name, Namespace and Priority of the EnvoyFilter the patch belongs to.
+	Name      string
+	Namespace string
+	Priority  int32
+	// Index is the position of the patch in the configPatches of the EnvoyFilter.
+	Index int
+	// applied is set once the patch is applied to the configuration of any proxy.
+	applied atomic.Bool
 }
 
 // wellKnownVersions defines a mapping of well known regex matches to prefix matches
@@ -68,12 +84,12 @@ var wellKnownVersions = map[string]string{
 func convertToEnvoyFilterWrapper(local *config.Config) *EnvoyFilterWrapper {
 	localEnvoyFilter := local.Spec.(*networking.EnvoyFilter)
 
-	out := &EnvoyFilterWrapper{Name: local.Name, Namespace: local.Namespace}
+	out := &EnvoyFilterWrapper{Name: local.Name, Namespace: local.Namespace, Priority: envoyFilterPriority(local)}
 	if localEnvoyFilter.WorkloadSelector != nil {
 		out.workloadSelector = localEnvoyFilter.WorkloadSelector.Labels
 	}
 	out.Patches = make(map[networking.EnvoyFilter_ApplyTo][]*EnvoyFilterConfigPatchWrapper)
-	for _, cp := range localEnvoyFilter.ConfigPatches {
+	for i, cp := range localEnvoyFilter.ConfigPatches {
 		if cp.Patch == nil {
 			// Should be caught by validation, but sometimes its disabled and we don't want to crash
 			// as a result.
@@ -86,6 +102,10 @@ func convertToEnvoyFilterWrapper(local *config.Config) *EnvoyFilterWrapper {
 			ApplyTo:   cp.ApplyTo,
 			Match:     cp.Match,
 			Operation: cp.Patch.Operation,
+			Name:      out.Name,
+			Namespace: out.Namespace,
+			Priority:  out.Priority,
+			Index:     i,
 		}
 		var err error
 		// Use non-strict building to avoid issues where EnvoyFilter is valid but meant
@@ -131,6 +151,21 @@ func convertToEnvoyFilterWrapper(local *config.Config) *EnvoyFilterWrapper {
 	return out
 }
 
+// envoyFilterPriority returns the priority set by the annotation of the EnvoyFilter, 0 if it is not set or invalid.
+func envoyFilterPriority(local *config.Config) int32 {
+	v, f := local.Annotations[constants.EnvoyFilterPriorityAnnotation]
+	if !f {
+		return 0
+	}
+	priority, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		// Should be caught by validation.
+		log.Warnf("envoyfilter %v/%v has an invalid priority %q, using 0", local.Namespace, local.Name, v)
+		return 0
+	}
+	return int32(priority)
+}
+
 func proxyMatch(proxy *Proxy, cp *EnvoyFilterConfigPatchWrapper) bool {
 	if cp.Match.Proxy == nil {
 		return true
@@ -166,3 +201,91 @@ func (efw *EnvoyFilterWrapper) Key() string {
 	}
 	return efw.Namespace + "/" + efw.Name
 }
+
+// Key returns the namespace/name of the EnvoyFilter the patch belongs to.
+func (cp *EnvoyFilterConfigPatchWrapper) Key() string {
+	return cp.Namespace + "/" + cp.Name
+}
+
+// Applied returns true if the patch was applied to the configuration of any proxy since the EnvoyFilters last changed.
+func (cp *EnvoyFilterConfigPatchWrapper) Applied() bool {
+	return cp.applied.Load()
+}
+
+// RecordApplied records that the patch was applied to the configuration generated with the wrapper.
+func (efw *EnvoyFilterWrapper) RecordApplied(cp *EnvoyFilterConfigPatchWrapper) {
+	if !cp.applied.Load() {
+		cp.applied.Store(true)
+	}
+	if efw != nil && efw.status != nil {
+		efw.status.record(cp)
+	}
+}
+
+// EnvoyFilterStatus records the EnvoyFilter patches applied to the configuration generated for a proxy with the
+// push context of a given version.
+type EnvoyFilterStatus struct {
+	mu          sync.Mutex
+	pushVersion string
+	applied     map[*EnvoyFilterConfigPatchWrapper]struct{}
+}
+
+// begin discards the recorded patches if they were applied with a different push context.
+func (s *EnvoyFilterStatus) begin(pushVersion string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pushVersion != pushVersion || s.applied == nil {
+		s.pushVersion = pushVersion
+		s.applied = make(map[*EnvoyFilterConfigPatchWrapper]struct{})
+	}
+}
+
+func (s *EnvoyFilterStatus) record(cp *EnvoyFilterConfigPatchWrapper) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.applied != nil {
+		s.applied[cp] = struct{}{}
+	}
+}
+
+// Applied returns the version of the push context the configuration of the proxy was last generated with,
+// and whether the patch was applied to it.
+func (s *EnvoyFilterStatus) Applied(cp *EnvoyFilterConfigPatchWrapper) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, f := s.applied[cp]
+	return s.pushVersion, f
+}
+
+// EnvoyFilterPatches returns the patches of all the EnvoyFilters, ordered by namespace, name and index.
+func (ps *PushContext) EnvoyFilterPatches() []*EnvoyFilterConfigPatchWrapper {
+	var out []*EnvoyFilterConfigPatchWrapper
+	for _, efws := range ps.envoyFiltersByNamespace {
+		for _, efw := range efws {
+			for _, cps := range efw.Patches {
+				out = append(out, cps...)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		if out[i].Name != out[j].Name {
+			return out[i].Name < out[j].Name
+		}
+		return out[i].Index < out[j].Index
+	})
+	return out
+}
+
+// UpdateEnvoyFilterMetrics records the number of EnvoyFilter patches which were not applied to any proxy.
+func (ps *PushContext) UpdateEnvoyFilterMetrics() {
+	unapplied := 0
+	for _, cp := range ps.EnvoyFilterPatches() {
+		if !cp.Applied() {
+			unapplied++
+		}
+	}
+	unappliedEnvoyFilterPatches.Record(float64(unapplied))
+}
@@ -15,10 +15,14 @@
 package model
 
 import (
+	"reflect"
+	"strconv"
 	"testing"
 
+	meshconfig "istio.io/api/mesh/v1alpha1"
 	networking "istio.io/api/networking/v1alpha3"
 	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/constants"
 )
 
 // TestEnvoyFilterMatch tests the matching logic for EnvoyFilter, in particular the regex -> prefix optimization
@@ -114,3 +118,114 @@ func TestEnvoyFilterMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestEnvoyFilterPriority(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		expected    int32
+	}{
+		{"no annotation", nil, 0},
+		{"positive", map[string]string{constants.EnvoyFilterPriorityAnnotation: "10"}, 10},
+		{"negative", map[string]string{constants.EnvoyFilterPriorityAnnotation: "-5"}, -5},
+		{"invalid", map[string]string{constants.EnvoyFilterPriorityAnnotation: "high"}, 0},
+		{"overflow", map[string]string{constants.EnvoyFilterPriorityAnnotation: "4294967296"}, 0},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convertToEnvoyFilterWrapper(&config.Config{
+				Meta: config.Meta{Name: "ef", Namespace: "ns", Annotations: tt.annotations},
+				Spec: &networking.EnvoyFilter{
+					ConfigPatches: []*networking.EnvoyFilter_EnvoyConfigObjectPatch{
+						{ApplyTo: networking.EnvoyFilter_CLUSTER, Patch: &networking.EnvoyFilter_Patch{}},
+					},
+				},
+			})
+			if got.Priority != tt.expected {
+				t.Errorf("expected priority %d, got %d", tt.expected, got.Priority)
+			}
+			cp := got.Patches[networking.EnvoyFilter_CLUSTER][0]
+			if cp.Priority != tt.expected || cp.Key() != "ns/ef" || cp.Index != 0 {
+				t.Errorf("unexpected patch attribution: %v %v %v", cp.Priority, cp.Key(), cp.Index)
+			}
+		})
+	}
+}
+
+func TestEnvoyFiltersOrder(t *testing.T) {
+	envoyFilter := func(namespace, name string, priority int32) *EnvoyFilterWrapper {
+		return convertToEnvoyFilterWrapper(&config.Config{
+			Meta: config.Meta{
+				Name:        name,
+				Namespace:   namespace,
+				Annotations: map[string]string{constants.EnvoyFilterPriorityAnnotation: strconv.Itoa(int(priority))},
+			},
+			Spec: &networking.EnvoyFilter{
+				ConfigPatches: []*networking.EnvoyFilter_EnvoyConfigObjectPatch{
+					{ApplyTo: networking.EnvoyFilter_CLUSTER, Patch: &networking.EnvoyFilter_Patch{}},
+				},
+			},
+		})
+	}
+	push := &PushContext{
+		Mesh: &meshconfig.MeshConfig{RootNamespace: "istio-system"},
+		envoyFiltersByNamespace: map[string][]*EnvoyFilterWrapper{
+			"istio-system": {envoyFilter("istio-system", "root-late", 10), envoyFilter("istio-system", "root", 0)},
+			"test-ns":      {envoyFilter("test-ns", "early", -10), envoyFilter("test-ns", "local", 0)},
+		},
+	}
+	for _, efws := range push.envoyFiltersByNamespace {
+		sortEnvoyFiltersByPriority(efws)
+	}
+
+	efw := push.EnvoyFilters(&Proxy{ConfigNamespace: "test-ns", Metadata: &NodeMetadata{}})
+	got := []string{}
+	for _, cp := range efw.Patches[networking.EnvoyFilter_CLUSTER] {
+		got = append(got, cp.Key())
+	}
+	expected := []string{"test-ns/early", "istio-system/root", "test-ns/local", "istio-system/root-late"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected order %v, got %v", expected, got)
+	}
+}
+
+func TestEnvoyFilterStatus(t *testing.T) {
+	efw := convertToEnvoyFilterWrapper(&config.Config{
+		Meta: config.Meta{Name: "ef", Namespace: "test-ns"},
+		Spec: &networking.EnvoyFilter{
+			ConfigPatches: []*networking.EnvoyFilter_EnvoyConfigObjectPatch{
+				{ApplyTo: networking.EnvoyFilter_CLUSTER, Patch: &networking.EnvoyFilter_Patch{}},
+				{ApplyTo: networking.EnvoyFilter_CLUSTER, Patch: &networking.EnvoyFilter_Patch{}},
+			},
+		},
+	})
+	push := &PushContext{
+		Mesh:                    &meshconfig.MeshConfig{RootNamespace: "istio-system"},
+		PushVersion:             "v1",
+		envoyFiltersByNamespace: map[string][]*EnvoyFilterWrapper{"test-ns": {efw}},
+	}
+	proxy := &Proxy{ConfigNamespace: "test-ns", Metadata: &NodeMetadata{}}
+	first, second := efw.Patches[networking.EnvoyFilter_CLUSTER][0], efw.Patches[networking.EnvoyFilter_CLUSTER][1]
+
+	push.EnvoyFilters(proxy).RecordApplied(first)
+	if version, applied := proxy.EnvoyFilterStatus.Applied(first); !applied || version != "v1" {
+		t.Errorf("expected first patch applied with v1, got %v %v", applied, version)
+	}
+	if _, applied := proxy.EnvoyFilterStatus.Applied(second); applied {
+		t.Errorf("expected second patch not applied")
+	}
+	if !first.Applied() || second.Applied() {
+		t.Errorf("unexpected global status: %v %v", first.Applied(), second.Applied())
+	}
+	got := push.EnvoyFilterPatches()
+	if len(got) != 2 || got[0] != first || got[1] != second {
+		t.Errorf("unexpected patches: %v", got)
+	}
+
+	// A new push context discards the patches recorded for the proxy.
+	push.PushVersion = "v2"
+	push.EnvoyFilters(proxy)
+	if version, applied := proxy.EnvoyFilterStatus.Applied(first); applied || version != "v2" {
+		t.Errorf("expected first patch not applied with v2, got %v %v", applied, version)
+	}
+}
@@ -204,6 +204,12 @@ type PushContext struct {
 	// JwtKeyResolver holds a reference to the JWT key resolver instance.
 	JwtKeyResolver *JwksResolver
 
+	// CertificateRevocationList is the PEM encoded CRL of the mesh CA, nil if nothing is revoked.
+	CertificateRevocationList []byte `json:"-"`
+
+	// RevokedIdentities are the sorted SPIFFE IDs revoked by the mesh CA.
+	RevokedIdentities []string `json:"-"`
+
 	// cache gateways addresses for each network
 	// this is mainly used for kubernetes multi-cluster scenario
 	networksMu      sync.RWMutex
@@ -497,6 +503,12 @@ var (
 		"Total virtual services known to pilot.",
 	)
 
+	// unappliedEnvoyFilterPatches tracks the EnvoyFilter patches which were not applied to any proxy.
+	unappliedEnvoyFilterPatches = monitoring.NewGauge(
+		"pilot_envoy_filter_unapplied_patches",
+		"Number of EnvoyFilter patches which were not applied to the configuration of any proxy since the EnvoyFilters last changed.",
+	)
+
 	// LastPushStatus preserves the metrics and data collected during lasts global push.
 	// It can be used by debugging tools to inspect the push event. It will be reset after each push with the
 	// new version.
@@ -525,6 +537,7 @@ func init() {
 		monitoring.MustRegister(m)
 	}
 	monitoring.MustRegister(totalVirtualServices)
+	monitoring.MustRegister(unappliedEnvoyFilterPatches)
 }
 
 // NewPushContext creates a new PushContext structure to track push status.
@@ -958,6 +971,8 @@ func (ps *PushContext) InitContext(env *Environment, oldPushContext *PushContext
 	ps.ServiceDiscovery = env.ServiceDiscovery
 	ps.IstioConfigStore = env.IstioConfigStore
 	ps.LedgerVersion = env.Version()
+	ps.CertificateRevocationList = env.CertificateRevocationList.Get()
+	ps.RevokedIdentities = env.RevokedIdentities.Get()
 
 	// Must be initialized first
 	// as initServiceRegistry/VirtualServices/Destrules
@@ -1616,9 +1631,19 @@ func (ps *PushContext) initEnvoyFilters(env *Environment) error {
 		}
 		ps.envoyFiltersByNamespace[envoyFilterConfig.Namespace] = append(ps.envoyFiltersByNamespace[envoyFilterConfig.Namespace], efw)
 	}
+	// EnvoyFilters of the same priority keep their creation order.
+	for _, efws := range ps.envoyFiltersByNamespace {
+		sortEnvoyFiltersByPriority(efws)
+	}
 	return nil
 }
 
+func sortEnvoyFiltersByPriority(efws []*EnvoyFilterWrapper) {
+	sort.SliceStable(efws, func(i, j int) bool {
+		return efws[i].Priority < efws[j].Priority
+	})
+}
+
 // EnvoyFilters return the merged EnvoyFilterWrapper of a proxy
 func (ps *PushContext) EnvoyFilters(proxy *Proxy) *EnvoyFilterWrapper {
 	// this should never happen
@@ -1658,11 +1683,16 @@ func (ps *PushContext) EnvoyFilters(proxy *Proxy) *EnvoyFilterWrapper {
 		}
 	}
 
+	// The root namespace EnvoyFilters are applied first among the ones of the same priority.
+	sortEnvoyFiltersByPriority(matchedEnvoyFilters)
+
 	var out *EnvoyFilterWrapper
 	if len(matchedEnvoyFilters) > 0 {
+		proxy.EnvoyFilterStatus.begin(ps.PushVersion)
 		out = &EnvoyFilterWrapper{
 			// no need populate workloadSelector, as it is not used later.
 			Patches: make(map[networking.EnvoyFilter_ApplyTo][]*EnvoyFilterConfigPatchWrapper),
+			status:  &proxy.EnvoyFilterStatus,
 		}
 		// merge EnvoyFilterWrapper
 		for _, efw := range matchedEnvoyFilters {
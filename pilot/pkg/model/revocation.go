@@ -0,0 +1,80 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"reflect"
+	"sync"
+)
+
+// CertificateRevocationList holds the PEM encoded CRL of the mesh CA. It is pushed to the proxies, so they
+// reject the revoked peer certificates during the TLS handshake.
+type CertificateRevocationList struct {
+	mutex sync.RWMutex
+	crl   []byte
+}
+
+// Get returns the CRL, or nil if nothing is revoked.
+func (c *CertificateRevocationList) Get() []byte {
+	if c == nil {
+		return nil
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.crl
+}
+
+// Set replaces the CRL, and returns true if it changed.
+func (c *CertificateRevocationList) Set(crl []byte) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if bytes.Equal(c.crl, crl) {
+		return false
+	}
+	c.crl = crl
+	return true
+}
+
+// RevokedIdentities holds the SPIFFE IDs revoked by the mesh CA. The proxies deny their requests by principal,
+// since their unexpired certificates may have been signed by any CA replica.
+type RevokedIdentities struct {
+	mutex      sync.RWMutex
+	identities []string
+}
+
+// Get returns the sorted revoked identities.
+func (r *RevokedIdentities) Get() []string {
+	if r == nil {
+		return nil
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.identities
+}
+
+// Set replaces the sorted revoked identities, and returns true if they changed.
+func (r *RevokedIdentities) Set(identities []string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if len(identities) == 0 {
+		identities = nil
+	}
+	if reflect.DeepEqual(r.identities, identities) {
+		return false
+	}
+	r.identities = identities
+	return true
+}
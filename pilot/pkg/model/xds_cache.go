@@ -16,6 +16,8 @@ package model
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/golang/protobuf/ptypes/any"
@@ -23,9 +25,11 @@ import (
 	"github.com/hashicorp/golang-lru/simplelru"
 	"go.uber.org/atomic"
 	"google.golang.org/protobuf/testing/protocmp"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/util/sets"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
 	"istio.io/istio/pkg/config"
 	"istio.io/pkg/monitoring"
 )
@@ -34,18 +38,22 @@ func init() {
 	monitoring.MustRegister(xdsCacheReads)
 	monitoring.MustRegister(xdsCacheEvictions)
 	monitoring.MustRegister(xdsCacheSize)
+	monitoring.MustRegister(xdsCacheBytes)
 }
 
 var (
+	resourceTag = monitoring.MustCreateLabel("resource")
+
 	xdsCacheReads = monitoring.NewSum(
 		"xds_cache_reads",
 		"Total number of xds cache xdsCacheReads.",
-		monitoring.WithLabels(typeTag),
+		monitoring.WithLabels(typeTag, resourceTag),
 	)
 
 	xdsCacheEvictions = monitoring.NewSum(
 		"xds_cache_evictions",
 		"Total number of xds cache evictions.",
+		monitoring.WithLabels(resourceTag),
 	)
 
 	xdsCacheSize = monitoring.NewGauge(
@@ -53,25 +61,28 @@ var (
 		"Current size of xds cache",
 	)
 
-	xdsCacheHits   = xdsCacheReads.With(typeTag.Value("hit"))
-	xdsCacheMisses = xdsCacheReads.With(typeTag.Value("miss"))
+	xdsCacheBytes = monitoring.NewGauge(
+		"xds_cache_bytes",
+		"Current size in bytes of the xds cache entries of a resource type.",
+		monitoring.WithLabels(resourceTag),
+	)
 )
 
-func hit() {
+func hit(typeURL string) {
 	if features.EnableXDSCacheMetrics {
-		xdsCacheHits.Increment()
+		xdsCacheReads.With(typeTag.Value("hit"), resourceTag.Value(v3.GetMetricType(typeURL))).Increment()
 	}
 }
 
-func miss() {
+func miss(typeURL string) {
 	if features.EnableXDSCacheMetrics {
-		xdsCacheMisses.Increment()
+		xdsCacheReads.With(typeTag.Value("miss"), resourceTag.Value(v3.GetMetricType(typeURL))).Increment()
 	}
 }
 
-func evict(k interface{}, v interface{}) {
+func evict(typeURL string) {
 	if features.EnableXDSCacheMetrics {
-		xdsCacheEvictions.Increment()
+		xdsCacheEvictions.With(resourceTag.Value(v3.GetMetricType(typeURL))).Increment()
 	}
 }
 
@@ -81,6 +92,12 @@ func size(cs int) {
 	}
 }
 
+func bytesSize(typeURL string, bytes int64) {
+	if features.EnableXDSCacheMetrics {
+		xdsCacheBytes.With(resourceTag.Value(v3.GetMetricType(typeURL))).Record(float64(bytes))
+	}
+}
+
 func indexConfig(configIndex map[ConfigKey]sets.Set, k string, entry XdsCacheEntry) {
 	for _, config := range entry.DependentConfigs() {
 		if configIndex[config] == nil {
@@ -104,6 +121,8 @@ func indexType(typeIndex map[config.GroupVersionKind]sets.Set, k string, entry X
 type XdsCacheEntry interface {
 	// Key is the key to be used in cache.
 	Key() string
+	// TypeURL is the type URL of the cached resource.
+	TypeURL() string
 	// DependentTypes are config types that this cache key is dependant on.
 	// Whenever any configs of this type changes, we should invalidate this cache entry.
 	// Note: DependentConfigs should be preferred wherever possible.
@@ -144,33 +163,93 @@ type XdsCache interface {
 	ClearAll()
 	// Keys returns all currently configured keys. This is for testing/debug only
 	Keys() []string
+	// Entries returns the size and dependent configs of all the cached values. This is for debug only.
+	Entries() []XdsCacheEntryInfo
+}
+
+// XdsCacheEntryInfo describes a cached value.
+type XdsCacheEntryInfo struct {
+	Key  string `json:"key"`
+	Type string `json:"type"`
+	// Size is the size of the cached value in bytes.
+	Size int64 `json:"size"`
+	// DependentConfigs are the configs invalidating the entry, from the config index of the cache.
+	DependentConfigs []string `json:"dependentConfigs,omitempty"`
 }
 
-// NewXdsCache returns an instance of a cache.
+// NewXdsCache returns an instance of a cache. If PILOT_XDS_CACHE_MAX_BYTES is set, the cached values of each
+// resource type are bounded by the given size in bytes.
 func NewXdsCache() XdsCache {
-	return &lruCache{
-		enableAssertions: features.EnableUnsafeAssertions,
-		store:            newLru(),
-		configIndex:      map[ConfigKey]sets.Set{},
-		typesIndex:       map[config.GroupVersionKind]sets.Set{},
-		nextToken:        atomic.NewUint64(0),
+	budgets, err := ParseXdsCacheBudgets(features.XDSCacheMaxBytes)
+	if err != nil {
+		log.Errorf("invalid PILOT_XDS_CACHE_MAX_BYTES, the XDS cache is only bounded by the number of entries: %v", err)
+		budgets = nil
 	}
+	return newLruCache(features.EnableUnsafeAssertions, budgets)
 }
 
 // NewLenientXdsCache returns an instance of a cache that does not validate token based get/set and enable assertions.
 func NewLenientXdsCache() XdsCache {
-	return &lruCache{
-		enableAssertions: false,
-		store:            newLru(),
-		configIndex:      map[ConfigKey]sets.Set{},
-		typesIndex:       map[config.GroupVersionKind]sets.Set{},
-		nextToken:        atomic.NewUint64(0),
+	return newLruCache(false, nil)
+}
+
+// NewLenientBudgetedXdsCache returns an instance of a cache that does not validate token based get/set and enable
+// assertions, with the cached values of each resource type bounded by the budgets in bytes, keyed by type URL.
+func NewLenientBudgetedXdsCache(budgets map[string]int64) XdsCache {
+	return newLruCache(false, budgets)
+}
+
+// ParseXdsCacheBudgets parses a comma separated list of <type>=<size> budgets, where type is the short
+// name of a cached resource type (eds or sds) and size is a quantity such as 64Mi. The budgets are keyed by type URL.
+func ParseXdsCacheBudgets(s string) (map[string]int64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	budgets := map[string]int64{}
+	for _, b := range strings.Split(s, ",") {
+		b = strings.TrimSpace(b)
+		if b == "" {
+			continue
+		}
+		kv := strings.SplitN(b, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid budget %q, expected <type>=<size>", b)
+		}
+		typeURL := xdsCacheTypeURL(strings.TrimSpace(kv[0]))
+		if typeURL == "" {
+			return nil, fmt.Errorf("invalid budget %q, resource type %s is not cached", b, kv[0])
+		}
+		q, err := resource.ParseQuantity(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid budget %q: %v", b, err)
+		}
+		if q.Value() <= 0 {
+			return nil, fmt.Errorf("invalid budget %q, the size must be positive", b)
+		}
+		budgets[typeURL] = q.Value()
 	}
+	return budgets, nil
+}
+
+// xdsCacheTypeURL returns the type URL of a cached resource type from its metric name, or an empty string if the
+// resource type is unknown or not cached. Only the EDS and SDS entries are cached.
+func xdsCacheTypeURL(name string) string {
+	for _, t := range []string{v3.EndpointType, v3.SecretType} {
+		if v3.GetMetricType(t) == strings.ToLower(name) {
+			return t
+		}
+	}
+	return ""
 }
 
 type lruCache struct {
 	enableAssertions bool
-	store            simplelru.LRUCache
+	// stores holds a LRU per resource type if the cache has budgets, or a single LRU for all the types otherwise.
+	stores map[string]simplelru.LRUCache
+	// budgets is the maximum size in bytes of the cached values of each resource type.
+	budgets map[string]int64
+	// sizes is the current size in bytes of the cached values of each resource type.
+	sizes map[string]int64
 	// nextToken stores the next token to use. The content here doesn't matter, we just need a cheap
 	// unique identifier.
 	nextToken   *atomic.Uint64
@@ -181,16 +260,67 @@ type lruCache struct {
 
 var _ XdsCache = &lruCache{}
 
-func newLru() simplelru.LRUCache {
+func newLruCache(enableAssertions bool, budgets map[string]int64) *lruCache {
+	if len(budgets) == 0 {
+		budgets = nil
+	}
+	return &lruCache{
+		enableAssertions: enableAssertions,
+		stores:           map[string]simplelru.LRUCache{},
+		budgets:          budgets,
+		sizes:            map[string]int64{},
+		configIndex:      map[ConfigKey]sets.Set{},
+		typesIndex:       map[config.GroupVersionKind]sets.Set{},
+		nextToken:        atomic.NewUint64(0),
+	}
+}
+
+// store returns the LRU holding the values of the resource type, creating it if needed.
+func (l *lruCache) store(typeURL string) simplelru.LRUCache {
+	if l.budgets == nil {
+		typeURL = ""
+	}
+	if s, f := l.stores[typeURL]; f {
+		return s
+	}
+	s := l.newLru()
+	l.stores[typeURL] = s
+	return s
+}
+
+func (l *lruCache) newLru() simplelru.LRUCache {
 	sz := features.XDSCacheMaxSize
 	if sz <= 0 {
 		sz = 20000
 	}
-	l, err := simplelru.NewLRU(sz, evict)
+	lru, err := simplelru.NewLRU(sz, l.onEvict)
 	if err != nil {
 		panic(fmt.Errorf("invalid lru configuration: %v", err))
 	}
-	return l
+	return lru
+}
+
+// onEvict is called by the LRUs, with the lock held, whenever a value is removed.
+func (l *lruCache) onEvict(k interface{}, v interface{}) {
+	cv := v.(cacheValue)
+	l.sizes[cv.typeURL] -= cv.size
+	evict(cv.typeURL)
+}
+
+func (l *lruCache) len() int {
+	n := 0
+	for _, s := range l.stores {
+		n += s.Len()
+	}
+	return n
+}
+
+// recordSizes records the size metrics.
+func (l *lruCache) recordSizes() {
+	size(l.len())
+	for t, bytes := range l.sizes {
+		bytesSize(t, bytes)
+	}
 }
 
 // assertUnchanged checks that a cache entry is not changed. This helps catch bad cache invalidation
@@ -220,8 +350,10 @@ func (l *lruCache) Add(entry XdsCacheEntry, token CacheToken, value *any.Any) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	k := entry.Key()
-	cur, f := l.store.Get(k)
-	toWrite := cacheValue{value: value}
+	t := entry.TypeURL()
+	store := l.store(t)
+	cur, f := store.Get(k)
+	toWrite := cacheValue{value: value, typeURL: t, size: int64(len(value.GetValue()))}
 	if f {
 		if token != cur.(cacheValue).token {
 			// entry may be stale, we need to drop it. This can happen when the cache is invalidated
@@ -242,15 +374,34 @@ func (l *lruCache) Add(entry XdsCacheEntry, token CacheToken, value *any.Any) {
 		}
 		l.assertUnchanged(cur.(cacheValue).value, value)
 	}
-	l.store.Add(k, toWrite)
+	budget, bounded := l.budgets[t]
+	if bounded && toWrite.size > budget {
+		// The value would evict every other value of its type, don't cache it.
+		store.Remove(k)
+		l.recordSizes()
+		return
+	}
+	// Updating an existing key does not call onEvict, account for the replaced value.
+	l.sizes[t] += toWrite.size - cur.(cacheValue).size
+	store.Add(k, toWrite)
+	if bounded {
+		for l.sizes[t] > budget {
+			if _, _, ok := store.RemoveOldest(); !ok {
+				break
+			}
+		}
+	}
 	indexConfig(l.configIndex, entry.Key(), entry)
 	indexType(l.typesIndex, entry.Key(), entry)
-	size(l.store.Len())
+	l.recordSizes()
 }
 
 type cacheValue struct {
 	value *any.Any
 	token CacheToken
+	// typeURL is the type of the cached value, and size its size in bytes.
+	typeURL string
+	size    int64
 }
 
 func (l *lruCache) Get(entry XdsCacheEntry) (*any.Any, CacheToken, bool) {
@@ -260,26 +411,34 @@ func (l *lruCache) Get(entry XdsCacheEntry) (*any.Any, CacheToken, bool) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	k := entry.Key()
-	val, ok := l.store.Get(k)
+	t := entry.TypeURL()
+	store := l.store(t)
+	val, ok := store.Get(k)
 	if !ok {
-		miss()
+		miss(t)
 		// If the entry is not found at all, this is our first read of it. We will generate and store
 		// a new token. Subsequent writes must include it.
 		tok := CacheToken(l.nextToken.Inc())
-		l.store.Add(k, cacheValue{token: tok})
+		store.Add(k, cacheValue{token: tok, typeURL: t})
 		return nil, tok, false
 	}
 	cv := val.(cacheValue)
 	if cv.value == nil {
-		miss()
+		miss(t)
 		// We have generated a token previously, so return that, but this is still a cache miss as
 		// no value is stored.
 		return nil, cv.token, false
 	}
-	hit()
+	hit(t)
 	return cv.value, cv.token, true
 }
 
+func (l *lruCache) remove(key string) {
+	for _, s := range l.stores {
+		s.Remove(key)
+	}
+}
+
 func (l *lruCache) Clear(configs map[ConfigKey]struct{}) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -287,36 +446,71 @@ func (l *lruCache) Clear(configs map[ConfigKey]struct{}) {
 		referenced := l.configIndex[ckey]
 		delete(l.configIndex, ckey)
 		for key := range referenced {
-			l.store.Remove(key)
+			l.remove(key)
 		}
 		tReferenced := l.typesIndex[ckey.Kind]
 		delete(l.typesIndex, ckey.Kind)
 		for key := range tReferenced {
-			l.store.Remove(key)
+			l.remove(key)
 		}
 	}
-	size(l.store.Len())
+	l.recordSizes()
 }
 
 func (l *lruCache) ClearAll() {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.store.Purge()
+	for _, s := range l.stores {
+		s.Purge()
+	}
 	l.configIndex = map[ConfigKey]sets.Set{}
-	size(l.store.Len())
+	l.recordSizes()
 }
 
 func (l *lruCache) Keys() []string {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
-	iKeys := l.store.Keys()
-	keys := make([]string, 0, len(iKeys))
-	for _, ik := range iKeys {
-		keys = append(keys, ik.(string))
+	keys := make([]string, 0, l.len())
+	for _, s := range l.stores {
+		for _, ik := range s.Keys() {
+			keys = append(keys, ik.(string))
+		}
 	}
 	return keys
 }
 
+func (l *lruCache) Entries() []XdsCacheEntryInfo {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	dependentConfigs := map[string][]string{}
+	for ckey, keys := range l.configIndex {
+		for key := range keys {
+			dependentConfigs[key] = append(dependentConfigs[key], ckey.String())
+		}
+	}
+	for _, configs := range dependentConfigs {
+		sort.Strings(configs)
+	}
+	entries := make([]XdsCacheEntryInfo, 0, l.len())
+	for _, s := range l.stores {
+		for _, ik := range s.Keys() {
+			v, f := s.Peek(ik)
+			if !f || v.(cacheValue).value == nil {
+				continue
+			}
+			cv := v.(cacheValue)
+			key := ik.(string)
+			entries = append(entries, XdsCacheEntryInfo{
+				Key:              key,
+				Type:             cv.typeURL,
+				Size:             cv.size,
+				DependentConfigs: dependentConfigs[key],
+			})
+		}
+	}
+	return entries
+}
+
 // DisabledCache is a cache that is always empty
 type DisabledCache struct{}
 
@@ -333,3 +527,5 @@ func (d DisabledCache) Clear(configsUpdated map[ConfigKey]struct{}) {}
 func (d DisabledCache) ClearAll() {}
 
 func (d DisabledCache) Keys() []string { return nil }
+
+func (d DisabledCache) Entries() []XdsCacheEntryInfo { return nil }
@@ -103,7 +103,7 @@ func (g *APIGenerator) Generate(proxy *model.Proxy, push *model.PushContext, w *
 		// Right now model.Config is not a proto - until we change it, mcp.Resource.
 		// This also helps migrating MCP users.
 
-		b, err := configToResource(&c)
+		b, err := ConfigToResource(&c)
 		if err != nil {
 			log.Warn("Resource error ", err, " ", c.Namespace, "/", c.Name)
 			continue
@@ -131,7 +131,7 @@ func (g *APIGenerator) Generate(proxy *model.Proxy, push *model.PushContext, w *
 				continue
 			}
 			c := serviceentry.ServiceToServiceEntry(s)
-			b, err := configToResource(c)
+			b, err := ConfigToResource(c)
 			if err != nil {
 				log.Warn("Resource error ", err, " ", c.Namespace, "/", c.Name)
 				continue
@@ -151,9 +151,9 @@ func (g *APIGenerator) Generate(proxy *model.Proxy, push *model.PushContext, w *
 	return resp, nil
 }
 
-// Convert from model.Config, which has no associated proto, to MCP Resource proto.
+// ConfigToResource converts from model.Config, which has no associated proto, to MCP Resource proto.
 // TODO: define a proto matching Config - to avoid useless superficial conversions.
-func configToResource(c *config.Config) (*mcp.Resource, error) {
+func ConfigToResource(c *config.Config) (*mcp.Resource, error) {
 	r := &mcp.Resource{}
 
 	// MCP, K8S and Istio configs use gogo configs
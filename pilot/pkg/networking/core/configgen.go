@@ -37,6 +37,11 @@ type ConfigGenerator interface {
 	// BuildClusters returns the list of clusters for the given proxy. This is the CDS output
 	BuildClusters(node *model.Proxy, push *model.PushContext) []*cluster.Cluster
 
+	// BuildDeltaClusters returns both a list of resources that need to be pushed for a given proxy and a list of resources
+	// that have been deleted and should be removed from a given proxy. This is Delta CDS output.
+	BuildDeltaClusters(node *model.Proxy, push *model.PushContext, updates *model.PushRequest,
+		watched *model.WatchedResource) ([]*cluster.Cluster, []string, bool)
+
 	// BuildHTTPRoutes returns the list of HTTP routes for the given proxy. This is the RDS output
 	BuildHTTPRoutes(node *model.Proxy, push *model.PushContext, routeNames []string) []*route.RouteConfiguration
 
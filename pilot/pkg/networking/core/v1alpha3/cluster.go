@@ -34,8 +34,10 @@ import (
 	"istio.io/istio/pilot/pkg/networking/core/v1alpha3/loadbalancer"
 	"istio.io/istio/pilot/pkg/networking/util"
 	"istio.io/istio/pilot/pkg/serviceregistry"
+	"istio.io/istio/pilot/pkg/util/sets"
 	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/config/schema/gvk"
 	"istio.io/istio/pkg/util/gogo"
 )
 
@@ -84,7 +86,7 @@ func (configgen *ConfigGeneratorImpl) BuildClusters(proxy *model.Proxy, push *mo
 	case model.SidecarProxy:
 		// Setup outbound clusters
 		outboundPatcher := clusterPatcher{efw: envoyFilterPatches, pctx: networking.EnvoyFilter_SIDECAR_OUTBOUND}
-		clusters = append(clusters, configgen.buildOutboundClusters(cb, outboundPatcher)...)
+		clusters = append(clusters, configgen.buildOutboundClusters(cb, outboundServices(cb), outboundPatcher)...)
 		// Add a blackhole and passthrough cluster for catching traffic to unresolved routes
 		clusters = outboundPatcher.conditionallyAppend(clusters, nil, cb.buildBlackHoleCluster(), cb.buildDefaultPassthroughCluster())
 		clusters = append(clusters, outboundPatcher.insertedClusters()...)
@@ -99,7 +101,7 @@ func (configgen *ConfigGeneratorImpl) BuildClusters(proxy *model.Proxy, push *mo
 		inboundPatcher.incrementFilterMetrics()
 	default: // Gateways
 		patcher := clusterPatcher{efw: envoyFilterPatches, pctx: networking.EnvoyFilter_GATEWAY}
-		clusters = append(clusters, configgen.buildOutboundClusters(cb, patcher)...)
+		clusters = append(clusters, configgen.buildOutboundClusters(cb, outboundServices(cb), patcher)...)
 		// Gateways do not require the default passthrough cluster as they do not have original dst listeners.
 		clusters = patcher.conditionallyAppend(clusters, nil, cb.buildBlackHoleCluster())
 		if proxy.Type == model.Router && proxy.GetRouterMode() == model.SniDnatRouter {
@@ -112,16 +114,67 @@ func (configgen *ConfigGeneratorImpl) BuildClusters(proxy *model.Proxy, push *mo
 	return cb.normalizeClusters(clusters)
 }
 
-func (configgen *ConfigGeneratorImpl) buildOutboundClusters(cb *ClusterBuilder, cp clusterPatcher) []*cluster.Cluster {
-	clusters := make([]*cluster.Cluster, 0)
-	networkView := model.GetNetworkView(cb.proxy)
+// BuildDeltaClusters returns the clusters of the services updated by the push request, along with the names of the
+// clusters which were removed. Only ServiceEntry updates impacting the outbound clusters of a sidecar are generated
+// as deltas. Otherwise, all the clusters are returned as in BuildClusters, and usedDelta is false.
+func (configgen *ConfigGeneratorImpl) BuildDeltaClusters(proxy *model.Proxy, push *model.PushContext, updates *model.PushRequest,
+	watched *model.WatchedResource) (clusters []*cluster.Cluster, removed []string, usedDelta bool) {
+	if updates == nil || watched == nil || proxy.Type != model.SidecarProxy ||
+		!model.HasConfigsOnlyOfKind(updates.ConfigsUpdated, gvk.ServiceEntry) {
+		return configgen.BuildClusters(proxy, push), nil, false
+	}
+	updatedHosts := model.ConfigNamesOfKind(updates.ConfigsUpdated, gvk.ServiceEntry)
+	// Inbound clusters are built from the proxy service instances, so fall back to a full push
+	// if any of the services of the proxy itself changed.
+	for _, instance := range proxy.ServiceInstances {
+		if _, f := updatedHosts[string(instance.Service.Hostname)]; f {
+			return configgen.BuildClusters(proxy, push), nil, false
+		}
+	}
+
+	cb := NewClusterBuilder(proxy, push)
+	services := make([]*model.Service, 0, len(updatedHosts))
+	for _, service := range outboundServices(cb) {
+		if _, f := updatedHosts[string(service.Hostname)]; f {
+			services = append(services, service)
+		}
+	}
+	outboundPatcher := clusterPatcher{efw: push.EnvoyFilters(proxy), pctx: networking.EnvoyFilter_SIDECAR_OUTBOUND}
+	clusters = cb.normalizeClusters(configgen.buildOutboundClusters(cb, services, outboundPatcher))
+
+	// Clusters of the updated hosts which are not generated anymore, because the service or some of its ports
+	// were deleted, must be removed.
+	generated := sets.NewSet()
+	for _, c := range clusters {
+		generated.Insert(c.Name)
+	}
+	for _, name := range watched.ResourceNames {
+		if generated.Contains(name) {
+			continue
+		}
+		dir, _, hostname, _ := model.ParseSubsetKey(name)
+		if dir != model.TrafficDirectionOutbound {
+			continue
+		}
+		if _, f := updatedHosts[string(hostname)]; f {
+			removed = append(removed, name)
+		}
+	}
+	return clusters, removed, true
+}
 
-	var services []*model.Service
+// outboundServices returns the services for which outbound clusters are generated.
+func outboundServices(cb *ClusterBuilder) []*model.Service {
 	if features.FilterGatewayClusterConfig && cb.proxy.Type == model.Router {
-		services = cb.push.GatewayServices(cb.proxy)
-	} else {
-		services = cb.push.Services(cb.proxy)
+		return cb.push.GatewayServices(cb.proxy)
 	}
+	return cb.push.Services(cb.proxy)
+}
+
+func (configgen *ConfigGeneratorImpl) buildOutboundClusters(cb *ClusterBuilder, services []*model.Service, cp clusterPatcher) []*cluster.Cluster {
+	clusters := make([]*cluster.Cluster, 0)
+	networkView := model.GetNetworkView(cb.proxy)
+
 	for _, service := range services {
 		for _, port := range service.Ports {
 			if port.Protocol == protocol.UDP {
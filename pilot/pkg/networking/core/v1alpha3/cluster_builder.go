@@ -747,6 +747,9 @@ func (cb *ClusterBuilder) buildUpstreamClusterTLSContext(opts *buildClusterOpts,
 				ValidationContextSdsSecretConfig: authn_model.ConstructSdsSecretConfig(authn_model.SDSRootResourceName, proxy),
 			},
 		}
+		if cb.push != nil {
+			authn_model.ApplyCertificateRevocationList(tlsContext.CommonTlsContext, cb.push.CertificateRevocationList)
+		}
 		// Set default SNI of cluster name for istio_mutual if sni is not set.
 		if len(tlsContext.Sni) == 0 {
 			tlsContext.Sni = c.cluster.Name
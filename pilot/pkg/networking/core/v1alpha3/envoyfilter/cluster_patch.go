@@ -53,6 +53,7 @@ func ApplyClusterMerge(pctx networking.EnvoyFilter_PatchContext, efw *model.Envo
 				continue
 			}
 			applied = true
+			efw.RecordApplied(cp)
 			if !ret {
 				proto.Merge(c, cp.Value)
 			}
@@ -116,6 +117,7 @@ func ShouldKeepCluster(pctx networking.EnvoyFilter_PatchContext, efw *model.Envo
 			continue
 		}
 		if commonConditionMatch(pctx, cp) && clusterMatch(c, cp, hosts) {
+			efw.RecordApplied(cp)
 			return false
 		}
 	}
@@ -133,6 +135,7 @@ func InsertedClusters(pctx networking.EnvoyFilter_PatchContext, efw *model.Envoy
 		if cp.Operation == networking.EnvoyFilter_Patch_ADD {
 			if commonConditionMatch(pctx, cp) {
 				result = append(result, proto.Clone(cp.Value).(*cluster.Cluster))
+				efw.RecordApplied(cp)
 			}
 		}
 	}
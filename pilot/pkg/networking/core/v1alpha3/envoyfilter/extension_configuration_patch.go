@@ -44,6 +44,7 @@ func InsertedExtensionConfigurations(efw *model.EnvoyFilterWrapper, names []stri
 		}
 		if _, ok := hasName[ec.GetName()]; ok {
 			result = append(result, proto.Clone(p.Value).(*core.TypedExtensionConfig))
+			efw.RecordApplied(p)
 		}
 	}
 	return result
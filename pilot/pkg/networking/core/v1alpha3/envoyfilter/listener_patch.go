@@ -67,7 +67,6 @@ func patchListeners(
 	listeners []*xdslistener.Listener,
 	skipAdds bool) []*xdslistener.Listener {
 	listenersRemoved := false
-	filterKey := efw.Key()
 
 	// do all the changes for a single envoy filter crd object. [including adds]
 	// then move on to the next one
@@ -78,7 +77,7 @@ func patchListeners(
 			// removed by another op
 			continue
 		}
-		patchListener(patchContext, filterKey, efw.Patches, listener, &listenersRemoved)
+		patchListener(patchContext, efw, listener, &listenersRemoved)
 	}
 	// adds at listener level if enabled
 	applied := false
@@ -93,10 +92,11 @@ func patchListeners(
 				// the master value stored in CP..
 				listeners = append(listeners, proto.Clone(lp.Value).(*xdslistener.Listener))
 				applied = true
+				efw.RecordApplied(lp)
 			}
 		}
 	}
-	IncrementEnvoyFilterMetric(filterKey, Listener, applied)
+	IncrementEnvoyFilterMetric(efw.Key(), Listener, applied)
 	if listenersRemoved {
 		tempArray := make([]*xdslistener.Listener, 0, len(listeners))
 		for _, l := range listeners {
@@ -110,16 +110,16 @@ func patchListeners(
 }
 
 func patchListener(patchContext networking.EnvoyFilter_PatchContext,
-	filterKey string,
-	patches map[networking.EnvoyFilter_ApplyTo][]*model.EnvoyFilterConfigPatchWrapper,
+	efw *model.EnvoyFilterWrapper,
 	listener *xdslistener.Listener, listenersRemoved *bool) {
 	applied := false
-	for _, cp := range patches[networking.EnvoyFilter_LISTENER] {
+	for _, cp := range efw.Patches[networking.EnvoyFilter_LISTENER] {
 		if !commonConditionMatch(patchContext, cp) ||
 			!listenerMatch(listener, cp) {
 			continue
 		}
 		applied = true
+		efw.RecordApplied(cp)
 		if cp.Operation == networking.EnvoyFilter_Patch_REMOVE {
 			listener.Name = ""
 			*listenersRemoved = true
@@ -129,40 +129,40 @@ func patchListener(patchContext networking.EnvoyFilter_PatchContext,
 			proto.Merge(listener, cp.Value)
 		}
 	}
-	IncrementEnvoyFilterMetric(filterKey, Listener, applied)
-	patchFilterChains(patchContext, filterKey, patches, listener)
+	IncrementEnvoyFilterMetric(efw.Key(), Listener, applied)
+	patchFilterChains(patchContext, efw, listener)
 }
 
 func patchFilterChains(patchContext networking.EnvoyFilter_PatchContext,
-	filterKey string,
-	patches map[networking.EnvoyFilter_ApplyTo][]*model.EnvoyFilterConfigPatchWrapper,
+	efw *model.EnvoyFilterWrapper,
 	listener *xdslistener.Listener) {
 	filterChainsRemoved := false
 	for i, fc := range listener.FilterChains {
 		if fc.Filters == nil {
 			continue
 		}
-		patchFilterChain(patchContext, filterKey, patches, listener, listener.FilterChains[i], &filterChainsRemoved)
+		patchFilterChain(patchContext, efw, listener, listener.FilterChains[i], &filterChainsRemoved)
 	}
 	if fc := listener.GetDefaultFilterChain(); fc.GetFilters() != nil {
 		removed := false
-		patchFilterChain(patchContext, filterKey, patches, listener, fc, &removed)
+		patchFilterChain(patchContext, efw, listener, fc, &removed)
 		if removed {
 			listener.DefaultFilterChain = nil
 		}
 	}
 	applied := false
-	for _, cp := range patches[networking.EnvoyFilter_FILTER_CHAIN] {
+	for _, cp := range efw.Patches[networking.EnvoyFilter_FILTER_CHAIN] {
 		if cp.Operation == networking.EnvoyFilter_Patch_ADD {
 			if !commonConditionMatch(patchContext, cp) ||
 				!listenerMatch(listener, cp) {
 				continue
 			}
 			applied = true
+			efw.RecordApplied(cp)
 			listener.FilterChains = append(listener.FilterChains, proto.Clone(cp.Value).(*xdslistener.FilterChain))
 		}
 	}
-	IncrementEnvoyFilterMetric(filterKey, FilterChain, applied)
+	IncrementEnvoyFilterMetric(efw.Key(), FilterChain, applied)
 	if filterChainsRemoved {
 		tempArray := make([]*xdslistener.FilterChain, 0, len(listener.FilterChains))
 		for _, fc := range listener.FilterChains {
@@ -175,18 +175,18 @@ func patchFilterChains(patchContext networking.EnvoyFilter_PatchContext,
 }
 
 func patchFilterChain(patchContext networking.EnvoyFilter_PatchContext,
-	filterKey string,
-	patches map[networking.EnvoyFilter_ApplyTo][]*model.EnvoyFilterConfigPatchWrapper,
+	efw *model.EnvoyFilterWrapper,
 	listener *xdslistener.Listener,
 	fc *xdslistener.FilterChain, filterChainRemoved *bool) {
 	applied := false
-	for _, cp := range patches[networking.EnvoyFilter_FILTER_CHAIN] {
+	for _, cp := range efw.Patches[networking.EnvoyFilter_FILTER_CHAIN] {
 		if !commonConditionMatch(patchContext, cp) ||
 			!listenerMatch(listener, cp) ||
 			!filterChainMatch(listener, fc, cp) {
 			continue
 		}
 		applied = true
+		efw.RecordApplied(cp)
 		if cp.Operation == networking.EnvoyFilter_Patch_REMOVE {
 			fc.Filters = nil
 			*filterChainRemoved = true
@@ -203,8 +203,8 @@ func patchFilterChain(patchContext networking.EnvoyFilter_PatchContext,
 			}
 		}
 	}
-	IncrementEnvoyFilterMetric(filterKey, FilterChain, applied)
-	patchNetworkFilters(patchContext, filterKey, patches, listener, fc)
+	IncrementEnvoyFilterMetric(efw.Key(), FilterChain, applied)
+	patchNetworkFilters(patchContext, efw, listener, fc)
 }
 
 // Test if the patch contains a config for TransportSocket
@@ -244,18 +244,17 @@ func mergeTransportSocketListener(fc *xdslistener.FilterChain, cp *model.EnvoyFi
 }
 
 func patchNetworkFilters(patchContext networking.EnvoyFilter_PatchContext,
-	filterKey string,
-	patches map[networking.EnvoyFilter_ApplyTo][]*model.EnvoyFilterConfigPatchWrapper,
+	efw *model.EnvoyFilterWrapper,
 	listener *xdslistener.Listener, fc *xdslistener.FilterChain) {
 	networkFiltersRemoved := false
 	for i, filter := range fc.Filters {
 		if filter.Name == "" {
 			continue
 		}
-		patchNetworkFilter(patchContext, filterKey, patches, listener, fc, fc.Filters[i], &networkFiltersRemoved)
+		patchNetworkFilter(patchContext, efw, listener, fc, fc.Filters[i], &networkFiltersRemoved)
 	}
 	applied := false
-	for _, cp := range patches[networking.EnvoyFilter_NETWORK_FILTER] {
+	for _, cp := range efw.Patches[networking.EnvoyFilter_NETWORK_FILTER] {
 		if !commonConditionMatch(patchContext, cp) ||
 			!listenerMatch(listener, cp) ||
 			!filterChainMatch(listener, fc, cp) {
@@ -264,13 +263,16 @@ func patchNetworkFilters(patchContext networking.EnvoyFilter_PatchContext,
 		if cp.Operation == networking.EnvoyFilter_Patch_ADD {
 			fc.Filters = append(fc.Filters, proto.Clone(cp.Value).(*xdslistener.Filter))
 			applied = true
+			efw.RecordApplied(cp)
 		} else if cp.Operation == networking.EnvoyFilter_Patch_INSERT_FIRST {
 			fc.Filters = append([]*xdslistener.Filter{proto.Clone(cp.Value).(*xdslistener.Filter)}, fc.Filters...)
 			applied = true
+			efw.RecordApplied(cp)
 		} else if cp.Operation == networking.EnvoyFilter_Patch_INSERT_AFTER {
 			// Insert after without a filter match is same as ADD in the end
 			if !hasNetworkFilterMatch(cp) {
 				fc.Filters = append(fc.Filters, proto.Clone(cp.Value).(*xdslistener.Filter))
+				efw.RecordApplied(cp)
 				continue
 			}
 			// find the matching filter first
@@ -286,6 +288,7 @@ func patchNetworkFilters(patchContext networking.EnvoyFilter_PatchContext,
 				continue
 			}
 			applied = true
+			efw.RecordApplied(cp)
 			clonedVal := proto.Clone(cp.Value).(*xdslistener.Filter)
 			fc.Filters = append(fc.Filters, clonedVal)
 			if insertPosition < len(fc.Filters)-1 {
@@ -296,6 +299,7 @@ func patchNetworkFilters(patchContext networking.EnvoyFilter_PatchContext,
 			// insert before without a filter match is same as insert in the beginning
 			if !hasNetworkFilterMatch(cp) {
 				fc.Filters = append([]*xdslistener.Filter{proto.Clone(cp.Value).(*xdslistener.Filter)}, fc.Filters...)
+				efw.RecordApplied(cp)
 				continue
 			}
 			// find the matching filter first
@@ -312,6 +316,7 @@ func patchNetworkFilters(patchContext networking.EnvoyFilter_PatchContext,
 				continue
 			}
 			applied = true
+			efw.RecordApplied(cp)
 			clonedVal := proto.Clone(cp.Value).(*xdslistener.Filter)
 			fc.Filters = append(fc.Filters, clonedVal)
 			copy(fc.Filters[insertPosition+1:], fc.Filters[insertPosition:])
@@ -332,10 +337,11 @@ func patchNetworkFilters(patchContext networking.EnvoyFilter_PatchContext,
 				continue
 			}
 			applied = true
+			efw.RecordApplied(cp)
 			fc.Filters[replacePosition] = proto.Clone(cp.Value).(*xdslistener.Filter)
 		}
 	}
-	IncrementEnvoyFilterMetric(filterKey, NetworkFilter, applied)
+	IncrementEnvoyFilterMetric(efw.Key(), NetworkFilter, applied)
 	if networkFiltersRemoved {
 		tempArray := make([]*xdslistener.Filter, 0, len(fc.Filters))
 		for _, filter := range fc.Filters {
@@ -348,12 +354,11 @@ func patchNetworkFilters(patchContext networking.EnvoyFilter_PatchContext,
 }
 
 func patchNetworkFilter(patchContext networking.EnvoyFilter_PatchContext,
-	filterKey string,
-	patches map[networking.EnvoyFilter_ApplyTo][]*model.EnvoyFilterConfigPatchWrapper,
+	efw *model.EnvoyFilterWrapper,
 	listener *xdslistener.Listener, fc *xdslistener.FilterChain,
 	filter *xdslistener.Filter, networkFilterRemoved *bool) {
 	applied := false
-	for _, cp := range patches[networking.EnvoyFilter_NETWORK_FILTER] {
+	for _, cp := range efw.Patches[networking.EnvoyFilter_NETWORK_FILTER] {
 		if !commonConditionMatch(patchContext, cp) ||
 			!listenerMatch(listener, cp) ||
 			!filterChainMatch(listener, fc, cp) ||
@@ -363,6 +368,7 @@ func patchNetworkFilter(patchContext networking.EnvoyFilter_PatchContext,
 		if cp.Operation == networking.EnvoyFilter_Patch_REMOVE {
 			filter.Name = ""
 			*networkFilterRemoved = true
+			efw.RecordApplied(cp)
 			// nothing more to do in other patches as we removed this filter
 			return
 		} else if cp.Operation == networking.EnvoyFilter_Patch_MERGE {
@@ -387,6 +393,7 @@ func patchNetworkFilter(patchContext networking.EnvoyFilter_PatchContext,
 			var retVal *any.Any
 			if userFilter.GetTypedConfig() != nil {
 				applied = true
+				efw.RecordApplied(cp)
 				// user has any typed struct
 				// The type may not match up exactly. For example, if we use v2 internally but they use v3.
 				// Assuming they are not using deprecated/new fields, we can safely swap out the TypeUrl
@@ -405,15 +412,14 @@ func patchNetworkFilter(patchContext networking.EnvoyFilter_PatchContext,
 			}
 		}
 	}
-	IncrementEnvoyFilterMetric(filterKey, NetworkFilter, applied)
+	IncrementEnvoyFilterMetric(efw.Key(), NetworkFilter, applied)
 	if filter.Name == wellknown.HTTPConnectionManager {
-		patchHTTPFilters(patchContext, filterKey, patches, listener, fc, filter)
+		patchHTTPFilters(patchContext, efw, listener, fc, filter)
 	}
 }
 
 func patchHTTPFilters(patchContext networking.EnvoyFilter_PatchContext,
-	filterKey string,
-	patches map[networking.EnvoyFilter_ApplyTo][]*model.EnvoyFilterConfigPatchWrapper,
+	efw *model.EnvoyFilterWrapper,
 	listener *xdslistener.Listener, fc *xdslistener.FilterChain, filter *xdslistener.Filter) {
 	hcm := &http_conn.HttpConnectionManager{}
 	if filter.GetTypedConfig() != nil {
@@ -428,10 +434,10 @@ func patchHTTPFilters(patchContext networking.EnvoyFilter_PatchContext,
 		if httpFilter.Name == "" {
 			continue
 		}
-		patchHTTPFilter(patchContext, filterKey, patches, listener, fc, filter, httpFilter, &httpFiltersRemoved)
+		patchHTTPFilter(patchContext, efw, listener, fc, filter, httpFilter, &httpFiltersRemoved)
 	}
 	applied := false
-	for _, cp := range patches[networking.EnvoyFilter_HTTP_FILTER] {
+	for _, cp := range efw.Patches[networking.EnvoyFilter_HTTP_FILTER] {
 		if !commonConditionMatch(patchContext, cp) ||
 			!listenerMatch(listener, cp) ||
 			!filterChainMatch(listener, fc, cp) ||
@@ -440,13 +446,16 @@ func patchHTTPFilters(patchContext networking.EnvoyFilter_PatchContext,
 		}
 		if cp.Operation == networking.EnvoyFilter_Patch_ADD {
 			applied = true
+			efw.RecordApplied(cp)
 			hcm.HttpFilters = append(hcm.HttpFilters, proto.Clone(cp.Value).(*http_conn.HttpFilter))
 		} else if cp.Operation == networking.EnvoyFilter_Patch_INSERT_FIRST {
 			hcm.HttpFilters = append([]*http_conn.HttpFilter{proto.Clone(cp.Value).(*http_conn.HttpFilter)}, hcm.HttpFilters...)
+			efw.RecordApplied(cp)
 		} else if cp.Operation == networking.EnvoyFilter_Patch_INSERT_AFTER {
 			// Insert after without a filter match is same as ADD in the end
 			if !hasHTTPFilterMatch(cp) {
 				hcm.HttpFilters = append(hcm.HttpFilters, proto.Clone(cp.Value).(*http_conn.HttpFilter))
+				efw.RecordApplied(cp)
 				continue
 			}
 
@@ -463,6 +472,7 @@ func patchHTTPFilters(patchContext networking.EnvoyFilter_PatchContext,
 				continue
 			}
 			applied = true
+			efw.RecordApplied(cp)
 			clonedVal := proto.Clone(cp.Value).(*http_conn.HttpFilter)
 			hcm.HttpFilters = append(hcm.HttpFilters, clonedVal)
 			if insertPosition < len(hcm.HttpFilters)-1 {
@@ -473,6 +483,7 @@ func patchHTTPFilters(patchContext networking.EnvoyFilter_PatchContext,
 			// insert before without a filter match is same as insert in the beginning
 			if !hasHTTPFilterMatch(cp) {
 				hcm.HttpFilters = append([]*http_conn.HttpFilter{proto.Clone(cp.Value).(*http_conn.HttpFilter)}, hcm.HttpFilters...)
+				efw.RecordApplied(cp)
 				continue
 			}
 
@@ -489,6 +500,7 @@ func patchHTTPFilters(patchContext networking.EnvoyFilter_PatchContext,
 				continue
 			}
 			applied = true
+			efw.RecordApplied(cp)
 			clonedVal := proto.Clone(cp.Value).(*http_conn.HttpFilter)
 			hcm.HttpFilters = append(hcm.HttpFilters, clonedVal)
 			copy(hcm.HttpFilters[insertPosition+1:], hcm.HttpFilters[insertPosition:])
@@ -511,6 +523,7 @@ func patchHTTPFilters(patchContext networking.EnvoyFilter_PatchContext,
 				continue
 			}
 			applied = true
+			efw.RecordApplied(cp)
 			clonedVal := proto.Clone(cp.Value).(*http_conn.HttpFilter)
 			hcm.HttpFilters[replacePosition] = clonedVal
 		}
@@ -524,7 +537,7 @@ func patchHTTPFilters(patchContext networking.EnvoyFilter_PatchContext,
 		}
 		hcm.HttpFilters = tempArray
 	}
-	IncrementEnvoyFilterMetric(filterKey, HttpFilter, applied)
+	IncrementEnvoyFilterMetric(efw.Key(), HttpFilter, applied)
 	if filter.GetTypedConfig() != nil {
 		// convert to any type
 		filter.ConfigType = &xdslistener.Filter_TypedConfig{TypedConfig: util.MessageToAny(hcm)}
@@ -532,12 +545,11 @@ func patchHTTPFilters(patchContext networking.EnvoyFilter_PatchContext,
 }
 
 func patchHTTPFilter(patchContext networking.EnvoyFilter_PatchContext,
-	filterKey string,
-	patches map[networking.EnvoyFilter_ApplyTo][]*model.EnvoyFilterConfigPatchWrapper,
+	efw *model.EnvoyFilterWrapper,
 	listener *xdslistener.Listener, fc *xdslistener.FilterChain, filter *xdslistener.Filter,
 	httpFilter *http_conn.HttpFilter, httpFilterRemoved *bool) {
 	applied := false
-	for _, cp := range patches[networking.EnvoyFilter_HTTP_FILTER] {
+	for _, cp := range efw.Patches[networking.EnvoyFilter_HTTP_FILTER] {
 		if !commonConditionMatch(patchContext, cp) ||
 			!listenerMatch(listener, cp) ||
 			!filterChainMatch(listener, fc, cp) ||
@@ -548,6 +560,7 @@ func patchHTTPFilter(patchContext networking.EnvoyFilter_PatchContext,
 		if cp.Operation == networking.EnvoyFilter_Patch_REMOVE {
 			httpFilter.Name = ""
 			*httpFilterRemoved = true
+			efw.RecordApplied(cp)
 			// nothing more to do in other patches as we removed this filter
 			return
 		} else if cp.Operation == networking.EnvoyFilter_Patch_MERGE {
@@ -584,13 +597,14 @@ func patchHTTPFilter(patchContext networking.EnvoyFilter_PatchContext,
 				}
 			}
 			applied = true
+			efw.RecordApplied(cp)
 			httpFilter.Name = toCanonicalName(httpFilterName)
 			if retVal != nil {
 				httpFilter.ConfigType = &http_conn.HttpFilter_TypedConfig{TypedConfig: retVal}
 			}
 		}
 	}
-	IncrementEnvoyFilterMetric(filterKey, HttpFilter, applied)
+	IncrementEnvoyFilterMetric(efw.Key(), HttpFilter, applied)
 }
 
 func listenerMatch(listener *xdslistener.Listener, cp *model.EnvoyFilterConfigPatchWrapper) bool {
@@ -54,39 +54,40 @@ func ApplyRouteConfigurationPatches(
 		if commonConditionMatch(patchContext, cp) &&
 			routeConfigurationMatch(patchContext, routeConfiguration, cp) {
 			proto.Merge(routeConfiguration, cp.Value)
+			efw.RecordApplied(cp)
 		} else {
 			applied = false
 		}
 	}
-	IncrementEnvoyFilterMetric(filterKey, Route, applied)
-	patchVirtualHosts(patchContext, filterKey, efw.Patches, routeConfiguration)
+	IncrementEnvoyFilterMetric(efw.Key(), Route, applied)
+	patchVirtualHosts(patchContext, efw, routeConfiguration)
 
 	return routeConfiguration
 }
 
 func patchVirtualHosts(patchContext networking.EnvoyFilter_PatchContext,
-	filterKey string,
-	patches map[networking.EnvoyFilter_ApplyTo][]*model.EnvoyFilterConfigPatchWrapper,
+	efw *model.EnvoyFilterWrapper,
 	routeConfiguration *route.RouteConfiguration) {
 	virtualHostsRemoved := false
 	// first do removes/merges
 	for _, vhost := range routeConfiguration.VirtualHosts {
-		patchVirtualHost(patchContext, filterKey, patches, routeConfiguration, vhost, &virtualHostsRemoved)
+		patchVirtualHost(patchContext, efw, routeConfiguration, vhost, &virtualHostsRemoved)
 	}
 
 	applied := false
 	// now for the adds
-	for _, cp := range patches[networking.EnvoyFilter_VIRTUAL_HOST] {
+	for _, cp := range efw.Patches[networking.EnvoyFilter_VIRTUAL_HOST] {
 		if cp.Operation != networking.EnvoyFilter_Patch_ADD {
 			continue
 		}
 		if commonConditionMatch(patchContext, cp) &&
 			routeConfigurationMatch(patchContext, routeConfiguration, cp) {
 			applied = true
+			efw.RecordApplied(cp)
 			routeConfiguration.VirtualHosts = append(routeConfiguration.VirtualHosts, proto.Clone(cp.Value).(*route.VirtualHost))
 		}
 	}
-	IncrementEnvoyFilterMetric(filterKey, VirtualHost, applied)
+	IncrementEnvoyFilterMetric(efw.Key(), VirtualHost, applied)
 	if virtualHostsRemoved {
 		trimmedVirtualHosts := make([]*route.VirtualHost, 0, len(routeConfiguration.VirtualHosts))
 		for _, virtualHost := range routeConfiguration.VirtualHosts {
@@ -100,15 +101,15 @@ func patchVirtualHosts(patchContext networking.EnvoyFilter_PatchContext,
 }
 
 func patchVirtualHost(patchContext networking.EnvoyFilter_PatchContext,
-	filterKey string,
-	patches map[networking.EnvoyFilter_ApplyTo][]*model.EnvoyFilterConfigPatchWrapper,
+	efw *model.EnvoyFilterWrapper,
 	routeConfiguration *route.RouteConfiguration, virtualHost *route.VirtualHost, virtualHostRemoved *bool) {
 	applied := false
-	for _, cp := range patches[networking.EnvoyFilter_VIRTUAL_HOST] {
+	for _, cp := range efw.Patches[networking.EnvoyFilter_VIRTUAL_HOST] {
 		if commonConditionMatch(patchContext, cp) &&
 			routeConfigurationMatch(patchContext, routeConfiguration, cp) &&
 			virtualHostMatch(virtualHost, cp) {
 			applied = true
+			efw.RecordApplied(cp)
 			if cp.Operation == networking.EnvoyFilter_Patch_REMOVE {
 				virtualHost.Name = ""
 				*virtualHostRemoved = true
@@ -119,8 +120,8 @@ func patchVirtualHost(patchContext networking.EnvoyFilter_PatchContext,
 			}
 		}
 	}
-	IncrementEnvoyFilterMetric(filterKey, VirtualHost, applied)
-	patchHTTPRoutes(patchContext, filterKey, patches, routeConfiguration, virtualHost)
+	IncrementEnvoyFilterMetric(efw.Key(), VirtualHost, applied)
+	patchHTTPRoutes(patchContext, efw, routeConfiguration, virtualHost)
 }
 
 func hasRouteMatch(cp *model.EnvoyFilterConfigPatchWrapper) bool {
@@ -138,18 +139,17 @@ func hasRouteMatch(cp *model.EnvoyFilterConfigPatchWrapper) bool {
 }
 
 func patchHTTPRoutes(patchContext networking.EnvoyFilter_PatchContext,
-	filterKey string,
-	patches map[networking.EnvoyFilter_ApplyTo][]*model.EnvoyFilterConfigPatchWrapper,
+	efw *model.EnvoyFilterWrapper,
 	routeConfiguration *route.RouteConfiguration, virtualHost *route.VirtualHost) {
 	routesRemoved := false
 	// Apply the route level removes/merges if any.
 	for index := range virtualHost.Routes {
-		patchHTTPRoute(patchContext, filterKey, patches, routeConfiguration, virtualHost, index, &routesRemoved)
+		patchHTTPRoute(patchContext, efw, routeConfiguration, virtualHost, index, &routesRemoved)
 	}
 
 	applied := false
 	// now for the adds
-	for _, cp := range patches[networking.EnvoyFilter_HTTP_ROUTE] {
+	for _, cp := range efw.Patches[networking.EnvoyFilter_HTTP_ROUTE] {
 		if !commonConditionMatch(patchContext, cp) ||
 			!routeConfigurationMatch(patchContext, routeConfiguration, cp) ||
 			!virtualHostMatch(virtualHost, cp) {
@@ -158,10 +158,12 @@ func patchHTTPRoutes(patchContext networking.EnvoyFilter_PatchContext,
 		if cp.Operation == networking.EnvoyFilter_Patch_ADD {
 			virtualHost.Routes = append(virtualHost.Routes, proto.Clone(cp.Value).(*route.Route))
 			applied = true
+			efw.RecordApplied(cp)
 		} else if cp.Operation == networking.EnvoyFilter_Patch_INSERT_AFTER {
 			// Insert after without a route match is same as ADD in the end
 			if !hasRouteMatch(cp) {
 				virtualHost.Routes = append(virtualHost.Routes, proto.Clone(cp.Value).(*route.Route))
+				efw.RecordApplied(cp)
 				continue
 			}
 			// find the matching route first
@@ -177,6 +179,7 @@ func patchHTTPRoutes(patchContext networking.EnvoyFilter_PatchContext,
 				continue
 			}
 			applied = true
+			efw.RecordApplied(cp)
 			clonedVal := proto.Clone(cp.Value).(*route.Route)
 			virtualHost.Routes = append(virtualHost.Routes, clonedVal)
 			if insertPosition < len(virtualHost.Routes)-1 {
@@ -187,6 +190,7 @@ func patchHTTPRoutes(patchContext networking.EnvoyFilter_PatchContext,
 			// insert before/first without a route match is same as insert in the beginning
 			if !hasRouteMatch(cp) {
 				virtualHost.Routes = append([]*route.Route{proto.Clone(cp.Value).(*route.Route)}, virtualHost.Routes...)
+				efw.RecordApplied(cp)
 				continue
 			}
 			// find the matching route first
@@ -204,6 +208,7 @@ func patchHTTPRoutes(patchContext networking.EnvoyFilter_PatchContext,
 			}
 
 			applied = true
+			efw.RecordApplied(cp)
 
 			// In case of INSERT_FIRST, if a match is found, still insert it at the top of the routes.
 			if cp.Operation == networking.EnvoyFilter_Patch_INSERT_FIRST {
@@ -216,7 +221,7 @@ func patchHTTPRoutes(patchContext networking.EnvoyFilter_PatchContext,
 			virtualHost.Routes[insertPosition] = clonedVal
 		}
 	}
-	IncrementEnvoyFilterMetric(filterKey, Route, applied)
+	IncrementEnvoyFilterMetric(efw.Key(), Route, applied)
 	if routesRemoved {
 		trimmedRoutes := make([]*route.Route, 0, len(virtualHost.Routes))
 		for i := range virtualHost.Routes {
@@ -230,11 +235,10 @@ func patchHTTPRoutes(patchContext networking.EnvoyFilter_PatchContext,
 }
 
 func patchHTTPRoute(patchContext networking.EnvoyFilter_PatchContext,
-	filterKey string,
-	patches map[networking.EnvoyFilter_ApplyTo][]*model.EnvoyFilterConfigPatchWrapper,
+	efw *model.EnvoyFilterWrapper,
 	routeConfiguration *route.RouteConfiguration, virtualHost *route.VirtualHost, routeIndex int, routesRemoved *bool) {
 	applied := false
-	for _, cp := range patches[networking.EnvoyFilter_HTTP_ROUTE] {
+	for _, cp := range efw.Patches[networking.EnvoyFilter_HTTP_ROUTE] {
 		if commonConditionMatch(patchContext, cp) &&
 			routeConfigurationMatch(patchContext, routeConfiguration, cp) &&
 			virtualHostMatch(virtualHost, cp) &&
@@ -245,14 +249,16 @@ func patchHTTPRoute(patchContext networking.EnvoyFilter_PatchContext,
 			if cp.Operation == networking.EnvoyFilter_Patch_REMOVE {
 				virtualHost.Routes[routeIndex] = nil
 				*routesRemoved = true
+				efw.RecordApplied(cp)
 				return
 			} else if cp.Operation == networking.EnvoyFilter_Patch_MERGE {
 				proto.Merge(virtualHost.Routes[routeIndex], cp.Value)
 			}
 			applied = true
+			efw.RecordApplied(cp)
 		}
 	}
-	IncrementEnvoyFilterMetric(filterKey, Route, applied)
+	IncrementEnvoyFilterMetric(efw.Key(), Route, applied)
 }
 
 func routeConfigurationMatch(patchContext networking.EnvoyFilter_PatchContext, rc *route.RouteConfiguration,
@@ -15,11 +15,13 @@
 package v1alpha3_test
 
 import (
+	"net/http"
 	"testing"
 
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/simulation"
 	"istio.io/istio/pilot/pkg/xds"
+	"istio.io/istio/pilot/test/simulationtest"
 	"istio.io/istio/pilot/test/xdstest"
 	"istio.io/istio/pkg/test/util/tmpl"
 	"istio.io/pkg/env"
@@ -54,7 +56,7 @@ spec:
 		simulationTest{
 			name:   "no virtual services",
 			config: createGateway("", "", httpServer),
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					// Expect listener, but no routing
 					"defined port",
@@ -106,7 +108,7 @@ spec:
         port:
           number: 9080
 `,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					"uri mismatch",
 					simulation.Call{
@@ -198,7 +200,7 @@ spec:
         port:
           number: 80
 `,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					"a",
 					simulation.Call{
@@ -228,6 +230,133 @@ spec:
 				},
 			},
 		},
+		simulationTest{
+			name: "header and query parameter matches",
+			config: createGateway("gateway", "", `port:
+  number: 80
+  name: http
+  protocol: HTTP
+hosts:
+- "example.com"`) + `
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: vs
+spec:
+  hosts:
+  - "example.com"
+  gateways:
+  - gateway
+  http:
+  - match:
+    - headers:
+        x-canary:
+          exact: "true"
+    route:
+    - destination:
+        host: canary
+        port:
+          number: 80
+  - match:
+    - headers:
+        x-version:
+          regex: "v[0-9]+"
+      withoutHeaders:
+        x-skip:
+          exact: "true"
+    route:
+    - destination:
+        host: versioned
+        port:
+          number: 80
+  - match:
+    - queryParams:
+        debug:
+          exact: "1"
+    route:
+    - destination:
+        host: debug
+        port:
+          number: 80
+  - route:
+    - destination:
+        host: default
+        port:
+          number: 80
+`,
+			calls: []simulationtest.Expect{
+				{
+					"exact header",
+					simulation.Call{
+						Port:       80,
+						HostHeader: "example.com",
+						Headers:    http.Header{"X-Canary": []string{"true"}},
+						Protocol:   simulation.HTTP,
+					},
+					simulation.Result{ClusterMatched: "outbound|80||canary.default"},
+				},
+				{
+					"exact header mismatch",
+					simulation.Call{
+						Port:       80,
+						HostHeader: "example.com",
+						Headers:    http.Header{"X-Canary": []string{"false"}},
+						Protocol:   simulation.HTTP,
+					},
+					simulation.Result{ClusterMatched: "outbound|80||default.default"},
+				},
+				{
+					"regex header",
+					simulation.Call{
+						Port:       80,
+						HostHeader: "example.com",
+						Headers:    http.Header{"X-Version": []string{"v2"}, "X-Skip": []string{"false"}},
+						Protocol:   simulation.HTTP,
+					},
+					simulation.Result{ClusterMatched: "outbound|80||versioned.default"},
+				},
+				{
+					"regex header is a full match",
+					simulation.Call{
+						Port:       80,
+						HostHeader: "example.com",
+						Headers:    http.Header{"X-Version": []string{"v2-beta"}, "X-Skip": []string{"false"}},
+						Protocol:   simulation.HTTP,
+					},
+					simulation.Result{ClusterMatched: "outbound|80||default.default"},
+				},
+				{
+					"inverted header",
+					simulation.Call{
+						Port:       80,
+						HostHeader: "example.com",
+						Headers:    http.Header{"X-Version": []string{"v2"}, "X-Skip": []string{"true"}},
+						Protocol:   simulation.HTTP,
+					},
+					simulation.Result{ClusterMatched: "outbound|80||default.default"},
+				},
+				{
+					"query parameter",
+					simulation.Call{
+						Port:       80,
+						HostHeader: "example.com",
+						Path:       "/?debug=1",
+						Protocol:   simulation.HTTP,
+					},
+					simulation.Result{ClusterMatched: "outbound|80||debug.default"},
+				},
+				{
+					"query parameter mismatch",
+					simulation.Call{
+						Port:       80,
+						HostHeader: "example.com",
+						Path:       "/?debug=2",
+						Protocol:   simulation.HTTP,
+					},
+					simulation.Result{ClusterMatched: "outbound|80||default.default"},
+				},
+			},
+		},
 		simulationTest{
 			name: "httpsRedirect without routes",
 			config: createGateway("gateway", "", `port:
@@ -238,7 +367,7 @@ hosts:
 - "example.com"
 tls:
   httpsRedirect: true`),
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					"request",
 					simulation.Call{
@@ -268,7 +397,7 @@ hosts:
 - "example.com"
 tls:
   httpsRedirect: true`) + simpleRoute,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					"request",
 					simulation.Call{
@@ -304,7 +433,7 @@ tls:
   protocol: HTTP
 hosts:
 - "example.com"`) + simpleRoute,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					"request",
 					simulation.Call{
@@ -334,7 +463,7 @@ tls:
   httpsRedirect: true
   mode: SIMPLE
   credentialName: test`) + simpleRoute,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					"request",
 					simulation.Call{
@@ -402,7 +531,7 @@ spec:
 				createGateway("gateway", "alpha", tcpServer) + // namespace comes before istio-system
 
 				gatewayCollision,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					"call",
 					simulation.Call{Port: 80, Protocol: simulation.TCP},
@@ -417,7 +546,7 @@ spec:
 			config: createGateway("gateway", "istio-system", tcpServer) +
 				createGateway("gateway", "zeta", tcpServer) + // namespace comes after istio-system
 				gatewayCollision,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					"call",
 					simulation.Call{Port: 80, Protocol: simulation.TCP},
@@ -431,7 +560,7 @@ spec:
 			// Create the same gateway in two namespaces
 			config: createGateway("", "istio-system", tlsServer) +
 				createGateway("", "default", tlsServer),
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					// TODO(https://github.com/istio/istio/issues/24638) This is a bug!
 					// We should not have multiple matches, envoy will NACK this
@@ -505,7 +634,7 @@ spec:
         port:
           number: 443
 `,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					"call",
 					simulation.Call{Port: 443, Protocol: simulation.HTTP, TLS: simulation.TLS, HostHeader: "mysite.example.com"},
@@ -521,7 +650,7 @@ spec:
 			name: "multiple protocols on a port - tcp first",
 			config: createGateway("alpha", "", tcpServer) +
 				createGateway("beta", "", httpServer),
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					"call tcp",
 					// TCP takes precedence. Since we have no tcp routes, this will result in no listeners
@@ -540,7 +669,7 @@ spec:
 			name: "multiple protocols on a port - http first",
 			config: createGateway("beta", "", tcpServer) +
 				createGateway("alpha", "", httpServer),
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					// Port define in gateway, but no virtual services
 					// Expect a 404
@@ -606,7 +735,7 @@ spec:
     - destination:
         host: echo
 `,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					"ns-1",
 					simulation.Call{
@@ -668,7 +797,7 @@ spec:
 		// "alpha" is created after "beta". This triggers a mismatch in the conflict resolution logic in Ingress and VirtualService, leading to unexpected results
 		kubeConfig: tmpl.MustEvaluate(cfg, map[string]string{"Name": "alpha", "Time": "2020-01-01T00:00:00Z"}) +
 			tmpl.MustEvaluate(cfg, map[string]string{"Name": "beta", "Time": "2010-01-01T00:00:00Z"}),
-		calls: []simulation.Expect{
+		calls: []simulationtest.Expect{
 			{
 				"http alpha",
 				simulation.Call{
@@ -735,7 +864,7 @@ spec:
 		// "alpha" is created before "beta". This avoids the bug in the previous test
 		kubeConfig: tmpl.MustEvaluate(cfg, map[string]string{"Name": "alpha", "Time": "2010-01-01T00:00:00Z"}) +
 			tmpl.MustEvaluate(cfg, map[string]string{"Name": "beta", "Time": "2020-01-01T00:00:00Z"}),
-		calls: []simulation.Expect{
+		calls: []simulationtest.Expect{
 			{
 				"http alpha",
 				simulation.Call{
@@ -804,7 +933,7 @@ type simulationTest struct {
 	kubeConfig string
 	// skipValidation disables validation of XDS resources. Should be used only when we expect a failure (regression catching)
 	skipValidation bool
-	calls          []simulation.Expect
+	calls          []simulationtest.Expect
 }
 
 var debugMode = env.RegisterBoolVar("SIMULATION_DEBUG", true, "if enabled, will dump verbose output").Get()
@@ -826,8 +955,8 @@ func runSimulationTest(t *testing.T, proxy *model.Proxy, o xds.FakeOptions, tt s
 		o.ConfigString = tt.config
 		o.KubernetesObjectString = tt.kubeConfig
 		s := xds.NewFakeDiscoveryServer(t, o)
-		sim := simulation.NewSimulation(t, s, s.SetupProxy(proxy))
-		sim.RunExpectations(tt.calls)
+		sim := simulationtest.NewSimulation(s, s.SetupProxy(proxy))
+		simulationtest.RunExpectations(t, sim, tt.calls)
 		if t.Failed() && debugMode {
 			t.Log(xdstest.MapKeys(xdstest.ExtractClusters(sim.Clusters)))
 			t.Log(xdstest.ExtractListenerNames(sim.Listeners))
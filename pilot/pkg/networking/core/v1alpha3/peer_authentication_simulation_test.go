@@ -20,6 +20,7 @@ import (
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/simulation"
 	"istio.io/istio/pilot/pkg/xds"
+	"istio.io/istio/pilot/test/simulationtest"
 )
 
 // TestPeerAuthenticationPassthrough tests the PeerAuthentication policy applies correctly on the passthrough filter chain,
@@ -134,12 +135,12 @@ spec:
 	cases := []struct {
 		name   string
 		config string
-		calls  []simulation.Expect
+		calls  []simulationtest.Expect
 	}{
 		{
 			name:   "global disable",
 			config: paDisable,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					Name:   "mtls",
 					Call:   mkCall(8000, simulation.MTLS),
@@ -155,7 +156,7 @@ spec:
 		{
 			name:   "global strict",
 			config: paStrict,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					Name:   "plaintext",
 					Call:   mkCall(8000, simulation.Plaintext),
@@ -171,7 +172,7 @@ spec:
 		{
 			name:   "global permissive",
 			config: paPermissive,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					Name:   "plaintext",
 					Call:   mkCall(8000, simulation.Plaintext),
@@ -187,7 +188,7 @@ spec:
 		{
 			name:   "global disable and port 9000 strict",
 			config: paDisableWithStrictOnPort9000,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					Name:   "plaintext on port 8000",
 					Call:   mkCall(8000, simulation.Plaintext),
@@ -213,7 +214,7 @@ spec:
 		{
 			name:   "global disable and port 9000 strict not in service",
 			config: paDisableWithStrictOnPort9000 + sePort8000,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					Name:   "plaintext on port 8000",
 					Call:   mkCall(8000, simulation.Plaintext),
@@ -240,7 +241,7 @@ spec:
 		{
 			name:   "global strict and port 9000 plaintext",
 			config: paStrictWithDisableOnPort9000,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					Name:   "plaintext on port 8000",
 					Call:   mkCall(8000, simulation.Plaintext),
@@ -266,7 +267,7 @@ spec:
 		{
 			name:   "global strict and port 9000 plaintext not in service",
 			config: paStrictWithDisableOnPort9000 + sePort8000,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					Name:   "plaintext on port 8000",
 					Call:   mkCall(8000, simulation.Plaintext),
@@ -292,7 +293,7 @@ spec:
 		{
 			name:   "global plaintext and port 9000 permissive",
 			config: paDisableWithPermissiveOnPort9000,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					Name:   "plaintext on port 8000",
 					Call:   mkCall(8000, simulation.Plaintext),
@@ -318,7 +319,7 @@ spec:
 		{
 			name:   "global plaintext and port 9000 permissive not in service",
 			config: paDisableWithPermissiveOnPort9000 + sePort8000,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					Name:   "plaintext on port 8000",
 					Call:   mkCall(8000, simulation.Plaintext),
@@ -466,12 +467,12 @@ spec:
 	cases := []struct {
 		name   string
 		config string
-		calls  []simulation.Expect
+		calls  []simulationtest.Expect
 	}{
 		{
 			name:   "service, no sidecar",
 			config: pa + instancePorts,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					Name:   "plaintext on tls port",
 					Call:   mkCall(8080, simulation.Plaintext),
@@ -498,7 +499,7 @@ spec:
 		{
 			name:   "service, full sidecar",
 			config: pa + sidecar + instancePorts,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					Name:   "plaintext on tls port",
 					Call:   mkCall(8080, simulation.Plaintext),
@@ -525,7 +526,7 @@ spec:
 		{
 			name:   "no service, no sidecar",
 			config: pa + instanceNoPorts,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					Name:   "plaintext on tls port",
 					Call:   mkCall(8080, simulation.Plaintext),
@@ -554,7 +555,7 @@ spec:
 		{
 			name:   "no service, full sidecar",
 			config: pa + sidecar + instanceNoPorts,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					Name:   "plaintext on tls port",
 					Call:   mkCall(8080, simulation.Plaintext),
@@ -581,7 +582,7 @@ spec:
 		{
 			name:   "service, partial sidecar",
 			config: pa + partialSidecar + instancePorts,
-			calls: []simulation.Expect{
+			calls: []simulationtest.Expect{
 				{
 					Name:   "plaintext on tls port",
 					Call:   mkCall(8080, simulation.Plaintext),
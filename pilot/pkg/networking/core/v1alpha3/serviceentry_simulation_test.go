@@ -20,6 +20,7 @@ import (
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/simulation"
 	"istio.io/istio/pilot/pkg/xds"
+	"istio.io/istio/pilot/test/simulationtest"
 )
 
 const se = `
@@ -75,7 +76,7 @@ func TestServiceEntry(t *testing.T) {
 			name:       "identical CIDR (ignoreing insignificant bits) is dropped",
 			config:     fmt.Sprintf(se, "1234:1f1:123:123:f816:3eff:feb8:2287/32", "1234:1f1:123:123:f816:3eff:febf:57ce/32"),
 			kubeConfig: "",
-			calls: []simulation.Expect{{
+			calls: []simulationtest.Expect{{
 				// Expect listener, but no routing
 				"defined port",
 				simulation.Call{
@@ -95,7 +96,7 @@ func TestServiceEntry(t *testing.T) {
 			name:       "overlapping CIDR causes multiple filter chain match",
 			config:     fmt.Sprintf(se, "1234:1f1:123:123:f816:3eff:feb8:2287/16", "1234:1f1:123:123:f816:3eff:febf:57ce/32"),
 			kubeConfig: "",
-			calls: []simulation.Expect{{
+			calls: []simulationtest.Expect{{
 				// Expect listener, but no routing
 				"defined port",
 				simulation.Call{
@@ -34,6 +34,7 @@ import (
 	"istio.io/istio/pilot/pkg/networking/util"
 	"istio.io/istio/pilot/pkg/simulation"
 	"istio.io/istio/pilot/pkg/xds"
+	"istio.io/istio/pilot/test/simulationtest"
 	"istio.io/istio/pilot/test/xdstest"
 	"istio.io/istio/pkg/config"
 	"istio.io/istio/pkg/config/host"
@@ -399,7 +400,7 @@ func TestInboundClusters(t *testing.T) {
 				Instances: tt.instances,
 				Configs:   tt.configs,
 			})
-			sim := simulation.NewSimulationFromConfigGen(t, s, s.SetupProxy(tt.proxy))
+			sim := simulationtest.NewSimulationFromConfigGen(s, s.SetupProxy(tt.proxy))
 
 			clusters := xdstest.FilterClusters(sim.Clusters, func(c *cluster.Cluster) bool {
 				return strings.HasPrefix(c.Name, "inbound")
@@ -440,12 +441,12 @@ func TestInboundClusters(t *testing.T) {
 						}
 					}
 				}
-				sim.Run(simulation.Call{
+				simulationtest.Matches(t, sim.Run(simulation.Call{
 					Port:     port,
 					Protocol: simulation.HTTP,
 					Address:  "1.2.3.4",
 					CallMode: simulation.CallModeInbound,
-				}).Matches(t, simulation.Result{
+				}), simulation.Result{
 					ClusterMatched: cname,
 				})
 			}
@@ -1018,9 +1019,9 @@ spec:
 		},
 	}
 	t.Run("Disable", func(t *testing.T) {
-		calls := []simulation.Expect{}
+		calls := []simulationtest.Expect{}
 		for _, c := range cases {
-			calls = append(calls, simulation.Expect{
+			calls = append(calls, simulationtest.Expect{
 				Name:   c.Name,
 				Call:   c.Call,
 				Result: c.Disabled,
@@ -1033,9 +1034,9 @@ spec:
 	})
 
 	t.Run("Permissive", func(t *testing.T) {
-		calls := []simulation.Expect{}
+		calls := []simulationtest.Expect{}
 		for _, c := range cases {
-			calls = append(calls, simulation.Expect{
+			calls = append(calls, simulationtest.Expect{
 				Name:   c.Name,
 				Call:   c.Call,
 				Result: c.Permissive,
@@ -1048,9 +1049,9 @@ spec:
 	})
 
 	t.Run("Strict", func(t *testing.T) {
-		calls := []simulation.Expect{}
+		calls := []simulationtest.Expect{}
 		for _, c := range cases {
-			calls = append(calls, simulation.Expect{
+			calls = append(calls, simulationtest.Expect{
 				Name:   c.Name,
 				Call:   c.Call,
 				Result: c.Strict,
@@ -1076,7 +1077,7 @@ func TestHeadlessServices(t *testing.T) {
   - name: https
     port: 84`
 
-	calls := []simulation.Expect{}
+	calls := []simulationtest.Expect{}
 	for _, call := range []simulation.Call{
 		{Address: "1.2.3.4", Port: 80, Protocol: simulation.HTTP, HostHeader: "headless.default.svc.cluster.local"},
 
@@ -1091,7 +1092,7 @@ func TestHeadlessServices(t *testing.T) {
 		{Address: "1.2.3.4", Port: 83, Protocol: simulation.TCP, TLS: simulation.TLS, HostHeader: "headless.default"},
 		{Address: "1.2.3.4", Port: 84, Protocol: simulation.HTTP, TLS: simulation.TLS, HostHeader: "headless.default"},
 	} {
-		calls = append(calls, simulation.Expect{
+		calls = append(calls, simulationtest.Expect{
 			Name: fmt.Sprintf("%s-%d", call.Protocol, call.Port),
 			Call: call,
 			Result: simulation.Result{
@@ -1199,9 +1200,9 @@ func TestPassthroughTraffic(t *testing.T) {
 				meshconfig.MeshConfig_OutboundTrafficPolicy_ALLOW_ANY:     util.PassthroughCluster,
 			}[tp]
 			t.Run("with VIP", func(t *testing.T) {
-				testCalls := []simulation.Expect{}
+				testCalls := []simulationtest.Expect{}
 				for name, call := range calls {
-					e := simulation.Expect{
+					e := simulationtest.Expect{
 						Name: name,
 						Call: call,
 						Result: simulation.Result{
@@ -1236,9 +1237,9 @@ spec:
 					})
 			})
 			t.Run("without VIP", func(t *testing.T) {
-				testCalls := []simulation.Expect{}
+				testCalls := []simulationtest.Expect{}
 				for name, call := range calls {
-					e := simulation.Expect{
+					e := simulationtest.Expect{
 						Name: name,
 						Call: call,
 						Result: simulation.Result{
@@ -1283,7 +1284,7 @@ spec:
 
 func TestLoop(t *testing.T) {
 	runSimulationTest(t, nil, xds.FakeOptions{}, simulationTest{
-		calls: []simulation.Expect{
+		calls: []simulationtest.Expect{
 			{
 				Name: "direct request to outbound port",
 				Call: simulation.Call{
@@ -0,0 +1,175 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcgen
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/golang/protobuf/ptypes/any"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/core/v1alpha3"
+	"istio.io/istio/pilot/pkg/networking/util"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/pkg/log"
+)
+
+// Handle a gRPC CDS request, used with the 'ApiListener' style of requests.
+// The main difference is that the request includes Resources.
+// Cluster names are either full_hostname:port, or the outbound|port|subset|hostname subset keys used by the routes.
+func (g *GrpcConfigGenerator) BuildClusters(node *model.Proxy, push *model.PushContext, names []string) []*any.Any {
+	resp := []*any.Any{}
+	// gRPC doesn't currently support any of the APIs - returning just the expected EDS result.
+	// Since the code is relatively strict - we'll add info as needed.
+	for _, n := range names {
+		var edsName, subset string
+		var hostname host.Name
+		var port int
+		if strings.Contains(n, "|") {
+			var dir model.TrafficDirection
+			dir, subset, hostname, port = model.ParseSubsetKey(n)
+			if dir != model.TrafficDirectionOutbound || hostname == "" {
+				log.Warn("Unsupported cluster name ", n)
+				continue
+			}
+			edsName = n
+		} else {
+			hn, portn, err := net.SplitHostPort(n)
+			if err != nil {
+				log.Warn("Failed to parse ", n, " ", err)
+				continue
+			}
+			port, err = strconv.Atoi(portn)
+			if err != nil {
+				log.Warn("Failed to parse port ", n, " ", err)
+				continue
+			}
+			hostname = host.Name(hn)
+			edsName = model.BuildSubsetKey(model.TrafficDirectionOutbound, "", hostname, port)
+		}
+
+		rc := &cluster.Cluster{
+			Name:                 n,
+			ClusterDiscoveryType: &cluster.Cluster_Type{Type: cluster.Cluster_EDS},
+			EdsClusterConfig: &cluster.Cluster_EdsClusterConfig{
+				ServiceName: edsName,
+				EdsConfig: &core.ConfigSource{
+					ConfigSourceSpecifier: &core.ConfigSource_Ads{
+						Ads: &core.AggregatedConfigSource{},
+					},
+				},
+			},
+		}
+		svc := push.ServiceForHostname(node, hostname)
+		policy, ok := trafficPolicy(node, push, svc, hostname, subset, port)
+		if !ok {
+			continue
+		}
+		settings := policy.GetTls()
+		if settings == nil && autoMtlsEnabled(push, policy, svc, port) {
+			settings = &networking.ClientTLSSettings{Mode: networking.ClientTLSSettings_ISTIO_MUTUAL}
+		}
+		rc.TransportSocket = buildUpstreamTransportSocket(push, settings, hostname, subset, port)
+		resp = append(resp, util.MessageToAny(rc))
+	}
+	return resp
+}
+
+// trafficPolicy returns the traffic policy of the DestinationRule for the service port, merged with the policy of the
+// subset. It returns false if the subset is not defined.
+func trafficPolicy(node *model.Proxy, push *model.PushContext, svc *model.Service,
+	hostname host.Name, subset string, port int) (*networking.TrafficPolicy, bool) {
+	cfg := push.DestinationRule(node, svc)
+	if cfg == nil {
+		if subset != "" {
+			log.Warnf("No DestinationRule defines the subset %s of %s", subset, hostname)
+			return nil, false
+		}
+		return nil, true
+	}
+	dr := cfg.Spec.(*networking.DestinationRule)
+	var svcPort *model.Port
+	if p, f := svc.Ports.GetByPort(port); f {
+		svcPort = p
+	}
+	policy := v1alpha3.MergeTrafficPolicy(nil, dr.TrafficPolicy, svcPort)
+	if subset == "" {
+		return policy, true
+	}
+	for _, s := range dr.Subsets {
+		if s.Name == subset {
+			return v1alpha3.MergeTrafficPolicy(policy, s.TrafficPolicy, svcPort), true
+		}
+	}
+	log.Warnf("Subset %s of %s is not defined in DestinationRule %s/%s", subset, hostname, cfg.Namespace, cfg.Name)
+	return nil, false
+}
+
+// autoMtlsEnabled returns true if auto mTLS applies to a service port without TLS settings, with the same decision
+// as the Envoy clusters: mTLS is used when the mesh enables auto mTLS and the service is in the mesh, with mTLS
+// STRICT or PERMISSIVE. gRPC does not support the transport socket matches that Envoy uses to only send mTLS to the
+// endpoints with the Istio TLS mode label, sidecars or proxyless servers, so in PERMISSIVE mode mTLS is only used
+// when all the endpoints have the label. Proxyless servers accept only mTLS on PERMISSIVE ports, see lds.go.
+func autoMtlsEnabled(push *model.PushContext, policy *networking.TrafficPolicy, svc *model.Service, port int) bool {
+	if svc == nil || svc.MeshExternal || !push.Mesh.GetEnableAutoMtls().GetValue() {
+		return false
+	}
+	svcPort, f := svc.Ports.GetByPort(port)
+	if !f {
+		return false
+	}
+	switch push.BestEffortInferServiceMTLSMode(policy, svc, svcPort) {
+	case model.MTLSStrict:
+		return true
+	case model.MTLSPermissive:
+		instances := push.ServiceInstancesByPort(svc, port, nil)
+		if len(instances) == 0 {
+			return false
+		}
+		for _, i := range instances {
+			if i.Endpoint.TLSMode != model.IstioMutualTLSModeLabel {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// buildUpstreamTransportSocket returns the transport socket for the TLS settings of a DestinationRule.
+// Only ISTIO_MUTUAL is supported, using the certificates provided to gRPC by istio-agent.
+func buildUpstreamTransportSocket(push *model.PushContext, settings *networking.ClientTLSSettings,
+	hostname host.Name, subset string, port int) *core.TransportSocket {
+	switch settings.GetMode() {
+	case networking.ClientTLSSettings_ISTIO_MUTUAL:
+		sans := settings.GetSubjectAltNames()
+		if len(sans) == 0 {
+			sans = push.ServiceAccounts[hostname][port]
+		}
+		return buildTransportSocket(&tls.UpstreamTlsContext{
+			CommonTlsContext: buildCommonTLSContext(sans),
+			Sni:              model.BuildDNSSrvSubsetKey(model.TrafficDirectionOutbound, subset, hostname, port),
+		})
+	case networking.ClientTLSSettings_SIMPLE, networking.ClientTLSSettings_MUTUAL:
+		log.Warnf("TLS mode %v is not supported for gRPC clusters of %s", settings.GetMode(), hostname)
+	}
+	return nil
+}
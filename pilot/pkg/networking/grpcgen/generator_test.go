@@ -0,0 +1,370 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcgen_test
+
+import (
+	"reflect"
+	"testing"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/grpcgen"
+	"istio.io/istio/pilot/pkg/xds"
+)
+
+const generatorConfig = `
+apiVersion: networking.istio.io/v1alpha3
+kind: ServiceEntry
+metadata:
+  name: echo
+  namespace: default
+spec:
+  hosts:
+  - echo.default.svc.cluster.local
+  addresses:
+  - 10.10.10.10
+  ports:
+  - number: 7070
+    name: grpc
+    protocol: GRPC
+  subjectAltNames:
+  - spiffe://cluster.local/ns/default/sa/echo
+  resolution: STATIC
+  endpoints:
+  - address: 10.0.0.1
+    labels:
+      version: v1
+  - address: 10.0.0.2
+    labels:
+      version: v2
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: DestinationRule
+metadata:
+  name: echo
+  namespace: default
+spec:
+  host: echo.default.svc.cluster.local
+  trafficPolicy:
+    tls:
+      mode: ISTIO_MUTUAL
+  subsets:
+  - name: v1
+    labels:
+      version: v1
+  - name: v2
+    labels:
+      version: v2
+    trafficPolicy:
+      tls:
+        mode: DISABLE
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: echo
+  namespace: default
+spec:
+  hosts:
+  - echo.default.svc.cluster.local
+  http:
+  - match:
+    - headers:
+        x-canary:
+          exact: "true"
+    route:
+    - destination:
+        host: echo.default.svc.cluster.local
+        subset: v2
+  - route:
+    - destination:
+        host: echo.default.svc.cluster.local
+        subset: v1
+      weight: 80
+    - destination:
+        host: echo.default.svc.cluster.local
+        subset: v2
+      weight: 20
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: ServiceEntry
+metadata:
+  name: sidecars
+  namespace: default
+spec:
+  hosts:
+  - sidecars.default.svc.cluster.local
+  location: MESH_INTERNAL
+  addresses:
+  - 10.10.10.11
+  ports:
+  - number: 7070
+    name: grpc
+    protocol: GRPC
+  resolution: STATIC
+  endpoints:
+  - address: 10.0.1.1
+    labels:
+      security.istio.io/tlsMode: istio
+  - address: 10.0.1.2
+    labels:
+      security.istio.io/tlsMode: istio
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: ServiceEntry
+metadata:
+  name: mixed
+  namespace: default
+spec:
+  hosts:
+  - mixed.default.svc.cluster.local
+  location: MESH_INTERNAL
+  addresses:
+  - 10.10.10.12
+  ports:
+  - number: 7070
+    name: grpc
+    protocol: GRPC
+  resolution: STATIC
+  endpoints:
+  - address: 10.0.2.1
+    labels:
+      security.istio.io/tlsMode: istio
+  - address: 10.0.2.2
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: ServiceEntry
+metadata:
+  name: strict
+  namespace: strict
+spec:
+  hosts:
+  - strict.strict.svc.cluster.local
+  location: MESH_INTERNAL
+  addresses:
+  - 10.10.10.13
+  ports:
+  - number: 7070
+    name: grpc
+    protocol: GRPC
+  resolution: STATIC
+  endpoints:
+  - address: 10.0.3.1
+---
+apiVersion: security.istio.io/v1beta1
+kind: PeerAuthentication
+metadata:
+  name: default
+  namespace: strict
+spec:
+  mtls:
+    mode: STRICT
+---
+apiVersion: security.istio.io/v1beta1
+kind: PeerAuthentication
+metadata:
+  name: echo
+  namespace: default
+spec:
+  selector:
+    matchLabels:
+      app: echo
+  mtls:
+    mode: STRICT
+  portLevelMtls:
+    8080:
+      mode: PERMISSIVE
+`
+
+func TestGenerator(t *testing.T) {
+	s := xds.NewFakeDiscoveryServer(t, xds.FakeOptions{ConfigString: generatorConfig})
+	proxy := s.SetupProxy(&model.Proxy{Metadata: &model.NodeMetadata{
+		Generator: "grpc",
+		Labels:    map[string]string{"app": "echo"},
+	}})
+	push := s.PushContext()
+	g := &grpcgen.GrpcConfigGenerator{}
+
+	t.Run("routes", func(t *testing.T) {
+		res := g.BuildHTTPRoutes(proxy, push, []string{"echo.default.svc.cluster.local:7070"})
+		if len(res) != 1 {
+			t.Fatalf("expected 1 route configuration, got %d", len(res))
+		}
+		rc := &route.RouteConfiguration{}
+		unmarshal(t, res[0], rc)
+		routes := rc.VirtualHosts[0].Routes
+		if len(routes) != 2 {
+			t.Fatalf("expected 2 routes, got %v", routes)
+		}
+		if h := routes[0].Match.Headers; len(h) != 1 || h[0].Name != "x-canary" {
+			t.Errorf("expected a header match on x-canary, got %v", h)
+		}
+		if got := routes[0].GetRoute().GetCluster(); got != "outbound|7070|v2|echo.default.svc.cluster.local" {
+			t.Errorf("unexpected cluster %s", got)
+		}
+		weights := map[string]uint32{}
+		for _, c := range routes[1].GetRoute().GetWeightedClusters().GetClusters() {
+			weights[c.Name] = c.Weight.GetValue()
+		}
+		want := map[string]uint32{
+			"outbound|7070|v1|echo.default.svc.cluster.local": 80,
+			"outbound|7070|v2|echo.default.svc.cluster.local": 20,
+		}
+		if !reflect.DeepEqual(weights, want) {
+			t.Errorf("got weighted clusters %v, want %v", weights, want)
+		}
+	})
+
+	t.Run("clusters", func(t *testing.T) {
+		res := g.BuildClusters(proxy, push, []string{
+			"echo.default.svc.cluster.local:7070",
+			"outbound|7070|v1|echo.default.svc.cluster.local",
+			"outbound|7070|v2|echo.default.svc.cluster.local",
+			"outbound|7070|v3|echo.default.svc.cluster.local",
+		})
+		if len(res) != 3 {
+			t.Fatalf("expected 3 clusters, got %d", len(res))
+		}
+		clusters := map[string]*cluster.Cluster{}
+		for _, r := range res {
+			c := &cluster.Cluster{}
+			unmarshal(t, r, c)
+			clusters[c.Name] = c
+		}
+
+		c := clusters["echo.default.svc.cluster.local:7070"]
+		if got := c.GetEdsClusterConfig().GetServiceName(); got != "outbound|7070||echo.default.svc.cluster.local" {
+			t.Errorf("unexpected EDS service name %s", got)
+		}
+		tlsContext := &tls.UpstreamTlsContext{}
+		unmarshal(t, c.GetTransportSocket().GetTypedConfig(), tlsContext)
+		sans := tlsContext.CommonTlsContext.GetCombinedValidationContext().GetDefaultValidationContext().GetMatchSubjectAltNames()
+		if len(sans) != 1 || sans[0].GetExact() != "spiffe://cluster.local/ns/default/sa/echo" {
+			t.Errorf("unexpected subject alt names %v", sans)
+		}
+		if tlsContext.CommonTlsContext.GetTlsCertificateCertificateProviderInstance() == nil {
+			t.Error("expected a certificate provider instance")
+		}
+
+		c = clusters["outbound|7070|v1|echo.default.svc.cluster.local"]
+		if got := c.GetEdsClusterConfig().GetServiceName(); got != "outbound|7070|v1|echo.default.svc.cluster.local" {
+			t.Errorf("unexpected EDS service name %s", got)
+		}
+		if c.TransportSocket == nil {
+			t.Error("expected the subset to inherit ISTIO_MUTUAL")
+		}
+		if c := clusters["outbound|7070|v2|echo.default.svc.cluster.local"]; c.TransportSocket != nil {
+			t.Error("expected the subset to disable TLS")
+		}
+	})
+
+	t.Run("auto mtls", func(t *testing.T) {
+		cases := map[string]bool{
+			// all the endpoints accept mTLS
+			"sidecars.default.svc.cluster.local:7070": true,
+			// PERMISSIVE, but some endpoints only accept plaintext
+			"mixed.default.svc.cluster.local:7070": false,
+			// STRICT PeerAuthentication in the namespace
+			"strict.strict.svc.cluster.local:7070": true,
+		}
+		for name, mtls := range cases {
+			res := g.BuildClusters(proxy, push, []string{name})
+			if len(res) != 1 {
+				t.Fatalf("%s: expected 1 cluster, got %d", name, len(res))
+			}
+			c := &cluster.Cluster{}
+			unmarshal(t, res[0], c)
+			if got := c.TransportSocket != nil; got != mtls {
+				t.Errorf("%s: got mTLS %v, want %v", name, got, mtls)
+			}
+		}
+	})
+
+	t.Run("server listeners", func(t *testing.T) {
+		res := g.BuildListeners(proxy, push, []string{
+			grpcgen.ServerListenerNamePrefix + "0.0.0.0:7070",
+			grpcgen.ServerListenerNamePrefix + "0.0.0.0:8080",
+		})
+		if len(res) != 2 {
+			t.Fatalf("expected 2 listeners, got %d", len(res))
+		}
+		strict := &listener.Listener{}
+		unmarshal(t, res[0], strict)
+		if strict.Address.GetSocketAddress().GetPortValue() != 7070 {
+			t.Errorf("unexpected address %v", strict.Address)
+		}
+		tlsContext := &tls.DownstreamTlsContext{}
+		unmarshal(t, strict.FilterChains[0].GetTransportSocket().GetTypedConfig(), tlsContext)
+		if !tlsContext.GetRequireClientCertificate().GetValue() {
+			t.Error("expected client certificates to be required")
+		}
+
+		// gRPC servers cannot accept both plaintext and mTLS, PERMISSIVE ports only accept mTLS
+		permissive := &listener.Listener{}
+		unmarshal(t, res[1], permissive)
+		tlsContext = &tls.DownstreamTlsContext{}
+		unmarshal(t, permissive.FilterChains[0].GetTransportSocket().GetTypedConfig(), tlsContext)
+		if !tlsContext.GetRequireClientCertificate().GetValue() {
+			t.Error("expected client certificates to be required")
+		}
+	})
+
+	t.Run("permissive client to server", func(t *testing.T) {
+		// A proxyless server of the sidecars service, in the PERMISSIVE default namespace.
+		server := s.SetupProxy(&model.Proxy{
+			IPAddresses: []string{"10.0.1.1"},
+			Metadata: &model.NodeMetadata{
+				Generator: "grpc",
+				Labels:    map[string]string{"security.istio.io/tlsMode": "istio"},
+			},
+		})
+		res := g.BuildListeners(server, push, []string{grpcgen.ServerListenerNamePrefix + "0.0.0.0:7070"})
+		if len(res) != 1 {
+			t.Fatalf("expected 1 listener, got %d", len(res))
+		}
+		l := &listener.Listener{}
+		unmarshal(t, res[0], l)
+		serverTLS := l.FilterChains[0].GetTransportSocket() != nil
+
+		res = g.BuildClusters(proxy, push, []string{"sidecars.default.svc.cluster.local:7070"})
+		if len(res) != 1 {
+			t.Fatalf("expected 1 cluster, got %d", len(res))
+		}
+		c := &cluster.Cluster{}
+		unmarshal(t, res[0], c)
+		clientTLS := c.GetTransportSocket() != nil
+
+		if !serverTLS || !clientTLS {
+			t.Errorf("expected mTLS between the client and the server, got client TLS %v and server TLS %v", clientTLS, serverTLS)
+		}
+	})
+}
+
+func unmarshal(t *testing.T, a *any.Any, msg proto.Message) {
+	t.Helper()
+	if a == nil {
+		t.Fatal("missing resource")
+	}
+	if err := ptypes.UnmarshalAny(a, msg); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -15,22 +15,13 @@
 package grpcgen
 
 import (
-	"net"
-	"strconv"
-	"strings"
-
-	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
-	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
-	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
-	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
-	"github.com/golang/protobuf/ptypes/any"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/golang/protobuf/proto"
 
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/networking/util"
 	v3 "istio.io/istio/pilot/pkg/xds/v3"
-	"istio.io/istio/pkg/config/host"
-	"istio.io/pkg/log"
 )
 
 // Support generation of 'ApiListener' LDS responses, used for native support of gRPC.
@@ -49,6 +40,20 @@ import (
 // using the generic structures. "Classical" CDS/LDS/RDS/EDS use separate logic -
 // this is used for the API-based LDS and generic messages.
 
+const (
+	// ServerListenerNamePrefix is the prefix of the server-side listener names. gRPC servers request them
+	// when their bootstrap sets server_listener_resource_name_template to ServerListenerNamePrefix + "%s",
+	// where %s is replaced with the listening address.
+	ServerListenerNamePrefix = "xds.istio.io/grpc/lds/inbound/"
+
+	// The TLS contexts use the certificates issued by istio-agent through the certificate provider plugin
+	// of gRPC. The gRPC bootstrap must define the certProviderInstance instance, for example with the
+	// file_watcher plugin reading the certificates written by istio-agent.
+	certProviderInstance = "default"
+	identityCertName     = "default"
+	rootCertName         = "ROOTCA"
+)
+
 type GrpcConfigGenerator struct{}
 
 func (g *GrpcConfigGenerator) Generate(proxy *model.Proxy, push *model.PushContext,
@@ -65,156 +70,33 @@ func (g *GrpcConfigGenerator) Generate(proxy *model.Proxy, push *model.PushConte
 	return nil, nil
 }
 
-// handleLDSApiType handles a LDS request, returning listeners of ApiListener type.
-// The request may include a list of resource names, using the full_hostname[:port] format to select only
-// specific services.
-func (g *GrpcConfigGenerator) BuildListeners(node *model.Proxy, push *model.PushContext, names []string) []*any.Any {
-	resp := []*any.Any{}
-
-	filter := map[string]bool{}
-	for _, name := range names {
-		if strings.Contains(name, ":") {
-			n, _, err := net.SplitHostPort(name)
-			if err == nil {
-				name = n
-			}
-		}
-		filter[name] = true
-	}
-
-	for _, el := range node.SidecarScope.EgressListeners {
-		for _, sv := range el.Services() {
-			shost := string(sv.Hostname)
-			if len(filter) > 0 {
-				// DiscReq has a filter - only return services that match
-				if !filter[shost] {
-					continue
-				}
-			}
-			for _, p := range sv.Ports {
-				hp := net.JoinHostPort(shost, strconv.Itoa(p.Port))
-				ll := &listener.Listener{
-					Name: hp,
-				}
-
-				ll.Address = &core.Address{
-					Address: &core.Address_SocketAddress{
-						SocketAddress: &core.SocketAddress{
-							Address: sv.Address,
-							PortSpecifier: &core.SocketAddress_PortValue{
-								PortValue: uint32(p.Port),
-							},
-						},
-					},
-				}
-				hcm := &hcm.HttpConnectionManager{
-					RouteSpecifier: &hcm.HttpConnectionManager_Rds{
-						Rds: &hcm.Rds{
-							ConfigSource: &core.ConfigSource{
-								ConfigSourceSpecifier: &core.ConfigSource_Ads{
-									Ads: &core.AggregatedConfigSource{},
-								},
-							},
-							RouteConfigName: hp,
-						},
-					},
-				}
-				hcmAny := util.MessageToAny(hcm)
-				// TODO: for TCP listeners don't generate RDS, but some indication of cluster name.
-				ll.ApiListener = &listener.ApiListener{
-					ApiListener: hcmAny,
-				}
-				resp = append(resp, util.MessageToAny(ll))
-			}
-		}
-	}
-
-	return resp
-}
-
-// Handle a gRPC CDS request, used with the 'ApiListener' style of requests.
-// The main difference is that the request includes Resources.
-func (g *GrpcConfigGenerator) BuildClusters(node *model.Proxy, push *model.PushContext, names []string) []*any.Any {
-	resp := []*any.Any{}
-	// gRPC doesn't currently support any of the APIs - returning just the expected EDS result.
-	// Since the code is relatively strict - we'll add info as needed.
-	for _, n := range names {
-		hn, portn, err := net.SplitHostPort(n)
-		if err != nil {
-			log.Warn("Failed to parse ", n, " ", err)
-			continue
-		}
-		rc := &cluster.Cluster{
-			Name:                 n,
-			ClusterDiscoveryType: &cluster.Cluster_Type{Type: cluster.Cluster_EDS},
-			EdsClusterConfig: &cluster.Cluster_EdsClusterConfig{
-				ServiceName: "outbound|" + portn + "||" + hn,
-				EdsConfig: &core.ConfigSource{
-					ConfigSourceSpecifier: &core.ConfigSource_Ads{
-						Ads: &core.AggregatedConfigSource{},
-					},
+// buildCommonTLSContext returns a TLS context presenting the workload certificate and validating the peer
+// certificate with the root certificate of the mesh. If not empty, the SAN of the peer certificate must be one
+// of subjectAltNames.
+func buildCommonTLSContext(subjectAltNames []string) *tls.CommonTlsContext {
+	return &tls.CommonTlsContext{
+		TlsCertificateCertificateProviderInstance: &tls.CommonTlsContext_CertificateProviderInstance{
+			InstanceName:    certProviderInstance,
+			CertificateName: identityCertName,
+		},
+		ValidationContextType: &tls.CommonTlsContext_CombinedValidationContext{
+			CombinedValidationContext: &tls.CommonTlsContext_CombinedCertificateValidationContext{
+				DefaultValidationContext: &tls.CertificateValidationContext{
+					MatchSubjectAltNames: util.StringToExactMatch(subjectAltNames),
+				},
+				ValidationContextCertificateProviderInstance: &tls.CommonTlsContext_CertificateProviderInstance{
+					InstanceName:    certProviderInstance,
+					CertificateName: rootCertName,
 				},
 			},
-		}
-		resp = append(resp, util.MessageToAny(rc))
+		},
 	}
-	return resp
 }
 
-// handleSplitRDS supports per-VIP routes, as used by GRPC.
-// This mode is indicated by using names containing full host:port instead of just port.
-// Returns true of the request is of this type.
-func (g *GrpcConfigGenerator) BuildHTTPRoutes(node *model.Proxy, push *model.PushContext, routeNames []string) []*any.Any {
-	resp := []*any.Any{}
-
-	// Currently this mode is only used by GRPC, to extract Cluster for the default
-	// route.
-	for _, n := range routeNames {
-		hn, portn, err := net.SplitHostPort(n)
-		if err != nil {
-			log.Warn("Failed to parse ", n, " ", err)
-			continue
-		}
-		port, err := strconv.Atoi(portn)
-		if err != nil {
-			log.Warn("Failed to parse port ", n, " ", err)
-			continue
-		}
-		el := node.SidecarScope.GetEgressListenerForRDS(port, "")
-		// TODO: use VirtualServices instead !
-		// Currently gRPC doesn't support matching the path.
-		svc := el.Services()
-		for _, s := range svc {
-			if s.Hostname.Matches(host.Name(hn)) {
-				// Only generate the required route for grpc. Will need to generate more
-				// as GRPC adds more features.
-				rc := &route.RouteConfiguration{
-					Name: n,
-					VirtualHosts: []*route.VirtualHost{
-						{
-							Name:    hn,
-							Domains: []string{hn, n},
-
-							Routes: []*route.Route{
-								{
-									Match: &route.RouteMatch{
-										PathSpecifier: &route.RouteMatch_Prefix{Prefix: ""},
-									},
-									Action: &route.Route_Route{
-										Route: &route.RouteAction{
-											ClusterSpecifier: &route.RouteAction_Cluster{
-												Cluster: n,
-											},
-										},
-									},
-								},
-							},
-						},
-					},
-				}
-				resp = append(resp, util.MessageToAny(rc))
-			}
-		}
+// buildTransportSocket wraps a TLS context in the transport socket expected by gRPC.
+func buildTransportSocket(tlsContext proto.Message) *core.TransportSocket {
+	return &core.TransportSocket{
+		Name:       util.EnvoyTLSSocketName,
+		ConfigType: &core.TransportSocket_TypedConfig{TypedConfig: util.MessageToAny(tlsContext)},
 	}
-	return resp
 }
@@ -0,0 +1,191 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcgen
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/golang/protobuf/ptypes/any"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/util"
+	"istio.io/istio/pilot/pkg/security/authn/factory"
+	xdsfilters "istio.io/istio/pilot/pkg/xds/filters"
+	"istio.io/istio/pkg/config/labels"
+	protovalue "istio.io/istio/pkg/proto"
+	"istio.io/pkg/log"
+)
+
+// handleLDSApiType handles a LDS request, returning listeners of ApiListener type.
+// The request may include a list of resource names, using the full_hostname[:port] format to select only
+// specific services. Names starting with ServerListenerNamePrefix select the server-side listeners.
+func (g *GrpcConfigGenerator) BuildListeners(node *model.Proxy, push *model.PushContext, names []string) []*any.Any {
+	resp := []*any.Any{}
+
+	filter := map[string]bool{}
+	for _, name := range names {
+		if strings.HasPrefix(name, ServerListenerNamePrefix) {
+			if ll := buildInboundListener(node, push, name); ll != nil {
+				resp = append(resp, util.MessageToAny(ll))
+			}
+			continue
+		}
+		if strings.Contains(name, ":") {
+			n, _, err := net.SplitHostPort(name)
+			if err == nil {
+				name = n
+			}
+		}
+		filter[name] = true
+	}
+	if len(names) > 0 && len(filter) == 0 {
+		// Only server-side listeners were requested.
+		return resp
+	}
+
+	for _, el := range node.SidecarScope.EgressListeners {
+		for _, sv := range el.Services() {
+			shost := string(sv.Hostname)
+			if len(filter) > 0 {
+				// DiscReq has a filter - only return services that match
+				if !filter[shost] {
+					continue
+				}
+			}
+			for _, p := range sv.Ports {
+				hp := net.JoinHostPort(shost, strconv.Itoa(p.Port))
+				ll := &listener.Listener{
+					Name: hp,
+				}
+
+				ll.Address = &core.Address{
+					Address: &core.Address_SocketAddress{
+						SocketAddress: &core.SocketAddress{
+							Address: sv.Address,
+							PortSpecifier: &core.SocketAddress_PortValue{
+								PortValue: uint32(p.Port),
+							},
+						},
+					},
+				}
+				hcm := &hcm.HttpConnectionManager{
+					RouteSpecifier: &hcm.HttpConnectionManager_Rds{
+						Rds: &hcm.Rds{
+							ConfigSource: &core.ConfigSource{
+								ConfigSourceSpecifier: &core.ConfigSource_Ads{
+									Ads: &core.AggregatedConfigSource{},
+								},
+							},
+							RouteConfigName: hp,
+						},
+					},
+				}
+				hcmAny := util.MessageToAny(hcm)
+				// TODO: for TCP listeners don't generate RDS, but some indication of cluster name.
+				ll.ApiListener = &listener.ApiListener{
+					ApiListener: hcmAny,
+				}
+				resp = append(resp, util.MessageToAny(ll))
+			}
+		}
+	}
+
+	return resp
+}
+
+// buildInboundListener builds the server-side listener of a gRPC server, named after its listening address.
+// The filter chains only carry the TLS settings matching the PeerAuthentication of the workload.
+func buildInboundListener(node *model.Proxy, push *model.PushContext, name string) *listener.Listener {
+	address := strings.TrimPrefix(name, ServerListenerNamePrefix)
+	ip, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		log.Warnf("Failed to parse server listener name %s: %v", name, err)
+		return nil
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		log.Warnf("Failed to parse port of server listener name %s: %v", name, err)
+		return nil
+	}
+
+	applier := factory.NewPolicyApplier(push, node.Metadata.Namespace, labels.Collection{node.Metadata.Labels})
+	mode := applier.GetMutualTLSModeForPort(uint32(port))
+
+	var filterChain *listener.FilterChain
+	switch mode {
+	case model.MTLSStrict, model.MTLSPermissive:
+		// gRPC servers cannot detect TLS on a port, so a PERMISSIVE port only accepts mTLS like a STRICT one: the
+		// clients send mTLS to the endpoints with the security.istio.io/tlsMode=istio label, which the servers
+		// using the certificates of istio-agent have.
+		filterChain = buildInboundFilterChain(port, "mtls", &tls.DownstreamTlsContext{
+			CommonTlsContext:         buildCommonTLSContext(nil),
+			RequireClientCertificate: protovalue.BoolTrue,
+		})
+	default:
+		filterChain = buildInboundFilterChain(port, "plaintext", nil)
+	}
+
+	return &listener.Listener{
+		Name: name,
+		Address: &core.Address{
+			Address: &core.Address_SocketAddress{
+				SocketAddress: &core.SocketAddress{
+					Address: ip,
+					PortSpecifier: &core.SocketAddress_PortValue{
+						PortValue: uint32(port),
+					},
+				},
+			},
+		},
+		FilterChains: []*listener.FilterChain{filterChain},
+	}
+}
+
+// buildInboundFilterChain builds a server-side filter chain, using the TLS context if not nil.
+func buildInboundFilterChain(port int, nameSuffix string, tlsContext *tls.DownstreamTlsContext) *listener.FilterChain {
+	routeName := model.BuildSubsetKey(model.TrafficDirectionInbound, "", "", port)
+	out := &listener.FilterChain{
+		Name: "inbound-" + nameSuffix,
+		Filters: []*listener.Filter{{
+			Name: "inbound-hcm-" + nameSuffix,
+			ConfigType: &listener.Filter_TypedConfig{
+				TypedConfig: util.MessageToAny(&hcm.HttpConnectionManager{
+					// gRPC serves the requests itself, the routes are not used.
+					RouteSpecifier: &hcm.HttpConnectionManager_RouteConfig{
+						RouteConfig: &route.RouteConfiguration{
+							Name: routeName,
+							VirtualHosts: []*route.VirtualHost{{
+								Name:    routeName,
+								Domains: []string{"*"},
+							}},
+						},
+					},
+					HttpFilters: []*hcm.HttpFilter{xdsfilters.Router},
+				}),
+			},
+		}},
+	}
+	if tlsContext != nil {
+		out.TransportSocket = buildTransportSocket(tlsContext)
+	}
+	return out
+}
@@ -0,0 +1,120 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcgen
+
+import (
+	"net"
+	"strconv"
+
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/golang/protobuf/ptypes/any"
+
+	"istio.io/istio/pilot/pkg/model"
+	istioroute "istio.io/istio/pilot/pkg/networking/core/v1alpha3/route"
+	"istio.io/istio/pilot/pkg/networking/util"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/pkg/log"
+)
+
+// handleSplitRDS supports per-VIP routes, as used by GRPC.
+// This mode is indicated by using names containing full host:port instead of just port.
+// Returns true of the request is of this type.
+func (g *GrpcConfigGenerator) BuildHTTPRoutes(node *model.Proxy, push *model.PushContext, routeNames []string) []*any.Any {
+	resp := []*any.Any{}
+
+	for _, n := range routeNames {
+		hn, portn, err := net.SplitHostPort(n)
+		if err != nil {
+			log.Warn("Failed to parse ", n, " ", err)
+			continue
+		}
+		port, err := strconv.Atoi(portn)
+		if err != nil {
+			log.Warn("Failed to parse port ", n, " ", err)
+			continue
+		}
+		el := node.SidecarScope.GetEgressListenerForRDS(port, "")
+		if el == nil {
+			continue
+		}
+		for _, s := range el.Services() {
+			if s.Hostname.Matches(host.Name(hn)) {
+				rc := &route.RouteConfiguration{
+					Name: n,
+					VirtualHosts: []*route.VirtualHost{
+						{
+							Name:    hn,
+							Domains: []string{hn, n},
+							Routes:  buildRoutes(node, push, el, s, port, n),
+						},
+					},
+				}
+				resp = append(resp, util.MessageToAny(rc))
+			}
+		}
+	}
+	return resp
+}
+
+// buildRoutes returns the routes of the VirtualServices for the service port, as generated for sidecars.
+// gRPC only understands the routes forwarding to clusters - the other routes are skipped. Without any route,
+// the default route forwards everything to defaultCluster.
+func buildRoutes(node *model.Proxy, push *model.PushContext, el *model.IstioEgressListenerWrapper,
+	svc *model.Service, port int, defaultCluster string) []*route.Route {
+	var routes []*route.Route
+	vhosts := istioroute.BuildSidecarVirtualHostsFromConfigAndRegistry(node, push,
+		map[host.Name]*model.Service{svc.Hostname: svc}, el.VirtualServices(), port)
+	for _, vh := range vhosts {
+		if vh.Port != port || !containsService(vh.Services, svc) {
+			continue
+		}
+		for _, r := range vh.Routes {
+			if ra, ok := r.Action.(*route.Route_Route); ok {
+				if _, ok := ra.Route.ClusterSpecifier.(*route.RouteAction_ClusterHeader); ok {
+					continue
+				}
+				routes = append(routes, r)
+			}
+		}
+	}
+	if len(routes) > 0 {
+		return routes
+	}
+
+	// Only generate the required route for grpc. gRPC expects "" instead of "/" as default prefix.
+	return []*route.Route{
+		{
+			Match: &route.RouteMatch{
+				PathSpecifier: &route.RouteMatch_Prefix{Prefix: ""},
+			},
+			Action: &route.Route_Route{
+				Route: &route.RouteAction{
+					ClusterSpecifier: &route.RouteAction_Cluster{
+						Cluster: defaultCluster,
+					},
+				},
+			},
+		},
+	}
+}
+
+func containsService(services []*model.Service, svc *model.Service) bool {
+	for _, s := range services {
+		if s.Hostname == svc.Hostname {
+			return true
+		}
+	}
+	return false
+}
@@ -17,6 +17,7 @@ package builder
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	tcppb "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	rbacpb "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
@@ -27,14 +28,19 @@ import (
 	"github.com/hashicorp/go-multierror"
 
 	"istio.io/api/annotation"
+	authpb "istio.io/api/security/v1beta1"
 	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/networking/plugin"
 	"istio.io/istio/pilot/pkg/networking/util"
 	authzmodel "istio.io/istio/pilot/pkg/security/authz/model"
 	"istio.io/istio/pilot/pkg/security/trustdomain"
 	"istio.io/istio/pkg/config/labels"
+	"istio.io/istio/pkg/spiffe"
 )
 
+// revokedIdentitiesPolicyName is the name of the DENY policy generated for the identities revoked by the Istio CA.
+const revokedIdentitiesPolicyName = "istio-ca-revoked-identities"
+
 var rbacPolicyMatchNever = &rbacpb.Policy{
 	Permissions: []*rbacpb.Permission{{Rule: &rbacpb.Permission_NotRule{
 		NotRule: &rbacpb.Permission{Rule: &rbacpb.Permission_Any{Any: true}},
@@ -82,6 +88,9 @@ func New(trustDomainBundle trustdomain.Bundle, in *plugin.InputParams, option Op
 		}
 	}
 
+	if len(in.Push.RevokedIdentities) != 0 {
+		policies.Deny = append([]model.AuthorizationPolicy{revokedIdentitiesPolicy(in.Push)}, policies.Deny...)
+	}
 	option.Logger.AppendDebugf("found %d DENY actions, %d ALLOW actions, %d AUDIT actions", len(policies.Deny), len(policies.Allow), len(policies.Audit))
 	if len(policies.Deny) == 0 && len(policies.Allow) == 0 && len(policies.Audit) == 0 {
 		return nil
@@ -95,6 +104,25 @@ func New(trustDomainBundle trustdomain.Bundle, in *plugin.InputParams, option Op
 	}
 }
 
+// revokedIdentitiesPolicy returns a DENY policy for the identities revoked by the Istio CA. Their certificates are
+// valid until they expire, whichever CA replica signed them, so their requests are denied by principal.
+func revokedIdentitiesPolicy(push *model.PushContext) model.AuthorizationPolicy {
+	principals := make([]string, 0, len(push.RevokedIdentities))
+	for _, id := range push.RevokedIdentities {
+		principals = append(principals, strings.TrimPrefix(id, spiffe.URIPrefix))
+	}
+	return model.AuthorizationPolicy{
+		Name:      revokedIdentitiesPolicyName,
+		Namespace: push.Mesh.GetRootNamespace(),
+		Spec: &authpb.AuthorizationPolicy{
+			Action: authpb.AuthorizationPolicy_DENY,
+			Rules: []*authpb.Rule{{
+				From: []*authpb.Rule_From{{Source: &authpb.Source{Principals: principals}}},
+			}},
+		},
+	}
+}
+
 // BuildHTTP returns the HTTP filters built from the authorization policy.
 func (b Builder) BuildHTTP() []*httppb.HttpFilter {
 	if b.option.IsCustomBuilder {
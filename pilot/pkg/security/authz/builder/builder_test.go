@@ -130,6 +130,7 @@ func TestGenerator_GenerateHTTP(t *testing.T) {
 		tdBundle   trustdomain.Bundle
 		meshConfig *meshconfig.MeshConfig
 		input      string
+		revoked    []string
 		want       []string
 	}{
 		{
@@ -246,6 +247,13 @@ func TestGenerator_GenerateHTTP(t *testing.T) {
 			input:    "td-aliases-source-principal-in.yaml",
 			want:     []string{"td-aliases-source-principal-out.yaml"},
 		},
+		{
+			name:     "revoked-identities",
+			tdBundle: trustdomain.NewBundle("cluster.local", []string{"old-td"}),
+			input:    "revoked-identities-in.yaml",
+			revoked:  []string{"spiffe://cluster.local/ns/foo/sa/revoked"},
+			want:     []string{"revoked-identities-out.yaml"},
+		},
 	}
 
 	baseDir := "http/"
@@ -256,6 +264,7 @@ func TestGenerator_GenerateHTTP(t *testing.T) {
 				Logger:          &AuthzLogger{},
 			}
 			in := inputParams(t, baseDir+tc.input, tc.meshConfig)
+			in.Push.RevokedIdentities = tc.revoked
 			defer option.Logger.Report(in)
 			g := New(tc.tdBundle, in, option)
 			if g == nil {
@@ -273,6 +282,7 @@ func TestGenerator_GenerateTCP(t *testing.T) {
 		tdBundle   trustdomain.Bundle
 		meshConfig *meshconfig.MeshConfig
 		input      string
+		revoked    []string
 		want       []string
 	}{
 		{
@@ -312,6 +322,13 @@ func TestGenerator_GenerateTCP(t *testing.T) {
 			input: "dry-run-mix-in.yaml",
 			want:  []string{"dry-run-mix-out.yaml"},
 		},
+		{
+			name:     "revoked-identities",
+			tdBundle: trustdomain.NewBundle("cluster.local", nil),
+			input:    "revoked-identities-in.yaml",
+			revoked:  []string{"spiffe://cluster.local/ns/bar/sa/revoked", "spiffe://cluster.local/ns/foo/sa/revoked"},
+			want:     []string{"revoked-identities-out.yaml"},
+		},
 	}
 
 	baseDir := "tcp/"
@@ -322,6 +339,7 @@ func TestGenerator_GenerateTCP(t *testing.T) {
 				Logger:          &AuthzLogger{},
 			}
 			in := inputParams(t, baseDir+tc.input, tc.meshConfig)
+			in.Push.RevokedIdentities = tc.revoked
 			defer option.Logger.Report(in)
 			g := New(tc.tdBundle, in, option)
 			if g == nil {
@@ -285,6 +285,23 @@ func ApplyToCommonTLSContext(tlsContext *tls.CommonTlsContext, proxy *model.Prox
 	}
 }
 
+// ApplyCertificateRevocationList sets the CRL of the mesh CA in the validation context built by
+// ApplyToCommonTLSContext, so the peer certificates revoked by the CA are rejected.
+func ApplyCertificateRevocationList(tlsContext *tls.CommonTlsContext, crl []byte) {
+	if len(crl) == 0 {
+		return
+	}
+	validationContext := tlsContext.GetCombinedValidationContext().GetDefaultValidationContext()
+	if validationContext == nil {
+		return
+	}
+	validationContext.Crl = &core.DataSource{
+		Specifier: &core.DataSource_InlineBytes{
+			InlineBytes: crl,
+		},
+	}
+}
+
 // ApplyCustomSDSToClientCommonTLSContext applies the customized sds to CommonTlsContext
 // Used for building upstream TLS context for egress gateway's TLS/mTLS origination
 func ApplyCustomSDSToClientCommonTLSContext(tlsContext *tls.CommonTlsContext, tlsOpts *networking.ClientTLSSettings) {
@@ -487,3 +487,25 @@ func TestApplyToCommonTLSContext(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyCertificateRevocationList(t *testing.T) {
+	crl := []byte("-----BEGIN X509 CRL-----")
+	proxy := &model.Proxy{Metadata: &model.NodeMetadata{}}
+
+	tlsContext := &auth.CommonTlsContext{}
+	ApplyToCommonTLSContext(tlsContext, proxy, nil, nil, true)
+	ApplyCertificateRevocationList(tlsContext, crl)
+	want := &core.DataSource{Specifier: &core.DataSource_InlineBytes{InlineBytes: crl}}
+	got := tlsContext.GetCombinedValidationContext().GetDefaultValidationContext().GetCrl()
+	if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected CRL: %v", diff)
+	}
+
+	// Nothing to set when the peer is not validated.
+	tlsContext = &auth.CommonTlsContext{}
+	ApplyToCommonTLSContext(tlsContext, proxy, nil, nil, false)
+	ApplyCertificateRevocationList(tlsContext, crl)
+	if tlsContext.ValidationContextType != nil {
+		t.Errorf("unexpected validation context %v", tlsContext.ValidationContextType)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// indexHeader is the response header holding the catalog index used for blocking queries.
+	indexHeader = "X-Consul-Index"
+	// tokenHeader is the request header holding the ACL token.
+	tokenHeader = "X-Consul-Token"
+)
+
+// catalogNode is the node of a catalog entry.
+type catalogNode struct {
+	Node       string
+	Address    string
+	Datacenter string
+	Meta       map[string]string
+}
+
+// catalogService is a service registered on a node.
+type catalogService struct {
+	ID      string
+	Service string
+	Tags    []string
+	Address string
+	Port    int
+	Meta    map[string]string
+}
+
+// healthCheck is a health check of a node or a service.
+type healthCheck struct {
+	CheckID   string
+	ServiceID string
+	Status    string
+}
+
+// serviceEntry is an entry returned by the health endpoint of the catalog:
+// a service instance with its node and the health checks which apply to it.
+type serviceEntry struct {
+	Node    *catalogNode
+	Service *catalogService
+	Checks  []healthCheck
+}
+
+// catalogClient is a minimal client of the catalog HTTP API.
+type catalogClient struct {
+	address    string
+	datacenter string
+	token      string
+	waitTime   time.Duration
+	client     *http.Client
+}
+
+// services returns the names and tags of all services in the catalog. If index is not zero, the
+// request blocks until the catalog changes past index or the wait time elapses.
+func (c *catalogClient) services(ctx context.Context, index uint64) (map[string][]string, uint64, error) {
+	out := map[string][]string{}
+	index, err := c.query(ctx, "/v1/catalog/services", index, &out)
+	return out, index, err
+}
+
+// healthService returns all instances of the service with their health checks. If index is not zero, the
+// request blocks until the instances change past index or the wait time elapses.
+func (c *catalogClient) healthService(ctx context.Context, service string, index uint64) ([]*serviceEntry, uint64, error) {
+	var out []*serviceEntry
+	index, err := c.query(ctx, "/v1/health/service/"+url.PathEscape(service), index, &out)
+	return out, index, err
+}
+
+// query performs a blocking query against the catalog and decodes the response into out.
+// It returns the catalog index of the response.
+func (c *catalogClient) query(ctx context.Context, path string, index uint64, out interface{}) (uint64, error) {
+	params := url.Values{}
+	if c.datacenter != "" {
+		params.Set("dc", c.datacenter)
+	}
+	if index > 0 {
+		params.Set("index", strconv.FormatUint(index, 10))
+		if c.waitTime > 0 {
+			params.Set("wait", fmt.Sprintf("%dms", c.waitTime.Milliseconds()))
+		}
+	}
+	u := strings.TrimSuffix(c.address, "/") + path
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	if c.token != "" {
+		req.Header.Set(tokenHeader, c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("catalog query %s failed with status %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return 0, fmt.Errorf("failed to decode catalog response of %s: %v", path, err)
+	}
+	newIndex, err := strconv.ParseUint(resp.Header.Get(indexHeader), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s header in catalog response of %s: %v", indexHeader, path, err)
+	}
+	// The index may go backwards, e.g. when the catalog is restored from a snapshot,
+	// in which case the next query must not block on the stale index.
+	if newIndex < index {
+		return 0, nil
+	}
+	return newIndex, nil
+}
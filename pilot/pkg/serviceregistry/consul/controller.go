@@ -0,0 +1,369 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consul
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/serviceregistry"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/labels"
+	istiolog "istio.io/pkg/log"
+)
+
+var log = istiolog.RegisterScope("consul", "consul catalog service registry controller", 0)
+
+const (
+	// defaultWaitTime is the maximum duration of a blocking query.
+	defaultWaitTime = 5 * time.Minute
+	// retryInterval is the delay before retrying a failed catalog query.
+	retryInterval = time.Second
+)
+
+// Options stores the configurable attributes of a Controller.
+type Options struct {
+	// Address is the base URL of the catalog HTTP API, e.g. http://127.0.0.1:8500.
+	Address string
+	// Datacenter to query. The datacenter of the catalog agent is used if empty.
+	Datacenter string
+	// Token is the ACL token sent with catalog queries.
+	Token string
+	// ClusterID identifies the registry.
+	ClusterID string
+	// WaitTime is the maximum duration of a blocking query. Defaults to 5 minutes.
+	WaitTime time.Duration
+	// HTTPClient is used to query the catalog. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// XDSUpdater is notified of service and endpoint changes.
+	XDSUpdater model.XDSUpdater
+}
+
+// Controller is a service registry backed by a Consul-compatible catalog. It watches the catalog
+// services and the health of their instances with blocking queries.
+type Controller struct {
+	opts   Options
+	client *catalogClient
+
+	mutex sync.RWMutex
+	// services and instances of the catalog, keyed by hostname.
+	services  map[host.Name]*model.Service
+	instances map[host.Name][]*model.ServiceInstance
+	// ip2instances indexes the instances by endpoint address, used to look up the instances of a proxy.
+	ip2instances map[string][]*model.ServiceInstance
+	handlers     []func(*model.Service, model.Event)
+	synced       bool
+
+	// watchers cancel the health watch of each catalog service. Only accessed by Run.
+	watchers map[string]context.CancelFunc
+}
+
+var _ serviceregistry.Instance = &Controller{}
+
+// NewController creates a new catalog service registry.
+func NewController(opts Options) *Controller {
+	if opts.WaitTime == 0 {
+		opts.WaitTime = defaultWaitTime
+	}
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Controller{
+		opts: opts,
+		client: &catalogClient{
+			address:    opts.Address,
+			datacenter: opts.Datacenter,
+			token:      opts.Token,
+			waitTime:   opts.WaitTime,
+			client:     httpClient,
+		},
+		services:     map[host.Name]*model.Service{},
+		instances:    map[host.Name][]*model.ServiceInstance{},
+		ip2instances: map[string][]*model.ServiceInstance{},
+		watchers:     map[string]context.CancelFunc{},
+	}
+}
+
+// Provider returns the ProviderID of the registry.
+func (c *Controller) Provider() serviceregistry.ProviderID {
+	return serviceregistry.Consul
+}
+
+// Cluster returns the cluster ID of the registry.
+func (c *Controller) Cluster() string {
+	return c.opts.ClusterID
+}
+
+// AppendServiceHandler registers a handler notified of service changes.
+func (c *Controller) AppendServiceHandler(f func(*model.Service, model.Event)) {
+	c.mutex.Lock()
+	c.handlers = append(c.handlers, f)
+	c.mutex.Unlock()
+}
+
+// AppendWorkloadHandler is a no-op, the catalog has no workloads other than service instances.
+func (c *Controller) AppendWorkloadHandler(func(*model.WorkloadInstance, model.Event)) {}
+
+// HasSynced returns true once the initial state of all catalog services has been loaded.
+func (c *Controller) HasSynced() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.synced
+}
+
+// Run watches the catalog until stop is closed.
+func (c *Controller) Run(stop <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	var index uint64
+	for {
+		names, newIndex, err := c.client.services(ctx, index)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			log.Warnf("failed to list catalog services from %s: %v", c.opts.Address, err)
+			if !sleep(ctx, retryInterval) {
+				break
+			}
+			continue
+		}
+		c.reconcileServices(ctx, names)
+		if index == 0 {
+			c.mutex.Lock()
+			c.synced = true
+			c.mutex.Unlock()
+			log.Infof("catalog %s synced with %d services", c.opts.Address, len(names))
+		}
+		index = newIndex
+	}
+
+	for _, cancelWatch := range c.watchers {
+		cancelWatch()
+	}
+	log.Infof("stopped watching catalog %s", c.opts.Address)
+}
+
+// reconcileServices starts watching new catalog services and removes the services which left the catalog.
+// The initial state of new services is loaded before returning, so the registry is complete once synced.
+func (c *Controller) reconcileServices(ctx context.Context, names map[string][]string) {
+	for name, cancelWatch := range c.watchers {
+		if _, f := names[name]; !f {
+			cancelWatch()
+			delete(c.watchers, name)
+			c.updateService(name, nil)
+		}
+	}
+
+	added := make([]string, 0)
+	for name := range names {
+		if _, f := c.watchers[name]; !f {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(added)
+	for _, name := range added {
+		entries, index, err := c.client.healthService(ctx, name, 0)
+		if err != nil {
+			log.Warnf("failed to fetch instances of catalog service %s: %v", name, err)
+		} else {
+			c.updateService(name, entries)
+		}
+		watchCtx, cancelWatch := context.WithCancel(ctx)
+		c.watchers[name] = cancelWatch
+		go c.watchService(watchCtx, name, index)
+	}
+}
+
+// watchService keeps the instances of the catalog service up to date until ctx is cancelled.
+func (c *Controller) watchService(ctx context.Context, name string, index uint64) {
+	for {
+		entries, newIndex, err := c.client.healthService(ctx, name, index)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Warnf("failed to watch instances of catalog service %s: %v", name, err)
+			if !sleep(ctx, retryInterval) {
+				return
+			}
+			continue
+		}
+		// A blocking query returning the same index timed out without any change.
+		if newIndex != index || index == 0 {
+			c.updateService(name, entries)
+		}
+		index = newIndex
+	}
+}
+
+// updateService converts the catalog instances of a service and notifies the handlers and the XDSUpdater of changes.
+// A service which left the catalog is removed.
+func (c *Controller) updateService(name string, entries []*serviceEntry) {
+	svc, instances := convertService(name, c.opts.ClusterID, entries)
+	hostname := serviceHostname(name)
+
+	c.mutex.Lock()
+	old := c.services[hostname]
+	if svc == nil {
+		delete(c.services, hostname)
+		delete(c.instances, hostname)
+	} else {
+		c.services[hostname] = svc
+		c.instances[hostname] = instances
+	}
+	c.rebuildIPIndex()
+	handlers := c.handlers
+	c.mutex.Unlock()
+
+	var event model.Event
+	switch {
+	case old == nil && svc == nil:
+		return
+	case svc == nil:
+		event = model.EventDelete
+		svc = old
+	case old == nil:
+		event = model.EventAdd
+	case !reflect.DeepEqual(old.Ports, svc.Ports):
+		event = model.EventUpdate
+	default:
+		// Only the instances changed.
+		if c.opts.XDSUpdater != nil {
+			c.opts.XDSUpdater.EDSUpdate(c.opts.ClusterID, string(hostname), svc.Attributes.Namespace, endpoints(instances))
+		}
+		return
+	}
+
+	log.Debugf("catalog service %s %v", hostname, event)
+	if c.opts.XDSUpdater != nil {
+		if event != model.EventDelete {
+			// A full push is triggered by the service handlers, so only update the endpoint cache.
+			c.opts.XDSUpdater.EDSCacheUpdate(c.opts.ClusterID, string(hostname), svc.Attributes.Namespace, endpoints(instances))
+		}
+		c.opts.XDSUpdater.SvcUpdate(c.opts.ClusterID, string(hostname), svc.Attributes.Namespace, event)
+	}
+	for _, f := range handlers {
+		f(svc, event)
+	}
+}
+
+// rebuildIPIndex rebuilds the index of instances by address. Must be called with the mutex held.
+func (c *Controller) rebuildIPIndex() {
+	c.ip2instances = map[string][]*model.ServiceInstance{}
+	for _, instances := range c.instances {
+		for _, instance := range instances {
+			c.ip2instances[instance.Endpoint.Address] = append(c.ip2instances[instance.Endpoint.Address], instance)
+		}
+	}
+}
+
+// Services returns the services of the catalog.
+func (c *Controller) Services() ([]*model.Service, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	out := make([]*model.Service, 0, len(c.services))
+	for _, svc := range c.services {
+		out = append(out, svc)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Hostname < out[j].Hostname
+	})
+	return out, nil
+}
+
+// GetService retrieves a service by hostname if it exists.
+func (c *Controller) GetService(hostname host.Name) (*model.Service, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.services[hostname], nil
+}
+
+// InstancesByPort returns the healthy instances of the service on the given port matching the labels.
+func (c *Controller) InstancesByPort(svc *model.Service, port int, labels labels.Collection) []*model.ServiceInstance {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	out := make([]*model.ServiceInstance, 0)
+	for _, instance := range c.instances[svc.Hostname] {
+		if instance.ServicePort.Port == port && labels.HasSubsetOf(instance.Endpoint.Labels) {
+			out = append(out, instance)
+		}
+	}
+	return out
+}
+
+// GetProxyServiceInstances returns the catalog instances co-located with the proxy.
+func (c *Controller) GetProxyServiceInstances(node *model.Proxy) []*model.ServiceInstance {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	out := make([]*model.ServiceInstance, 0)
+	for _, ip := range node.IPAddresses {
+		out = append(out, c.ip2instances[ip]...)
+	}
+	return out
+}
+
+// GetProxyWorkloadLabels returns the labels of the catalog instances co-located with the proxy.
+func (c *Controller) GetProxyWorkloadLabels(proxy *model.Proxy) labels.Collection {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	out := make(labels.Collection, 0)
+	for _, ip := range proxy.IPAddresses {
+		for _, instance := range c.ip2instances[ip] {
+			out = append(out, instance.Endpoint.Labels)
+		}
+	}
+	return out
+}
+
+// GetIstioServiceAccounts returns the service accounts of the instances of the service.
+func (c *Controller) GetIstioServiceAccounts(svc *model.Service, ports []int) []string {
+	return model.GetServiceAccounts(svc, ports, c)
+}
+
+// NetworkGateways is not supported by the catalog.
+func (c *Controller) NetworkGateways() map[string][]*model.Gateway {
+	return nil
+}
+
+func endpoints(instances []*model.ServiceInstance) []*model.IstioEndpoint {
+	out := make([]*model.IstioEndpoint, 0, len(instances))
+	for _, instance := range instances {
+		out = append(out, instance.Endpoint)
+	}
+	return out
+}
+
+// sleep waits for the duration, and returns false if ctx is cancelled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consul
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config/labels"
+	"istio.io/istio/pkg/test/util/retry"
+)
+
+// fakeCatalog is a catalog HTTP API serving blocking queries from an in-memory state.
+type fakeCatalog struct {
+	mu       sync.Mutex
+	index    uint64
+	changed  chan struct{}
+	services map[string][]*serviceEntry
+	token    string
+}
+
+func newFakeCatalog(t *testing.T) (*fakeCatalog, string) {
+	c := &fakeCatalog{
+		index:    1,
+		changed:  make(chan struct{}),
+		services: map[string][]*serviceEntry{},
+		token:    "secret",
+	}
+	ts := httptest.NewServer(c)
+	t.Cleanup(ts.Close)
+	return c, ts.URL
+}
+
+// set replaces the instances of the service, removing it from the catalog if there is none.
+func (c *fakeCatalog) set(service string, entries ...*serviceEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(entries) == 0 {
+		delete(c.services, service)
+	} else {
+		c.services[service] = entries
+	}
+	c.index++
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func (c *fakeCatalog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get(tokenHeader) != c.token {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	index, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+	c.mu.Lock()
+	if index >= c.index {
+		changed := c.changed
+		c.mu.Unlock()
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+		case <-time.After(100 * time.Millisecond):
+		}
+		c.mu.Lock()
+	}
+	defer c.mu.Unlock()
+
+	var out interface{}
+	switch {
+	case r.URL.Path == "/v1/catalog/services":
+		services := map[string][]string{}
+		for name, entries := range c.services {
+			services[name] = entries[0].Service.Tags
+		}
+		out = services
+	case strings.HasPrefix(r.URL.Path, "/v1/health/service/"):
+		entries := c.services[strings.TrimPrefix(r.URL.Path, "/v1/health/service/")]
+		if entries == nil {
+			entries = []*serviceEntry{}
+		}
+		out = entries
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set(indexHeader, strconv.FormatUint(c.index, 10))
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func instance(service, ip string, port int, status string, tags ...string) *serviceEntry {
+	return &serviceEntry{
+		Node:    &catalogNode{Node: "node-" + ip, Address: ip, Datacenter: "dc1"},
+		Service: &catalogService{ID: fmt.Sprintf("%s-%s", service, ip), Service: service, Port: port, Tags: tags},
+		Checks:  []healthCheck{{CheckID: "serfHealth", Status: status}},
+	}
+}
+
+type fakeXdsUpdater struct {
+	mu     sync.Mutex
+	events []string
+}
+
+var _ model.XDSUpdater = &fakeXdsUpdater{}
+
+func (fx *fakeXdsUpdater) record(event string) {
+	fx.mu.Lock()
+	fx.events = append(fx.events, event)
+	fx.mu.Unlock()
+}
+
+func (fx *fakeXdsUpdater) EDSUpdate(_, hostname string, _ string, entry []*model.IstioEndpoint) {
+	fx.record(fmt.Sprintf("eds %s %d", hostname, len(entry)))
+}
+
+func (fx *fakeXdsUpdater) EDSCacheUpdate(_, hostname string, _ string, entry []*model.IstioEndpoint) {
+	fx.record(fmt.Sprintf("edscache %s %d", hostname, len(entry)))
+}
+
+func (fx *fakeXdsUpdater) SvcUpdate(_, hostname string, _ string, event model.Event) {
+	fx.record(fmt.Sprintf("svcupdate %s %v", hostname, event))
+}
+
+func (fx *fakeXdsUpdater) ConfigUpdate(*model.PushRequest) {}
+
+func (fx *fakeXdsUpdater) ProxyUpdate(_, _ string) {}
+
+func (fx *fakeXdsUpdater) has(event string) bool {
+	fx.mu.Lock()
+	defer fx.mu.Unlock()
+	for _, e := range fx.events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func TestController(t *testing.T) {
+	catalog, address := newFakeCatalog(t)
+	catalog.set("web", instance("web", "10.0.0.1", 8080, "passing", "protocol=http", "version=v1"))
+
+	xdsUpdater := &fakeXdsUpdater{}
+	c := NewController(Options{
+		Address:    address,
+		Token:      "secret",
+		ClusterID:  "consul",
+		WaitTime:   time.Second,
+		XDSUpdater: xdsUpdater,
+	})
+	var handlerMu sync.Mutex
+	handlerEvents := map[string]model.Event{}
+	c.AppendServiceHandler(func(svc *model.Service, event model.Event) {
+		handlerMu.Lock()
+		handlerEvents[string(svc.Hostname)] = event
+		handlerMu.Unlock()
+	})
+	expectHandlerEvent := func(hostname string, event model.Event) {
+		t.Helper()
+		retry.UntilSuccessOrFail(t, func() error {
+			handlerMu.Lock()
+			defer handlerMu.Unlock()
+			if got, f := handlerEvents[hostname]; !f || got != event {
+				return fmt.Errorf("got event %v for %s, want %v", got, hostname, event)
+			}
+			return nil
+		}, retry.Timeout(5*time.Second))
+	}
+	expectXdsEvent := func(event string) {
+		t.Helper()
+		retry.UntilSuccessOrFail(t, func() error {
+			if !xdsUpdater.has(event) {
+				return fmt.Errorf("missing xds event %q", event)
+			}
+			return nil
+		}, retry.Timeout(5*time.Second))
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.Run(stop)
+	retry.UntilSuccessOrFail(t, func() error {
+		if !c.HasSynced() {
+			return fmt.Errorf("not synced")
+		}
+		return nil
+	}, retry.Timeout(5*time.Second))
+
+	// The initial state is loaded once synced.
+	svc, _ := c.GetService("web.service.consul")
+	if svc == nil {
+		t.Fatal("service web.service.consul not found")
+	}
+	expectHandlerEvent("web.service.consul", model.EventAdd)
+	expectXdsEvent("edscache web.service.consul 1")
+	if got := c.InstancesByPort(svc, 8080, labels.Collection{{"version": "v1"}}); len(got) != 1 {
+		t.Errorf("InstancesByPort() got %d instances, want 1", len(got))
+	}
+	if got := c.InstancesByPort(svc, 8080, labels.Collection{{"version": "v2"}}); len(got) != 0 {
+		t.Errorf("InstancesByPort() with unmatched labels got %d instances, want 0", len(got))
+	}
+	proxy := &model.Proxy{IPAddresses: []string{"10.0.0.1"}}
+	if got := c.GetProxyServiceInstances(proxy); len(got) != 1 {
+		t.Errorf("GetProxyServiceInstances() got %d instances, want 1", len(got))
+	}
+	if got := c.GetProxyWorkloadLabels(proxy); len(got) != 1 || got[0]["version"] != "v1" {
+		t.Errorf("GetProxyWorkloadLabels() got %v", got)
+	}
+
+	// A new healthy instance only updates the endpoints, an unhealthy one is ignored.
+	catalog.set("web",
+		instance("web", "10.0.0.1", 8080, "passing", "protocol=http", "version=v1"),
+		instance("web", "10.0.0.2", 8080, "passing", "protocol=http", "version=v2"),
+		instance("web", "10.0.0.3", 8080, "critical", "protocol=http", "version=v2"))
+	expectXdsEvent("eds web.service.consul 2")
+
+	// A new port updates the service.
+	catalog.set("web",
+		instance("web", "10.0.0.1", 8080, "passing", "protocol=http", "version=v1"),
+		instance("web", "10.0.0.1", 9090, "passing", "protocol=grpc"))
+	expectHandlerEvent("web.service.consul", model.EventUpdate)
+	svc, _ = c.GetService("web.service.consul")
+	if len(svc.Ports) != 2 {
+		t.Errorf("got ports %v, want 2 ports", svc.Ports)
+	}
+
+	// A new catalog service is watched.
+	catalog.set("db", instance("db", "10.0.1.1", 5432, "passing"))
+	expectHandlerEvent("db.service.consul", model.EventAdd)
+	services, _ := c.Services()
+	if len(services) != 2 {
+		t.Errorf("Services() got %d services, want 2", len(services))
+	}
+
+	// A service whose instances fail their health checks keeps its ports, only its endpoints are updated.
+	catalog.set("db", instance("db", "10.0.1.1", 5432, "critical"))
+	expectXdsEvent("eds db.service.consul 0")
+	catalog.set("db", instance("db", "10.0.1.1", 5432, "passing"))
+	expectXdsEvent("eds db.service.consul 1")
+	if xdsUpdater.has("svcupdate db.service.consul delete") || xdsUpdater.has("svcupdate db.service.consul update") {
+		t.Errorf("health check flaps updated the service db.service.consul")
+	}
+	expectHandlerEvent("db.service.consul", model.EventAdd)
+	if svc, _ := c.GetService("db.service.consul"); svc == nil || len(svc.Ports) != 1 {
+		t.Errorf("got service %v, want db.service.consul with 1 port", svc)
+	}
+
+	// A service which left the catalog is removed.
+	catalog.set("db")
+	expectHandlerEvent("db.service.consul", model.EventDelete)
+	catalog.set("web")
+	expectHandlerEvent("web.service.consul", model.EventDelete)
+	expectXdsEvent("svcupdate web.service.consul delete")
+	if svc, _ := c.GetService("web.service.consul"); svc != nil {
+		t.Errorf("service web.service.consul not removed")
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consul
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/serviceregistry"
+	"istio.io/istio/pkg/config/constants"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/labels"
+	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/spiffe"
+)
+
+const (
+	// protocolKey is the service meta key, or the `key=value` tag key, holding the protocol of the service port.
+	protocolKey = "protocol"
+	// serviceAccountKey is the service meta key holding the service account of the instance.
+	serviceAccountKey = "serviceAccount"
+	// healthCritical is the status of a failing health check.
+	healthCritical = "critical"
+)
+
+// serviceHostname returns the hostname of a catalog service, following the catalog DNS interface.
+func serviceHostname(name string) host.Name {
+	return host.Name(fmt.Sprintf("%s.service.consul", name))
+}
+
+// convertLabels merges the service meta and the `key=value` tags of a catalog service into labels.
+// Tags without a value are ignored, meta takes precedence over tags.
+func convertLabels(svc *catalogService) labels.Instance {
+	out := make(labels.Instance, len(svc.Meta)+len(svc.Tags))
+	for _, tag := range svc.Tags {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) == 2 && kv[0] != "" {
+			out[kv[0]] = kv[1]
+		}
+	}
+	for k, v := range svc.Meta {
+		out[k] = v
+	}
+	return out
+}
+
+// convertProtocol returns the protocol of the catalog service, defaulting to TCP.
+func convertProtocol(l labels.Instance) protocol.Instance {
+	if p := protocol.Parse(l[protocolKey]); p != protocol.Unsupported {
+		return p
+	}
+	return protocol.TCP
+}
+
+// convertPort returns the service port of a catalog service instance.
+func convertPort(svc *catalogService, l labels.Instance) *model.Port {
+	p := convertProtocol(l)
+	return &model.Port{
+		Name:     fmt.Sprintf("%s-%d", strings.ToLower(string(p)), svc.Port),
+		Port:     svc.Port,
+		Protocol: p,
+	}
+}
+
+// isHealthy reports whether none of the checks which apply to the instance is critical.
+func isHealthy(entry *serviceEntry) bool {
+	for _, check := range entry.Checks {
+		if check.Status == healthCritical {
+			return false
+		}
+	}
+	return true
+}
+
+// convertService builds the Istio service from all the catalog instances of a service, and the Istio instances from
+// the healthy ones only, so that the service outlives health check failures. It returns nil if the service has no
+// catalog instance, since the ports of the service are derived from the instances.
+func convertService(name, clusterID string, entries []*serviceEntry) (*model.Service, []*model.ServiceInstance) {
+	hostname := serviceHostname(name)
+	svc := &model.Service{
+		Hostname:     hostname,
+		Address:      constants.UnspecifiedIP,
+		Resolution:   model.ClientSideLB,
+		MeshExternal: false,
+		Attributes: model.ServiceAttributes{
+			ServiceRegistry: string(serviceregistry.Consul),
+			Name:            string(hostname),
+			Namespace:       model.IstioDefaultConfigNamespace,
+		},
+	}
+
+	ports := map[int]*model.Port{}
+	instances := make([]*model.ServiceInstance, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Service == nil || entry.Node == nil {
+			continue
+		}
+		l := convertLabels(entry.Service)
+		port := convertPort(entry.Service, l)
+		if existing, f := ports[port.Port]; f {
+			// The first registered protocol wins if instances disagree on the protocol of a port.
+			port = existing
+		} else {
+			ports[port.Port] = port
+		}
+		if !isHealthy(entry) {
+			continue
+		}
+
+		addr := entry.Service.Address
+		if addr == "" {
+			addr = entry.Node.Address
+		}
+		sa := ""
+		if entry.Service.Meta[serviceAccountKey] != "" {
+			sa = spiffe.MustGenSpiffeURI(svc.Attributes.Namespace, entry.Service.Meta[serviceAccountKey])
+		}
+		instances = append(instances, &model.ServiceInstance{
+			Service:     svc,
+			ServicePort: port,
+			Endpoint: &model.IstioEndpoint{
+				Labels:          l,
+				Address:         addr,
+				ServicePortName: port.Name,
+				ServiceAccount:  sa,
+				Locality: model.Locality{
+					Label:     entry.Node.Datacenter,
+					ClusterID: clusterID,
+				},
+				EndpointPort: uint32(entry.Service.Port),
+				TLSMode:      model.GetTLSModeFromEndpointLabels(l),
+				Namespace:    svc.Attributes.Namespace,
+				WorkloadName: entry.Node.Node,
+			},
+		})
+	}
+	if len(ports) == 0 {
+		return nil, nil
+	}
+
+	for _, port := range ports {
+		svc.Ports = append(svc.Ports, port)
+	}
+	sort.Slice(svc.Ports, func(i, j int) bool {
+		return svc.Ports[i].Port < svc.Ports[j].Port
+	})
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Endpoint.Address != instances[j].Endpoint.Address {
+			return instances[i].Endpoint.Address < instances[j].Endpoint.Address
+		}
+		return instances[i].Endpoint.EndpointPort < instances[j].Endpoint.EndpointPort
+	})
+	return svc, instances
+}
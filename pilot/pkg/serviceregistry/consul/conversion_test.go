@@ -0,0 +1,126 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consul
+
+import (
+	"reflect"
+	"testing"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config/labels"
+	"istio.io/istio/pkg/config/protocol"
+)
+
+func TestConvertLabels(t *testing.T) {
+	got := convertLabels(&catalogService{
+		Tags: []string{"version=v1", "primary", "protocol=tcp", "=bad"},
+		Meta: map[string]string{"protocol": "http", "team": "a"},
+	})
+	want := labels.Instance{"version": "v1", "protocol": "http", "team": "a"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convertLabels() got %v, want %v", got, want)
+	}
+}
+
+func TestConvertProtocol(t *testing.T) {
+	cases := []struct {
+		labels labels.Instance
+		want   protocol.Instance
+	}{
+		{labels.Instance{}, protocol.TCP},
+		{labels.Instance{"protocol": "http"}, protocol.HTTP},
+		{labels.Instance{"protocol": "GRPC"}, protocol.GRPC},
+		{labels.Instance{"protocol": "bogus"}, protocol.TCP},
+	}
+	for _, c := range cases {
+		if got := convertProtocol(c.labels); got != c.want {
+			t.Errorf("convertProtocol(%v) got %v, want %v", c.labels, got, c.want)
+		}
+	}
+}
+
+func TestConvertService(t *testing.T) {
+	node := &catalogNode{Node: "vm-1", Address: "10.0.0.1", Datacenter: "dc1"}
+	entries := []*serviceEntry{
+		{
+			Node:    node,
+			Service: &catalogService{ID: "web-1", Service: "web", Port: 8080, Meta: map[string]string{"protocol": "http", "serviceAccount": "web"}},
+			Checks:  []healthCheck{{CheckID: "serfHealth", Status: "passing"}},
+		},
+		{
+			Node:    &catalogNode{Node: "vm-2", Address: "10.0.0.2", Datacenter: "dc1"},
+			Service: &catalogService{ID: "web-2", Service: "web", Address: "10.0.1.2", Port: 8080, Tags: []string{"version=v2"}},
+		},
+		{
+			Node:    &catalogNode{Node: "vm-3", Address: "10.0.0.3", Datacenter: "dc1"},
+			Service: &catalogService{ID: "web-3", Service: "web", Port: 8080},
+			Checks:  []healthCheck{{CheckID: "service:web-3", ServiceID: "web-3", Status: "critical"}},
+		},
+		{
+			Node:    node,
+			Service: &catalogService{ID: "web-admin", Service: "web", Port: 9090, Tags: []string{"protocol=grpc"}},
+			Checks:  []healthCheck{{CheckID: "service:web-admin", ServiceID: "web-admin", Status: "warning"}},
+		},
+	}
+
+	svc, instances := convertService("web", "consul", entries)
+	if svc == nil {
+		t.Fatal("convertService() got no service")
+	}
+	if svc.Hostname != "web.service.consul" || svc.Resolution != model.ClientSideLB {
+		t.Errorf("convertService() got service %v", svc)
+	}
+	wantPorts := model.PortList{
+		{Name: "http-8080", Port: 8080, Protocol: protocol.HTTP},
+		{Name: "grpc-9090", Port: 9090, Protocol: protocol.GRPC},
+	}
+	if !reflect.DeepEqual(svc.Ports, wantPorts) {
+		t.Errorf("convertService() got ports %v, want %v", svc.Ports, wantPorts)
+	}
+
+	type endpoint struct {
+		address  string
+		port     uint32
+		portName string
+	}
+	got := make([]endpoint, 0, len(instances))
+	for _, instance := range instances {
+		got = append(got, endpoint{instance.Endpoint.Address, instance.Endpoint.EndpointPort, instance.Endpoint.ServicePortName})
+	}
+	// The critical instance is excluded, the instance with a warning check is kept.
+	want := []endpoint{
+		{"10.0.0.1", 8080, "http-8080"},
+		{"10.0.0.1", 9090, "grpc-9090"},
+		{"10.0.1.2", 8080, "http-8080"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convertService() got endpoints %v, want %v", got, want)
+	}
+	if sa := instances[0].Endpoint.ServiceAccount; sa != "spiffe://cluster.local/ns/default/sa/web" {
+		t.Errorf("convertService() got service account %v", sa)
+	}
+	if l := instances[0].Endpoint.Locality; l.Label != "dc1" || l.ClusterID != "consul" {
+		t.Errorf("convertService() got locality %v", l)
+	}
+
+	// The service and its ports outlive the health check failures of its instances.
+	svc, instances = convertService("web", "consul", entries[2:3])
+	if svc == nil || len(svc.Ports) != 1 || len(instances) != 0 {
+		t.Errorf("convertService() without healthy instances got service %v and instances %v", svc, instances)
+	}
+	if svc, _ := convertService("web", "consul", nil); svc != nil {
+		t.Errorf("convertService() without instances got service %v", svc)
+	}
+}
@@ -0,0 +1,357 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peer
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/gogo/protobuf/proto"
+	"google.golang.org/grpc"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/serviceregistry"
+	"istio.io/istio/pkg/adsc"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/labels"
+	"istio.io/istio/pkg/config/schema/gvk"
+	istiolog "istio.io/pkg/log"
+)
+
+var log = istiolog.RegisterScope("peer", "peer istiod service registry controller", 0)
+
+const (
+	// defaultSyncTimeout is the maximum time a peer which is down or rejects the connection delays readiness.
+	defaultSyncTimeout = 30 * time.Second
+	// retryInterval is the delay before retrying to open the stream to the peer.
+	retryInterval = time.Second
+)
+
+// Options stores the configurable attributes of a Controller.
+type Options struct {
+	// Address of the xDS server of the peer istiod, e.g. istiod.istio-system.svc.cluster2:15012.
+	Address string
+	// ClusterID is the cluster of the peer istiod, used as the cluster of the imported services and endpoints.
+	ClusterID string
+	// LocalClusterID is the cluster of this istiod, sent to the peer in the node metadata.
+	LocalClusterID string
+	// Network is the network of the local cluster. The network gateways are not exported by the peer, so the
+	// endpoints of the peer on other networks are dropped. Empty if the mesh has a single unnamed network.
+	Network string
+	// Namespace of this istiod. The peer checks it against the namespace of the client certificate identity.
+	Namespace string
+	// DialOptions are used to connect to the peer, typically with the transport credentials of this istiod.
+	// The connection is insecure if empty, which the peer will reject.
+	DialOptions []grpc.DialOption
+	// SyncTimeout is the maximum time to wait for the initial services of the peer before reporting the
+	// registry as synced. Defaults to 30 seconds.
+	SyncTimeout time.Duration
+	// XDSUpdater is notified of service and endpoint changes.
+	XDSUpdater model.XDSUpdater
+}
+
+// Controller is a service registry holding the services and endpoints exported by a peer istiod.
+// The peer sends the services of its own cluster as ServiceEntry resources with inlined endpoints, see Generator.
+type Controller struct {
+	opts Options
+
+	mutex sync.RWMutex
+	// configs, services and instances received from the peer, keyed by hostname.
+	configs   map[host.Name]*config.Config
+	services  map[host.Name]*model.Service
+	instances map[host.Name][]*model.ServiceInstance
+	handlers  []func(*model.Service, model.Event)
+	synced    bool
+}
+
+var (
+	_ serviceregistry.Instance = &Controller{}
+	_ adsc.ResponseHandler     = &Controller{}
+)
+
+// NewController creates a new registry for the services of a peer istiod.
+func NewController(opts Options) *Controller {
+	if opts.SyncTimeout == 0 {
+		opts.SyncTimeout = defaultSyncTimeout
+	}
+	return &Controller{
+		opts:      opts,
+		configs:   map[host.Name]*config.Config{},
+		services:  map[host.Name]*model.Service{},
+		instances: map[host.Name][]*model.ServiceInstance{},
+	}
+}
+
+// Provider returns the ProviderID of the registry. The services of the peer are those of its Kubernetes
+// registry, so they are merged by hostname with the services of the other clusters.
+func (c *Controller) Provider() serviceregistry.ProviderID {
+	return serviceregistry.Kubernetes
+}
+
+// Cluster returns the cluster ID of the peer.
+func (c *Controller) Cluster() string {
+	return c.opts.ClusterID
+}
+
+// AppendServiceHandler registers a handler notified of service changes.
+func (c *Controller) AppendServiceHandler(f func(*model.Service, model.Event)) {
+	c.mutex.Lock()
+	c.handlers = append(c.handlers, f)
+	c.mutex.Unlock()
+}
+
+// AppendWorkloadHandler is a no-op, the peer only exports service instances.
+func (c *Controller) AppendWorkloadHandler(func(*model.WorkloadInstance, model.Event)) {}
+
+// HasSynced returns true once the initial services of the peer have been received, or once the sync timeout
+// expired so that an unreachable peer does not block readiness.
+func (c *Controller) HasSynced() bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.synced
+}
+
+func (c *Controller) markSynced() {
+	c.mutex.Lock()
+	c.synced = true
+	c.mutex.Unlock()
+}
+
+// Run watches the services exported by the peer until stop is closed. The connection is re-established with
+// backoff if the stream fails, the services are kept meanwhile.
+func (c *Controller) Run(stop <-chan struct{}) {
+	client, err := adsc.New(c.opts.Address, &adsc.Config{
+		Namespace: c.opts.Namespace,
+		Workload:  "istiod",
+		Meta: model.NodeMetadata{
+			Generator: GeneratorName,
+			ClusterID: c.opts.LocalClusterID,
+		}.ToStruct(),
+		InitialDiscoveryRequests: []*discovery.DiscoveryRequest{{TypeUrl: gvk.ServiceEntry.String()}},
+		ResponseHandler:          c,
+		GrpcOpts:                 c.opts.DialOptions,
+	})
+	if err != nil {
+		log.Errorf("failed to dial peer %s: %v", c.opts.Address, err)
+		c.markSynced()
+		return
+	}
+
+	syncTimer := time.AfterFunc(c.opts.SyncTimeout, func() {
+		if !c.HasSynced() {
+			log.Warnf("services of peer %s not received after %v, marking as synced", c.opts.Address, c.opts.SyncTimeout)
+			c.markSynced()
+		}
+	})
+	for {
+		err := client.Run()
+		if err == nil {
+			break
+		}
+		log.Warnf("failed to watch services of peer %s: %v", c.opts.Address, err)
+		select {
+		case <-stop:
+			syncTimer.Stop()
+			client.Close()
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+
+	<-stop
+	syncTimer.Stop()
+	client.Close()
+	log.Infof("stopped watching peer %s", c.opts.Address)
+}
+
+// HandleResponse applies the state of the world sent by the peer.
+func (c *Controller) HandleResponse(_ *adsc.ADSC, resp *discovery.DiscoveryResponse) {
+	if resp.TypeUrl != gvk.ServiceEntry.String() {
+		return
+	}
+	received := map[host.Name]*config.Config{}
+	for _, rsc := range resp.Resources {
+		cfg, err := decodeResource(rsc)
+		if err != nil {
+			log.Warnf("invalid service received from peer %s: %v", c.opts.Address, err)
+			continue
+		}
+		received[host.Name(cfg.Spec.(*networking.ServiceEntry).Hosts[0])] = cfg
+	}
+
+	c.mutex.RLock()
+	removed := make([]host.Name, 0)
+	for hostname := range c.configs {
+		if _, f := received[hostname]; !f {
+			removed = append(removed, hostname)
+		}
+	}
+	c.mutex.RUnlock()
+	for _, hostname := range removed {
+		c.updateService(hostname, nil)
+	}
+
+	hostnames := make([]string, 0, len(received))
+	for hostname := range received {
+		hostnames = append(hostnames, string(hostname))
+	}
+	sort.Strings(hostnames)
+	for _, hostname := range hostnames {
+		c.updateService(host.Name(hostname), received[host.Name(hostname)])
+	}
+
+	if !c.HasSynced() {
+		c.markSynced()
+		log.Infof("peer %s synced with %d services", c.opts.Address, len(received))
+	}
+}
+
+// updateService converts the ServiceEntry exported by the peer and notifies the handlers and the XDSUpdater of
+// changes. A nil config removes the service.
+func (c *Controller) updateService(hostname host.Name, cfg *config.Config) {
+	var svc *model.Service
+	var instances []*model.ServiceInstance
+	dropped := 0
+	if cfg != nil {
+		svc, instances, dropped = convertServiceEntry(cfg, c.opts.ClusterID, c.opts.Network)
+	}
+
+	c.mutex.Lock()
+	oldCfg := c.configs[hostname]
+	old := c.services[hostname]
+	if cfg != nil && oldCfg != nil && oldCfg.Namespace == cfg.Namespace &&
+		reflect.DeepEqual(oldCfg.Labels, cfg.Labels) &&
+		proto.Equal(oldCfg.Spec.(*networking.ServiceEntry), cfg.Spec.(*networking.ServiceEntry)) {
+		// The peer sends all its services on every push.
+		c.mutex.Unlock()
+		return
+	}
+	if cfg == nil {
+		delete(c.configs, hostname)
+		delete(c.services, hostname)
+		delete(c.instances, hostname)
+	} else {
+		c.configs[hostname] = cfg
+		c.services[hostname] = svc
+		c.instances[hostname] = instances
+	}
+	handlers := c.handlers
+	c.mutex.Unlock()
+
+	if dropped > 0 {
+		log.Warnf("dropped %d endpoints of service %s from peer %s, which are not on the local network %q",
+			dropped, hostname, c.opts.ClusterID, c.opts.Network)
+	}
+
+	var event model.Event
+	switch {
+	case old == nil && svc == nil:
+		return
+	case svc == nil:
+		event = model.EventDelete
+		svc = old
+	case old == nil:
+		event = model.EventAdd
+	case oldCfg.Namespace != cfg.Namespace || !reflect.DeepEqual(oldCfg.Labels, cfg.Labels) ||
+		!endpointsOnlyChanged(oldCfg.Spec.(*networking.ServiceEntry), cfg.Spec.(*networking.ServiceEntry)):
+		event = model.EventUpdate
+	default:
+		// Only the instances changed.
+		if c.opts.XDSUpdater != nil {
+			c.opts.XDSUpdater.EDSUpdate(c.opts.ClusterID, string(hostname), svc.Attributes.Namespace, endpoints(instances))
+		}
+		return
+	}
+
+	log.Debugf("peer %s service %s %v", c.opts.ClusterID, hostname, event)
+	if c.opts.XDSUpdater != nil {
+		if event != model.EventDelete {
+			// A full push is triggered by the service handlers, so only update the endpoint cache.
+			c.opts.XDSUpdater.EDSCacheUpdate(c.opts.ClusterID, string(hostname), svc.Attributes.Namespace, endpoints(instances))
+		}
+		c.opts.XDSUpdater.SvcUpdate(c.opts.ClusterID, string(hostname), svc.Attributes.Namespace, event)
+	}
+	for _, f := range handlers {
+		f(svc, event)
+	}
+}
+
+// Services returns the services exported by the peer.
+func (c *Controller) Services() ([]*model.Service, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	out := make([]*model.Service, 0, len(c.services))
+	for _, svc := range c.services {
+		out = append(out, svc)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Hostname < out[j].Hostname
+	})
+	return out, nil
+}
+
+// GetService retrieves a service by hostname if it exists.
+func (c *Controller) GetService(hostname host.Name) (*model.Service, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.services[hostname], nil
+}
+
+// InstancesByPort returns the instances of the service on the given port matching the labels.
+func (c *Controller) InstancesByPort(svc *model.Service, port int, labels labels.Collection) []*model.ServiceInstance {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	out := make([]*model.ServiceInstance, 0)
+	for _, instance := range c.instances[svc.Hostname] {
+		if instance.ServicePort.Port == port && labels.HasSubsetOf(instance.Endpoint.Labels) {
+			out = append(out, instance)
+		}
+	}
+	return out
+}
+
+// GetProxyServiceInstances returns nothing, the proxies of the peer cluster are not connected to this istiod.
+func (c *Controller) GetProxyServiceInstances(*model.Proxy) []*model.ServiceInstance {
+	return nil
+}
+
+// GetProxyWorkloadLabels returns nothing, the proxies of the peer cluster are not connected to this istiod.
+func (c *Controller) GetProxyWorkloadLabels(*model.Proxy) labels.Collection {
+	return nil
+}
+
+// GetIstioServiceAccounts returns the service accounts of the instances of the service.
+func (c *Controller) GetIstioServiceAccounts(svc *model.Service, ports []int) []string {
+	return model.GetServiceAccounts(svc, ports, c)
+}
+
+// NetworkGateways returns nothing, the gateways are not exported by the peer. Multi-network meshes are not
+// supported, the endpoints of the peer on other networks are dropped, see Options.Network.
+func (c *Controller) NetworkGateways() map[string][]*model.Gateway {
+	return nil
+}
+
+func endpoints(instances []*model.ServiceInstance) []*model.IstioEndpoint {
+	out := make([]*model.IstioEndpoint, 0, len(instances))
+	for _, instance := range instances {
+		out = append(out, instance.Endpoint)
+	}
+	return out
+}
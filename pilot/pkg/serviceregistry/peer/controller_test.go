@@ -0,0 +1,161 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peer
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	golangany "github.com/golang/protobuf/ptypes/any"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pkg/config/schema/gvk"
+)
+
+type fakeXdsUpdater struct {
+	mu     sync.Mutex
+	events []string
+}
+
+var _ model.XDSUpdater = &fakeXdsUpdater{}
+
+func (fx *fakeXdsUpdater) record(event string) {
+	fx.mu.Lock()
+	fx.events = append(fx.events, event)
+	fx.mu.Unlock()
+}
+
+func (fx *fakeXdsUpdater) EDSUpdate(cluster, hostname string, _ string, entry []*model.IstioEndpoint) {
+	fx.record(fmt.Sprintf("eds %s %s %d", cluster, hostname, len(entry)))
+}
+
+func (fx *fakeXdsUpdater) EDSCacheUpdate(cluster, hostname string, _ string, entry []*model.IstioEndpoint) {
+	fx.record(fmt.Sprintf("edscache %s %s %d", cluster, hostname, len(entry)))
+}
+
+func (fx *fakeXdsUpdater) SvcUpdate(cluster, hostname string, _ string, event model.Event) {
+	fx.record(fmt.Sprintf("svcupdate %s %s %v", cluster, hostname, event))
+}
+
+func (fx *fakeXdsUpdater) ConfigUpdate(*model.PushRequest) {}
+
+func (fx *fakeXdsUpdater) ProxyUpdate(_, _ string) {}
+
+// take returns the recorded events and clears them.
+func (fx *fakeXdsUpdater) take() []string {
+	fx.mu.Lock()
+	defer fx.mu.Unlock()
+	out := fx.events
+	fx.events = nil
+	return out
+}
+
+func response(resources ...*golangany.Any) *discovery.DiscoveryResponse {
+	return &discovery.DiscoveryResponse{TypeUrl: gvk.ServiceEntry.String(), Resources: resources}
+}
+
+func TestController(t *testing.T) {
+	xdsUpdater := &fakeXdsUpdater{}
+	c := NewController(Options{
+		Address:    "istiod.cluster2:15012",
+		ClusterID:  "cluster2",
+		Network:    "network1",
+		XDSUpdater: xdsUpdater,
+	})
+	handlerEvents := []string{}
+	c.AppendServiceHandler(func(svc *model.Service, event model.Event) {
+		handlerEvents = append(handlerEvents, fmt.Sprintf("%s %v", svc.Hostname, event))
+	})
+	expect := func(events ...string) {
+		t.Helper()
+		if got := xdsUpdater.take(); !reflect.DeepEqual(got, events) {
+			t.Fatalf("got events %v, want %v", got, events)
+		}
+	}
+
+	if c.HasSynced() {
+		t.Fatal("registry synced before the first response")
+	}
+	web := makeService("web", "10.0.0.1", 80)
+	c.HandleResponse(nil, response(encode(t, web, makeInstances(web, "10.1.0.1"))))
+	if !c.HasSynced() {
+		t.Fatal("registry not synced after the first response")
+	}
+	expect("edscache cluster2 web.default.svc.cluster.local 1", "svcupdate cluster2 web.default.svc.cluster.local add")
+
+	svc, _ := c.GetService(web.Hostname)
+	if svc == nil || svc.ClusterVIPs["cluster2"] != "10.0.0.1" {
+		t.Fatalf("unexpected service %v", svc)
+	}
+	if instances := c.InstancesByPort(svc, 80, nil); len(instances) != 1 || instances[0].Endpoint.Locality.ClusterID != "cluster2" {
+		t.Fatalf("unexpected instances %v", instances)
+	}
+
+	// The peer sends all its services on every push, unchanged services are ignored.
+	c.HandleResponse(nil, response(encode(t, web, makeInstances(web, "10.1.0.1"))))
+	expect()
+
+	// Only the endpoints changed.
+	c.HandleResponse(nil, response(encode(t, web, makeInstances(web, "10.1.0.1", "10.1.0.2"))))
+	expect("eds cluster2 web.default.svc.cluster.local 2")
+
+	// The ports changed.
+	web = makeService("web", "10.0.0.1", 80, 81)
+	c.HandleResponse(nil, response(encode(t, web, makeInstances(web, "10.1.0.1", "10.1.0.2"))))
+	expect("edscache cluster2 web.default.svc.cluster.local 4", "svcupdate cluster2 web.default.svc.cluster.local update")
+
+	// A new service is added and the existing one removed.
+	api := makeService("api", "10.0.0.2", 80)
+	c.HandleResponse(nil, response(encode(t, api, nil)))
+	expect("svcupdate cluster2 web.default.svc.cluster.local delete",
+		"edscache cluster2 api.default.svc.cluster.local 0", "svcupdate cluster2 api.default.svc.cluster.local add")
+	if services, _ := c.Services(); len(services) != 1 || services[0].Hostname != api.Hostname {
+		t.Fatalf("unexpected services %v", services)
+	}
+
+	want := []string{
+		"web.default.svc.cluster.local add",
+		"web.default.svc.cluster.local update",
+		"web.default.svc.cluster.local delete",
+		"api.default.svc.cluster.local add",
+	}
+	if !reflect.DeepEqual(handlerEvents, want) {
+		t.Fatalf("got handler events %v, want %v", handlerEvents, want)
+	}
+}
+
+func TestControllerSyncTimeout(t *testing.T) {
+	// Nothing listens on the address, the registry must not block readiness.
+	c := NewController(Options{
+		Address:     "127.0.0.1:1",
+		ClusterID:   "cluster2",
+		SyncTimeout: 10 * time.Millisecond,
+	})
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.Run(stop)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !c.HasSynced() {
+		if time.Now().After(deadline) {
+			t.Fatal("registry not synced after the sync timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	golangany "github.com/golang/protobuf/ptypes/any"
+
+	"istio.io/api/label"
+	mcp "istio.io/api/mcp/v1alpha1"
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/serviceregistry"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/labels"
+	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/config/visibility"
+	"istio.io/istio/pkg/spiffe"
+)
+
+// exportService converts a service and its instances to a ServiceEntry, with one inlined endpoint per address.
+// The tlsMode of the endpoints is carried as a label, the service account as the name within the service namespace.
+func exportService(svc *model.Service, instances []*model.ServiceInstance) *config.Config {
+	se := &networking.ServiceEntry{
+		Hosts:           []string{string(svc.Hostname)},
+		Location:        networking.ServiceEntry_MESH_INTERNAL,
+		SubjectAltNames: svc.ServiceAccounts,
+	}
+	if svc.Address != "" {
+		se.Addresses = []string{svc.Address}
+	}
+	switch svc.Resolution {
+	case model.Passthrough:
+		se.Resolution = networking.ServiceEntry_NONE
+	case model.DNSLB:
+		se.Resolution = networking.ServiceEntry_DNS
+	default:
+		se.Resolution = networking.ServiceEntry_STATIC
+	}
+	for _, p := range svc.Ports {
+		se.Ports = append(se.Ports, &networking.Port{
+			Number:   uint32(p.Port),
+			Name:     p.Name,
+			Protocol: string(p.Protocol),
+		})
+	}
+	for k, v := range svc.Attributes.ExportTo {
+		if v {
+			se.ExportTo = append(se.ExportTo, string(k))
+		}
+	}
+	sort.Strings(se.ExportTo)
+
+	byAddress := map[string]*networking.WorkloadEntry{}
+	for _, instance := range instances {
+		ep := instance.Endpoint
+		wle, f := byAddress[ep.Address]
+		if !f {
+			wle = &networking.WorkloadEntry{
+				Address:  ep.Address,
+				Ports:    map[string]uint32{},
+				Labels:   map[string]string{},
+				Network:  ep.Network,
+				Locality: ep.Locality.Label,
+				Weight:   ep.LbWeight,
+			}
+			for k, v := range ep.Labels {
+				wle.Labels[k] = v
+			}
+			if ep.TLSMode != "" {
+				wle.Labels[label.SecurityTlsMode.Name] = ep.TLSMode
+			}
+			if id, err := spiffe.ParseIdentity(ep.ServiceAccount); err == nil {
+				wle.ServiceAccount = id.ServiceAccount
+			}
+			byAddress[ep.Address] = wle
+			se.Endpoints = append(se.Endpoints, wle)
+		}
+		wle.Ports[instance.ServicePort.Name] = ep.EndpointPort
+	}
+	sort.Slice(se.Endpoints, func(i, j int) bool {
+		return se.Endpoints[i].Address < se.Endpoints[j].Address
+	})
+
+	return &config.Config{
+		Meta: config.Meta{
+			GroupVersionKind:  gvk.ServiceEntry,
+			Name:              svc.Attributes.Name,
+			Namespace:         svc.Attributes.Namespace,
+			Labels:            svc.Attributes.Labels,
+			CreationTimestamp: svc.CreationTime,
+		},
+		Spec: se,
+	}
+}
+
+// decodeResource decodes a ServiceEntry sent by a peer as an MCP resource.
+// nolint: staticcheck
+func decodeResource(rsc *golangany.Any) (*config.Config, error) {
+	m := &mcp.Resource{}
+	if err := types.UnmarshalAny(&types.Any{TypeUrl: rsc.TypeUrl, Value: rsc.Value}, m); err != nil {
+		return nil, err
+	}
+	if m.Metadata == nil || m.Body == nil {
+		return nil, fmt.Errorf("resource has no metadata or body")
+	}
+	nsn := strings.SplitN(m.Metadata.Name, "/", 2)
+	if len(nsn) != 2 {
+		return nil, fmt.Errorf("invalid name %s", m.Metadata.Name)
+	}
+	se := &networking.ServiceEntry{}
+	if err := types.UnmarshalAny(m.Body, se); err != nil {
+		return nil, err
+	}
+	if len(se.Hosts) != 1 {
+		return nil, fmt.Errorf("service entry %s has %d hosts, expected 1", m.Metadata.Name, len(se.Hosts))
+	}
+	cfg := &config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: gvk.ServiceEntry,
+			Namespace:        nsn[0],
+			Name:             nsn[1],
+			Labels:           m.Metadata.Labels,
+			ResourceVersion:  m.Metadata.Version,
+		},
+		Spec: se,
+	}
+	if m.Metadata.CreateTime != nil {
+		cfg.CreationTimestamp, _ = types.TimestampFromProto(m.Metadata.CreateTime)
+	}
+	return cfg, nil
+}
+
+// convertServiceEntry converts a ServiceEntry exported by the peer cluster back to a service and its instances.
+// The service is owned by the Kubernetes registry of the peer cluster, so it is merged with the services of the
+// same hostname in the other clusters. Endpoints which are not on the given network are dropped, and their number
+// returned, since the gateways of the networks of the peer are not exported and they would be unreachable.
+func convertServiceEntry(cfg *config.Config, clusterID, network string) (*model.Service, []*model.ServiceInstance, int) {
+	se := cfg.Spec.(*networking.ServiceEntry)
+
+	var resolution model.Resolution
+	switch se.Resolution {
+	case networking.ServiceEntry_NONE:
+		resolution = model.Passthrough
+	case networking.ServiceEntry_DNS:
+		resolution = model.DNSLB
+	default:
+		resolution = model.ClientSideLB
+	}
+	ports := make(model.PortList, 0, len(se.Ports))
+	for _, p := range se.Ports {
+		ports = append(ports, &model.Port{
+			Name:     p.Name,
+			Port:     int(p.Number),
+			Protocol: protocol.Parse(p.Protocol),
+		})
+	}
+	var exportTo map[visibility.Instance]bool
+	if len(se.ExportTo) > 0 {
+		exportTo = make(map[visibility.Instance]bool, len(se.ExportTo))
+		for _, e := range se.ExportTo {
+			exportTo[visibility.Instance(e)] = true
+		}
+	}
+	address := ""
+	if len(se.Addresses) > 0 {
+		address = se.Addresses[0]
+	}
+	svc := &model.Service{
+		Hostname:        host.Name(se.Hosts[0]),
+		Address:         address,
+		ClusterVIPs:     map[string]string{clusterID: address},
+		Ports:           ports,
+		Resolution:      resolution,
+		ServiceAccounts: se.SubjectAltNames,
+		CreationTime:    cfg.CreationTimestamp,
+		Attributes: model.ServiceAttributes{
+			ServiceRegistry: string(serviceregistry.Kubernetes),
+			Name:            cfg.Name,
+			Namespace:       cfg.Namespace,
+			Labels:          cfg.Labels,
+			UID:             fmt.Sprintf("istio://%s/services/%s", cfg.Namespace, cfg.Name),
+			ExportTo:        exportTo,
+		},
+	}
+
+	instances := make([]*model.ServiceInstance, 0, len(se.Endpoints)*len(ports))
+	dropped := 0
+	for _, wle := range se.Endpoints {
+		if wle.Network != network {
+			dropped++
+			continue
+		}
+		tlsMode := model.DisabledTLSModeLabel
+		if m, f := wle.Labels[label.SecurityTlsMode.Name]; f {
+			tlsMode = m
+		}
+		sa := ""
+		if wle.ServiceAccount != "" {
+			sa = spiffe.MustGenSpiffeURI(cfg.Namespace, wle.ServiceAccount)
+		}
+		for _, port := range ports {
+			endpointPort, f := wle.Ports[port.Name]
+			if !f {
+				continue
+			}
+			instances = append(instances, &model.ServiceInstance{
+				Service:     svc,
+				ServicePort: port,
+				Endpoint: &model.IstioEndpoint{
+					Address:         wle.Address,
+					EndpointPort:    endpointPort,
+					ServicePortName: port.Name,
+					Labels:          labels.Instance(wle.Labels),
+					ServiceAccount:  sa,
+					Network:         wle.Network,
+					Locality: model.Locality{
+						Label:     wle.Locality,
+						ClusterID: clusterID,
+					},
+					LbWeight:  wle.Weight,
+					TLSMode:   tlsMode,
+					Namespace: cfg.Namespace,
+				},
+			})
+		}
+	}
+	return svc, instances, dropped
+}
+
+// endpointsOnlyChanged reports whether two versions of an exported ServiceEntry only differ by their endpoints.
+func endpointsOnlyChanged(old, cur *networking.ServiceEntry) bool {
+	o, c := *old, *cur
+	o.Endpoints, c.Endpoints = nil, nil
+	return proto.Equal(&o, &c)
+}
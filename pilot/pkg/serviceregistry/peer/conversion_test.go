@@ -0,0 +1,188 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	gogotypes "github.com/gogo/protobuf/types"
+	golangany "github.com/golang/protobuf/ptypes/any"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/apigen"
+	"istio.io/istio/pilot/pkg/serviceregistry"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/config/visibility"
+	"istio.io/istio/pkg/spiffe"
+)
+
+func makeService(name string, address string, ports ...int) *model.Service {
+	svc := &model.Service{
+		Hostname:        host.Name(name + ".default.svc.cluster.local"),
+		Address:         address,
+		ClusterVIPs:     map[string]string{"cluster1": address},
+		ServiceAccounts: []string{"spiffe://cluster.local/ns/default/sa/" + name},
+		CreationTime:    time.Unix(1600000000, 0).UTC(),
+		Attributes: model.ServiceAttributes{
+			ServiceRegistry: string(serviceregistry.Kubernetes),
+			Name:            name,
+			Namespace:       "default",
+			Labels:          map[string]string{"app": name},
+			ExportTo:        map[visibility.Instance]bool{visibility.Public: true},
+		},
+	}
+	for _, p := range ports {
+		svc.Ports = append(svc.Ports, &model.Port{Name: "http-" + string(rune('a'+len(svc.Ports))), Port: p, Protocol: protocol.HTTP})
+	}
+	return svc
+}
+
+func makeInstances(svc *model.Service, addresses ...string) []*model.ServiceInstance {
+	out := make([]*model.ServiceInstance, 0)
+	for _, address := range addresses {
+		for _, port := range svc.Ports {
+			out = append(out, &model.ServiceInstance{
+				Service:     svc,
+				ServicePort: port,
+				Endpoint: &model.IstioEndpoint{
+					Address:         address,
+					EndpointPort:    uint32(port.Port + 8000),
+					ServicePortName: port.Name,
+					Labels:          map[string]string{"app": svc.Attributes.Name, "security.istio.io/tlsMode": "istio"},
+					ServiceAccount:  spiffe.MustGenSpiffeURI("default", svc.Attributes.Name),
+					Network:         "network1",
+					Locality:        model.Locality{Label: "region/zone", ClusterID: "cluster1"},
+					LbWeight:        1,
+					TLSMode:         model.IstioMutualTLSModeLabel,
+					Namespace:       "default",
+					WorkloadName:    svc.Attributes.Name,
+				},
+			})
+		}
+	}
+	return out
+}
+
+// encode returns the services as sent by the Generator.
+func encode(t *testing.T, svc *model.Service, instances []*model.ServiceInstance) *golangany.Any {
+	t.Helper()
+	r, err := apigen.ConfigToResource(exportService(svc, instances))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := gogotypes.MarshalAny(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &golangany.Any{TypeUrl: a.TypeUrl, Value: a.Value}
+}
+
+func TestExportService(t *testing.T) {
+	svc := makeService("web", "10.0.0.1", 80, 81)
+	svc.Resolution = model.Passthrough
+	cfg := exportService(svc, makeInstances(svc, "10.1.0.2", "10.1.0.1"))
+
+	if cfg.Name != "web" || cfg.Namespace != "default" {
+		t.Fatalf("unexpected name %s/%s", cfg.Namespace, cfg.Name)
+	}
+	se := cfg.Spec.(*networking.ServiceEntry)
+	if !reflect.DeepEqual(se.Hosts, []string{"web.default.svc.cluster.local"}) || !reflect.DeepEqual(se.Addresses, []string{"10.0.0.1"}) {
+		t.Fatalf("unexpected hosts %v and addresses %v", se.Hosts, se.Addresses)
+	}
+	if se.Resolution != networking.ServiceEntry_NONE || se.Location != networking.ServiceEntry_MESH_INTERNAL {
+		t.Fatalf("unexpected resolution %v and location %v", se.Resolution, se.Location)
+	}
+	if len(se.Endpoints) != 2 || se.Endpoints[0].Address != "10.1.0.1" {
+		t.Fatalf("expected 2 endpoints sorted by address, got %v", se.Endpoints)
+	}
+	want := &networking.WorkloadEntry{
+		Address:        "10.1.0.1",
+		Ports:          map[string]uint32{"http-a": 8080, "http-b": 8081},
+		Labels:         map[string]string{"app": "web", "security.istio.io/tlsMode": "istio"},
+		Network:        "network1",
+		Locality:       "region/zone",
+		Weight:         1,
+		ServiceAccount: "web",
+	}
+	if !reflect.DeepEqual(se.Endpoints[0], want) {
+		t.Fatalf("got endpoint %v, want %v", se.Endpoints[0], want)
+	}
+}
+
+func TestConvertServiceEntry(t *testing.T) {
+	svc := makeService("web", "10.0.0.1", 80, 81)
+	cfg, err := decodeResource(encode(t, svc, makeInstances(svc, "10.1.0.1", "10.1.0.2")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, instances, dropped := convertServiceEntry(cfg, "cluster2", "network1")
+
+	if got.Hostname != svc.Hostname || got.Address != svc.Address || !got.CreationTime.Equal(svc.CreationTime) {
+		t.Fatalf("unexpected service %v", got)
+	}
+	if !reflect.DeepEqual(got.ClusterVIPs, map[string]string{"cluster2": "10.0.0.1"}) {
+		t.Fatalf("unexpected cluster VIPs %v", got.ClusterVIPs)
+	}
+	if !reflect.DeepEqual(got.Ports, svc.Ports) || !reflect.DeepEqual(got.ServiceAccounts, svc.ServiceAccounts) {
+		t.Fatalf("unexpected ports %v or service accounts %v", got.Ports, got.ServiceAccounts)
+	}
+	if !reflect.DeepEqual(got.Attributes.ExportTo, svc.Attributes.ExportTo) || !reflect.DeepEqual(got.Attributes.Labels, svc.Attributes.Labels) {
+		t.Fatalf("unexpected attributes %v", got.Attributes)
+	}
+	if got.Attributes.ServiceRegistry != string(serviceregistry.Kubernetes) || got.Attributes.Namespace != "default" {
+		t.Fatalf("unexpected attributes %v", got.Attributes)
+	}
+
+	if len(instances) != 4 || dropped != 0 {
+		t.Fatalf("expected 4 instances and no dropped endpoint, got %d and %d", len(instances), dropped)
+	}
+	ep := instances[0].Endpoint
+	if ep.Address != "10.1.0.1" || ep.EndpointPort != 8080 || ep.ServicePortName != "http-a" || instances[0].ServicePort.Port != 80 {
+		t.Fatalf("unexpected endpoint %v", ep)
+	}
+	if ep.Locality.ClusterID != "cluster2" || ep.Locality.Label != "region/zone" || ep.Network != "network1" {
+		t.Fatalf("unexpected endpoint locality %v or network %s", ep.Locality, ep.Network)
+	}
+	if ep.TLSMode != model.IstioMutualTLSModeLabel || ep.ServiceAccount != spiffe.MustGenSpiffeURI("default", "web") {
+		t.Fatalf("unexpected endpoint tls mode %s or service account %s", ep.TLSMode, ep.ServiceAccount)
+	}
+
+	// The gateways of network1 are not known, the endpoints are not reachable from network2.
+	got, instances, dropped = convertServiceEntry(cfg, "cluster2", "network2")
+	if got == nil || len(instances) != 0 || dropped != 2 {
+		t.Fatalf("expected the service without instances and 2 dropped endpoints, got %v, %d and %d", got, len(instances), dropped)
+	}
+}
+
+func TestDecodeResourceInvalid(t *testing.T) {
+	svc := makeService("web", "10.0.0.1", 80)
+	cfg := exportService(svc, nil)
+	cfg.Spec.(*networking.ServiceEntry).Hosts = nil
+	r, err := apigen.ConfigToResource(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := gogotypes.MarshalAny(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decodeResource(&golangany.Any{TypeUrl: a.TypeUrl, Value: a.Value}); err == nil {
+		t.Fatal("expected an error for a service entry without hosts")
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peer
+
+import (
+	"fmt"
+
+	gogotypes "github.com/gogo/protobuf/types"
+	golangany "github.com/golang/protobuf/ptypes/any"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/apigen"
+	"istio.io/istio/pilot/pkg/serviceregistry"
+	"istio.io/istio/pilot/pkg/serviceregistry/aggregate"
+	"istio.io/istio/pkg/config/schema/gvk"
+)
+
+// GeneratorName is the generator requested in the node metadata of peer istiods.
+const GeneratorName = "peer"
+
+// Generator exports the services and endpoints of the local cluster to peer istiods, as ServiceEntry
+// resources with inlined endpoints. Only the Kubernetes registry of the local cluster is exported, so services
+// discovered from remote clusters or imported from other peers are not sent back.
+//
+// Peers must authenticate with a client certificate whose identity is allowed.
+type Generator struct {
+	controller *aggregate.Controller
+	clusterID  string
+	identities map[string]struct{}
+}
+
+var _ model.XdsResourceGenerator = &Generator{}
+
+// NewGenerator creates a generator exporting the registry of clusterID to the peers with the given
+// SPIFFE identities. All peers are rejected if identities is empty.
+func NewGenerator(controller *aggregate.Controller, clusterID string, identities []string) *Generator {
+	g := &Generator{
+		controller: controller,
+		clusterID:  clusterID,
+		identities: make(map[string]struct{}, len(identities)),
+	}
+	for _, id := range identities {
+		g.identities[id] = struct{}{}
+	}
+	return g
+}
+
+// Generate returns all the services of the local cluster, since a peer replaces its state with every response.
+// Pushes which do not update any service or endpoint are skipped.
+func (g *Generator) Generate(proxy *model.Proxy, _ *model.PushContext, w *model.WatchedResource,
+	req *model.PushRequest) (model.Resources, error) {
+	if w.TypeUrl != gvk.ServiceEntry.String() {
+		return nil, nil
+	}
+	if err := g.authorize(proxy); err != nil {
+		return nil, err
+	}
+	if !servicesUpdated(req) {
+		return nil, nil
+	}
+
+	resp := model.Resources{}
+	registry := g.localRegistry()
+	if registry == nil {
+		return resp, nil
+	}
+	services, err := registry.Services()
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range services {
+		instances := make([]*model.ServiceInstance, 0)
+		for _, port := range svc.Ports {
+			instances = append(instances, registry.InstancesByPort(svc, port.Port, nil)...)
+		}
+		c := exportService(svc, instances)
+		b, err := apigen.ConfigToResource(c)
+		if err != nil {
+			log.Warnf("failed to export service %s: %v", svc.Hostname, err)
+			continue
+		}
+		bany, err := gogotypes.MarshalAny(b)
+		if err != nil {
+			log.Warnf("failed to export service %s: %v", svc.Hostname, err)
+			continue
+		}
+		resp = append(resp, &golangany.Any{
+			TypeUrl: bany.TypeUrl,
+			Value:   bany.Value,
+		})
+	}
+	return resp, nil
+}
+
+// servicesUpdated returns true if the push may change the exported services or endpoints. Service and endpoint
+// changes of all the registries are recorded as ServiceEntry updates, and requests with no updated config, like
+// the initial request of a peer, are full pushes.
+func servicesUpdated(req *model.PushRequest) bool {
+	if req == nil || len(req.ConfigsUpdated) == 0 {
+		return true
+	}
+	for key := range req.ConfigsUpdated {
+		if key.Kind == gvk.ServiceEntry {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize checks the identity verified from the client certificate of the peer.
+func (g *Generator) authorize(proxy *model.Proxy) error {
+	if proxy.VerifiedIdentity == nil {
+		return fmt.Errorf("peer %s has no verified identity", proxy.ID)
+	}
+	if _, f := g.identities[proxy.VerifiedIdentity.String()]; !f {
+		return fmt.Errorf("peer %s with identity %s is not allowed to discover services", proxy.ID, proxy.VerifiedIdentity)
+	}
+	return nil
+}
+
+// localRegistry returns the Kubernetes registry of the local cluster, if any.
+func (g *Generator) localRegistry() serviceregistry.Instance {
+	for _, r := range g.controller.GetRegistries() {
+		if r.Provider() == serviceregistry.Kubernetes && r.Cluster() == g.clusterID {
+			if _, isPeer := r.(*Controller); !isPeer {
+				return r
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peer
+
+import (
+	"reflect"
+	"testing"
+
+	networking "istio.io/api/networking/v1alpha3"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/serviceregistry"
+	"istio.io/istio/pilot/pkg/serviceregistry/aggregate"
+	"istio.io/istio/pilot/pkg/serviceregistry/mock"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/schema/gvk"
+	"istio.io/istio/pkg/spiffe"
+)
+
+func TestGenerator(t *testing.T) {
+	local := mock.MakeService("web.default.svc.cluster.local", "10.0.0.1", nil, "cluster1")
+	remote := mock.MakeService("remote.default.svc.cluster.local", "10.0.1.1", nil, "cluster3")
+	controller := aggregate.NewController(aggregate.Options{})
+	controller.AddRegistry(serviceregistry.Simple{
+		ProviderID:       serviceregistry.Kubernetes,
+		ClusterID:        "cluster1",
+		ServiceDiscovery: mock.NewDiscovery(map[host.Name]*model.Service{local.Hostname: local}, 2),
+		Controller:       &mock.Controller{},
+	})
+	controller.AddRegistry(serviceregistry.Simple{
+		ProviderID:       serviceregistry.Kubernetes,
+		ClusterID:        "cluster3",
+		ServiceDiscovery: mock.NewDiscovery(map[host.Name]*model.Service{remote.Hostname: remote}, 2),
+		Controller:       &mock.Controller{},
+	})
+	g := NewGenerator(controller, "cluster1", []string{"spiffe://cluster.local/ns/istio-system/sa/istiod"})
+	watched := &model.WatchedResource{TypeUrl: gvk.ServiceEntry.String()}
+
+	cases := []struct {
+		name     string
+		identity *spiffe.Identity
+		allowed  bool
+	}{
+		{"unauthenticated", nil, false},
+		{"not allowed", &spiffe.Identity{TrustDomain: "cluster.local", Namespace: "default", ServiceAccount: "istiod"}, false},
+		{"allowed", &spiffe.Identity{TrustDomain: "cluster.local", Namespace: "istio-system", ServiceAccount: "istiod"}, true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := &model.Proxy{ID: "istiod.istio-system", VerifiedIdentity: tt.identity}
+			resources, err := g.Generate(proxy, nil, watched, nil)
+			if !tt.allowed {
+				if err == nil {
+					t.Fatal("expected the peer to be rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Only the services of the local cluster are exported.
+			if len(resources) != 1 {
+				t.Fatalf("expected 1 service, got %d", len(resources))
+			}
+			cfg, err := decodeResource(resources[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			se := cfg.Spec.(*networking.ServiceEntry)
+			if !reflect.DeepEqual(se.Hosts, []string{string(local.Hostname)}) {
+				t.Fatalf("unexpected hosts %v", se.Hosts)
+			}
+			if len(se.Endpoints) != 2 || len(se.Endpoints[0].Ports) != len(local.Ports) {
+				t.Fatalf("unexpected endpoints %v", se.Endpoints)
+			}
+		})
+	}
+
+	t.Run("updates", func(t *testing.T) {
+		proxy := &model.Proxy{
+			ID:               "istiod.istio-system",
+			VerifiedIdentity: &spiffe.Identity{TrustDomain: "cluster.local", Namespace: "istio-system", ServiceAccount: "istiod"},
+		}
+		pushes := []struct {
+			name    string
+			req     *model.PushRequest
+			skipped bool
+		}{
+			{"full push", &model.PushRequest{Full: true}, false},
+			{"virtual service", &model.PushRequest{
+				Full:           true,
+				ConfigsUpdated: map[model.ConfigKey]struct{}{{Kind: gvk.VirtualService, Name: "web", Namespace: "default"}: {}},
+			}, true},
+			{"endpoints", &model.PushRequest{
+				ConfigsUpdated: map[model.ConfigKey]struct{}{{Kind: gvk.ServiceEntry, Name: string(local.Hostname), Namespace: "default"}: {}},
+			}, false},
+		}
+		for _, p := range pushes {
+			resources, err := g.Generate(proxy, nil, watched, p.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.skipped != (resources == nil) {
+				t.Fatalf("%s: expected skipped %v, got resources %v", p.name, p.skipped, resources)
+			}
+		}
+	})
+
+	t.Run("other types", func(t *testing.T) {
+		proxy := &model.Proxy{ID: "istiod.istio-system"}
+		resources, err := g.Generate(proxy, nil, &model.WatchedResource{TypeUrl: v3.ClusterType}, nil)
+		if err != nil || resources != nil {
+			t.Fatalf("expected no resources, got %v %v", resources, err)
+		}
+	})
+}
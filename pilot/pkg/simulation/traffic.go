@@ -19,27 +19,26 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
-	"testing"
 
 	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	tcpproxy "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/tcp_proxy/v3"
 	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
-	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
+	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/yl2chen/cidranger"
 
-	"istio.io/istio/pilot/pkg/model"
 	"istio.io/istio/pilot/pkg/networking/core/v1alpha3"
 	"istio.io/istio/pilot/pkg/util/sets"
-	"istio.io/istio/pilot/pkg/xds"
 	xdsfilters "istio.io/istio/pilot/pkg/xds/filters"
-	"istio.io/istio/pilot/test/xdstest"
 	"istio.io/istio/pkg/config/host"
-	"istio.io/istio/pkg/test"
 )
 
 type Protocol string
@@ -56,6 +55,9 @@ const (
 	Plaintext TLSMode = "plaintext"
 	TLS       TLSMode = "tls"
 	MTLS      TLSMode = "mtls"
+	// AutoMTLS is only reported for upstream connections, which use mTLS to the endpoints able to
+	// receive it and plaintext otherwise.
+	AutoMTLS TLSMode = "auto-mtls"
 )
 
 func (c Call) IsHTTP() bool {
@@ -77,12 +79,6 @@ var (
 	ErrMTLSError     = errors.New("invalid mTLS")
 )
 
-type Expect struct {
-	Name   string
-	Call   Call
-	Result Result
-}
-
 type CallMode string
 
 var (
@@ -162,94 +158,63 @@ type Result struct {
 	// if we pass the test. This is to ensure that if the behavior changes, we still capture it; the skip
 	// just ensures we notice a test is wrong
 	Skip string
-	t    test.Failer
+
+	// filterChain is the filter chain matched, used to report the downstream TLS mode.
+	filterChain *listener.FilterChain
 }
 
-func (r Result) Matches(t *testing.T, want Result) {
-	r.StrictMatch = want.StrictMatch // to make diff pass
-	r.Skip = want.Skip               // to make diff pass
-	diff := cmp.Diff(want, r, cmpopts.IgnoreUnexported(Result{}), cmpopts.EquateErrors())
-	if want.StrictMatch && diff != "" {
-		t.Errorf("Diff: %v", diff)
-		return
-	}
-	if want.Error != r.Error {
-		t.Errorf("want error %v got %v", want.Error, r.Error)
-	}
-	if want.ListenerMatched != "" && want.ListenerMatched != r.ListenerMatched {
-		t.Errorf("want listener matched %q got %q", want.ListenerMatched, r.ListenerMatched)
-	}
-	if want.FilterChainMatched != "" && want.FilterChainMatched != r.FilterChainMatched {
-		t.Errorf("want filter chain matched %q got %q", want.FilterChainMatched, r.FilterChainMatched)
-	}
-	if want.RouteMatched != "" && want.RouteMatched != r.RouteMatched {
-		t.Errorf("want route matched %q got %q", want.RouteMatched, r.RouteMatched)
+// DownstreamTLSMode returns the TLS mode terminated by the filter chain matched.
+func (r Result) DownstreamTLSMode() TLSMode {
+	if r.filterChain == nil || r.filterChain.TransportSocket == nil {
+		return Plaintext
 	}
-	if want.RouteConfigMatched != "" && want.RouteConfigMatched != r.RouteConfigMatched {
-		t.Errorf("want route config matched %q got %q", want.RouteConfigMatched, r.RouteConfigMatched)
-	}
-	if want.VirtualHostMatched != "" && want.VirtualHostMatched != r.VirtualHostMatched {
-		t.Errorf("want virtual host matched %q got %q", want.VirtualHostMatched, r.VirtualHostMatched)
-	}
-	if want.ClusterMatched != "" && want.ClusterMatched != r.ClusterMatched {
-		t.Errorf("want cluster matched %q got %q", want.ClusterMatched, r.ClusterMatched)
-	}
-	if t.Failed() {
-		t.Logf("Diff: %+v", diff)
-	} else if want.Skip != "" {
-		t.Skip(fmt.Sprintf("Known bug: %v", r.Skip))
+	if mtls, err := requiresMTLS(r.filterChain); err == nil && mtls {
+		return MTLS
 	}
+	return TLS
 }
 
 type Simulation struct {
-	t         *testing.T
 	Listeners []*listener.Listener
 	Clusters  []*cluster.Cluster
 	Routes    []*route.RouteConfiguration
 }
 
-func NewSimulationFromConfigGen(t *testing.T, s *v1alpha3.ConfigGenTest, proxy *model.Proxy) *Simulation {
-	sim := &Simulation{
-		t:         t,
-		Listeners: s.Listeners(proxy),
-		Clusters:  s.Clusters(proxy),
-		Routes:    s.Routes(proxy),
-	}
-	return sim
-}
-
-func NewSimulation(t *testing.T, s *xds.FakeDiscoveryServer, proxy *model.Proxy) *Simulation {
-	return NewSimulationFromConfigGen(t, s.ConfigGenTest, proxy)
-}
-
-// withT swaps out the testing struct. This allows executing sub tests.
-func (sim *Simulation) withT(t *testing.T) *Simulation {
-	cpy := *sim
-	cpy.t = t
-	return &cpy
-}
-
-func (sim *Simulation) RunExpectations(es []Expect) {
-	for _, e := range es {
-		sim.t.Run(e.Name, func(t *testing.T) {
-			sim.withT(t).Run(e.Call).Matches(t, e.Result)
-		})
+func hasFilterOnPort(l *listener.Listener, filter string, port int) bool {
+	for _, lf := range l.ListenerFilters {
+		if lf.Name != filter {
+			continue
+		}
+		if lf.FilterDisabled == nil {
+			return true
+		}
+		return !evaluateListenerFilterPredicates(lf.FilterDisabled, port)
 	}
+	return false
 }
 
-func hasFilterOnPort(l *listener.Listener, filter string, port int) bool {
-	got, f := xdstest.ExtractListenerFilters(l)[filter]
-	if !f {
+func evaluateListenerFilterPredicates(predicate *listener.ListenerFilterChainMatchPredicate, port int) bool {
+	if predicate == nil {
 		return false
 	}
-	if got.FilterDisabled == nil {
-		return true
+	switch r := predicate.Rule.(type) {
+	case *listener.ListenerFilterChainMatchPredicate_NotMatch:
+		return !evaluateListenerFilterPredicates(r.NotMatch, port)
+	case *listener.ListenerFilterChainMatchPredicate_OrMatch:
+		matches := false
+		for _, r := range r.OrMatch.Rules {
+			matches = matches || evaluateListenerFilterPredicates(r, port)
+		}
+		return matches
+	case *listener.ListenerFilterChainMatchPredicate_DestinationPortRange:
+		return int32(port) >= r.DestinationPortRange.GetStart() && int32(port) < r.DestinationPortRange.GetEnd()
+	default:
+		// Other predicates are not used by Istio, assume the filter is enabled.
+		return false
 	}
-	return !xdstest.EvaluateListenerFilterPredicates(got.FilterDisabled, port)
 }
 
 func (sim *Simulation) Run(input Call) (result Result) {
-	result = Result{t: sim.t}
 	input = input.FillDefaults()
 	if input.Alpn != "" && input.TLS == Plaintext {
 		result.Error = fmt.Errorf("invalid call, ALPN can only be sent in TLS requests")
@@ -278,25 +243,37 @@ func (sim *Simulation) Run(input Call) (result Result) {
 		}
 	}
 
-	fc, err := sim.matchFilterChain(l.FilterChains, l.DefaultFilterChain, input, hasTLSInspector)
+	fc, err := matchFilterChain(l.FilterChains, l.DefaultFilterChain, input, hasTLSInspector)
 	if err != nil {
 		result.Error = err
 		return
 	}
 	result.FilterChainMatched = fc.Name
+	result.filterChain = fc
 	// Plaintext to TLS is an error
 	if fc.TransportSocket != nil && input.TLS == Plaintext {
 		result.Error = ErrTLSError
 		return
 	}
 	// mTLS listener will only accept mTLS traffic
-	if fc.TransportSocket != nil && sim.requiresMTLS(fc) != (input.TLS == MTLS) {
-		// If there is no tls inspector, then
-		result.Error = ErrMTLSError
-		return
+	if fc.TransportSocket != nil {
+		mtls, err := requiresMTLS(fc)
+		if err != nil {
+			result.Error = err
+			return
+		}
+		if mtls != (input.TLS == MTLS) {
+			result.Error = ErrMTLSError
+			return
+		}
 	}
 
-	if hcm := xdstest.ExtractHTTPConnectionManager(sim.t, fc); hcm != nil {
+	h, tcp, err := extractNetworkFilters(fc)
+	if err != nil {
+		result.Error = err
+		return
+	}
+	if h != nil {
 		// We matched HCM and didn't terminate TLS, but we are sending TLS traffic - decoding will fail
 		if input.TLS != Plaintext && fc.TransportSocket == nil {
 			result.Error = ErrProtocolError
@@ -309,18 +286,18 @@ func (sim *Simulation) Run(input Call) (result Result) {
 		}
 
 		// Fetch inline route
-		rc := hcm.GetRouteConfig()
+		rc := h.GetRouteConfig()
 		if rc == nil {
 			// If not set, fallback to RDS
-			routeName := hcm.GetRds().RouteConfigName
+			routeName := h.GetRds().RouteConfigName
 			result.RouteConfigMatched = routeName
-			rc = xdstest.ExtractRouteConfigurations(sim.Routes)[routeName]
+			rc = sim.routeConfiguration(routeName)
 		}
 		hostHeader := ""
 		if len(input.Headers["Host"]) > 0 {
 			hostHeader = input.Headers["Host"][0]
 		}
-		vh := sim.matchVirtualHost(rc, hostHeader)
+		vh := matchVirtualHost(rc, hostHeader)
 		if vh == nil {
 			result.Error = ErrNoVirtualHost
 			return
@@ -331,7 +308,11 @@ func (sim *Simulation) Run(input Call) (result Result) {
 			return
 		}
 
-		r := sim.matchRoute(vh, input)
+		r, err := matchRoute(vh, input)
+		if err != nil {
+			result.Error = err
+			return
+		}
 		if r == nil {
 			result.Error = ErrNoRoute
 			return
@@ -341,62 +322,299 @@ func (sim *Simulation) Run(input Call) (result Result) {
 		case *route.Route_Route:
 			result.ClusterMatched = t.Route.GetCluster()
 		}
-	} else if tcp := xdstest.ExtractTCPProxy(sim.t, fc); tcp != nil {
+	} else if tcp != nil {
 		result.ClusterMatched = tcp.GetCluster()
 	}
 	return
 }
 
-func (sim *Simulation) requiresMTLS(fc *listener.FilterChain) bool {
+// UpstreamTLSMode returns the TLS mode of the connections originated to the cluster.
+func (sim *Simulation) UpstreamTLSMode(clusterName string) (TLSMode, error) {
+	var c *cluster.Cluster
+	for _, cl := range sim.Clusters {
+		if cl.Name == clusterName {
+			c = cl
+			break
+		}
+	}
+	if c == nil {
+		return "", fmt.Errorf("cluster %q not found", clusterName)
+	}
+	if c.TransportSocket != nil {
+		return upstreamTLSMode(c.TransportSocket)
+	}
+	for _, m := range c.TransportSocketMatches {
+		if m.TransportSocket == nil {
+			continue
+		}
+		mode, err := upstreamTLSMode(m.TransportSocket)
+		if err != nil {
+			return "", err
+		}
+		if mode == MTLS {
+			return AutoMTLS, nil
+		}
+	}
+	return Plaintext, nil
+}
+
+func upstreamTLSMode(ts *core.TransportSocket) (TLSMode, error) {
+	t := &tls.UpstreamTlsContext{}
+	if err := ts.GetTypedConfig().UnmarshalTo(t); err != nil {
+		return "", fmt.Errorf("failed to unmarshal upstream tls context: %v", err)
+	}
+	if isIstioCertificate(t.GetCommonTlsContext()) {
+		return MTLS, nil
+	}
+	return TLS, nil
+}
+
+func requiresMTLS(fc *listener.FilterChain) (bool, error) {
 	if fc.TransportSocket == nil {
-		return false
+		return false, nil
 	}
 	t := &tls.DownstreamTlsContext{}
 	if err := fc.GetTransportSocket().GetTypedConfig().UnmarshalTo(t); err != nil {
-		sim.t.Fatal(err)
+		return false, fmt.Errorf("failed to unmarshal downstream tls context: %v", err)
 	}
+	return isIstioCertificate(t.GetCommonTlsContext()), nil
+}
 
-	if len(t.GetCommonTlsContext().GetTlsCertificateSdsSecretConfigs()) == 0 {
+func isIstioCertificate(ctx *tls.CommonTlsContext) bool {
+	if len(ctx.GetTlsCertificateSdsSecretConfigs()) == 0 {
 		return false
 	}
 	// This is a lazy heuristic, we could check for explicit default resource or spiffe if it becomes necessary
-	return t.GetCommonTlsContext().GetTlsCertificateSdsSecretConfigs()[0].Name == "default"
+	return ctx.GetTlsCertificateSdsSecretConfigs()[0].Name == "default"
 }
 
-func (sim *Simulation) matchRoute(vh *route.VirtualHost, input Call) *route.Route {
+// extractNetworkFilters returns the HTTP connection manager or TCP proxy of the filter chain.
+func extractNetworkFilters(fc *listener.FilterChain) (*hcm.HttpConnectionManager, *tcpproxy.TcpProxy, error) {
+	for _, f := range fc.Filters {
+		switch f.Name {
+		case wellknown.HTTPConnectionManager:
+			h := &hcm.HttpConnectionManager{}
+			if f.GetTypedConfig() != nil {
+				if err := f.GetTypedConfig().UnmarshalTo(h); err != nil {
+					return nil, nil, fmt.Errorf("failed to unmarshal hcm: %v", err)
+				}
+			}
+			return h, nil, nil
+		case wellknown.TCPProxy:
+			tcp := &tcpproxy.TcpProxy{}
+			if f.GetTypedConfig() != nil {
+				if err := f.GetTypedConfig().UnmarshalTo(tcp); err != nil {
+					return nil, nil, fmt.Errorf("failed to unmarshal tcp proxy: %v", err)
+				}
+			}
+			return nil, tcp, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+func (sim *Simulation) routeConfiguration(name string) *route.RouteConfiguration {
+	for _, rc := range sim.Routes {
+		if rc.Name == name {
+			return rc
+		}
+	}
+	return nil
+}
+
+func matchRoute(vh *route.VirtualHost, input Call) (*route.Route, error) {
+	path, rawQuery := input.Path, ""
+	if i := strings.Index(path, "?"); i >= 0 {
+		path, rawQuery = path[:i], path[i+1:]
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query %v: %v", rawQuery, err)
+	}
 	for _, r := range vh.Routes {
 		// check path
+		normalize := func(s string) string { return s }
+		if r.Match.GetCaseSensitive() != nil && !r.Match.GetCaseSensitive().GetValue() {
+			normalize = strings.ToLower
+		}
 		switch pt := r.Match.GetPathSpecifier().(type) {
 		case *route.RouteMatch_Prefix:
-			if !strings.HasPrefix(input.Path, pt.Prefix) {
+			// Unlike the other path matches, the prefix applies to the path with the query
+			if !strings.HasPrefix(normalize(input.Path), normalize(pt.Prefix)) {
 				continue
 			}
 		case *route.RouteMatch_Path:
-			if input.Path != pt.Path {
+			if normalize(path) != normalize(pt.Path) {
 				continue
 			}
 		case *route.RouteMatch_SafeRegex:
-			r, err := regexp.Compile(pt.SafeRegex.GetRegex())
+			re, err := regexp.Compile(pt.SafeRegex.GetRegex())
 			if err != nil {
-				sim.t.Fatalf("invalid regex %v: %v", r, err)
+				return nil, fmt.Errorf("invalid regex %v: %v", pt.SafeRegex.GetRegex(), err)
 			}
-			if !r.MatchString(input.Path) {
+			if !re.MatchString(path) {
 				continue
 			}
 		default:
-			sim.t.Fatalf("unknown route path type")
+			return nil, fmt.Errorf("unknown route path type %T", pt)
+		}
+
+		matched, err := matchHeaders(r.Match.GetHeaders(), input)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
+		}
+		matched, err = matchQueryParameters(r.Match.GetQueryParameters(), query)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			continue
 		}
 
-		// TODO this only handles path - we need to add headers, query params, etc to be complete.
+		return r, nil
+	}
+	return nil, nil
+}
 
-		return r
+// headerValue returns the value of a request header, including the pseudo headers, and whether it is set.
+func headerValue(input Call, name string) (string, bool) {
+	switch name {
+	case ":authority", "host":
+		name = "Host"
+	case ":path":
+		return input.Path, true
+	case ":method":
+		if v, f := input.Headers[":method"]; f && len(v) > 0 {
+			return v[0], true
+		}
+		return http.MethodGet, true
+	case ":scheme":
+		if input.TLS == TLS {
+			return "https", true
+		}
+		return "http", true
+	}
+	v, f := input.Headers[http.CanonicalHeaderKey(name)]
+	if !f {
+		// Pseudo headers are not canonicalized
+		v, f = input.Headers[name]
+	}
+	if !f {
+		return "", false
+	}
+	// Multiple values are matched as a single comma separated value, as Envoy does
+	return strings.Join(v, ","), true
+}
+
+// matchHeaders returns true if the request matches all the header matchers of a route.
+func matchHeaders(matchers []*route.HeaderMatcher, input Call) (bool, error) {
+	for _, m := range matchers {
+		value, present := headerValue(input, m.GetName())
+		if !present {
+			// A missing header only matches an inverted presence match
+			if _, ok := m.GetHeaderMatchSpecifier().(*route.HeaderMatcher_PresentMatch); ok && m.GetInvertMatch() {
+				continue
+			}
+			return false, nil
+		}
+		var matched bool
+		switch hm := m.GetHeaderMatchSpecifier().(type) {
+		case *route.HeaderMatcher_ExactMatch:
+			matched = value == hm.ExactMatch
+		case *route.HeaderMatcher_PrefixMatch:
+			matched = strings.HasPrefix(value, hm.PrefixMatch)
+		case *route.HeaderMatcher_SuffixMatch:
+			matched = strings.HasSuffix(value, hm.SuffixMatch)
+		case *route.HeaderMatcher_ContainsMatch:
+			matched = strings.Contains(value, hm.ContainsMatch)
+		case *route.HeaderMatcher_PresentMatch:
+			matched = hm.PresentMatch
+		case *route.HeaderMatcher_SafeRegexMatch:
+			re, err := fullMatchRegex(hm.SafeRegexMatch.GetRegex())
+			if err != nil {
+				return false, err
+			}
+			matched = re.MatchString(value)
+		case *route.HeaderMatcher_RangeMatch:
+			v, err := strconv.ParseInt(value, 10, 64)
+			matched = err == nil && v >= hm.RangeMatch.GetStart() && v < hm.RangeMatch.GetEnd()
+		default:
+			return false, fmt.Errorf("unknown header match type %T", hm)
+		}
+		if matched == m.GetInvertMatch() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchQueryParameters returns true if the request matches all the query parameter matchers of a route.
+func matchQueryParameters(matchers []*route.QueryParameterMatcher, query url.Values) (bool, error) {
+	for _, m := range matchers {
+		values, present := query[m.GetName()]
+		switch qm := m.GetQueryParameterMatchSpecifier().(type) {
+		case *route.QueryParameterMatcher_PresentMatch:
+			if present != qm.PresentMatch {
+				return false, nil
+			}
+		case *route.QueryParameterMatcher_StringMatch:
+			if !present {
+				return false, nil
+			}
+			matched, err := matchString(qm.StringMatch, values[0])
+			if err != nil {
+				return false, err
+			}
+			if !matched {
+				return false, nil
+			}
+		default:
+			return false, fmt.Errorf("unknown query parameter match type %T", qm)
+		}
+	}
+	return true, nil
+}
+
+func matchString(m *matcher.StringMatcher, value string) (bool, error) {
+	normalize := func(s string) string { return s }
+	if m.GetIgnoreCase() {
+		normalize = strings.ToLower
+	}
+	switch sm := m.GetMatchPattern().(type) {
+	case *matcher.StringMatcher_Exact:
+		return normalize(value) == normalize(sm.Exact), nil
+	case *matcher.StringMatcher_Prefix:
+		return strings.HasPrefix(normalize(value), normalize(sm.Prefix)), nil
+	case *matcher.StringMatcher_Suffix:
+		return strings.HasSuffix(normalize(value), normalize(sm.Suffix)), nil
+	case *matcher.StringMatcher_Contains:
+		return strings.Contains(normalize(value), normalize(sm.Contains)), nil
+	case *matcher.StringMatcher_SafeRegex:
+		re, err := fullMatchRegex(sm.SafeRegex.GetRegex())
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(value), nil
+	default:
+		return false, fmt.Errorf("unknown string match type %T", sm)
 	}
-	return nil
 }
 
-func (sim *Simulation) matchVirtualHost(rc *route.RouteConfiguration, host string) *route.VirtualHost {
+// fullMatchRegex compiles a regex matching the whole value, as Envoy does for headers and query parameters.
+func fullMatchRegex(regex string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("^(?:" + regex + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %v: %v", regex, err)
+	}
+	return re, nil
+}
+
+func matchVirtualHost(rc *route.RouteConfiguration, host string) *route.VirtualHost {
 	// Exact match
-	for _, vh := range rc.VirtualHosts {
+	for _, vh := range rc.GetVirtualHosts() {
 		for _, d := range vh.Domains {
 			if d == host {
 				return vh
@@ -406,7 +624,7 @@ func (sim *Simulation) matchVirtualHost(rc *route.RouteConfiguration, host strin
 	// prefix match
 	var bestMatch *route.VirtualHost
 	longest := 0
-	for _, vh := range rc.VirtualHosts {
+	for _, vh := range rc.GetVirtualHosts() {
 		for _, d := range vh.Domains {
 			if d[0] != '*' {
 				continue
@@ -422,7 +640,7 @@ func (sim *Simulation) matchVirtualHost(rc *route.RouteConfiguration, host strin
 	}
 	// Suffix match
 	longest = 0
-	for _, vh := range rc.VirtualHosts {
+	for _, vh := range rc.GetVirtualHosts() {
 		for _, d := range vh.Domains {
 			if d[len(d)-1] != '*' {
 				continue
@@ -437,7 +655,7 @@ func (sim *Simulation) matchVirtualHost(rc *route.RouteConfiguration, host strin
 		return bestMatch
 	}
 	// wildcard match
-	for _, vh := range rc.VirtualHosts {
+	for _, vh := range rc.GetVirtualHosts() {
 		for _, d := range vh.Domains {
 			if d == "*" {
 				return vh
@@ -453,8 +671,9 @@ func (sim *Simulation) matchVirtualHost(rc *route.RouteConfiguration, host strin
 // Envoy algorithm - at each level we will filter out all FilterChains that do
 // not match. This means an empty match (`{}`) may not match if another chain
 // matches one criteria but not another.
-func (sim *Simulation) matchFilterChain(chains []*listener.FilterChain, defaultChain *listener.FilterChain,
+func matchFilterChain(chains []*listener.FilterChain, defaultChain *listener.FilterChain,
 	input Call, hasTLSInspector bool) (*listener.FilterChain, error) {
+	var cidrErr error
 	chains = filter(chains, func(fc *listener.FilterChainMatch) bool {
 		return fc.GetDestinationPort() == nil
 	}, func(fc *listener.FilterChainMatch) bool {
@@ -468,18 +687,24 @@ func (sim *Simulation) matchFilterChain(chains []*listener.FilterChain, defaultC
 			s := fmt.Sprintf("%s/%d", a.AddressPrefix, a.GetPrefixLen().GetValue())
 			_, cidr, err := net.ParseCIDR(s)
 			if err != nil {
-				sim.t.Fatalf("failed to parse cidr %v: %v", s, err)
+				cidrErr = fmt.Errorf("failed to parse cidr %v: %v", s, err)
+				return false
 			}
 			if err := ranger.Insert(cidranger.NewBasicRangerEntry(*cidr)); err != nil {
-				sim.t.Fatalf("failed to insert cidr %v: %v", cidr, err)
+				cidrErr = fmt.Errorf("failed to insert cidr %v: %v", cidr, err)
+				return false
 			}
 		}
 		f, err := ranger.Contains(net.ParseIP(input.Address))
 		if err != nil {
-			sim.t.Fatalf("cidr containers %v failed: %v", input.Address, err)
+			cidrErr = fmt.Errorf("cidr containers %v failed: %v", input.Address, err)
+			return false
 		}
 		return f
 	})
+	if cidrErr != nil {
+		return nil, cidrErr
+	}
 	chains = filter(chains, func(fc *listener.FilterChainMatch) bool {
 		return fc.GetServerNames() == nil
 	}, func(fc *listener.FilterChainMatch) bool {
@@ -591,7 +816,12 @@ func protocolToAlpn(s Protocol) string {
 
 func matchListener(listeners []*listener.Listener, input Call) *listener.Listener {
 	if input.CallMode == CallModeInbound {
-		return xdstest.ExtractListener(v1alpha3.VirtualInboundListenerName, listeners)
+		for _, l := range listeners {
+			if l.Name == v1alpha3.VirtualInboundListenerName {
+				return l
+			}
+		}
+		return nil
 	}
 	// First find exact match for the IP/Port, then fallback to wildcard IP/Port
 	// There is no wildcard port
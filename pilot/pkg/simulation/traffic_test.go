@@ -0,0 +1,138 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulation
+
+import (
+	"net/http"
+	"testing"
+
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	matcher "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	envoytype "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+)
+
+func TestMatchRoute(t *testing.T) {
+	prefix := &route.RouteMatch_Prefix{Prefix: "/"}
+	cases := []struct {
+		name  string
+		match *route.RouteMatch
+		call  Call
+		want  bool
+	}{
+		{
+			name:  "path ignores the query",
+			match: &route.RouteMatch{PathSpecifier: &route.RouteMatch_Path{Path: "/foo"}},
+			call:  Call{Path: "/foo?a=b"},
+			want:  true,
+		},
+		{
+			name: "case insensitive path",
+			match: &route.RouteMatch{
+				PathSpecifier: &route.RouteMatch_Path{Path: "/foo"},
+				CaseSensitive: &wrappers.BoolValue{Value: false},
+			},
+			call: Call{Path: "/FOO"},
+			want: true,
+		},
+		{
+			name: "present header",
+			match: &route.RouteMatch{PathSpecifier: prefix, Headers: []*route.HeaderMatcher{{
+				Name: "x-foo", HeaderMatchSpecifier: &route.HeaderMatcher_PresentMatch{PresentMatch: true},
+			}}},
+			call: Call{Headers: http.Header{"X-Foo": []string{"bar"}}},
+			want: true,
+		},
+		{
+			name: "missing header",
+			match: &route.RouteMatch{PathSpecifier: prefix, Headers: []*route.HeaderMatcher{{
+				Name: "x-foo", HeaderMatchSpecifier: &route.HeaderMatcher_PresentMatch{PresentMatch: true},
+			}}},
+			call: Call{},
+			want: false,
+		},
+		{
+			name: "inverted present header",
+			match: &route.RouteMatch{PathSpecifier: prefix, Headers: []*route.HeaderMatcher{{
+				Name: "x-foo", InvertMatch: true, HeaderMatchSpecifier: &route.HeaderMatcher_PresentMatch{PresentMatch: true},
+			}}},
+			call: Call{},
+			want: true,
+		},
+		{
+			name: "inverted exact header on a missing header",
+			match: &route.RouteMatch{PathSpecifier: prefix, Headers: []*route.HeaderMatcher{{
+				Name: "x-foo", InvertMatch: true, HeaderMatchSpecifier: &route.HeaderMatcher_ExactMatch{ExactMatch: "bar"},
+			}}},
+			call: Call{},
+			want: false,
+		},
+		{
+			name: "prefix header",
+			match: &route.RouteMatch{PathSpecifier: prefix, Headers: []*route.HeaderMatcher{{
+				Name: "x-foo", HeaderMatchSpecifier: &route.HeaderMatcher_PrefixMatch{PrefixMatch: "ba"},
+			}}},
+			call: Call{Headers: http.Header{"X-Foo": []string{"bar"}}},
+			want: true,
+		},
+		{
+			name: "range header",
+			match: &route.RouteMatch{PathSpecifier: prefix, Headers: []*route.HeaderMatcher{{
+				Name: "x-foo", HeaderMatchSpecifier: &route.HeaderMatcher_RangeMatch{RangeMatch: &envoytype.Int64Range{Start: 1, End: 10}},
+			}}},
+			call: Call{Headers: http.Header{"X-Foo": []string{"10"}}},
+			want: false,
+		},
+		{
+			name: "authority",
+			match: &route.RouteMatch{PathSpecifier: prefix, Headers: []*route.HeaderMatcher{{
+				Name: ":authority", HeaderMatchSpecifier: &route.HeaderMatcher_ExactMatch{ExactMatch: "foo.com"},
+			}}},
+			call: Call{HostHeader: "foo.com"},
+			want: true,
+		},
+		{
+			name: "present query parameter",
+			match: &route.RouteMatch{PathSpecifier: prefix, QueryParameters: []*route.QueryParameterMatcher{{
+				Name: "debug", QueryParameterMatchSpecifier: &route.QueryParameterMatcher_PresentMatch{PresentMatch: true},
+			}}},
+			call: Call{Path: "/?debug"},
+			want: true,
+		},
+		{
+			name: "regex query parameter",
+			match: &route.RouteMatch{PathSpecifier: prefix, QueryParameters: []*route.QueryParameterMatcher{{
+				Name: "v",
+				QueryParameterMatchSpecifier: &route.QueryParameterMatcher_StringMatch{StringMatch: &matcher.StringMatcher{
+					MatchPattern: &matcher.StringMatcher_SafeRegex{SafeRegex: &matcher.RegexMatcher{Regex: "[0-9]+"}},
+				}},
+			}}},
+			call: Call{Path: "/?v=12a"},
+			want: false,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			vh := &route.VirtualHost{Routes: []*route.Route{{Name: "route", Match: tt.match}}}
+			r, err := matchRoute(vh, tt.call.FillDefaults())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r != nil; got != tt.want {
+				t.Fatalf("got match %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -55,13 +55,23 @@ type inProgressEntry struct {
 	completedIterations int
 }
 
+// resourceChange records when a version of a resource was first observed.
+type resourceChange struct {
+	version string
+	time    time.Time
+}
+
 type Reporter struct {
 	mu sync.RWMutex
 	// map from connection id to latest nonce
 	status map[string]string
+	// map from connection id to the time the latest nonce was first acked
+	ackTimes map[string]time.Time
 	// map from nonce to connection ids for which it is current
 	// using map[string]struct to approximate a hashset
-	reverseStatus          map[string]map[string]struct{}
+	reverseStatus map[string]map[string]struct{}
+	// map from model key to the latest change of the resource
+	resourceChanges        map[string]resourceChange
 	inProgressResources    map[string]*inProgressEntry
 	client                 v1.ConfigMapInterface
 	cm                     *corev1.ConfigMap
@@ -93,7 +103,9 @@ func (r *Reporter) Init(ledger ledger.Ledger) {
 	}
 	r.distributionEventQueue = make(chan distributionEvent, 100_000)
 	r.status = make(map[string]string)
+	r.ackTimes = make(map[string]time.Time)
 	r.reverseStatus = make(map[string]map[string]struct{})
+	r.resourceChanges = make(map[string]resourceChange)
 	r.inProgressResources = make(map[string]*inProgressEntry)
 	go r.readFromEventQueue()
 }
@@ -229,6 +241,10 @@ func (r *Reporter) AddInProgressResource(res config.Config) {
 		Resource:            *myRes,
 		completedIterations: 0,
 	}
+	key := config.Key(res.GroupVersionKind.Kind, res.Name, res.Namespace)
+	if r.resourceChanges[key].version != myRes.Generation {
+		r.resourceChanges[key] = resourceChange{version: myRes.Generation, time: r.clock.Now()}
+	}
 }
 
 func (r *Reporter) DeleteInProgressResource(res config.Config) {
@@ -239,6 +255,7 @@ func (r *Reporter) DeleteInProgressResource(res config.Config) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	delete(r.inProgressResources, res.Key())
+	delete(r.resourceChanges, config.Key(res.GroupVersionKind.Kind, res.Name, res.Namespace))
 }
 
 // generate a distribution report and write it to a ConfigMap for the leader to read.
@@ -287,6 +304,27 @@ func (r *Reporter) QueryLastNonce(conID string, distributionType xds.EventType)
 	return r.status[key]
 }
 
+// QueryLastAck returns the latest nonce prefix acked by a connection, and when it was first acked.
+func (r *Reporter) QueryLastAck(conID string, distributionType xds.EventType) (noncePrefix string, ackTime time.Time) {
+	key := GenStatusReporterMapKey(conID, distributionType)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.status[key], r.ackTimes[key]
+}
+
+// QueryLastChange returns the latest version of a resource, identified by its model key, and when it was
+// first observed. The time is zero if the resource has not changed since this instance started.
+func (r *Reporter) QueryLastChange(key string) (version string, changeTime time.Time) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	change, ok := r.resourceChanges[key]
+	if !ok {
+		version, _ = r.ledger.Get(key)
+		return version, time.Time{}
+	}
+	return change.version, change.time
+}
+
 // Register that a dataplane has acknowledged a new version of the config.
 // Theoretically, we could use the ads connections themselves to harvest this data,
 // but the mutex there is pretty hot, and it seems best to trade memory for time.
@@ -325,6 +363,9 @@ func (r *Reporter) processEvent(conID string, distributionType xds.EventType, no
 		version = nonce
 	}
 	// touch
+	if r.status[key] != version {
+		r.ackTimes[key] = r.clock.Now()
+	}
 	r.status[key] = version
 	if _, ok := r.reverseStatus[version]; !ok {
 		r.reverseStatus[version] = make(map[string]struct{})
@@ -353,6 +394,7 @@ func (r *Reporter) RegisterDisconnect(conID string, types []xds.EventType) {
 		key := GenStatusReporterMapKey(conID, xdsType)
 		r.deleteKeyFromReverseMap(key)
 		delete(r.status, key)
+		delete(r.ackTimes, key)
 	}
 }
 
@@ -20,6 +20,7 @@ import (
 
 	. "github.com/onsi/gomega"
 	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
 
 	"istio.io/istio/pilot/pkg/xds"
 	"istio.io/istio/pkg/config"
@@ -55,6 +56,8 @@ func initReporterWithoutStarting() (out Reporter) {
 	out.cm = nil // TODO
 	out.reverseStatus = make(map[string]map[string]struct{})
 	out.status = make(map[string]string)
+	out.ackTimes = make(map[string]time.Time)
+	out.resourceChanges = make(map[string]resourceChange)
 	return
 }
 
@@ -124,3 +127,57 @@ func TestBuildReport(t *testing.T) {
 	}))
 	Expect(r.inProgressResources).NotTo(ContainElement(resources[0]))
 }
+
+func TestQueryLastAckAndChange(t *testing.T) {
+	RegisterTestingT(t)
+	r := initReporterWithoutStarting()
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	r.clock = fakeClock
+	r.ledger = ledger.Make(time.Minute)
+	vs := config.Config{
+		Meta: config.Meta{
+			GroupVersionKind: collections.IstioNetworkingV1Alpha3Virtualservices.Resource().GroupVersionKind(),
+			Namespace:        "default",
+			Name:             "foo",
+			Generation:       1,
+		},
+	}
+	key := config.Key(vs.GroupVersionKind.Kind, vs.Name, vs.Namespace)
+
+	r.AddInProgressResource(vs)
+	firstChange := fakeClock.Now()
+	version, changeTime := r.QueryLastChange(key)
+	Expect(version).To(Equal("1"))
+	Expect(changeTime).To(Equal(firstChange))
+
+	r.processEvent("conA", "", r.ledger.RootHash())
+	nonce, ackTime := r.QueryLastAck("conA", "")
+	Expect(nonce).To(Equal(r.ledger.RootHash()))
+	Expect(ackTime).To(Equal(firstChange))
+
+	// acking the same version again does not move the ack time.
+	fakeClock.Step(time.Second)
+	r.processEvent("conA", "", r.ledger.RootHash())
+	_, ackTime = r.QueryLastAck("conA", "")
+	Expect(ackTime).To(Equal(firstChange))
+
+	// an update without a new generation keeps the change time.
+	r.AddInProgressResource(vs)
+	_, changeTime = r.QueryLastChange(key)
+	Expect(changeTime).To(Equal(firstChange))
+
+	vs.Generation = 2
+	r.AddInProgressResource(vs)
+	version, changeTime = r.QueryLastChange(key)
+	Expect(version).To(Equal("2"))
+	Expect(changeTime).To(Equal(fakeClock.Now()))
+
+	r.RegisterDisconnect("conA", []xds.EventType{""})
+	nonce, ackTime = r.QueryLastAck("conA", "")
+	Expect(nonce).To(BeEmpty())
+	Expect(ackTime.IsZero()).To(BeTrue())
+
+	r.DeleteInProgressResource(vs)
+	_, changeTime = r.QueryLastChange(key)
+	Expect(changeTime.IsZero()).To(BeTrue())
+}
@@ -34,6 +34,8 @@ type WorkerQueue interface {
 	Run(ctx context.Context)
 	// Delete a task
 	Delete(target Resource)
+	// Length returns the number of tasks waiting to run
+	Length() int
 }
 
 type cacheEntry struct {
@@ -146,6 +148,10 @@ func (wp *WorkerPool) Delete(target Resource) {
 	wp.q.Delete(&target)
 }
 
+func (wp *WorkerPool) Length() int {
+	return wp.q.Length()
+}
+
 func (wp *WorkerPool) Push(target Resource, progress Progress) {
 	wp.q.Push(target, progress)
 	wp.maybeAddWorker()
@@ -0,0 +1,107 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"strconv"
+	"strings"
+
+	"istio.io/pkg/monitoring"
+)
+
+var (
+	shardTag = monitoring.MustCreateLabel("shard")
+
+	shardBacklog = monitoring.NewGauge(
+		"pilot_status_shard_backlog",
+		"Number of resources of the status shard waiting for a status write.",
+		monitoring.WithLabels(shardTag),
+	)
+
+	shardResources = monitoring.NewGauge(
+		"pilot_status_shard_resources",
+		"Number of resources with a distribution report assigned to the status shard.",
+		monitoring.WithLabels(shardTag),
+	)
+
+	shardRebalances = monitoring.NewSum(
+		"pilot_status_shard_rebalances",
+		"Total number of times the status writes were rebalanced across the istiod replicas.",
+	)
+)
+
+func init() {
+	monitoring.MustRegister(shardBacklog, shardResources, shardRebalances)
+}
+
+// virtualNodes is the number of points of each replica on the hash ring. More points spread the resources more evenly.
+const virtualNodes = 100
+
+// hashRing assigns the resources to the istiod replicas with consistent hashing: when a replica joins or leaves,
+// only the resources of that replica move.
+type hashRing struct {
+	points []uint32
+	owners map[uint32]string
+}
+
+func newHashRing(replicas []string) *hashRing {
+	h := &hashRing{
+		owners: make(map[uint32]string, len(replicas)*virtualNodes),
+	}
+	for _, replica := range replicas {
+		for i := 0; i < virtualNodes; i++ {
+			p := hash(replica + "#" + strconv.Itoa(i))
+			if owner, f := h.owners[p]; f && owner < replica {
+				// keep the ownership of colliding points independent of the order of the replicas
+				continue
+			}
+			h.owners[p] = replica
+		}
+	}
+	for p := range h.owners {
+		h.points = append(h.points, p)
+	}
+	sort.Slice(h.points, func(i, j int) bool { return h.points[i] < h.points[j] })
+	return h
+}
+
+// owner returns the replica owning the key, or "" if the ring is empty.
+func (h *hashRing) owner(key string) string {
+	if len(h.points) == 0 {
+		return ""
+	}
+	p := hash(key)
+	i := sort.Search(len(h.points), func(i int) bool { return h.points[i] >= p })
+	if i == len(h.points) {
+		i = 0
+	}
+	return h.owners[h.points[i]]
+}
+
+// hash spreads the keys uniformly on the ring. FNV is not used since it clusters keys sharing a prefix, as the
+// points of a replica do.
+func hash(s string) uint32 {
+	sum := sha256.Sum256([]byte(s))
+	return binary.BigEndian.Uint32(sum[:4])
+}
+
+// shardKey returns the key used to shard the resource. It ignores the version and generation of the resource, so
+// that all the reports of a resource are written by the same replica.
+func shardKey(r Resource) string {
+	return strings.Join([]string{r.Group, r.Resource, r.Namespace, r.Name}, "/")
+}
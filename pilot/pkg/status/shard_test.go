@@ -0,0 +1,166 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/clock"
+)
+
+func testResources(n int) []Resource {
+	out := make([]Resource, 0, n)
+	for i := 0; i < n; i++ {
+		out = append(out, Resource{
+			GroupVersionResource: schema.GroupVersionResource{
+				Group:    "networking.istio.io",
+				Version:  "v1alpha3",
+				Resource: "virtualservices",
+			},
+			Namespace:  "default",
+			Name:       "vs-" + strconv.Itoa(i),
+			Generation: "1",
+		})
+	}
+	return out
+}
+
+func TestHashRing(t *testing.T) {
+	resources := testResources(3000)
+	ring := newHashRing([]string{"istiod-a", "istiod-b", "istiod-c"})
+
+	counts := map[string]int{}
+	before := map[Resource]string{}
+	for _, r := range resources {
+		owner := ring.owner(shardKey(r))
+		counts[owner]++
+		before[r] = owner
+	}
+	for _, replica := range []string{"istiod-a", "istiod-b", "istiod-c"} {
+		// each replica should own roughly a third of the resources
+		if counts[replica] < 600 || counts[replica] > 1400 {
+			t.Errorf("unbalanced ring: %v", counts)
+		}
+	}
+
+	// The order of the replicas does not matter
+	reordered := newHashRing([]string{"istiod-c", "istiod-a", "istiod-b"})
+	for _, r := range resources {
+		if got := reordered.owner(shardKey(r)); got != before[r] {
+			t.Fatalf("owner of %v changed with the order of the replicas: %s != %s", r, got, before[r])
+		}
+	}
+
+	// Only the resources of a leaving replica move
+	shrunk := newHashRing([]string{"istiod-a", "istiod-c"})
+	for _, r := range resources {
+		got := shrunk.owner(shardKey(r))
+		if before[r] != "istiod-b" && got != before[r] {
+			t.Fatalf("%v moved from %s to %s", r, before[r], got)
+		}
+		if got == "istiod-b" {
+			t.Fatalf("%v owned by a replica which left", r)
+		}
+	}
+
+	// All generations of a resource have the same owner
+	r := resources[0]
+	r.Generation = "2"
+	if got := ring.owner(shardKey(r)); got != before[resources[0]] {
+		t.Fatalf("owner changed with the generation: %s != %s", got, before[resources[0]])
+	}
+
+	if got := newHashRing(nil).owner(shardKey(r)); got != "" {
+		t.Fatalf("expected no owner on an empty ring, got %s", got)
+	}
+}
+
+type fakeWorkers struct {
+	pushed []Resource
+}
+
+func (f *fakeWorkers) Push(target Resource, progress Progress) {
+	f.pushed = append(f.pushed, target)
+}
+
+func (f *fakeWorkers) Run(ctx context.Context) {}
+
+func (f *fakeWorkers) Delete(target Resource) {}
+
+func (f *fakeWorkers) Length() int {
+	return len(f.pushed)
+}
+
+func TestDistributionControllerSharding(t *testing.T) {
+	resources := testResources(100)
+	newController := func(shard string) (*DistributionController, *fakeWorkers) {
+		workers := &fakeWorkers{}
+		c := &DistributionController{
+			CurrentState:    make(map[Resource]map[string]Progress),
+			ObservationTime: make(map[string]time.Time),
+			StaleInterval:   time.Minute,
+			clock:           clock.RealClock{},
+			workers:         workers,
+		}
+		if shard != "" {
+			c.EnableSharding(shard)
+		}
+		for _, r := range resources {
+			c.CurrentState[r] = map[string]Progress{"pod": {AckedInstances: 1, TotalInstances: 2}}
+		}
+		c.ObservationTime["pod"] = c.clock.Now()
+		return c, workers
+	}
+
+	// Without sharding, all resources are written
+	c, workers := newController("")
+	c.SetReplicas([]string{"istiod-a", "istiod-b"})
+	c.writeAllStatus()
+	if len(workers.pushed) != len(resources) {
+		t.Fatalf("expected %d writes, got %d", len(resources), len(workers.pushed))
+	}
+
+	// With sharding, each resource is written by exactly one replica
+	owners := map[Resource]int{}
+	for _, shard := range []string{"istiod-a", "istiod-b"} {
+		c, workers := newController(shard)
+		c.SetReplicas([]string{"istiod-a", "istiod-b"})
+		c.writeAllStatus()
+		if len(workers.pushed) == 0 || len(workers.pushed) == len(resources) {
+			t.Errorf("expected %s to write a share of the resources, got %d", shard, len(workers.pushed))
+		}
+		for _, r := range workers.pushed {
+			owners[r]++
+		}
+	}
+	for _, r := range resources {
+		if owners[r] != 1 {
+			t.Errorf("%v written %d times", r, owners[r])
+		}
+	}
+
+	// When the other replica leaves, the remaining one writes everything
+	c, workers = newController("istiod-a")
+	c.SetReplicas([]string{"istiod-a", "istiod-b"})
+	c.SetReplicas([]string{"istiod-a"})
+	c.writeAllStatus()
+	if len(workers.pushed) != len(resources) {
+		t.Fatalf("expected %d writes after rebalancing, got %d", len(resources), len(workers.pushed))
+	}
+}
@@ -64,6 +64,10 @@ type DistributionController struct {
 	workers         WorkerQueue
 	StaleInterval   time.Duration
 	cmInformer      cache.SharedIndexInformer
+	// shard is the name of this replica when the status writes are sharded across the istiod replicas.
+	shard string
+	// ring assigns the resources to the live replicas, nil until the replicas are known.
+	ring *hashRing
 }
 
 func NewController(restConfig rest.Config, namespace string, cs model.ConfigStore) *DistributionController {
@@ -98,6 +102,36 @@ func NewController(restConfig rest.Config, namespace string, cs model.ConfigStor
 	return c
 }
 
+// EnableSharding makes the controller write the status of the resources owned by the replica named shard only,
+// instead of all the resources. The owners are assigned among the replicas passed to SetReplicas.
+func (c *DistributionController) EnableSharding(shard string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.shard = shard
+}
+
+// SetReplicas rebalances the status writes across the live istiod replicas. All replicas receive the reports of
+// every resource, so the resources moved to this replica are written at the next update interval.
+func (c *DistributionController) SetReplicas(replicas []string) {
+	ring := newHashRing(replicas)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.shard == "" {
+		return
+	}
+	c.ring = ring
+	shardRebalances.Increment()
+	scope.Infof("Rebalanced status writes of %s across replicas %v", c.shard, replicas)
+}
+
+// owns returns true if this replica writes the status of the resource. c.mu must be held.
+func (c *DistributionController) owns(r Resource) bool {
+	if c.shard == "" || c.ring == nil {
+		return true
+	}
+	return c.ring.owner(shardKey(r)) == c.shard
+}
+
 func (c *DistributionController) Start(stop <-chan struct{}) {
 	scope.Info("Starting status leader controller")
 
@@ -144,7 +178,12 @@ func (c *DistributionController) handleReport(d DistributionReport) {
 func (c *DistributionController) writeAllStatus() (staleReporters []string) {
 	defer c.mu.RUnlock()
 	c.mu.RLock()
+	owned := 0
 	for config, fractions := range c.CurrentState {
+		if !c.owns(config) {
+			continue
+		}
+		owned++
 		var distributionState Progress
 		for reporter, w := range fractions {
 			// check for stale data here
@@ -160,10 +199,21 @@ func (c *DistributionController) writeAllStatus() (staleReporters []string) {
 			c.queueWriteStatus(config, distributionState)
 		}
 	}
+	if c.shard != "" {
+		shardResources.With(shardTag.Value(c.shard)).Record(float64(owned))
+		shardBacklog.With(shardTag.Value(c.shard)).Record(float64(c.workers.Length()))
+	}
 	return
 }
 
 func (c *DistributionController) writeStatus(config Resource, distributionState Progress) {
+	c.mu.RLock()
+	owned := c.owns(config)
+	c.mu.RUnlock()
+	if !owned {
+		// the resource moved to another replica since it was queued
+		return
+	}
 	schema, _ := collections.All.FindByGroupVersionResource(config.GroupVersionResource)
 	if schema == nil {
 		scope.Warnf("schema %v could not be identified", schema)
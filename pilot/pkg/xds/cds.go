@@ -25,7 +25,7 @@ type CdsGenerator struct {
 	Server *DiscoveryServer
 }
 
-var _ model.XdsResourceGenerator = &CdsGenerator{}
+var _ model.XdsDeltaResourceGenerator = &CdsGenerator{}
 
 // Map of all configs that do not impact CDS
 var skippedCdsConfigs = map[config.GroupVersionKind]struct{}{
@@ -80,3 +80,18 @@ func (c CdsGenerator) Generate(proxy *model.Proxy, push *model.PushContext, w *m
 	}
 	return resources, nil
 }
+
+// GenerateDeltas for CDS currently only builds the clusters of the services updated by ServiceEntry changes,
+// and removes the clusters of the deleted services. Other changes fall back to a full generation.
+func (c CdsGenerator) GenerateDeltas(proxy *model.Proxy, push *model.PushContext, w *model.WatchedResource,
+	req *model.PushRequest) (model.Resources, []string, bool, error) {
+	if !cdsNeedsPush(req, proxy) {
+		return nil, nil, false, nil
+	}
+	updatedClusters, removedClusters, usedDelta := c.Server.ConfigGenerator.BuildDeltaClusters(proxy, push, req, w)
+	resources := model.Resources{}
+	for _, c := range updatedClusters {
+		resources = append(resources, util.MessageToAny(c))
+	}
+	return resources, removedClusters, usedDelta, nil
+}
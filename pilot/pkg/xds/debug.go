@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/golang/protobuf/ptypes/any"
 	"google.golang.org/protobuf/types/known/anypb"
 
+	networking "istio.io/api/networking/v1alpha3"
 	"istio.io/istio/pilot/pkg/config/kube/crd"
 	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model"
@@ -123,6 +125,21 @@ type SyncedVersions struct {
 	RouteVersion    string `json:"route_acked,omitempty"`
 }
 
+// ConfigStatus shows whether a proxy has acked the latest version of a given resource.
+type ConfigStatus struct {
+	ProxyID string `json:"proxy"`
+	// AckedVersion is the version of the resource acked by the proxy. When the proxy is not synced, this is
+	// the version of the first xDS type not running the latest version.
+	AckedVersion  string `json:"acked_version,omitempty"`
+	LatestVersion string `json:"latest_version,omitempty"`
+	Synced        bool   `json:"synced"`
+	// LastAck is when the proxy acked the oldest config it is running, or nil if no ack time is known.
+	LastAck *time.Time `json:"last_ack,omitempty"`
+	// Lag is how long the latest version of the resource has not been acked by the proxy. It is zero when
+	// the proxy is synced, or when the resource changed before this Pilot instance started.
+	Lag time.Duration `json:"lag,omitempty"`
+}
+
 // InitDebug initializes the debug handlers and adds a debug in-memory registry.
 func (s *DiscoveryServer) InitDebug(mux *http.ServeMux, sctl *aggregate.Controller, enableProfiling bool,
 	fetchWebhook func() map[string]string) {
@@ -172,13 +189,18 @@ func (s *DiscoveryServer) AddDebugHandlers(mux *http.ServeMux, enableProfiling b
 
 	s.addDebugHandler(mux, "/debug/syncz", "Synchronization status of all Envoys connected to this Pilot instance", s.Syncz)
 	s.addDebugHandler(mux, "/debug/config_distribution", "Version status of all Envoys connected to this Pilot instance", s.distributedVersions)
+	s.addDebugHandler(mux, "/debug/config_status", "Sync state of a resource on all Envoys connected to this Pilot instance", s.configStatus)
 
 	s.addDebugHandler(mux, "/debug/registryz", "Debug support for registry", s.registryz)
 	s.addDebugHandler(mux, "/debug/endpointz", "Debug support for endpoints", s.endpointz)
 	s.addDebugHandler(mux, "/debug/endpointShardz", "Info about the endpoint shards", s.endpointShardz)
 	s.addDebugHandler(mux, "/debug/cachez", "Info about the internal XDS caches", s.cachez)
+	s.addDebugHandler(mux, "/debug/cachez?sizes=true", "Size of the internal XDS caches and largest entries", s.cachez)
 	s.addDebugHandler(mux, "/debug/configz", "Debug support for config", s.configz)
+	s.addDebugHandler(mux, "/debug/config_filez", "Errors found in the files of the file config sources", s.configFilez)
+	s.addDebugHandler(mux, "/debug/snapshotz", "Archive of the state the push context is built from", s.snapshotz)
 	s.addDebugHandler(mux, "/debug/sidecarz", "Debug sidecar scope for a proxy", s.sidecarz)
+	s.addDebugHandler(mux, "/debug/envoyfilterz", "Debug EnvoyFilter patches applied to a proxy", s.envoyfilterz)
 	s.addDebugHandler(mux, "/debug/resourcesz", "Debug support for watched resources", s.resourcez)
 	s.addDebugHandler(mux, "/debug/instancesz", "Debug support for service instances", s.instancesz)
 
@@ -266,7 +288,23 @@ func (s *DiscoveryServer) endpointShardz(w http.ResponseWriter, req *http.Reques
 	_, _ = w.Write(out)
 }
 
+// cacheTypeSummary is the size of the XDS cache entries of a resource type.
+type cacheTypeSummary struct {
+	Entries int   `json:"entries"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// cacheSizes is the output of /debug/cachez?sizes=true.
+type cacheSizes struct {
+	Types   map[string]cacheTypeSummary `json:"types"`
+	Largest []model.XdsCacheEntryInfo   `json:"largest"`
+}
+
 func (s *DiscoveryServer) cachez(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Query().Get("sizes") != "" {
+		s.cacheSizez(w, req)
+		return
+	}
 	keys := s.Cache.Keys()
 	sort.Strings(keys)
 	bytes, err := json.Marshal(keys)
@@ -278,6 +316,64 @@ func (s *DiscoveryServer) cachez(w http.ResponseWriter, req *http.Request) {
 	_, _ = w.Write(bytes)
 }
 
+// cacheSizez writes the size of the XDS cache per resource type and the largest entries, with the configs
+// invalidating them. The number of entries is limited by the limit parameter, 20 by default.
+func (s *DiscoveryServer) cacheSizez(w http.ResponseWriter, req *http.Request) {
+	limit := 20
+	if l := req.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprintf(w, "invalid limit %q", l)
+			return
+		}
+		limit = n
+	}
+	entries := s.Cache.Entries()
+	out := cacheSizes{Types: map[string]cacheTypeSummary{}}
+	for _, e := range entries {
+		t := out.Types[v3.GetMetricType(e.Type)]
+		t.Entries++
+		t.Bytes += e.Size
+		out.Types[v3.GetMetricType(e.Type)] = t
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Size != entries[j].Size {
+			return entries[i].Size > entries[j].Size
+		}
+		return entries[i].Key < entries[j].Key
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	out.Largest = entries
+	b, err := json.MarshalIndent(&out, "", "  ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = fmt.Fprintf(w, "unable to marshal cache sizes: %v", err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+// configFilez writes the errors found in the files of the file config sources, keyed by path.
+// The files without errors are not listed.
+func (s *DiscoveryServer) configFilez(w http.ResponseWriter, _ *http.Request) {
+	out := map[string][]string{}
+	if s.ConfigFileErrors != nil {
+		out = s.ConfigFileErrors()
+	}
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = fmt.Fprintf(w, "unable to marshal config file errors: %v", err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
 // Endpoint debugging
 func (s *DiscoveryServer) endpointz(w http.ResponseWriter, req *http.Request) {
 	_ = req.ParseForm()
@@ -362,6 +458,64 @@ func (s *DiscoveryServer) distributedVersions(w http.ResponseWriter, req *http.R
 	}
 }
 
+func (s *DiscoveryServer) configStatus(w http.ResponseWriter, req *http.Request) {
+	if !features.EnableDistributionTracking || s.StatusReporter == nil {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = fmt.Fprint(w, "Pilot Version tracking is disabled.  Please set the "+
+			"PILOT_ENABLE_CONFIG_DISTRIBUTION_TRACKING environment variable to true to enable.")
+		return
+	}
+	resourceID := req.URL.Query().Get("resource")
+	if resourceID == "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = fmt.Fprintf(w, "querystring parameter 'resource' is required")
+		return
+	}
+	proxyNamespace := req.URL.Query().Get("proxy_namespace")
+	latestVersion, changeTime := s.StatusReporter.QueryLastChange(resourceID)
+	now := time.Now()
+	knownVersions := make(map[string]string)
+	results := []ConfigStatus{}
+	for _, con := range s.Clients() {
+		con.proxy.RLock()
+		if proxyNamespace != "" && proxyNamespace != con.proxy.ConfigNamespace {
+			con.proxy.RUnlock()
+			continue
+		}
+		status := ConfigStatus{
+			ProxyID:       con.proxy.ID,
+			AckedVersion:  latestVersion,
+			LatestVersion: latestVersion,
+			Synced:        true,
+		}
+		con.proxy.RUnlock()
+		for _, typeURL := range []string{v3.ClusterType, v3.ListenerType, v3.RouteType} {
+			nonce, ackTime := s.StatusReporter.QueryLastAck(con.ConID, typeURL)
+			if !ackTime.IsZero() && (status.LastAck == nil || ackTime.Before(*status.LastAck)) {
+				ackTime := ackTime
+				status.LastAck = &ackTime
+			}
+			if version := s.getResourceVersion(nonce, resourceID, knownVersions); status.Synced && version != latestVersion {
+				status.AckedVersion = version
+				status.Synced = false
+			}
+		}
+		if !status.Synced && !changeTime.IsZero() {
+			status.Lag = now.Sub(changeTime)
+		}
+		results = append(results, status)
+	}
+
+	out, err := json.MarshalIndent(&results, "", "    ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = fmt.Fprintf(w, "unable to marshal config status information: %v", err)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	_, _ = w.Write(out)
+}
+
 // The Config Version is only used as the nonce prefix, but we can reconstruct it because is is a
 // b64 encoding of a 64 bit array, which will always be 12 chars in length.
 // len = ceil(bitlength/(2^6))+1
@@ -443,6 +597,89 @@ func (s *DiscoveryServer) sidecarz(w http.ResponseWriter, req *http.Request) {
 	_, _ = w.Write(by)
 }
 
+// EnvoyFilterPatchStatus holds debug information for an EnvoyFilter patch.
+type EnvoyFilterPatchStatus struct {
+	EnvoyFilter string `json:"envoyFilter"`
+	Priority    int32  `json:"priority"`
+	Index       int    `json:"index"`
+	ApplyTo     string `json:"applyTo"`
+	Operation   string `json:"operation"`
+	Context     string `json:"context,omitempty"`
+	Applied     bool   `json:"applied"`
+}
+
+// EnvoyFilterDebug holds the EnvoyFilter patches in the order they are applied, and whether they were applied.
+type EnvoyFilterDebug struct {
+	ProxyID     string                   `json:"proxyID,omitempty"`
+	PushVersion string                   `json:"pushVersion"`
+	Patches     []EnvoyFilterPatchStatus `json:"patches"`
+}
+
+func envoyFilterPatchStatus(cp *model.EnvoyFilterConfigPatchWrapper, applied bool) EnvoyFilterPatchStatus {
+	st := EnvoyFilterPatchStatus{
+		EnvoyFilter: cp.Key(),
+		Priority:    cp.Priority,
+		Index:       cp.Index,
+		ApplyTo:     cp.ApplyTo.String(),
+		Operation:   cp.Operation.String(),
+		Applied:     applied,
+	}
+	if cp.Match != nil {
+		st.Context = cp.Match.Context.String()
+	}
+	return st
+}
+
+// EnvoyFilter debugging. Without a proxyID, it lists the patches of all EnvoyFilters and whether they were applied
+// to any proxy. With a proxyID, it regenerates the configuration of the proxy and lists the patches matching the
+// proxy in their effective order, and whether they were applied.
+func (s *DiscoveryServer) envoyfilterz(w http.ResponseWriter, req *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+	push := s.globalPushContext()
+	proxyID := req.URL.Query().Get("proxyID")
+	out := EnvoyFilterDebug{ProxyID: proxyID, PushVersion: push.PushVersion, Patches: []EnvoyFilterPatchStatus{}}
+
+	if proxyID == "" {
+		for _, cp := range push.EnvoyFilterPatches() {
+			out.Patches = append(out.Patches, envoyFilterPatchStatus(cp, cp.Applied()))
+		}
+	} else {
+		con := s.getProxyConnection(proxyID)
+		if con == nil {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("Proxy not connected to this Pilot instance"))
+			return
+		}
+		// Regenerate the configuration so the applied patches reflect the current push context.
+		s.ConfigGenerator.BuildClusters(con.proxy, push)
+		s.ConfigGenerator.BuildListeners(con.proxy, push)
+		s.ConfigGenerator.BuildHTTPRoutes(con.proxy, push, con.Routes())
+		if efw := push.EnvoyFilters(con.proxy); efw != nil {
+			applyTo := make([]networking.EnvoyFilter_ApplyTo, 0, len(efw.Patches))
+			for k := range efw.Patches {
+				applyTo = append(applyTo, k)
+			}
+			sort.Slice(applyTo, func(i, j int) bool {
+				return applyTo[i] < applyTo[j]
+			})
+			for _, k := range applyTo {
+				for _, cp := range efw.Patches[k] {
+					_, applied := con.proxy.EnvoyFilterStatus.Applied(cp)
+					out.Patches = append(out.Patches, envoyFilterPatchStatus(cp, applied))
+				}
+			}
+		}
+	}
+
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+	_, _ = w.Write(b)
+}
+
 // Resource debugging.
 func (s *DiscoveryServer) resourcez(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Add("Content-Type", "application/json")
@@ -427,8 +427,18 @@ func (s *DiscoveryServer) pushDeltaXds(con *Connection, push *model.PushContext,
 
 	t0 := time.Now()
 
-	res, err := gen.Generate(con.proxy, push, w, req)
-	if err != nil || res == nil {
+	var res model.Resources
+	var removed []string
+	usedDelta := false
+	var err error
+	// Generators can only compute deltas against the resources already sent. When the client subscribes to
+	// new resources, they have to be generated in full.
+	if dgen, f := gen.(model.XdsDeltaResourceGenerator); f && subscribe == nil {
+		res, removed, usedDelta, err = dgen.GenerateDeltas(con.proxy, push, w, req)
+	} else {
+		res, err = gen.Generate(con.proxy, push, w, req)
+	}
+	if err != nil || res == nil || (usedDelta && len(res) == 0 && len(removed) == 0) {
 		// If we have nothing to send, report that we got an ACK for this version.
 		if s.StatusReporter != nil {
 			s.StatusReporter.RegisterEvent(con.ConID, w.TypeUrl, push.LedgerVersion)
@@ -459,18 +469,30 @@ func (s *DiscoveryServer) pushDeltaXds(con *Connection, push *model.PushContext,
 		Nonce:             nonce(push.LedgerVersion),
 		Resources:         deltaResponse,
 	}
-	// We take the set of watched resources and anything not in the response is sent as RemovedResources
-	// This is similar to SotW, but done on the server side instead of the client.
-	cur := sets.NewSet(w.ResourceNames...)
-	cur.Delete(extractNames(originalResponse)...)
-	resp.RemovedResources = cur.SortedList()
+	if usedDelta {
+		// The generator only returned the changed resources, and explicitly told us which ones were removed.
+		resp.RemovedResources = removed
+	} else {
+		// We take the set of watched resources and anything not in the response is sent as RemovedResources
+		// This is similar to SotW, but done on the server side instead of the client.
+		cur := sets.NewSet(w.ResourceNames...)
+		cur.Delete(extractNames(originalResponse)...)
+		resp.RemovedResources = cur.SortedList()
+	}
 	if len(resp.RemovedResources) > 0 {
 		log.Infof("ADS:%v REMOVE %v", v3.GetShortType(w.TypeUrl), resp.RemovedResources)
 	}
 	if isWildcardTypeURL(w.TypeUrl) {
 		// this is probably a bad idea...
 		con.proxy.Lock()
-		w.ResourceNames = extractNames(originalResponse)
+		if usedDelta {
+			names := sets.NewSet(w.ResourceNames...)
+			names.Insert(extractNames(originalResponse)...)
+			names.Delete(removed...)
+			w.ResourceNames = names.SortedList()
+		} else {
+			w.ResourceNames = extractNames(originalResponse)
+		}
 		con.proxy.Unlock()
 	}
 
@@ -478,6 +500,13 @@ func (s *DiscoveryServer) pushDeltaXds(con *Connection, push *model.PushContext,
 		recordSendError(w.TypeUrl, con.ConID, err)
 		return err
 	}
+	con.proxy.Lock()
+	if usedDelta {
+		recordDeltaPush(w.TypeUrl, w.LastSize, w.LastFullSize)
+	} else if subscribe == nil {
+		w.LastFullSize = w.LastSize
+	}
+	con.proxy.Unlock()
 
 	// Some types handle logs inside Generate, skip them here
 	// TODO because we filter out after the fact, SkipLogTypes report wrong info
@@ -485,11 +514,12 @@ func (s *DiscoveryServer) pushDeltaXds(con *Connection, push *model.PushContext,
 	if _, f := SkipLogTypes[w.TypeUrl]; !f {
 		if log.DebugEnabled() {
 			// Add additional information to logs when debug mode enabled
-			log.Infof("%s: PUSH for node:%s resources:%d size:%s nonce:%v version:%v",
-				v3.GetShortType(w.TypeUrl), con.proxy.ID, len(res), util.ByteCount(ResourceSize(res)), resp.Nonce, resp.SystemVersionInfo)
+			log.Infof("%s: PUSH for node:%s resources:%d removed:%d size:%s delta:%v nonce:%v version:%v",
+				v3.GetShortType(w.TypeUrl), con.proxy.ID, len(res), len(resp.RemovedResources), util.ByteCount(ResourceSize(res)),
+				usedDelta, resp.Nonce, resp.SystemVersionInfo)
 		} else {
-			log.Infof("%s: PUSH for node:%s resources:%d size:%s",
-				v3.GetShortType(w.TypeUrl), con.proxy.ID, len(res), util.ByteCount(ResourceSize(res)))
+			log.Infof("%s: PUSH for node:%s resources:%d removed:%d size:%s delta:%v",
+				v3.GetShortType(w.TypeUrl), con.proxy.ID, len(res), len(resp.RemovedResources), util.ByteCount(ResourceSize(res)), usedDelta)
 		}
 	}
 	return nil
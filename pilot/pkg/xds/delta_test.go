@@ -15,13 +15,18 @@
 package xds
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
 	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 
+	"istio.io/istio/pilot/pkg/model"
 	v3 "istio.io/istio/pilot/pkg/xds/v3"
 	"istio.io/istio/pilot/test/xdstest"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/protocol"
+	"istio.io/istio/pkg/config/schema/gvk"
 	"istio.io/istio/tests/util/leak"
 )
 
@@ -57,3 +62,106 @@ func TestDeltaAdsClusterUpdate(t *testing.T) {
 	// TODO: should we just respond with nothing here? Probably...
 	sendEDSReqAndVerify(nil, []string{"outbound|81||local.default.svc.cluster.local"}, []string{"outbound|80||local.default.svc.cluster.local"})
 }
+
+func TestDeltaAdsServiceUpdate(t *testing.T) {
+	s := NewFakeDiscoveryServer(t, FakeOptions{})
+	ads := s.ConnectDeltaADS().WithType(v3.ClusterType)
+	ads.RequestResponseAck(nil)
+
+	hostname := host.Name("delta.default.svc.cluster.local")
+	updateService := func(ports ...int) *discovery.DeltaDiscoveryResponse {
+		t.Helper()
+		if len(ports) == 0 {
+			s.Discovery.MemRegistry.RemoveService(hostname)
+		} else {
+			svc := &model.Service{
+				Hostname:   hostname,
+				Address:    "10.11.0.1",
+				Attributes: model.ServiceAttributes{Namespace: "default"},
+			}
+			for _, p := range ports {
+				svc.Ports = append(svc.Ports, &model.Port{Name: fmt.Sprintf("http-%d", p), Port: p, Protocol: protocol.HTTP})
+			}
+			s.Discovery.MemRegistry.AddService(hostname, svc)
+		}
+		s.Discovery.ConfigUpdate(&model.PushRequest{Full: true, ConfigsUpdated: map[model.ConfigKey]struct{}{
+			{Kind: gvk.ServiceEntry, Name: string(hostname), Namespace: "default"}: {},
+		}})
+		res := ads.ExpectResponse()
+		ads.Request(&discovery.DeltaDiscoveryRequest{ResponseNonce: res.Nonce})
+		return res
+	}
+	verify := func(res *discovery.DeltaDiscoveryResponse, wantResources, wantRemoved []string) {
+		t.Helper()
+		if got := extractNames(res.Resources); !listEqualUnordered(got, wantResources) {
+			t.Errorf("expected resources %v got %v", wantResources, got)
+		}
+		if !listEqualUnordered(res.RemovedResources, wantRemoved) {
+			t.Errorf("expected removed resources %v got %v", wantRemoved, res.RemovedResources)
+		}
+	}
+
+	// Only the clusters of the new service are pushed.
+	verify(updateService(80, 81), []string{"outbound|80||" + string(hostname), "outbound|81||" + string(hostname)}, nil)
+	// Removing a port removes its cluster.
+	verify(updateService(80), []string{"outbound|80||" + string(hostname)}, []string{"outbound|81||" + string(hostname)})
+	// Removing the service removes all of its clusters.
+	verify(updateService(), nil, []string{"outbound|80||" + string(hostname)})
+}
+
+func TestDeltaAdsEdsSubscribe(t *testing.T) {
+	s := NewFakeDiscoveryServer(t, FakeOptions{})
+	setEndpoint := func(hostname, ip string) {
+		s.Discovery.MemRegistry.SetEndpoints(hostname, "", []*model.IstioEndpoint{
+			{Address: ip, ServicePortName: "http-main", EndpointPort: 80},
+		})
+	}
+	s.Discovery.MemRegistry.AddHTTPService("a.default.svc.cluster.local", "10.10.0.1", 8080)
+	s.Discovery.MemRegistry.AddHTTPService("b.default.svc.cluster.local", "10.10.0.2", 8080)
+	setEndpoint("a.default.svc.cluster.local", "10.20.0.1")
+	setEndpoint("b.default.svc.cluster.local", "10.20.0.2")
+	clusterA := "outbound|8080||a.default.svc.cluster.local"
+	clusterB := "outbound|8080||b.default.svc.cluster.local"
+
+	ads := s.ConnectDeltaADS().WithType(v3.EndpointType)
+	nonce := ""
+	verify := func(res *discovery.DeltaDiscoveryResponse, expect map[string][]string) {
+		t.Helper()
+		nonce = res.Nonce
+		got := xdstest.ExtractLoadAssignments(xdstest.UnmarshalClusterLoadAssignment(t, ConvertDeltaToResponse(res.Resources)))
+		if !reflect.DeepEqual(expect, got) {
+			t.Fatalf("expected endpoints %v got %v", expect, got)
+		}
+		if len(res.RemovedResources) > 0 {
+			t.Fatalf("expected no removed resources, got %v", res.RemovedResources)
+		}
+	}
+	subscribe := func(add, remove []string) *discovery.DeltaDiscoveryResponse {
+		t.Helper()
+		return ads.RequestResponseAck(&discovery.DeltaDiscoveryRequest{
+			ResourceNamesSubscribe:   add,
+			ResourceNamesUnsubscribe: remove,
+			ResponseNonce:            nonce,
+		})
+	}
+	expectPush := func() *discovery.DeltaDiscoveryResponse {
+		t.Helper()
+		res := ads.ExpectResponse()
+		ads.Request(&discovery.DeltaDiscoveryRequest{ResponseNonce: res.Nonce})
+		return res
+	}
+
+	// Subscribing only sends the endpoints of the new clusters.
+	verify(subscribe([]string{clusterA}, nil), map[string][]string{clusterA: {"10.20.0.1:80"}})
+	verify(subscribe([]string{clusterB}, nil), map[string][]string{clusterB: {"10.20.0.2:80"}})
+
+	// An endpoint update only pushes the endpoints of the updated service.
+	setEndpoint("a.default.svc.cluster.local", "10.20.0.3")
+	verify(expectPush(), map[string][]string{clusterA: {"10.20.0.3:80"}})
+
+	verify(subscribe(nil, []string{clusterB}), map[string][]string{clusterA: {"10.20.0.3:80"}})
+	// The endpoints of unsubscribed clusters are not pushed anymore.
+	setEndpoint("b.default.svc.cluster.local", "10.20.0.4")
+	setEndpoint("a.default.svc.cluster.local", "10.20.0.5")
+	verify(expectPush(), map[string][]string{clusterA: {"10.20.0.5:80"}})
+}
@@ -143,6 +143,9 @@ type DiscoveryServer struct {
 
 	// JwtKeyResolver holds a reference to the JWT key resolver instance.
 	JwtKeyResolver *model.JwksResolver
+
+	// ConfigFileErrors returns the errors found in the files of the file config sources, keyed by path.
+	ConfigFileErrors func() map[string][]string
 }
 
 // EndpointShards holds the set of endpoint shards of a service. Registries update
@@ -176,7 +179,7 @@ func NewDiscoveryServer(env *model.Environment, plugins []string, instanceID str
 		InboundUpdates:          atomic.NewInt64(0),
 		CommittedUpdates:        atomic.NewInt64(0),
 		pushChannel:             make(chan *model.PushRequest, 10),
-		pushQueue:               NewPushQueue(),
+		pushQueue:               NewPushQueueWithPolicy(pushQueuePolicyFromEnv()),
 		debugHandlers:           map[string]string{},
 		adsClients:              map[string]*Connection{},
 		debounceOptions: debounceOptions{
@@ -289,6 +292,7 @@ func (s *DiscoveryServer) periodicRefreshMetrics(stopCh <-chan struct{}) {
 				}
 			}
 			model.LastPushMutex.Unlock()
+			push.UpdateEnvoyFilterMetrics()
 		case <-stopCh:
 			return
 		}
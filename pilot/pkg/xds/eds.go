@@ -325,7 +325,7 @@ type EdsGenerator struct {
 	Server *DiscoveryServer
 }
 
-var _ model.XdsResourceGenerator = &EdsGenerator{}
+var _ model.XdsDeltaResourceGenerator = &EdsGenerator{}
 
 // Map of all configs that do not impact EDS
 var skippedEdsConfigs = map[config.GroupVersionKind]struct{}{
@@ -358,6 +358,44 @@ func (eds *EdsGenerator) Generate(proxy *model.Proxy, push *model.PushContext, w
 	if !req.Full {
 		edsUpdatedServices = model.ConfigNamesOfKind(req.ConfigsUpdated, gvk.ServiceEntry)
 	}
+	return eds.buildEndpoints(proxy, push, w, req, edsUpdatedServices), nil
+}
+
+// GenerateDeltas for EDS only builds the endpoints of the services updated by ServiceEntry changes, for both full
+// and incremental pushes. Endpoints are never removed, as Envoy unsubscribes from the endpoints of removed clusters.
+func (eds *EdsGenerator) GenerateDeltas(proxy *model.Proxy, push *model.PushContext, w *model.WatchedResource,
+	req *model.PushRequest) (model.Resources, []string, bool, error) {
+	if !edsNeedsPush(req.ConfigsUpdated) {
+		return nil, nil, false, nil
+	}
+	if !edsCanPushDeltas(req.ConfigsUpdated) {
+		res, err := eds.Generate(proxy, push, w, req)
+		return res, nil, false, err
+	}
+	edsUpdatedServices := model.ConfigNamesOfKind(req.ConfigsUpdated, gvk.ServiceEntry)
+	return eds.buildEndpoints(proxy, push, w, req, edsUpdatedServices), nil, true, nil
+}
+
+// edsCanPushDeltas returns true if the endpoints impacted by the updates are known, which is the case if
+// the updates only contain ServiceEntry changes, besides configs that do not impact EDS.
+func edsCanPushDeltas(updates model.XdsUpdates) bool {
+	hasServiceUpdates := false
+	for config := range updates {
+		if config.Kind == gvk.ServiceEntry {
+			hasServiceUpdates = true
+			continue
+		}
+		if _, f := skippedEdsConfigs[config.Kind]; !f {
+			return false
+		}
+	}
+	return hasServiceUpdates
+}
+
+// buildEndpoints builds the endpoints of the watched clusters. If edsUpdatedServices is not nil,
+// only the endpoints of those services are built.
+func (eds *EdsGenerator) buildEndpoints(proxy *model.Proxy, push *model.PushContext, w *model.WatchedResource,
+	req *model.PushRequest, edsUpdatedServices map[string]struct{}) model.Resources {
 	resources := make([]*any.Any, 0)
 	empty := 0
 
@@ -399,7 +437,7 @@ func (eds *EdsGenerator) Generate(proxy *model.Proxy, push *model.PushContext, w
 		log.Debugf("EDS: PUSH INC%s for node:%s clusters:%d size:%s empty:%v cached:%v/%v",
 			req.PushReason(), proxy.ID, len(resources), util.ByteCount(ResourceSize(resources)), empty, cached, cached+regenerated)
 	}
-	return resources, nil
+	return resources
 }
 
 func getOutlierDetectionAndLoadBalancerSettings(
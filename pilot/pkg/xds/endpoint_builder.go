@@ -29,6 +29,7 @@ import (
 	"istio.io/istio/pilot/pkg/networking"
 	"istio.io/istio/pilot/pkg/networking/util"
 	"istio.io/istio/pilot/pkg/security/authn/factory"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
 	"istio.io/istio/pkg/config"
 	"istio.io/istio/pkg/config/host"
 	"istio.io/istio/pkg/config/labels"
@@ -130,6 +131,10 @@ func (b EndpointBuilder) Key() string {
 	return strings.Join(params, "~")
 }
 
+func (b EndpointBuilder) TypeURL() string {
+	return v3.EndpointType
+}
+
 // MultiNetworkConfigured determines if we have gateways to use for building cross-network endpoints.
 func (b *EndpointBuilder) MultiNetworkConfigured() bool {
 	return b.push.NetworkGateways() != nil && len(b.push.NetworkGateways()) > 0
@@ -14,7 +14,11 @@
 
 package xds
 
-import v3 "istio.io/istio/pilot/pkg/xds/v3"
+import (
+	"time"
+
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
+)
 
 // EventType represents the type of object we are tracking, mapping to envoy TypeUrl.
 type EventType = string
@@ -36,4 +40,8 @@ type DistributionStatusCache interface {
 	RegisterEvent(conID string, eventType EventType, nonce string)
 	RegisterDisconnect(s string, types []EventType)
 	QueryLastNonce(conID string, eventType EventType) (noncePrefix string)
+	// QueryLastAck is similar to QueryLastNonce, but also returns when the nonce was first acked.
+	QueryLastAck(conID string, eventType EventType) (noncePrefix string, ackTime time.Time)
+	// QueryLastChange returns the latest version of a resource, and when it was observed, if known.
+	QueryLastChange(key string) (version string, changeTime time.Time)
 }
@@ -61,6 +61,8 @@ type FakeOptions struct {
 	KubernetesObjectString string
 	// Endpoint mode for the Kubernetes service registry
 	KubernetesEndpointMode kube.EndpointMode
+	// Additional service registries to use
+	ServiceRegistries []serviceregistry.Instance
 	// If provided, these configs will be used directly
 	Configs []config.Config
 	// If provided, the yaml string will be parsed and used as configs
@@ -156,6 +158,7 @@ func NewFakeDiscoveryServer(t test.Failer, opts FakeOptions) *FakeDiscoveryServe
 		}
 		registries = append(registries, k8s)
 	}
+	registries = append(registries, opts.ServiceRegistries...)
 
 	sc := kubesecrets.NewMulticluster(defaultKubeClient, "", "", stop)
 	s.Generators[v3.SecretType] = NewSecretGen(sc, &model.DisabledCache{})
@@ -0,0 +1,160 @@
+// +build !agent
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"sort"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/serviceregistry"
+	memregistry "istio.io/istio/pilot/pkg/serviceregistry/memory"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/labels"
+	"istio.io/istio/pkg/config/mesh"
+	"istio.io/istio/pkg/test"
+)
+
+// NewFakeDiscoveryServerFromSnapshot builds a FakeDiscoveryServer with the state captured in the snapshot,
+// so the xDS of any proxy of the mesh can be generated again. The configs, mesh config and mesh networks
+// of opts are replaced by the ones of the snapshot.
+func NewFakeDiscoveryServerFromSnapshot(t test.Failer, snapshot *Snapshot, opts FakeOptions) *FakeDiscoveryServer {
+	opts.Configs = snapshot.Configs
+	opts.ConfigString = ""
+	opts.MeshConfig = snapshot.MeshConfig
+	opts.NetworksWatcher = mesh.NewFixedNetworksWatcher(snapshot.MeshNetworks)
+	opts.ServiceRegistries = append(opts.ServiceRegistries, serviceregistry.Simple{
+		ProviderID:       serviceregistry.Mock,
+		ServiceDiscovery: newSnapshotServiceDiscovery(snapshot),
+		Controller:       &memregistry.ServiceController{},
+	})
+	modifier := opts.DiscoveryServerModifier
+	opts.DiscoveryServerModifier = func(s *DiscoveryServer) {
+		for hostname, byNamespace := range snapshot.EndpointShards {
+			for namespace, shards := range byNamespace {
+				for cluster, endpoints := range shards {
+					s.EDSCacheUpdate(cluster, hostname, namespace, endpoints)
+				}
+			}
+		}
+		if modifier != nil {
+			modifier(s)
+		}
+	}
+	return NewFakeDiscoveryServer(t, opts)
+}
+
+// snapshotServiceDiscovery serves the services and endpoints of a Snapshot. The endpoints are served
+// as service instances, for the proxies and the services that do not use EDS.
+type snapshotServiceDiscovery struct {
+	services        []*model.Service
+	serviceAccounts map[host.Name]map[int][]string
+	networkGateways map[string][]*model.Gateway
+	// instances by hostname and namespace
+	instances map[host.Name]map[string][]*model.ServiceInstance
+	// instances by endpoint address
+	instancesByIP map[string][]*model.ServiceInstance
+}
+
+var _ model.ServiceDiscovery = &snapshotServiceDiscovery{}
+
+func newSnapshotServiceDiscovery(snapshot *Snapshot) *snapshotServiceDiscovery {
+	sd := &snapshotServiceDiscovery{
+		services:        snapshot.Services,
+		serviceAccounts: snapshot.ServiceAccounts,
+		networkGateways: snapshot.NetworkGateways,
+		instances:       map[host.Name]map[string][]*model.ServiceInstance{},
+		instancesByIP:   map[string][]*model.ServiceInstance{},
+	}
+	for _, svc := range snapshot.Services {
+		shards := snapshot.EndpointShards[string(svc.Hostname)][svc.Attributes.Namespace]
+		clusters := make([]string, 0, len(shards))
+		for cluster := range shards {
+			clusters = append(clusters, cluster)
+		}
+		sort.Strings(clusters)
+		if sd.instances[svc.Hostname] == nil {
+			sd.instances[svc.Hostname] = map[string][]*model.ServiceInstance{}
+		}
+		for _, cluster := range clusters {
+			for _, ep := range shards[cluster] {
+				port, f := svc.Ports.Get(ep.ServicePortName)
+				if !f {
+					continue
+				}
+				instance := &model.ServiceInstance{
+					Service:     svc,
+					ServicePort: port,
+					Endpoint:    ep,
+				}
+				sd.instances[svc.Hostname][svc.Attributes.Namespace] = append(sd.instances[svc.Hostname][svc.Attributes.Namespace], instance)
+				sd.instancesByIP[ep.Address] = append(sd.instancesByIP[ep.Address], instance)
+			}
+		}
+	}
+	return sd
+}
+
+func (sd *snapshotServiceDiscovery) Services() ([]*model.Service, error) {
+	return sd.services, nil
+}
+
+func (sd *snapshotServiceDiscovery) GetService(hostname host.Name) (*model.Service, error) {
+	for _, svc := range sd.services {
+		if svc.Hostname == hostname {
+			return svc, nil
+		}
+	}
+	return nil, nil
+}
+
+func (sd *snapshotServiceDiscovery) InstancesByPort(svc *model.Service, port int, labels labels.Collection) []*model.ServiceInstance {
+	out := make([]*model.ServiceInstance, 0)
+	for _, instance := range sd.instances[svc.Hostname][svc.Attributes.Namespace] {
+		if instance.ServicePort.Port == port && labels.HasSubsetOf(instance.Endpoint.Labels) {
+			out = append(out, instance)
+		}
+	}
+	return out
+}
+
+func (sd *snapshotServiceDiscovery) GetProxyServiceInstances(proxy *model.Proxy) []*model.ServiceInstance {
+	out := make([]*model.ServiceInstance, 0)
+	for _, ip := range proxy.IPAddresses {
+		out = append(out, sd.instancesByIP[ip]...)
+	}
+	return out
+}
+
+func (sd *snapshotServiceDiscovery) GetProxyWorkloadLabels(proxy *model.Proxy) labels.Collection {
+	for _, ip := range proxy.IPAddresses {
+		if instances := sd.instancesByIP[ip]; len(instances) > 0 {
+			return labels.Collection{instances[0].Endpoint.Labels}
+		}
+	}
+	return nil
+}
+
+func (sd *snapshotServiceDiscovery) GetIstioServiceAccounts(svc *model.Service, ports []int) []string {
+	out := make([]string, 0)
+	for _, port := range ports {
+		out = append(out, sd.serviceAccounts[svc.Hostname][port]...)
+	}
+	return out
+}
+
+func (sd *snapshotServiceDiscovery) NetworkGateways() map[string][]*model.Gateway {
+	return sd.networkGateways
+}
@@ -30,6 +30,7 @@ var (
 	nodeTag    = monitoring.MustCreateLabel("node")
 	typeTag    = monitoring.MustCreateLabel("type")
 	versionTag = monitoring.MustCreateLabel("version")
+	queueTag   = monitoring.MustCreateLabel("queue")
 
 	// pilot_total_xds_rejects should be used instead. This is for backwards compatibility
 	cdsReject = monitoring.NewGauge(
@@ -138,6 +139,19 @@ var (
 		[]float64{.1, .5, 1, 3, 5, 10, 20, 30},
 	)
 
+	pushQueueDepth = monitoring.NewGauge(
+		"pilot_push_queue_depth",
+		"Number of proxies waiting for a push, labeled by push queue.",
+		monitoring.WithLabels(queueTag),
+	)
+
+	pushQueueTime = monitoring.NewDistribution(
+		"pilot_push_queue_time",
+		"Time in seconds, a proxy is in the push queue before being dequeued, labeled by push queue.",
+		[]float64{.1, .5, 1, 3, 5, 10, 20, 30},
+		monitoring.WithLabels(queueTag),
+	)
+
 	pushTriggers = monitoring.NewSum(
 		"pilot_push_triggers",
 		"Total number of times a push was triggered, labeled by reason for the push.",
@@ -187,6 +201,21 @@ var (
 		monitoring.WithLabels(typeTag),
 		monitoring.WithUnit(monitoring.Bytes),
 	)
+
+	deltaPushedBytes = monitoring.NewSum(
+		"pilot_xds_delta_pushed_bytes",
+		"Total size of the resources pushed by delta generators.",
+		monitoring.WithLabels(typeTag),
+		monitoring.WithUnit(monitoring.Bytes),
+	)
+
+	// Comparing with pilot_xds_delta_pushed_bytes gives the savings of delta generation.
+	deltaFullPushBytes = monitoring.NewSum(
+		"pilot_xds_delta_full_push_equivalent_bytes",
+		"Estimated total size of the resources that would have been pushed without delta generators.",
+		monitoring.WithLabels(typeTag),
+		monitoring.WithUnit(monitoring.Bytes),
+	)
 )
 
 func recordXDSClients(version string, delta float64) {
@@ -250,6 +279,11 @@ func recordPushTime(xdsType string, duration time.Duration) {
 	pushes.With(typeTag.Value(v3.GetMetricType(xdsType))).Increment()
 }
 
+func recordDeltaPush(xdsType string, pushedBytes, fullPushBytes int) {
+	deltaPushedBytes.With(typeTag.Value(v3.GetMetricType(xdsType))).Record(float64(pushedBytes))
+	deltaFullPushBytes.With(typeTag.Value(v3.GetMetricType(xdsType))).Record(float64(fullPushBytes))
+}
+
 func init() {
 	monitoring.MustRegister(
 		cdsReject,
@@ -265,6 +299,8 @@ func init() {
 		pushTime,
 		proxiesConvergeDelay,
 		proxiesQueueTime,
+		pushQueueDepth,
+		pushQueueTime,
 		pushContextErrors,
 		totalXDSInternalErrors,
 		inboundUpdates,
@@ -274,5 +310,7 @@ func init() {
 		totalDelayedPushTimeouts,
 		pilotSDSCertificateErrors,
 		configSizeBytes,
+		deltaPushedBytes,
+		deltaFullPushBytes,
 	)
 }
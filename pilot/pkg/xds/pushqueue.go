@@ -15,11 +15,132 @@
 package xds
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"golang.org/x/time/rate"
+
+	"istio.io/istio/pilot/pkg/features"
 	"istio.io/istio/pilot/pkg/model"
 )
 
+const (
+	// PushQueueFairnessNamespace queues the connections by proxy type and namespace.
+	PushQueueFairnessNamespace = "namespace"
+	// PushQueueFairnessProxyType queues the connections by proxy type.
+	PushQueueFairnessProxyType = "proxy-type"
+
+	// defaultPushQueueKey is the key of the single queue used without fairness policy.
+	defaultPushQueueKey = "default"
+)
+
+// PushQueuePolicy configures how a PushQueue shares the pushes between the proxies. The connections are
+// grouped in queues by key, which are served in proportion to their weight, so that a churning group of
+// proxies does not delay the pushes of the others. Each queue can also be rate limited.
+type PushQueuePolicy struct {
+	// Key returns the key of the queue of a connection. All connections share a single queue if nil.
+	Key func(con *Connection) string
+	// Weights of the queues. A key without weight uses the weight of its proxy type, the part of the key
+	// before the first "/", or 1.
+	Weights map[string]float64
+	// QPS limits the rate of pushes of each queue. The rate is unlimited if 0.
+	QPS float64
+	// Burst is the number of pushes of a queue allowed above QPS.
+	Burst int
+}
+
+// NewPushQueuePolicy returns the policy of the fairness mode, either empty, PushQueueFairnessNamespace or
+// PushQueueFairnessProxyType. weights is a comma separated list of <key>=<weight>, where the key is a proxy
+// type such as router, or <proxy type>/<namespace> in namespace mode.
+func NewPushQueuePolicy(fairness, weights string, qps float64, burst int) (PushQueuePolicy, error) {
+	policy := PushQueuePolicy{QPS: qps, Burst: burst}
+	switch fairness {
+	case "":
+	case PushQueueFairnessProxyType:
+		policy.Key = proxyTypeKey
+	case PushQueueFairnessNamespace:
+		policy.Key = namespaceKey
+	default:
+		return PushQueuePolicy{}, fmt.Errorf("invalid push queue fairness %q, expected %s or %s",
+			fairness, PushQueueFairnessNamespace, PushQueueFairnessProxyType)
+	}
+	if qps < 0 {
+		return PushQueuePolicy{}, fmt.Errorf("invalid push queue QPS %v, must not be negative", qps)
+	}
+	if qps > 0 && burst < 1 {
+		return PushQueuePolicy{}, fmt.Errorf("invalid push queue burst %v, must be positive", burst)
+	}
+	policy.Weights = map[string]float64{}
+	for _, w := range strings.Split(weights, ",") {
+		w = strings.TrimSpace(w)
+		if w == "" {
+			continue
+		}
+		kv := strings.SplitN(w, "=", 2)
+		if len(kv) != 2 {
+			return PushQueuePolicy{}, fmt.Errorf("invalid weight %q, expected <key>=<weight>", w)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+		if err != nil {
+			return PushQueuePolicy{}, fmt.Errorf("invalid weight %q: %v", w, err)
+		}
+		if v <= 0 {
+			return PushQueuePolicy{}, fmt.Errorf("invalid weight %q, the weight must be positive", w)
+		}
+		policy.Weights[strings.TrimSpace(kv[0])] = v
+	}
+	return policy, nil
+}
+
+// pushQueuePolicyFromEnv returns the policy configured by the PILOT_PUSH_QUEUE_* variables. An invalid policy
+// falls back to a single FIFO queue.
+func pushQueuePolicyFromEnv() PushQueuePolicy {
+	policy, err := NewPushQueuePolicy(features.PushQueueFairness, features.PushQueueWeights,
+		features.PushQueueQPS, features.PushQueueBurst)
+	if err != nil {
+		log.Errorf("invalid push queue policy, using a single queue: %v", err)
+		return PushQueuePolicy{}
+	}
+	return policy
+}
+
+func proxyTypeKey(con *Connection) string {
+	if con.proxy == nil {
+		return "unknown"
+	}
+	return string(con.proxy.Type)
+}
+
+func namespaceKey(con *Connection) string {
+	if con.proxy == nil {
+		return "unknown"
+	}
+	return string(con.proxy.Type) + "/" + con.proxy.ConfigNamespace
+}
+
+func (p PushQueuePolicy) key(con *Connection) string {
+	if p.Key == nil {
+		return defaultPushQueueKey
+	}
+	return p.Key(con)
+}
+
+func (p PushQueuePolicy) weight(key string) float64 {
+	if w, f := p.Weights[key]; f {
+		return w
+	}
+	if i := strings.Index(key, "/"); i >= 0 {
+		if w, f := p.Weights[key[:i]]; f {
+			return w
+		}
+	}
+	return 1
+}
+
 type PushQueue struct {
 	cond *sync.Cond
 
@@ -27,22 +148,55 @@ type PushQueue struct {
 	// the PushRequest will be merged.
 	pending map[*Connection]*model.PushRequest
 
-	// queue maintains ordering of the queue
-	queue []*Connection
+	// queues maintain the ordering of the connections of each key
+	queues map[string]*keyQueue
+
+	// size is the number of connections in all queues
+	size int
+
+	// virtualTime is the pass of the last served queue. A queue becoming active starts from it, so that
+	// idle queues do not accumulate credit.
+	virtualTime float64
 
 	// processing stores all connections that have been Dequeue(), but not MarkDone().
 	// The value stored will be initially be nil, but may be populated if the connection is Enqueue().
 	// If model.PushRequest is not nil, it will be Enqueued again once MarkDone has been called.
 	processing map[*Connection]*model.PushRequest
 
+	policy PushQueuePolicy
+
+	// wakeup signals the waiters on Dequeue when a rate limited queue can be served again.
+	wakeup   *time.Timer
+	wakeupAt time.Time
+
 	shuttingDown bool
 }
 
+// keyQueue is the queue of the connections of a key. Queues are served by increasing pass, which is advanced
+// by the inverse of the weight on each push: a queue with twice the weight gets twice the pushes.
+type keyQueue struct {
+	key      string
+	queue    []*Connection
+	enqueued map[*Connection]time.Time
+	pass     float64
+	weight   float64
+	limiter  *rate.Limiter
+	lastPush time.Time
+}
+
+// NewPushQueue returns a single FIFO queue.
 func NewPushQueue() *PushQueue {
+	return NewPushQueueWithPolicy(PushQueuePolicy{})
+}
+
+// NewPushQueueWithPolicy returns a queue sharing the pushes according to the policy.
+func NewPushQueueWithPolicy(policy PushQueuePolicy) *PushQueue {
 	return &PushQueue{
 		pending:    make(map[*Connection]*model.PushRequest),
+		queues:     make(map[string]*keyQueue),
 		processing: make(map[*Connection]*model.PushRequest),
 		cond:       sync.NewCond(&sync.Mutex{}),
+		policy:     policy,
 	}
 }
 
@@ -68,28 +222,59 @@ func (p *PushQueue) Enqueue(con *Connection, pushRequest *model.PushRequest) {
 	}
 
 	p.pending[con] = pushRequest
-	p.queue = append(p.queue, con)
+	p.push(con)
 	// Signal waiters on Dequeue that a new item is available
 	p.cond.Signal()
 }
 
+// push adds the connection to the queue of its key. p.cond.L must be held.
+func (p *PushQueue) push(con *Connection) {
+	key := p.policy.key(con)
+	q, f := p.queues[key]
+	if !f {
+		q = &keyQueue{
+			key:      key,
+			enqueued: make(map[*Connection]time.Time),
+			weight:   p.policy.weight(key),
+		}
+		if p.policy.QPS > 0 {
+			q.limiter = rate.NewLimiter(rate.Limit(p.policy.QPS), p.policy.Burst)
+		}
+		p.queues[key] = q
+	}
+	if len(q.queue) == 0 && q.pass < p.virtualTime {
+		q.pass = p.virtualTime
+	}
+	q.queue = append(q.queue, con)
+	q.enqueued[con] = time.Now()
+	p.size++
+	pushQueueDepth.With(queueTag.Value(key)).Record(float64(len(q.queue)))
+}
+
 // Remove a proxy from the queue. If there are no proxies ready to be removed, this will block
 func (p *PushQueue) Dequeue() (con *Connection, request *model.PushRequest, shutdown bool) {
 	p.cond.L.Lock()
 	defer p.cond.L.Unlock()
 
-	// Block until there is one to remove. Enqueue will signal when one is added.
-	for len(p.queue) == 0 && !p.shuttingDown {
+	// Block until there is one to remove. Enqueue will signal when one is added, and the wakeup timer
+	// when a rate limited queue can be served again.
+	for {
+		if p.size == 0 && p.shuttingDown {
+			// We must be shutting down.
+			return nil, nil, true
+		}
+		if p.size > 0 {
+			now := time.Now()
+			q, delay := p.next(now)
+			if q != nil {
+				con = p.pop(q, now)
+				break
+			}
+			p.scheduleWakeup(now, delay)
+		}
 		p.cond.Wait()
 	}
 
-	if len(p.queue) == 0 {
-		// We must be shutting down.
-		return nil, nil, true
-	}
-
-	con, p.queue = p.queue[0], p.queue[1:]
-
 	request = p.pending[con]
 	delete(p.pending, con)
 
@@ -99,6 +284,85 @@ func (p *PushQueue) Dequeue() (con *Connection, request *model.PushRequest, shut
 	return con, request, false
 }
 
+// next returns the queue to serve, or the delay until a rate limited queue can be served. Rate limits are
+// ignored when shutting down, to drain the queues. p.cond.L must be held.
+func (p *PushQueue) next(now time.Time) (*keyQueue, time.Duration) {
+	active := make([]*keyQueue, 0, len(p.queues))
+	for key, q := range p.queues {
+		if len(q.queue) > 0 {
+			active = append(active, q)
+		} else if p.idle(q, now) {
+			delete(p.queues, key)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		if active[i].pass != active[j].pass {
+			return active[i].pass < active[j].pass
+		}
+		return active[i].key < active[j].key
+	})
+	delay := time.Duration(-1)
+	for _, q := range active {
+		if q.limiter == nil || p.shuttingDown || q.limiter.AllowN(now, 1) {
+			return q, 0
+		}
+		r := q.limiter.ReserveN(now, 1)
+		if d := r.DelayFrom(now); delay < 0 || d < delay {
+			delay = d
+		}
+		r.CancelAt(now)
+	}
+	return nil, delay
+}
+
+// idle returns true if dropping the empty queue loses no state: it has no credit left and its rate limit
+// is replenished. p.cond.L must be held.
+func (p *PushQueue) idle(q *keyQueue, now time.Time) bool {
+	if q.pass > p.virtualTime {
+		return false
+	}
+	if q.limiter != nil && now.Sub(q.lastPush).Seconds()*p.policy.QPS < float64(p.policy.Burst) {
+		return false
+	}
+	return true
+}
+
+// pop removes the first connection of the queue. p.cond.L must be held.
+func (p *PushQueue) pop(q *keyQueue, now time.Time) *Connection {
+	con := q.queue[0]
+	q.queue = q.queue[1:]
+	p.size--
+	p.virtualTime = q.pass
+	q.pass += 1 / q.weight
+	q.lastPush = now
+	pushQueueTime.With(queueTag.Value(q.key)).Record(now.Sub(q.enqueued[con]).Seconds())
+	delete(q.enqueued, con)
+	pushQueueDepth.With(queueTag.Value(q.key)).Record(float64(len(q.queue)))
+	return con
+}
+
+// scheduleWakeup wakes up the waiters on Dequeue after delay, unless they are woken up earlier.
+// p.cond.L must be held.
+func (p *PushQueue) scheduleWakeup(now time.Time, delay time.Duration) {
+	if delay < 0 {
+		return
+	}
+	at := now.Add(delay)
+	if p.wakeup != nil {
+		if !p.wakeupAt.After(at) {
+			return
+		}
+		p.wakeup.Stop()
+	}
+	p.wakeupAt = at
+	p.wakeup = time.AfterFunc(delay, func() {
+		p.cond.L.Lock()
+		defer p.cond.L.Unlock()
+		p.wakeup = nil
+		p.cond.Broadcast()
+	})
+}
+
 func (p *PushQueue) MarkDone(con *Connection) {
 	p.cond.L.Lock()
 	defer p.cond.L.Unlock()
@@ -109,7 +373,7 @@ func (p *PushQueue) MarkDone(con *Connection) {
 	// This means we need to add it back to the queue.
 	if request != nil {
 		p.pending[con] = request
-		p.queue = append(p.queue, con)
+		p.push(con)
 		p.cond.Signal()
 	}
 }
@@ -118,7 +382,7 @@ func (p *PushQueue) MarkDone(con *Connection) {
 func (p *PushQueue) Pending() int {
 	p.cond.L.Lock()
 	defer p.cond.L.Unlock()
-	return len(p.queue)
+	return p.size
 }
 
 // ShutDown will cause queue to ignore all new items added to it. As soon as the
@@ -128,5 +392,9 @@ func (p *PushQueue) ShutDown() {
 	p.cond.L.Lock()
 	defer p.cond.L.Unlock()
 	p.shuttingDown = true
+	if p.wakeup != nil {
+		p.wakeup.Stop()
+		p.wakeup = nil
+	}
 	p.cond.Broadcast()
 }
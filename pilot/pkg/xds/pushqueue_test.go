@@ -294,3 +294,135 @@ func TestProxyQueue(t *testing.T) {
 		}
 	})
 }
+
+func TestFairPushQueue(t *testing.T) {
+	leak.Check(t)
+	newConnection := func(id string, nodeType model.NodeType, ns string) *Connection {
+		return &Connection{ConID: id, proxy: &model.Proxy{Type: nodeType, ConfigNamespace: ns}}
+	}
+	dequeueAll := func(t *testing.T, p *PushQueue, n int) []*Connection {
+		t.Helper()
+		var got []*Connection
+		for i := 0; i < n; i++ {
+			con := getWithTimeout(p)
+			if con == nil {
+				t.Fatalf("timed out after %d dequeues", i)
+			}
+			got = append(got, con)
+			p.MarkDone(con)
+		}
+		return got
+	}
+
+	t.Run("churning namespace does not delay others", func(t *testing.T) {
+		policy, err := NewPushQueuePolicy(PushQueueFairnessNamespace, "", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := NewPushQueueWithPolicy(policy)
+		defer p.ShutDown()
+
+		for i := 0; i < 100; i++ {
+			p.Enqueue(newConnection(fmt.Sprintf("churn-%d", i), model.SidecarProxy, "churn"), &model.PushRequest{})
+		}
+		quiet := newConnection("quiet", model.SidecarProxy, "quiet")
+		p.Enqueue(quiet, &model.PushRequest{})
+
+		got := dequeueAll(t, p, 2)
+		if got[0] != quiet && got[1] != quiet {
+			t.Fatalf("expected the quiet namespace to be served among the first pushes, got %v and %v",
+				got[0].ConID, got[1].ConID)
+		}
+	})
+
+	t.Run("gateways are weighted", func(t *testing.T) {
+		policy, err := NewPushQueuePolicy(PushQueueFairnessProxyType, "router=4", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := NewPushQueueWithPolicy(policy)
+		defer p.ShutDown()
+
+		for i := 0; i < 50; i++ {
+			p.Enqueue(newConnection(fmt.Sprintf("sidecar-%d", i), model.SidecarProxy, "default"), &model.PushRequest{})
+			p.Enqueue(newConnection(fmt.Sprintf("gateway-%d", i), model.Router, "default"), &model.PushRequest{})
+		}
+		counts := map[model.NodeType]int{}
+		for _, con := range dequeueAll(t, p, 50) {
+			counts[con.proxy.Type]++
+		}
+		if counts[model.Router] != 40 || counts[model.SidecarProxy] != 10 {
+			t.Fatalf("expected gateways to get 4 times the pushes of sidecars, got %v", counts)
+		}
+		if p.Pending() != 50 {
+			t.Fatalf("expected 50 pending pushes, got %d", p.Pending())
+		}
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		policy, err := NewPushQueuePolicy(PushQueueFairnessNamespace, "", 10, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := NewPushQueueWithPolicy(policy)
+		defer p.ShutDown()
+
+		limited := []*Connection{
+			newConnection("limited-1", model.SidecarProxy, "limited"),
+			newConnection("limited-2", model.SidecarProxy, "limited"),
+		}
+		other := newConnection("other", model.SidecarProxy, "other")
+		p.Enqueue(limited[0], &model.PushRequest{})
+		p.Enqueue(limited[1], &model.PushRequest{})
+		p.Enqueue(other, &model.PushRequest{})
+
+		got := dequeueAll(t, p, 2)
+		if got[0] != limited[0] || got[1] != other {
+			t.Fatalf("expected the rate limited queue to be skipped, got %v and %v", got[0].ConID, got[1].ConID)
+		}
+		// The next push of the limited queue waits for a token
+		start := time.Now()
+		ExpectDequeue(t, p, limited[1])
+		if time.Since(start) < 50*time.Millisecond {
+			t.Fatalf("expected the push to be rate limited")
+		}
+	})
+}
+
+func TestNewPushQueuePolicy(t *testing.T) {
+	policy, err := NewPushQueuePolicy(PushQueueFairnessNamespace, "router=4, sidecar/prod=2", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]float64{
+		"router/istio-system": 4,
+		"sidecar/prod":        2,
+		"sidecar/default":     1,
+	} {
+		if got := policy.weight(key); got != want {
+			t.Errorf("weight of %s: got %v, want %v", key, got, want)
+		}
+	}
+	con := &Connection{proxy: &model.Proxy{Type: model.Router, ConfigNamespace: "istio-system"}}
+	if got := policy.key(con); got != "router/istio-system" {
+		t.Errorf("unexpected key %s", got)
+	}
+
+	for _, tt := range []struct {
+		fairness string
+		weights  string
+		qps      float64
+		burst    int
+	}{
+		{fairness: "pod"},
+		{weights: "router"},
+		{weights: "router=0"},
+		{weights: "router=high"},
+		{qps: -1},
+		{qps: 10, burst: 0},
+	} {
+		if _, err := NewPushQueuePolicy(tt.fairness, tt.weights, tt.qps, tt.burst); err == nil {
+			t.Errorf("expected an error for %+v", tt)
+		}
+	}
+}
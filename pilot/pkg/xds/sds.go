@@ -27,6 +27,7 @@ import (
 	"istio.io/istio/pilot/pkg/networking/util"
 	"istio.io/istio/pilot/pkg/secrets"
 	authnmodel "istio.io/istio/pilot/pkg/security/model"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
 	"istio.io/istio/pkg/config"
 	"istio.io/istio/pkg/config/schema/gvk"
 )
@@ -48,6 +49,10 @@ func (sr SecretResource) Key() string {
 	return "sds://" + sr.ResourceName
 }
 
+func (sr SecretResource) TypeURL() string {
+	return v3.SecretType
+}
+
 // DependentTypes is not needed; we know exactly which configs impact SDS, so we can scope at DependentConfigs level
 func (sr SecretResource) DependentTypes() []config.GroupVersionKind {
 	return nil
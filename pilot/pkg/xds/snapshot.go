@@ -0,0 +1,313 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+
+	meshconfig "istio.io/api/mesh/v1alpha1"
+	"istio.io/istio/pilot/pkg/config/kube/crd"
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/serviceregistry"
+	"istio.io/istio/pkg/config"
+	"istio.io/istio/pkg/config/host"
+	"istio.io/istio/pkg/config/schema/collection"
+	"istio.io/istio/pkg/config/schema/collections"
+	"istio.io/istio/pkg/config/schema/resource"
+)
+
+// Names of the files of a snapshot archive.
+const (
+	snapshotMeshConfigFile      = "mesh.json"
+	snapshotMeshNetworksFile    = "meshnetworks.json"
+	snapshotConfigsFile         = "configs.json"
+	snapshotServicesFile        = "services.json"
+	snapshotServiceAccountsFile = "serviceaccounts.json"
+	snapshotEndpointsFile       = "endpoints.json"
+	snapshotNetworkGatewaysFile = "networkgateways.json"
+)
+
+// Snapshot holds everything a PushContext is built from, so the xDS generated by istiod can be reproduced
+// outside of the cluster with NewFakeDiscoveryServerFromSnapshot.
+type Snapshot struct {
+	MeshConfig   *meshconfig.MeshConfig
+	MeshNetworks *meshconfig.MeshNetworks
+
+	// Configs contains all the configs of the config store, including ServiceEntry and WorkloadEntry.
+	Configs []config.Config
+
+	// Services contains the services discovered from the service registries. Services created from
+	// ServiceEntry configs are not included, they are built again from Configs.
+	Services []*model.Service
+
+	// ServiceAccounts contains the service accounts of each port of the services, by hostname.
+	ServiceAccounts map[host.Name]map[int][]string
+
+	// EndpointShards contains the endpoints of the services, by hostname, namespace and cluster.
+	EndpointShards map[string]map[string]map[string][]*model.IstioEndpoint
+
+	// NetworkGateways contains the gateways of each network.
+	NetworkGateways map[string][]*model.Gateway
+}
+
+// Snapshot captures the state the current PushContext was built from. Configs and endpoints are read
+// from the config store and the endpoint shards, so they may include changes that are not pushed yet.
+func (s *DiscoveryServer) Snapshot() (*Snapshot, error) {
+	push := s.globalPushContext()
+	snapshot := &Snapshot{
+		MeshConfig:      s.Env.Mesh(),
+		MeshNetworks:    s.Env.Networks(),
+		ServiceAccounts: push.ServiceAccounts,
+		EndpointShards:  map[string]map[string]map[string][]*model.IstioEndpoint{},
+		NetworkGateways: push.NetworkGateways(),
+	}
+
+	var err error
+	s.Env.IstioConfigStore.Schemas().ForEach(func(schema collection.Schema) bool {
+		var cfgs []config.Config
+		cfgs, err = s.Env.IstioConfigStore.List(schema.Resource().GroupVersionKind(), "")
+		if err != nil {
+			err = fmt.Errorf("failed to list %v: %v", schema.Resource().GroupVersionKind(), err)
+			return true
+		}
+		snapshot.Configs = append(snapshot.Configs, cfgs...)
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, byNamespace := range push.ServiceIndex.HostnameAndNamespace {
+		for _, svc := range byNamespace {
+			if svc.Attributes.ServiceRegistry == string(serviceregistry.External) {
+				continue
+			}
+			snapshot.Services = append(snapshot.Services, svc)
+		}
+	}
+	sort.Slice(snapshot.Services, func(i, j int) bool {
+		if snapshot.Services[i].Hostname != snapshot.Services[j].Hostname {
+			return snapshot.Services[i].Hostname < snapshot.Services[j].Hostname
+		}
+		return snapshot.Services[i].Attributes.Namespace < snapshot.Services[j].Attributes.Namespace
+	})
+
+	s.mutex.RLock()
+	for hostname, byNamespace := range s.EndpointShardsByService {
+		for namespace, shards := range byNamespace {
+			shards.mutex.RLock()
+			for cluster, endpoints := range shards.Shards {
+				if len(endpoints) == 0 {
+					continue
+				}
+				if snapshot.EndpointShards[hostname] == nil {
+					snapshot.EndpointShards[hostname] = map[string]map[string][]*model.IstioEndpoint{}
+				}
+				if snapshot.EndpointShards[hostname][namespace] == nil {
+					snapshot.EndpointShards[hostname][namespace] = map[string][]*model.IstioEndpoint{}
+				}
+				out := make([]*model.IstioEndpoint, 0, len(endpoints))
+				for _, ep := range endpoints {
+					// The Envoy endpoint is only a cache, built again from the other fields when needed.
+					cp := *ep
+					cp.EnvoyEndpoint = nil
+					out = append(out, &cp)
+				}
+				snapshot.EndpointShards[hostname][namespace][cluster] = out
+			}
+			shards.mutex.RUnlock()
+		}
+	}
+	s.mutex.RUnlock()
+
+	return snapshot, nil
+}
+
+// WriteArchive writes the snapshot as a gzipped tar archive, with a JSON file for each part of the snapshot.
+func (snapshot *Snapshot) WriteArchive(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	now := time.Now()
+	writeFile := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	writeJSON := func(name string, v interface{}) error {
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %v", name, err)
+		}
+		return writeFile(name, b)
+	}
+
+	m := &jsonpb.Marshaler{Indent: "  "}
+	mc, err := m.MarshalToString(snapshot.MeshConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %v", snapshotMeshConfigFile, err)
+	}
+	if err := writeFile(snapshotMeshConfigFile, []byte(mc)); err != nil {
+		return err
+	}
+	if snapshot.MeshNetworks != nil {
+		mn, err := m.MarshalToString(snapshot.MeshNetworks)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %v", snapshotMeshNetworksFile, err)
+		}
+		if err := writeFile(snapshotMeshNetworksFile, []byte(mn)); err != nil {
+			return err
+		}
+	}
+
+	configs := make([]kubernetesConfig, 0, len(snapshot.Configs))
+	for _, c := range snapshot.Configs {
+		configs = append(configs, kubernetesConfig{c})
+	}
+	files := []struct {
+		name  string
+		value interface{}
+	}{
+		{snapshotConfigsFile, configs},
+		{snapshotServicesFile, snapshot.Services},
+		{snapshotServiceAccountsFile, snapshot.ServiceAccounts},
+		{snapshotEndpointsFile, snapshot.EndpointShards},
+		{snapshotNetworkGatewaysFile, snapshot.NetworkGateways},
+	}
+	for _, f := range files {
+		if err := writeJSON(f.name, f.value); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// ReadSnapshot reads a snapshot archive written by Snapshot.WriteArchive.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot archive: %v", err)
+	}
+	defer gr.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot archive: %v", err)
+		}
+		if files[hdr.Name], err = ioutil.ReadAll(tr); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", hdr.Name, err)
+		}
+	}
+
+	snapshot := &Snapshot{}
+	mc, f := files[snapshotMeshConfigFile]
+	if !f {
+		return nil, fmt.Errorf("invalid snapshot archive: %s not found", snapshotMeshConfigFile)
+	}
+	snapshot.MeshConfig = &meshconfig.MeshConfig{}
+	if err := jsonpb.Unmarshal(bytes.NewReader(mc), snapshot.MeshConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %v", snapshotMeshConfigFile, err)
+	}
+	if mn, f := files[snapshotMeshNetworksFile]; f {
+		snapshot.MeshNetworks = &meshconfig.MeshNetworks{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(mn), snapshot.MeshNetworks); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %v", snapshotMeshNetworksFile, err)
+		}
+	}
+
+	var objects []crd.IstioKind
+	values := []struct {
+		name  string
+		value interface{}
+	}{
+		{snapshotConfigsFile, &objects},
+		{snapshotServicesFile, &snapshot.Services},
+		{snapshotServiceAccountsFile, &snapshot.ServiceAccounts},
+		{snapshotEndpointsFile, &snapshot.EndpointShards},
+		{snapshotNetworkGatewaysFile, &snapshot.NetworkGateways},
+	}
+	for _, v := range values {
+		b, f := files[v.name]
+		if !f {
+			continue
+		}
+		if err := json.Unmarshal(b, v.value); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %v", v.name, err)
+		}
+	}
+
+	for i := range objects {
+		obj := &objects[i]
+		gvk := obj.GroupVersionKind()
+		s, f := collections.PilotServiceApi.FindByGroupVersionKind(resource.FromKubernetesGVK(&gvk))
+		if !f {
+			return nil, fmt.Errorf("unknown config type %v for %s/%s", gvk, obj.Namespace, obj.Name)
+		}
+		cfg, err := crd.ConvertObject(s, obj, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert config %s/%s: %v", obj.Namespace, obj.Name, err)
+		}
+		snapshot.Configs = append(snapshot.Configs, *cfg)
+	}
+	return snapshot, nil
+}
+
+// Snapshot debugging
+func (s *DiscoveryServer) snapshotz(w http.ResponseWriter, _ *http.Request) {
+	snapshot, err := s.Snapshot()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+	// Build the archive before writing the headers, so a failure can still be reported.
+	var buf bytes.Buffer
+	if err := snapshot.WriteArchive(&buf); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+	w.Header().Add("Content-Type", "application/gzip")
+	w.Header().Add("Content-Disposition", "attachment; filename=istiod-snapshot.tar.gz")
+	_, _ = w.Write(buf.Bytes())
+}
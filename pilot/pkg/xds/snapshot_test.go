@@ -0,0 +1,181 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"istio.io/istio/pilot/pkg/model"
+)
+
+const snapshotKubeConfig = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+  namespace: default
+spec:
+  clusterIP: 10.0.0.1
+  selector:
+    app: app
+  ports:
+  - name: http
+    port: 80
+    targetPort: 8080
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app-0
+  namespace: default
+  labels:
+    app: app
+    version: v1
+spec:
+  serviceAccountName: app
+status:
+  podIP: 1.2.3.4
+  phase: Running
+---
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: app
+  namespace: default
+subsets:
+- addresses:
+  - ip: 1.2.3.4
+    targetRef:
+      kind: Pod
+      name: app-0
+      namespace: default
+  ports:
+  - name: http
+    port: 8080
+`
+
+const snapshotConfig = `
+apiVersion: networking.istio.io/v1alpha3
+kind: DestinationRule
+metadata:
+  name: app
+  namespace: default
+spec:
+  host: app.default.svc.cluster.local
+  subsets:
+  - name: v1
+    labels:
+      version: v1
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: app
+  namespace: default
+spec:
+  hosts:
+  - app.default.svc.cluster.local
+  http:
+  - route:
+    - destination:
+        host: app.default.svc.cluster.local
+        subset: v1
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: ServiceEntry
+metadata:
+  name: external
+  namespace: default
+spec:
+  hosts:
+  - external.example.com
+  ports:
+  - number: 443
+    name: tls
+    protocol: TLS
+  resolution: STATIC
+  endpoints:
+  - address: 2.2.2.2
+`
+
+func TestSnapshot(t *testing.T) {
+	s := NewFakeDiscoveryServer(t, FakeOptions{
+		KubernetesObjectString: snapshotKubeConfig,
+		ConfigString:           snapshotConfig,
+	})
+
+	// Round trip through the debug endpoint, as done when reproducing an issue.
+	rr := httptest.NewRecorder()
+	s.Discovery.snapshotz(rr, httptest.NewRequest(http.MethodGet, "/debug/snapshotz", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rr.Code, rr.Body.String())
+	}
+	snapshot, err := ReadSnapshot(bytes.NewReader(rr.Body.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot.Configs) != 3 {
+		t.Fatalf("expected 3 configs, got %d", len(snapshot.Configs))
+	}
+	for _, svc := range snapshot.Services {
+		if svc.Hostname == "external.example.com" {
+			t.Fatal("services built from service entries must not be in the snapshot")
+		}
+	}
+	if eps := snapshot.EndpointShards["app.default.svc.cluster.local"]["default"]["Kubernetes"]; len(eps) != 1 || eps[0].EnvoyEndpoint != nil {
+		t.Fatalf("unexpected endpoints %v", eps)
+	}
+
+	loaded := NewFakeDiscoveryServerFromSnapshot(t, snapshot, FakeOptions{})
+
+	for _, ip := range []string{"1.2.3.4", "1.1.1.1"} {
+		t.Run(ip, func(t *testing.T) {
+			proxy := func(f *FakeDiscoveryServer) *model.Proxy {
+				return f.SetupProxy(&model.Proxy{
+					IPAddresses: []string{ip},
+					Metadata:    &model.NodeMetadata{ClusterID: "Kubernetes"},
+				})
+			}
+			want, got := proxy(s), proxy(loaded)
+			if len(want.ServiceInstances) != len(got.ServiceInstances) {
+				t.Fatalf("got %d service instances, want %d", len(got.ServiceInstances), len(want.ServiceInstances))
+			}
+			if diff := cmp.Diff(s.Listeners(want), loaded.Listeners(got), protocmp.Transform()); diff != "" {
+				t.Fatalf("listeners differ: %v", diff)
+			}
+			if diff := cmp.Diff(s.Clusters(want), loaded.Clusters(got), protocmp.Transform()); diff != "" {
+				t.Fatalf("clusters differ: %v", diff)
+			}
+			if diff := cmp.Diff(s.Routes(want), loaded.Routes(got), protocmp.Transform()); diff != "" {
+				t.Fatalf("routes differ: %v", diff)
+			}
+			if diff := cmp.Diff(s.Endpoints(want), loaded.Endpoints(got), protocmp.Transform()); diff != "" {
+				t.Fatalf("endpoints differ: %v", diff)
+			}
+		})
+	}
+}
+
+func TestReadSnapshotInvalid(t *testing.T) {
+	if _, err := ReadSnapshot(bytes.NewReader([]byte("not an archive"))); err == nil {
+		t.Fatal("expected an error for an invalid archive")
+	}
+}
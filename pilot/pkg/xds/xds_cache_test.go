@@ -25,6 +25,7 @@ import (
 	"go.uber.org/atomic"
 
 	"istio.io/istio/pilot/pkg/model"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
 	"istio.io/istio/pkg/config"
 	"istio.io/istio/pkg/config/schema/gvk"
 	"istio.io/istio/pkg/test/util/retry"
@@ -216,3 +217,94 @@ func TestXdsCache(t *testing.T) {
 		}
 	})
 }
+
+func TestXdsCacheBudget(t *testing.T) {
+	mkv := func(size int) *any.Any {
+		return &any.Any{TypeUrl: v3.EndpointType, Value: make([]byte, size)}
+	}
+	ep := func(i int) EndpointBuilder {
+		return EndpointBuilder{
+			clusterName: fmt.Sprintf("outbound|%d||foo.com", i),
+			service:     &model.Service{Hostname: "foo.com"},
+		}
+	}
+	addWithToken := func(c model.XdsCache, entry model.XdsCacheEntry, value *any.Any) {
+		_, tok, _ := c.Get(entry)
+		c.Add(entry, tok, value)
+	}
+	secret := SecretResource{ResourceName: "kubernetes://default/foo", Name: "foo", Namespace: "default"}
+
+	c := model.NewLenientBudgetedXdsCache(map[string]int64{v3.EndpointType: 10})
+	addWithToken(c, ep(1), mkv(4))
+	addWithToken(c, ep(2), mkv(4))
+	addWithToken(c, secret, &any.Any{TypeUrl: v3.SecretType, Value: make([]byte, 100)})
+	if len(c.Keys()) != 3 {
+		t.Fatalf("expected 3 keys, got: %v", c.Keys())
+	}
+
+	// Going over the budget evicts the least recently used entries of the same type.
+	addWithToken(c, ep(3), mkv(4))
+	if _, _, f := c.Get(ep(1)); f {
+		t.Fatalf("expected %s to be evicted", ep(1).Key())
+	}
+	for _, e := range []model.XdsCacheEntry{ep(2), ep(3), secret} {
+		if _, _, f := c.Get(e); !f {
+			t.Fatalf("expected %s to be cached", e.Key())
+		}
+	}
+
+	// Values larger than the budget are not cached.
+	addWithToken(c, ep(4), mkv(11))
+	if _, _, f := c.Get(ep(4)); f {
+		t.Fatalf("expected %s not to be cached", ep(4).Key())
+	}
+	if _, _, f := c.Get(ep(2)); !f {
+		t.Fatalf("expected %s to be cached", ep(2).Key())
+	}
+
+	// Replacing a value accounts for the size of the replaced value.
+	addWithToken(c, ep(2), mkv(6))
+	for _, e := range []model.XdsCacheEntry{ep(2), ep(3)} {
+		if _, _, f := c.Get(e); !f {
+			t.Fatalf("expected %s to be cached", e.Key())
+		}
+	}
+
+	sizes := map[string]int64{}
+	for _, e := range c.Entries() {
+		sizes[e.Key] = e.Size
+		if e.Key == ep(2).Key() && !reflect.DeepEqual(e.DependentConfigs, []string{"ServiceEntry//foo.com"}) {
+			t.Fatalf("unexpected dependent configs %v", e.DependentConfigs)
+		}
+	}
+	want := map[string]int64{ep(2).Key(): 6, ep(3).Key(): 4, secret.Key(): 100}
+	if !reflect.DeepEqual(sizes, want) {
+		t.Fatalf("got sizes %v, want %v", sizes, want)
+	}
+}
+
+func TestParseXdsCacheBudgets(t *testing.T) {
+	cases := []struct {
+		in   string
+		want map[string]int64
+		err  bool
+	}{
+		{in: "", want: nil},
+		{in: "eds=1Ki, SDS=100", want: map[string]int64{v3.EndpointType: 1024, v3.SecretType: 100}},
+		{in: "eds", err: true},
+		{in: "foo=1Mi", err: true},
+		{in: "cds=1Mi", err: true},
+		{in: "rds=1Mi", err: true},
+		{in: "eds=abc", err: true},
+		{in: "eds=0", err: true},
+	}
+	for _, tt := range cases {
+		got, err := model.ParseXdsCacheBudgets(tt.in)
+		if tt.err != (err != nil) {
+			t.Fatalf("%q: unexpected error %v", tt.in, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%q: got %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package simulationtest contains the helpers to run simulations from Go tests.
+package simulationtest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/core/v1alpha3"
+	"istio.io/istio/pilot/pkg/simulation"
+	"istio.io/istio/pilot/pkg/xds"
+)
+
+// Expect is the expected result of a simulated call.
+type Expect struct {
+	Name   string
+	Call   simulation.Call
+	Result simulation.Result
+}
+
+// Matches fails the test if the result of a simulation does not match the expected result.
+func Matches(t *testing.T, r, want simulation.Result) {
+	r.StrictMatch = want.StrictMatch // to make diff pass
+	r.Skip = want.Skip               // to make diff pass
+	diff := cmp.Diff(want, r, cmpopts.IgnoreUnexported(simulation.Result{}), cmpopts.EquateErrors())
+	if want.StrictMatch && diff != "" {
+		t.Errorf("Diff: %v", diff)
+		return
+	}
+	if want.Error != r.Error {
+		t.Errorf("want error %v got %v", want.Error, r.Error)
+	}
+	if want.ListenerMatched != "" && want.ListenerMatched != r.ListenerMatched {
+		t.Errorf("want listener matched %q got %q", want.ListenerMatched, r.ListenerMatched)
+	}
+	if want.FilterChainMatched != "" && want.FilterChainMatched != r.FilterChainMatched {
+		t.Errorf("want filter chain matched %q got %q", want.FilterChainMatched, r.FilterChainMatched)
+	}
+	if want.RouteMatched != "" && want.RouteMatched != r.RouteMatched {
+		t.Errorf("want route matched %q got %q", want.RouteMatched, r.RouteMatched)
+	}
+	if want.RouteConfigMatched != "" && want.RouteConfigMatched != r.RouteConfigMatched {
+		t.Errorf("want route config matched %q got %q", want.RouteConfigMatched, r.RouteConfigMatched)
+	}
+	if want.VirtualHostMatched != "" && want.VirtualHostMatched != r.VirtualHostMatched {
+		t.Errorf("want virtual host matched %q got %q", want.VirtualHostMatched, r.VirtualHostMatched)
+	}
+	if want.ClusterMatched != "" && want.ClusterMatched != r.ClusterMatched {
+		t.Errorf("want cluster matched %q got %q", want.ClusterMatched, r.ClusterMatched)
+	}
+	if t.Failed() {
+		t.Logf("Diff: %+v", diff)
+	} else if want.Skip != "" {
+		t.Skip(fmt.Sprintf("Known bug: %v", r.Skip))
+	}
+}
+
+func NewSimulationFromConfigGen(s *v1alpha3.ConfigGenTest, proxy *model.Proxy) *simulation.Simulation {
+	sim := &simulation.Simulation{
+		Listeners: s.Listeners(proxy),
+		Clusters:  s.Clusters(proxy),
+		Routes:    s.Routes(proxy),
+	}
+	return sim
+}
+
+func NewSimulation(s *xds.FakeDiscoveryServer, proxy *model.Proxy) *simulation.Simulation {
+	return NewSimulationFromConfigGen(s.ConfigGenTest, proxy)
+}
+
+// RunExpectations runs each call in a subtest, checking its result.
+func RunExpectations(t *testing.T, sim *simulation.Simulation, es []Expect) {
+	for _, e := range es {
+		t.Run(e.Name, func(t *testing.T) {
+			Matches(t, sim.Run(e.Call), e.Result)
+		})
+	}
+}
@@ -133,4 +133,9 @@ const (
 
 	// TrustworthyJWTPath is the defaut 3P token to authenticate with third party services
 	TrustworthyJWTPath = "./var/run/secrets/tokens/istio-token"
+
+	// EnvoyFilterPriorityAnnotation sets the priority of an EnvoyFilter, a signed 32 bits integer defaulting to 0.
+	// The patches of the EnvoyFilters with the lowest priority are applied first. EnvoyFilters of the same priority
+	// are applied in creation order, the ones of the config root namespace before the ones of the workload namespace.
+	EnvoyFilterPriorityAnnotation = "envoyfilter.istio.io/priority"
 )
@@ -681,6 +681,12 @@ var ValidateEnvoyFilter = registerValidateFunc("ValidateEnvoyFilter",
 			return nil, err
 		}
 
+		if priority, f := cfg.Annotations[constants.EnvoyFilterPriorityAnnotation]; f {
+			if _, err := strconv.ParseInt(priority, 10, 32); err != nil {
+				errs = appendValidation(errs, fmt.Errorf("Envoy filter: invalid priority %q: must be a 32 bits integer", priority)) // nolint: golint,stylecheck
+			}
+		}
+
 		for _, cp := range rule.ConfigPatches {
 			if cp == nil {
 				errs = appendValidation(errs, fmt.Errorf("Envoy filter: null config patch")) // nolint: golint,stylecheck
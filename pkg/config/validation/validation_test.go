@@ -3710,6 +3710,32 @@ func TestValidateEnvoyFilter(t *testing.T) {
 	}
 }
 
+func TestValidateEnvoyFilterPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority string
+		error    string
+	}{
+		{name: "negative", priority: "-10"},
+		{name: "positive", priority: "100"},
+		{name: "not a number", priority: "high", error: `Envoy filter: invalid priority "high"`},
+		{name: "overflow", priority: "4294967296", error: `Envoy filter: invalid priority "4294967296"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warn, err := ValidateEnvoyFilter(config.Config{
+				Meta: config.Meta{
+					Name:        someName,
+					Namespace:   someNamespace,
+					Annotations: map[string]string{constants.EnvoyFilterPriorityAnnotation: tt.priority},
+				},
+				Spec: &networking.EnvoyFilter{},
+			})
+			checkValidationMessage(t, warn, err, "", tt.error)
+		})
+	}
+}
+
 func TestValidateServiceEntries(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -21,6 +21,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	mesh "istio.io/api/mesh/v1alpha1"
 	"istio.io/istio/pilot/pkg/dns"
@@ -107,6 +108,9 @@ type AgentOptions struct {
 	// DNSCapture indicates if the XDS proxy has dns capture enabled or not
 	// This option will not be considered if proxyXDSViaAgent is false.
 	DNSCapture bool
+	// DNSOptions tunes the TTL of the name table answers and the cache of upstream responses
+	// of the local DNS server, when DNSCapture is enabled.
+	DNSOptions dns.Options
 	// ProxyType is the type of proxy we are configured to handle
 	ProxyType model.NodeType
 	// ProxyNamespace to use for local dns resolution
@@ -137,6 +141,13 @@ type AgentOptions struct {
 
 	// All of the proxy's IP Addresses
 	ProxyIPAddresses []string
+
+	// XdsSnapshotDir, if set, is the directory where the XDS proxy saves the last responses acked by Envoy,
+	// to serve them when istiod cannot be reached at startup.
+	XdsSnapshotDir string
+
+	// XdsSnapshotMaxAge is the maximum age of the saved responses served to Envoy. Zero means no limit.
+	XdsSnapshotMaxAge time.Duration
 }
 
 // NewAgent hosts the functionality for local SDS and XDS. This consists of the local SDS server and
@@ -192,7 +203,7 @@ func (a *Agent) Start() error {
 func (a *Agent) initLocalDNSServer() (err error) {
 	// we dont need dns server on gateways
 	if a.cfg.DNSCapture && a.cfg.ProxyXDSViaAgent && a.cfg.ProxyType == model.SidecarProxy {
-		if a.localDNSServer, err = dns.NewLocalDNSServer(a.cfg.ProxyNamespace, a.cfg.ProxyDomain); err != nil {
+		if a.localDNSServer, err = dns.NewLocalDNSServer(a.cfg.ProxyNamespace, a.cfg.ProxyDomain, a.cfg.DNSOptions); err != nil {
 			return err
 		}
 		a.localDNSServer.StartDNS()
@@ -55,6 +55,12 @@ var (
 		"The total number of Xds Proxy Responses",
 	)
 
+	// XdsProxySnapshotResponses records total number of responses served from the saved xDS snapshot.
+	XdsProxySnapshotResponses = monitoring.NewSum(
+		"xds_proxy_snapshot_responses",
+		"The total number of Xds Proxy responses served from the saved snapshot while Istiod is unreachable",
+	)
+
 	IstiodConnectionCancellations = istiodDisconnections.With(disconnectionTypeTag.Value(Cancel))
 	IstiodConnectionErrors        = istiodDisconnections.With(disconnectionTypeTag.Value(Error))
 	EnvoyConnectionCancellations  = envoyDisconnections.With(disconnectionTypeTag.Value(Cancel))
@@ -72,5 +78,6 @@ func init() {
 		IstiodConnectionErrors,
 		istiodDisconnections,
 		envoyDisconnections,
+		XdsProxySnapshotResponses,
 	)
 }
@@ -45,6 +45,7 @@ import (
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 
 	meshconfig "istio.io/api/mesh/v1alpha1"
 	"istio.io/istio/pilot/cmd/pilot-agent/status/ready"
@@ -118,6 +119,13 @@ type XdsProxy struct {
 	// in case istiod changes its behavior, or a different ECDS server is used.
 	ecdsLastAckVersion atomic.String
 	ecdsLastNonce      atomic.String
+
+	// snapshot saves the xDS responses acked by Envoy, to serve them when istiod cannot be reached at startup.
+	snapshot *xdsSnapshot
+	// snapshotProbeInterval is how often istiod is probed while the snapshot is served.
+	snapshotProbeInterval time.Duration
+	// upstreamSynced is set once a response has been received from istiod.
+	upstreamSynced atomic.Bool
 }
 
 var proxyLog = log.RegisterScope("xdsproxy", "XDS Proxy in Istio Agent", 0)
@@ -125,6 +133,8 @@ var proxyLog = log.RegisterScope("xdsproxy", "XDS Proxy in Istio Agent", 0)
 const (
 	localHostIPv4 = "127.0.0.1"
 	localHostIPv6 = "[::1]"
+
+	defaultSnapshotProbeInterval = 5 * time.Second
 )
 
 func initXdsProxy(ia *Agent) (*XdsProxy, error) {
@@ -147,6 +157,13 @@ func initXdsProxy(ia *Agent) (*XdsProxy, error) {
 		xdsUdsPath:     ia.cfg.XdsUdsPath,
 		wasmCache:      wasm.NewLocalFileCache(constants.IstioDataDir, wasm.DefaultWasmModulePurgeInteval, wasm.DefaultWasmModuleExpiry),
 		proxyAddresses: ia.cfg.ProxyIPAddresses,
+
+		snapshotProbeInterval: defaultSnapshotProbeInterval,
+	}
+	if ia.cfg.XdsSnapshotDir != "" {
+		if proxy.snapshot, err = newXdsSnapshot(ia.cfg.XdsSnapshotDir, ia.cfg.XdsSnapshotMaxAge); err != nil {
+			return nil, err
+		}
 	}
 
 	if ia.localDNSServer != nil {
@@ -315,6 +332,9 @@ func (p *XdsProxy) StreamAggregatedResources(downstream discovery.AggregatedDisc
 			}
 			// forward to istiod
 			con.requestsChan <- req
+			if p.snapshot != nil {
+				p.snapshot.received(req)
+			}
 			if !initialRequestsSent && req.TypeUrl == v3.ListenerType {
 				// fire off an initial NDS request
 				if _, f := p.handlers[v3.NameTableType]; f {
@@ -343,7 +363,7 @@ func (p *XdsProxy) StreamAggregatedResources(downstream discovery.AggregatedDisc
 	if err != nil {
 		proxyLog.Errorf("failed to connect to upstream %s: %v", p.istiodAddress, err)
 		metrics.IstiodConnectionFailures.Increment()
-		return err
+		return p.serveSnapshot(con, err)
 	}
 	defer upstreamConn.Close()
 
@@ -362,7 +382,7 @@ func (p *XdsProxy) HandleUpstream(ctx context.Context, con *ProxyConnection, xds
 	if err != nil {
 		// Envoy logs errors again, so no need to log beyond debug level
 		proxyLog.Debugf("failed to create upstream grpc client: %v", err)
-		return err
+		return p.serveSnapshot(con, err)
 	}
 	proxyLog.Infof("connected to upstream XDS server: %s", p.istiodAddress)
 	defer proxyLog.Debugf("disconnected from XDS server: %s", p.istiodAddress)
@@ -446,6 +466,7 @@ func (p *XdsProxy) handleUpstreamResponse(con *ProxyConnection) {
 			// TODO: separate upstream response handling from requests sending, which are both time costly
 			proxyLog.Debugf("response for type url %s", resp.TypeUrl)
 			metrics.XdsProxyResponses.Increment()
+			p.upstreamSynced.Store(true)
 			if h, f := p.handlers[resp.TypeUrl]; f {
 				if len(resp.Resources) == 0 {
 					// Empty response, nothing to do
@@ -461,12 +482,17 @@ func (p *XdsProxy) handleUpstreamResponse(con *ProxyConnection) {
 					}
 				}
 				// Send ACK/NACK
-				con.requestsChan <- &discovery.DiscoveryRequest{
+				ack := &discovery.DiscoveryRequest{
 					VersionInfo:   resp.VersionInfo,
 					TypeUrl:       resp.TypeUrl,
 					ResponseNonce: resp.Nonce,
 					ErrorDetail:   errorResp,
 				}
+				if p.snapshot != nil {
+					p.snapshot.sent(resp)
+					p.snapshot.received(ack)
+				}
+				con.requestsChan <- ack
 				continue
 			}
 			switch resp.TypeUrl {
@@ -482,6 +508,9 @@ func (p *XdsProxy) handleUpstreamResponse(con *ProxyConnection) {
 				if strings.HasPrefix(resp.TypeUrl, "istio.io/debug") {
 					p.forwardToTap(resp)
 				} else {
+					if p.snapshot != nil {
+						p.snapshot.sent(resp)
+					}
 					forwardToEnvoy(con, resp)
 				}
 			}
@@ -510,6 +539,85 @@ func (p *XdsProxy) rewriteAndForward(con *ProxyConnection, resp *discovery.Disco
 	forwardToEnvoy(con, resp)
 }
 
+// serveSnapshot configures Envoy with the saved xDS responses when istiod cannot be reached before the first
+// response was received from it, e.g. when the pod restarts during an istiod outage. Each type is served once per
+// stream, and later requests, including ACKs, are dropped. Once istiod is reachable again the stream is terminated,
+// so that Envoy reconnects and resyncs through istiod while keeping its current configuration.
+// If no snapshot can be served, upstreamErr is returned.
+func (p *XdsProxy) serveSnapshot(con *ProxyConnection, upstreamErr error) error {
+	if p.snapshot == nil || p.upstreamSynced.Load() {
+		return upstreamErr
+	}
+	responses := p.snapshot.loadAll()
+	if len(responses) == 0 {
+		return upstreamErr
+	}
+	proxyLog.Warnf("upstream %s unreachable (%v), serving saved xDS snapshot", p.istiodAddress, upstreamErr)
+	for typeURL, resp := range responses {
+		if h, f := p.handlers[typeURL]; f && len(resp.Resources) > 0 {
+			if err := h(resp.Resources[0]); err != nil {
+				proxyLog.Warnf("failed to apply xDS snapshot for type url %s: %v", typeURL, err)
+			}
+		}
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	reachable := make(chan struct{})
+	go func() {
+		t := time.NewTicker(p.snapshotProbeInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				if p.upstreamReachable() {
+					close(reachable)
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	served := map[string]struct{}{}
+	for {
+		select {
+		case req := <-con.requestsChan:
+			resp, f := responses[req.TypeUrl]
+			if !f || !v3.IsEnvoyType(req.TypeUrl) {
+				continue
+			}
+			if _, f := served[req.TypeUrl]; f {
+				continue
+			}
+			served[req.TypeUrl] = struct{}{}
+			proxyLog.Infof("serving saved xDS snapshot for type url %s, version %s", req.TypeUrl, resp.VersionInfo)
+			metrics.XdsProxySnapshotResponses.Increment()
+			forwardToEnvoy(con, resp)
+		case <-reachable:
+			proxyLog.Infof("upstream %s reachable again, terminating the stream to resync", p.istiodAddress)
+			return status.Error(codes.Unavailable, "upstream reachable again")
+		case err := <-con.downstreamError:
+			return err
+		case <-con.stopChan:
+			return nil
+		}
+	}
+}
+
+// upstreamReachable returns whether a connection can be established to istiod.
+func (p *XdsProxy) upstreamReachable() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, p.istiodAddress, append(p.istiodDialOptions, grpc.WithBlock())...)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
 func (p *XdsProxy) forwardToTap(resp *discovery.DiscoveryResponse) {
 	select {
 	case p.tapResponseChannel <- resp:
@@ -0,0 +1,307 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package istioagent
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/protobuf/ptypes/any"
+	"google.golang.org/protobuf/proto"
+
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
+)
+
+// snapshotFormatVersion is the version of the format of the snapshot files. Files with another version are ignored.
+const snapshotFormatVersion = 1
+
+// snapshotTypes are the types of the xDS responses saved in the snapshot.
+var snapshotTypes = map[string]struct{}{
+	v3.ClusterType:   {},
+	v3.EndpointType:  {},
+	v3.ListenerType:  {},
+	v3.RouteType:     {},
+	v3.NameTableType: {},
+}
+
+// partialSnapshotTypes are the types whose responses may only hold some of the resources: a response updates
+// the resources it holds, the others are kept until Envoy stops requesting them.
+var partialSnapshotTypes = map[string]struct{}{
+	v3.EndpointType: {},
+	v3.RouteType:    {},
+}
+
+// maxPendingResponses bounds the responses of a type waiting for an ACK, in case Envoy never acks them.
+const maxPendingResponses = 16
+
+// snapshotFile is the content of a snapshot file, holding the resources of a type acked by Envoy.
+type snapshotFile struct {
+	FormatVersion int       `json:"formatVersion"`
+	TypeURL       string    `json:"typeUrl"`
+	SavedAt       time.Time `json:"savedAt"`
+	// Checksum is the hex encoded SHA-256 of Response.
+	Checksum string `json:"checksum"`
+	// Response is the serialized DiscoveryResponse.
+	Response []byte `json:"response"`
+}
+
+// xdsSnapshot saves the xDS resources acked by Envoy on disk, one file per type URL, so that Envoy
+// can be configured when istiod cannot be reached after a restart.
+type xdsSnapshot struct {
+	dir    string
+	maxAge time.Duration
+
+	mu sync.Mutex
+	// pending holds, per type URL, the responses sent to Envoy which have not been acked or rejected yet, in order.
+	pending map[string][]*discovery.DiscoveryResponse
+	// acked holds, per type URL, the resources acked by Envoy, as saved on disk.
+	acked map[string]*discovery.DiscoveryResponse
+}
+
+func newXdsSnapshot(dir string, maxAge time.Duration) (*xdsSnapshot, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create xDS snapshot directory: %v", err)
+	}
+	return &xdsSnapshot{
+		dir:     dir,
+		maxAge:  maxAge,
+		pending: map[string][]*discovery.DiscoveryResponse{},
+		acked:   map[string]*discovery.DiscoveryResponse{},
+	}, nil
+}
+
+// sent records a response sent to Envoy, to be saved once Envoy acks it.
+func (s *xdsSnapshot) sent(resp *discovery.DiscoveryResponse) {
+	if _, f := snapshotTypes[resp.TypeUrl]; !f {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending := append(s.pending[resp.TypeUrl], resp)
+	if len(pending) > maxPendingResponses {
+		pending = pending[len(pending)-maxPendingResponses:]
+	}
+	s.pending[resp.TypeUrl] = pending
+}
+
+// received matches the request with the pending response of the same nonce. A NACK drops the response. An ACK
+// saves the response, along with the earlier responses which were not rejected, since Envoy applies them in order.
+func (s *xdsSnapshot) received(req *discovery.DiscoveryRequest) {
+	if req.ResponseNonce == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending := s.pending[req.TypeUrl]
+	idx := -1
+	for i, resp := range pending {
+		if resp.Nonce == req.ResponseNonce {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return
+	}
+	if req.ErrorDetail != nil {
+		s.pending[req.TypeUrl] = append(pending[:idx:idx], pending[idx+1:]...)
+		return
+	}
+	s.pending[req.TypeUrl] = pending[idx+1:]
+
+	state, f := s.acked[req.TypeUrl]
+	if !f {
+		// Partial responses update the resources saved before a restart.
+		state = s.load(req.TypeUrl)
+	}
+	for _, resp := range pending[:idx+1] {
+		state = mergeSnapshot(state, resp)
+	}
+	if _, f := partialSnapshotTypes[req.TypeUrl]; f && len(req.ResourceNames) > 0 {
+		state = filterSnapshot(state, req.ResourceNames)
+	}
+	s.acked[req.TypeUrl] = state
+	if err := s.save(state); err != nil {
+		proxyLog.Warnf("failed to save xDS snapshot for type url %s: %v", req.TypeUrl, err)
+	}
+}
+
+// mergeSnapshot returns the resources acked with the response. The response replaces the previous resources,
+// except for partial types, where it only replaces the resources with the same name.
+func mergeSnapshot(previous, resp *discovery.DiscoveryResponse) *discovery.DiscoveryResponse {
+	if _, f := partialSnapshotTypes[resp.TypeUrl]; !f || previous == nil {
+		return resp
+	}
+	byName := map[string]*any.Any{}
+	for _, r := range previous.Resources {
+		byName[snapshotResourceName(resp.TypeUrl, r)] = r
+	}
+	for _, r := range resp.Resources {
+		byName[snapshotResourceName(resp.TypeUrl, r)] = r
+	}
+	out := proto.Clone(resp).(*discovery.DiscoveryResponse)
+	out.Resources = sortedResources(byName)
+	return out
+}
+
+// filterSnapshot drops the resources Envoy no longer requests.
+func filterSnapshot(state *discovery.DiscoveryResponse, names []string) *discovery.DiscoveryResponse {
+	requested := map[string]struct{}{}
+	for _, n := range names {
+		requested[n] = struct{}{}
+	}
+	byName := map[string]*any.Any{}
+	for _, r := range state.Resources {
+		name := snapshotResourceName(state.TypeUrl, r)
+		if _, f := requested[name]; f {
+			byName[name] = r
+		}
+	}
+	out := proto.Clone(state).(*discovery.DiscoveryResponse)
+	out.Resources = sortedResources(byName)
+	return out
+}
+
+func sortedResources(byName map[string]*any.Any) []*any.Any {
+	names := make([]string, 0, len(byName))
+	for n := range byName {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	out := make([]*any.Any, 0, len(names))
+	for _, n := range names {
+		out = append(out, byName[n])
+	}
+	return out
+}
+
+// snapshotResourceName returns the name of a resource of a partial type, or an empty string if it cannot be decoded.
+func snapshotResourceName(typeURL string, r *any.Any) string {
+	switch typeURL {
+	case v3.EndpointType:
+		cla := &endpoint.ClusterLoadAssignment{}
+		if err := r.UnmarshalTo(cla); err == nil {
+			return cla.ClusterName
+		}
+	case v3.RouteType:
+		rc := &route.RouteConfiguration{}
+		if err := r.UnmarshalTo(rc); err == nil {
+			return rc.Name
+		}
+	}
+	return ""
+}
+
+// save writes the acked resources of a type to disk, replacing the previous ones.
+func (s *xdsSnapshot) save(resp *discovery.DiscoveryResponse) error {
+	b, err := proto.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(b)
+	out, err := json.Marshal(snapshotFile{
+		FormatVersion: snapshotFormatVersion,
+		TypeURL:       resp.TypeUrl,
+		SavedAt:       time.Now(),
+		Checksum:      hex.EncodeToString(sum[:]),
+		Response:      b,
+	})
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that a crash never leaves a partial snapshot.
+	tmp, err := ioutil.TempFile(s.dir, ".snapshot")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(out); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(resp.TypeUrl))
+}
+
+// load returns the saved response of a type, or nil if there is none, or if it is invalid or too old.
+func (s *xdsSnapshot) load(typeURL string) *discovery.DiscoveryResponse {
+	b, err := ioutil.ReadFile(s.path(typeURL))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			proxyLog.Warnf("failed to read xDS snapshot for type url %s: %v", typeURL, err)
+		}
+		return nil
+	}
+	resp, err := parseSnapshot(b, typeURL, s.maxAge)
+	if err != nil {
+		proxyLog.Warnf("ignoring xDS snapshot for type url %s: %v", typeURL, err)
+		return nil
+	}
+	return resp
+}
+
+// loadAll returns all the valid saved responses, by type URL.
+func (s *xdsSnapshot) loadAll() map[string]*discovery.DiscoveryResponse {
+	out := map[string]*discovery.DiscoveryResponse{}
+	for typeURL := range snapshotTypes {
+		if resp := s.load(typeURL); resp != nil {
+			out[typeURL] = resp
+		}
+	}
+	return out
+}
+
+func (s *xdsSnapshot) path(typeURL string) string {
+	return filepath.Join(s.dir, typeURL[strings.LastIndex(typeURL, "/")+1:]+".json")
+}
+
+func parseSnapshot(b []byte, typeURL string, maxAge time.Duration) (*discovery.DiscoveryResponse, error) {
+	f := snapshotFile{}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, err
+	}
+	if f.FormatVersion != snapshotFormatVersion {
+		return nil, fmt.Errorf("unsupported format version %d", f.FormatVersion)
+	}
+	if f.TypeURL != typeURL {
+		return nil, fmt.Errorf("unexpected type url %s", f.TypeURL)
+	}
+	if maxAge > 0 && time.Since(f.SavedAt) > maxAge {
+		return nil, fmt.Errorf("saved at %v, older than %v", f.SavedAt, maxAge)
+	}
+	sum := sha256.Sum256(f.Response)
+	if hex.EncodeToString(sum[:]) != f.Checksum {
+		return nil, fmt.Errorf("checksum mismatch")
+	}
+	resp := &discovery.DiscoveryResponse{}
+	if err := proto.Unmarshal(f.Response, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
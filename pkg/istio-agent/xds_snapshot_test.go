@@ -0,0 +1,302 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package istioagent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/golang/protobuf/ptypes/any"
+	"go.uber.org/atomic"
+	google_rpc "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"istio.io/istio/pilot/pkg/model"
+	"istio.io/istio/pilot/pkg/networking/util"
+	"istio.io/istio/pilot/pkg/xds"
+	v3 "istio.io/istio/pilot/pkg/xds/v3"
+)
+
+func TestXdsSnapshotSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	s, err := newXdsSnapshot(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := &discovery.DiscoveryResponse{TypeUrl: v3.ClusterType, VersionInfo: "v1", Nonce: "n1"}
+
+	// Not acked yet.
+	s.sent(resp)
+	if got := s.load(v3.ClusterType); got != nil {
+		t.Fatalf("expected no snapshot before ack, got %v", got)
+	}
+	// NACKs and ACKs of other responses are not saved.
+	s.received(&discovery.DiscoveryRequest{TypeUrl: v3.ClusterType, ResponseNonce: "n1", ErrorDetail: &google_rpc.Status{}})
+	s.received(&discovery.DiscoveryRequest{TypeUrl: v3.ClusterType, ResponseNonce: "n0"})
+	if got := s.load(v3.ClusterType); got != nil {
+		t.Fatalf("expected no snapshot after NACK, got %v", got)
+	}
+	// The NACKed response was dropped, it must be sent again.
+	s.received(&discovery.DiscoveryRequest{TypeUrl: v3.ClusterType, ResponseNonce: "n1"})
+	if got := s.load(v3.ClusterType); got != nil {
+		t.Fatalf("expected no snapshot after ACK of a NACKed response, got %v", got)
+	}
+	s.sent(resp)
+	s.received(&discovery.DiscoveryRequest{TypeUrl: v3.ClusterType, ResponseNonce: "n1"})
+	if got := s.load(v3.ClusterType); !proto.Equal(got, resp) {
+		t.Fatalf("got snapshot %v, want %v", got, resp)
+	}
+
+	// Types which are not part of the snapshot are ignored.
+	s.sent(&discovery.DiscoveryResponse{TypeUrl: v3.ProxyConfigType, Nonce: "n2"})
+	s.received(&discovery.DiscoveryRequest{TypeUrl: v3.ProxyConfigType, ResponseNonce: "n2"})
+	if got := s.loadAll(); len(got) != 1 {
+		t.Fatalf("expected only the cluster snapshot, got %v", got)
+	}
+}
+
+func TestXdsSnapshotPartialResponses(t *testing.T) {
+	s, err := newXdsSnapshot(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cla := func(name string, port uint32) *any.Any {
+		return util.MessageToAny(&endpoint.ClusterLoadAssignment{
+			ClusterName: name,
+			Endpoints: []*endpoint.LocalityLbEndpoints{{
+				LbEndpoints: []*endpoint.LbEndpoint{{
+					HostIdentifier: &endpoint.LbEndpoint_Endpoint{Endpoint: &endpoint.Endpoint{
+						Address: util.BuildAddress("10.0.0.1", port),
+					}},
+				}},
+			}},
+		})
+	}
+	eds := func(nonce string, resources ...*any.Any) *discovery.DiscoveryResponse {
+		return &discovery.DiscoveryResponse{TypeUrl: v3.EndpointType, Nonce: nonce, Resources: resources}
+	}
+	ack := func(nonce string, names ...string) *discovery.DiscoveryRequest {
+		return &discovery.DiscoveryRequest{TypeUrl: v3.EndpointType, ResponseNonce: nonce, ResourceNames: names}
+	}
+	expect := func(want ...*any.Any) {
+		t.Helper()
+		got := s.load(v3.EndpointType)
+		if got == nil {
+			t.Fatal("expected a snapshot")
+		}
+		if len(got.Resources) != len(want) {
+			t.Fatalf("got %d resources, want %d", len(got.Resources), len(want))
+		}
+		for i := range want {
+			if !proto.Equal(got.Resources[i], want[i]) {
+				t.Fatalf("got resource %v, want %v", got.Resources[i], want[i])
+			}
+		}
+	}
+
+	s.sent(eds("n1", cla("a", 80), cla("b", 80)))
+	s.received(ack("n1", "a", "b"))
+	expect(cla("a", 80), cla("b", 80))
+
+	// A partial response only updates its resources.
+	s.sent(eds("n2", cla("b", 90)))
+	s.received(ack("n2", "a", "b"))
+	expect(cla("a", 80), cla("b", 90))
+
+	// Responses sent before an ACK are matched by nonce, and rejected responses are dropped.
+	s.sent(eds("n3", cla("a", 90)))
+	s.sent(eds("n4", cla("b", 100)))
+	s.sent(eds("n5", cla("a", 100)))
+	s.received(ack("n3", "a", "b"))
+	expect(cla("a", 90), cla("b", 90))
+	s.received(&discovery.DiscoveryRequest{TypeUrl: v3.EndpointType, ResponseNonce: "n4", ErrorDetail: &google_rpc.Status{}})
+	s.received(ack("n5", "a", "b"))
+	expect(cla("a", 100), cla("b", 90))
+
+	// Resources no longer requested are dropped.
+	s.sent(eds("n6"))
+	s.received(ack("n6", "b"))
+	expect(cla("b", 90))
+
+	// The snapshot saved before a restart is updated by partial responses.
+	s, err = newXdsSnapshot(s.dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.sent(eds("n7", cla("c", 80)))
+	s.received(ack("n7", "b", "c"))
+	expect(cla("b", 90), cla("c", 80))
+}
+
+func TestXdsSnapshotValidation(t *testing.T) {
+	resp := &discovery.DiscoveryResponse{TypeUrl: v3.ListenerType, VersionInfo: "v1", Nonce: "n1"}
+	cases := []struct {
+		name   string
+		maxAge time.Duration
+		modify func(f *snapshotFile)
+		valid  bool
+	}{
+		{
+			name:  "valid",
+			valid: true,
+		},
+		{
+			name:   "corrupted",
+			modify: func(f *snapshotFile) { f.Response = append(f.Response, 0) },
+		},
+		{
+			name:   "unknown format version",
+			modify: func(f *snapshotFile) { f.FormatVersion = snapshotFormatVersion + 1 },
+		},
+		{
+			name:   "wrong type",
+			modify: func(f *snapshotFile) { f.TypeURL = v3.ClusterType },
+		},
+		{
+			name:   "too old",
+			maxAge: time.Hour,
+			modify: func(f *snapshotFile) { f.SavedAt = time.Now().Add(-2 * time.Hour) },
+		},
+		{
+			name:   "old without max age",
+			modify: func(f *snapshotFile) { f.SavedAt = time.Now().Add(-2 * time.Hour) },
+			valid:  true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := newXdsSnapshot(t.TempDir(), tt.maxAge)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.save(resp); err != nil {
+				t.Fatal(err)
+			}
+			if tt.modify != nil {
+				b, err := ioutil.ReadFile(s.path(v3.ListenerType))
+				if err != nil {
+					t.Fatal(err)
+				}
+				f := snapshotFile{}
+				if err := json.Unmarshal(b, &f); err != nil {
+					t.Fatal(err)
+				}
+				tt.modify(&f)
+				if b, err = json.Marshal(f); err != nil {
+					t.Fatal(err)
+				}
+				if err := ioutil.WriteFile(s.path(v3.ListenerType), b, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := s.load(v3.ListenerType); (got != nil) != tt.valid {
+				t.Fatalf("got snapshot %v, want valid %v", got, tt.valid)
+			}
+		})
+	}
+}
+
+// Validates that the proxy saves the acked responses, and serves them when istiod cannot be reached at startup.
+func TestXdsProxySnapshot(t *testing.T) {
+	dir := t.TempDir()
+	node := &core.Node{
+		Id:       "sidecar~1.1.1.1~debug~cluster.local",
+		Metadata: model.NodeMetadata{Namespace: "default", InstanceIPs: []string{"1.1.1.1"}}.ToStruct(),
+	}
+	f := xds.NewFakeDiscoveryServer(t, xds.FakeOptions{})
+
+	// Save the clusters acked by Envoy while istiod is reachable.
+	proxy := setupXdsProxy(t)
+	if proxy.snapshot, _ = newXdsSnapshot(dir, time.Hour); proxy.snapshot == nil {
+		t.Fatal("failed to create snapshot")
+	}
+	setDialOptions(proxy, f.Listener)
+	downstream := stream(t, setupDownstreamConnection(t, proxy))
+	if err := downstream.Send(&discovery.DiscoveryRequest{TypeUrl: v3.ClusterType, Node: node}); err != nil {
+		t.Fatal(err)
+	}
+	clusters, err := downstream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := downstream.Send(&discovery.DiscoveryRequest{
+		TypeUrl:       v3.ClusterType,
+		Node:          node,
+		VersionInfo:   clusters.VersionInfo,
+		ResponseNonce: clusters.Nonce,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := downstream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Restart the proxy while istiod is unreachable.
+	proxy = setupXdsProxy(t)
+	if proxy.snapshot, _ = newXdsSnapshot(dir, time.Hour); proxy.snapshot == nil {
+		t.Fatal("failed to create snapshot")
+	}
+	proxy.snapshotProbeInterval = 10 * time.Millisecond
+	reachable := atomic.NewBool(false)
+	proxy.istiodDialOptions = []grpc.DialOption{
+		grpc.WithBlock(),
+		grpc.WithInsecure(),
+		grpc.FailOnNonTempDialError(true),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			if !reachable.Load() {
+				return nil, errors.New("istiod unreachable")
+			}
+			return f.Listener.Dial()
+		}),
+	}
+	downstream = stream(t, setupDownstreamConnection(t, proxy))
+	if err := downstream.Send(&discovery.DiscoveryRequest{TypeUrl: v3.ClusterType, Node: node}); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := downstream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(saved, clusters) {
+		t.Fatalf("expected the saved clusters, got %v", saved)
+	}
+
+	// Once istiod is reachable, the stream is terminated for Envoy to resync.
+	reachable.Store(true)
+	if _, err := downstream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected the stream to be terminated, got %v", err)
+	}
+	downstream = stream(t, setupDownstreamConnection(t, proxy))
+	if err := downstream.Send(&discovery.DiscoveryRequest{TypeUrl: v3.ClusterType, Node: node}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := downstream.Recv(); err != nil {
+		t.Fatalf("expected a response from istiod, got %v", err)
+	}
+	if !proxy.upstreamSynced.Load() {
+		t.Fatal("expected the proxy to be synced with istiod")
+	}
+}
@@ -18,7 +18,10 @@ package inject
 
 import (
 	"encoding/json"
+	"net"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/gogo/protobuf/types"
 	corev1 "k8s.io/api/core/v1"
@@ -26,9 +29,14 @@ import (
 
 	"istio.io/api/annotation"
 	"istio.io/istio/pilot/cmd/pilot-agent/status"
+	"istio.io/istio/pkg/kube/apimirror"
 	"istio.io/pkg/log"
 )
 
+// grpcHealthProbeBinary is the name of the binary commonly used in exec probes to check the gRPC health of an app.
+// See https://github.com/grpc-ecosystem/grpc-health-probe.
+const grpcHealthProbeBinary = "grpc_health_probe"
+
 // ShouldRewriteAppHTTPProbers returns if we should rewrite apps' probers config.
 func ShouldRewriteAppHTTPProbers(annotations map[string]string, specSetting *types.BoolValue) bool {
 	if annotations != nil {
@@ -61,7 +69,21 @@ func FindContainer(name string, containers []corev1.Container) *corev1.Container
 
 // convertAppProber returns an overwritten `Probe` for pilot agent to take over.
 func convertAppProber(probe *corev1.Probe, newURL string, statusPort int) *corev1.Probe {
-	if probe == nil || probe.HTTPGet == nil {
+	if probe == nil {
+		return nil
+	}
+	if probe.TCPSocket != nil || grpcHealthProbeAction(probe.Exec) != nil {
+		// TCP and gRPC probers are replaced by a HTTP prober, pilot agent checks the application on its behalf.
+		p := probe.DeepCopy()
+		p.Handler = corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: newURL,
+				Port: intstr.FromInt(statusPort),
+			},
+		}
+		return p
+	}
+	if probe.HTTPGet == nil {
 		return nil
 	}
 	p := probe.DeepCopy()
@@ -81,8 +103,10 @@ type KubeAppProbers map[string]*Prober
 
 // Prober represents a single container prober
 type Prober struct {
-	HTTPGet        *corev1.HTTPGetAction `json:"httpGet"`
-	TimeoutSeconds int32                 `json:"timeoutSeconds,omitempty"`
+	HTTPGet        *corev1.HTTPGetAction   `json:"httpGet,omitempty"`
+	TCPSocket      *corev1.TCPSocketAction `json:"tcpSocket,omitempty"`
+	GRPC           *apimirror.GRPCAction   `json:"grpc,omitempty"`
+	TimeoutSeconds int32                   `json:"timeoutSeconds,omitempty"`
 }
 
 // DumpAppProbers returns a json encoded string as `status.KubeAppProbers`.
@@ -90,16 +114,26 @@ type Prober struct {
 func DumpAppProbers(podspec *corev1.PodSpec, targetPort int32) string {
 	out := KubeAppProbers{}
 	updateNamedPort := func(p *Prober, portMap map[string]int32) *Prober {
-		if p == nil || p.HTTPGet == nil {
+		if p == nil {
 			return nil
 		}
-		if p.HTTPGet.Port.Type == intstr.String {
-			port, exists := portMap[p.HTTPGet.Port.StrVal]
+		var port *intstr.IntOrString
+		switch {
+		case p.HTTPGet != nil:
+			port = &p.HTTPGet.Port
+		case p.TCPSocket != nil:
+			port = &p.TCPSocket.Port
+		default:
+			// gRPC probers always use a port number.
+			return p
+		}
+		if port.Type == intstr.String {
+			portNum, exists := portMap[port.StrVal]
 			if !exists {
 				return nil
 			}
-			p.HTTPGet.Port = intstr.FromInt(int(port))
-		} else if p.HTTPGet.Port.IntVal == targetPort {
+			*port = intstr.FromInt(int(portNum))
+		} else if p.HTTPGet != nil && port.IntVal == targetPort {
 			// Already is rewritten
 			return nil
 		}
@@ -182,12 +216,69 @@ func kubeProbeToInternalProber(probe *corev1.Probe) *Prober {
 		return nil
 	}
 
-	if probe.HTTPGet == nil {
-		return nil
+	switch {
+	case probe.HTTPGet != nil:
+		return &Prober{
+			HTTPGet:        probe.HTTPGet,
+			TimeoutSeconds: probe.TimeoutSeconds,
+		}
+	case probe.TCPSocket != nil:
+		return &Prober{
+			TCPSocket:      probe.TCPSocket,
+			TimeoutSeconds: probe.TimeoutSeconds,
+		}
+	}
+	if grpc := grpcHealthProbeAction(probe.Exec); grpc != nil {
+		return &Prober{
+			GRPC:           grpc,
+			TimeoutSeconds: probe.TimeoutSeconds,
+		}
 	}
+	return nil
+}
 
-	return &Prober{
-		HTTPGet:        probe.HTTPGet,
-		TimeoutSeconds: probe.TimeoutSeconds,
+// grpcHealthProbeAction returns the gRPC health check done by an exec prober running grpc_health_probe,
+// or nil if the prober does something else, or uses flags which pilot agent does not support, such as TLS.
+func grpcHealthProbeAction(exec *corev1.ExecAction) *apimirror.GRPCAction {
+	if exec == nil || len(exec.Command) == 0 || filepath.Base(exec.Command[0]) != grpcHealthProbeBinary {
+		return nil
+	}
+	var addr string
+	var service *string
+	args := exec.Command[1:]
+	for i := 0; i < len(args); i++ {
+		name := strings.TrimLeft(args[i], "-")
+		var value string
+		if idx := strings.Index(name, "="); idx >= 0 {
+			name, value = name[:idx], name[idx+1:]
+		} else if i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		switch name {
+		case "addr":
+			addr = value
+		case "service":
+			service = &value
+		case "connect-timeout", "rpc-timeout", "user-agent":
+			// The timeout of the prober applies instead.
+		default:
+			return nil
+		}
+	}
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil
+	}
+	if host != "" && host != "localhost" && host != "127.0.0.1" && host != "::1" {
+		return nil
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port <= 0 || port > 65535 {
+		return nil
+	}
+	return &apimirror.GRPCAction{
+		Port:    int32(port),
+		Service: service,
 	}
 }
@@ -14,12 +14,14 @@
 package inject
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/gogo/protobuf/types"
 	corev1 "k8s.io/api/core/v1"
 
 	"istio.io/api/annotation"
+	"istio.io/istio/pkg/kube/apimirror"
 )
 
 func TestFindSidecar(t *testing.T) {
@@ -110,3 +112,26 @@ func TestShouldRewriteAppHTTPProbers(t *testing.T) {
 		}
 	}
 }
+
+func TestGRPCHealthProbeAction(t *testing.T) {
+	service := "foo"
+	for _, tc := range []struct {
+		name     string
+		command  []string
+		expected *apimirror.GRPCAction
+	}{
+		{"not-grpc", []string{"cat", "/tmp/healthy"}, nil},
+		{"addr", []string{"/bin/grpc_health_probe", "-addr=:5000"}, &apimirror.GRPCAction{Port: 5000}},
+		{"localhost", []string{"grpc_health_probe", "--addr", "localhost:5000"}, &apimirror.GRPCAction{Port: 5000}},
+		{"service", []string{"grpc_health_probe", "-addr=:5000", "-service=foo", "-connect-timeout=2s"},
+			&apimirror.GRPCAction{Port: 5000, Service: &service}},
+		{"remote-host", []string{"grpc_health_probe", "-addr=example.com:5000"}, nil},
+		{"tls", []string{"grpc_health_probe", "-addr=:5000", "-tls"}, nil},
+		{"no-addr", []string{"grpc_health_probe"}, nil},
+	} {
+		got := grpcHealthProbeAction(&corev1.ExecAction{Command: tc.command})
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("[%v] failed, want %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
@@ -54,8 +54,11 @@ const (
 	WorkloadKeyCertResourceName = "default"
 
 	// Credential fetcher type
-	GCE  = "GoogleComputeEngine"
-	Mock = "Mock" // testing only
+	GCE        = "GoogleComputeEngine"
+	Exec       = "Exec"
+	UnixSocket = "UnixSocket"
+	File       = "File"
+	Mock       = "Mock" // testing only
 )
 
 // TODO: For 1.8, make sure MeshConfig is updated with those settings,
@@ -15,6 +15,7 @@
 package wasm
 
 import (
+	"context"
 	"crypto/sha256"
 	"fmt"
 	"io/ioutil"
@@ -36,11 +37,14 @@ const (
 
 	// DefaultWasmModuleExpiry is the default duration for least recently touched Wasm module to become stale.
 	DefaultWasmModuleExpiry = 24 * time.Hour
+
+	// defaultImageFetchTimeout is the timeout of pulling a Wasm module from an OCI registry, if none is configured.
+	defaultImageFetchTimeout = 5 * time.Second
 )
 
 // Cache models a Wasm module cache.
 type Cache interface {
-	Get(url, checksum string, timeout time.Duration) (string, error)
+	Get(url, checksum string, timeout time.Duration, pullSecret []byte) (string, error)
 	Cleanup()
 }
 
@@ -99,7 +103,9 @@ func NewLocalFileCache(dir string, purgeInterval, moduleExpiry time.Duration) *L
 }
 
 // Get returns path the local Wasm module file.
-func (c *LocalFileCache) Get(downloadURL, checksum string, timeout time.Duration) (string, error) {
+// For modules stored in an OCI registry, the checksum is the digest of the image manifest,
+// and the pull secret is used to authenticate against the registry.
+func (c *LocalFileCache) Get(downloadURL, checksum string, timeout time.Duration, pullSecret []byte) (string, error) {
 	url, err := url.Parse(downloadURL)
 	if err != nil {
 		return "", fmt.Errorf("fail to parse Wasm module fetch url: %s", downloadURL)
@@ -143,11 +149,56 @@ func (c *LocalFileCache) Get(downloadURL, checksum string, timeout time.Duration
 		}
 
 		return f, nil
+	case ociURLScheme:
+		return c.getImage(downloadURL, checksum, timeout, pullSecret)
 	default:
 		return "", fmt.Errorf("unsupported Wasm module downloading URL scheme: %v", url.Scheme)
 	}
 }
 
+// getImage returns path of the local Wasm module file pulled from an OCI registry.
+// Image tags are resolved to digests, and the module is cached by repository and digest.
+func (c *LocalFileCache) getImage(imageURL, checksum string, timeout time.Duration, pullSecret []byte) (string, error) {
+	if timeout == 0 {
+		timeout = defaultImageFetchTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	fetcher := NewImageFetcher(ctx, ImageFetcherOption{PullSecret: pullSecret})
+
+	repository, digest, err := fetcher.Resolve(imageURL)
+	if err != nil {
+		wasmRemoteFetchCount.With(resultTag.Value(downloadFailure)).Increment()
+		return "", err
+	}
+	if checksum != "" && digest != checksum {
+		wasmRemoteFetchCount.With(resultTag.Value(checksumMismatch)).Increment()
+		return "", fmt.Errorf("image %v has digest %v, which does not match: %v", imageURL, digest, checksum)
+	}
+
+	key := cacheKey{
+		downloadURL: ociURLScheme + "://" + repository,
+		checksum:    digest,
+	}
+	if modulePath := c.getEntry(key); modulePath != "" {
+		return modulePath, nil
+	}
+
+	// Pull the resolved digest rather than the tag, so that the module matches the cache key even if the tag moves.
+	b, _, err := fetcher.Fetch(fmt.Sprintf("%s://%s@sha256:%s", ociURLScheme, repository, digest))
+	if err != nil {
+		wasmRemoteFetchCount.With(resultTag.Value(downloadFailure)).Increment()
+		return "", err
+	}
+	wasmRemoteFetchCount.With(resultTag.Value(fetchSuccess)).Increment()
+
+	f := filepath.Join(c.dir, fmt.Sprintf("%x.wasm", sha256.Sum256(b)))
+	if err := c.addEntry(key, b, f); err != nil {
+		return "", err
+	}
+	return f, nil
+}
+
 // Cleanup closes background Wasm module purge routine.
 func (c *LocalFileCache) Cleanup() {
 	close(c.stopChan)
@@ -78,12 +78,12 @@ func TestWasmCache(t *testing.T) {
 		{
 			name:                 "invalid scheme",
 			initialCachedModules: map[cacheKey]cacheEntry{},
-			fetchURL:             "oci://abc",
+			fetchURL:             "ftp://abc",
 			purgeInterval:        DefaultWasmModulePurgeInteval,
 			wasmModuleExpiry:     DefaultWasmModuleExpiry,
 			checksum:             dataCheckSum,
 			wantFileName:         fmt.Sprintf("%x.wasm", dataCheckSum),
-			wantErrorMsgPrefix:   "unsupported Wasm module downloading URL scheme: oci",
+			wantErrorMsgPrefix:   "unsupported Wasm module downloading URL scheme: ftp",
 			wantServerReqNum:     0,
 		},
 		{
@@ -168,7 +168,7 @@ func TestWasmCache(t *testing.T) {
 				}
 			}
 
-			gotFilePath, gotErr := cache.Get(c.fetchURL, fmt.Sprintf("%x", c.checksum), 0)
+			gotFilePath, gotErr := cache.Get(c.fetchURL, fmt.Sprintf("%x", c.checksum), 0, nil)
 			wantFilePath := filepath.Join(tmpDir, c.wantFileName)
 			if c.wantErrorMsgPrefix != "" {
 				if gotErr == nil {
@@ -210,7 +210,7 @@ func TestWasmCacheMissChecksum(t *testing.T) {
 
 	// Get wasm module three times, since checksum is not specified, it will be fetched from module server every time.
 	// 1st time
-	gotFilePath, err := cache.Get(ts.URL, "", 0)
+	gotFilePath, err := cache.Get(ts.URL, "", 0, nil)
 	if err != nil {
 		t.Fatalf("failed to download Wasm module: %v", err)
 	}
@@ -219,7 +219,7 @@ func TestWasmCacheMissChecksum(t *testing.T) {
 	}
 
 	// 2nd time
-	gotFilePath, err = cache.Get(ts.URL, "", 0)
+	gotFilePath, err = cache.Get(ts.URL, "", 0, nil)
 	if err != nil {
 		t.Fatalf("failed to download Wasm module: %v", err)
 	}
@@ -228,7 +228,7 @@ func TestWasmCacheMissChecksum(t *testing.T) {
 	}
 
 	// 3rd time
-	gotFilePath, err = cache.Get(ts.URL, "", 0)
+	gotFilePath, err = cache.Get(ts.URL, "", 0, nil)
 	if err != nil {
 		t.Fatalf("failed to download Wasm module: %v", err)
 	}
@@ -33,6 +33,10 @@ const (
 	apiTypePrefix      = "type.googleapis.com/"
 	typedStructType    = apiTypePrefix + "udpa.type.v1.TypedStruct"
 	wasmHTTPFilterType = apiTypePrefix + "envoy.extensions.filters.http.wasm.v3.Wasm"
+
+	// WasmSecretEnv is the Wasm VM environment variable which carries the image pull secret of a module
+	// stored in an OCI registry. It is consumed by istio-agent and never forwarded to Envoy.
+	WasmSecretEnv = "ISTIO_META_WASM_IMAGE_PULL_SECRET"
 )
 
 // MaybeConvertWasmExtensionConfig converts any presence of module remote download to local file.
@@ -52,11 +56,11 @@ func MaybeConvertWasmExtensionConfig(resources []*any.Any, cache Cache) bool {
 			defer wg.Done()
 
 			newExtensionConfig, nack := convert(resources[i], cache)
+			// The resource is replaced even on NACK: the pull secret is stripped from it on every path.
+			resources[i] = newExtensionConfig
 			if nack {
 				sendNack.Store(true)
-				return
 			}
-			resources[i] = newExtensionConfig
 		}(i)
 	}
 
@@ -81,11 +85,11 @@ func MaybeConvertWasmExtensionConfigDelta(resources []*discovery.Resource, cache
 			defer wg.Done()
 
 			newExtensionConfig, nack := convert(resources[i].Resource, cache)
+			// The resource is replaced even on NACK: the pull secret is stripped from it on every path.
+			resources[i].Resource = newExtensionConfig
 			if nack {
 				sendNack.Store(true)
-				return
 			}
-			resources[i].Resource = newExtensionConfig
 		}(i)
 	}
 
@@ -109,7 +113,8 @@ func convert(resource *any.Any, cache Cache) (newExtensionConfig *any.Any, sendN
 	}
 
 	// Currently Wasm filter can only be configured using typed struct via EnvoyFilter.
-	wasmLog.Debugf("original extension config resource %+v", ec)
+	// The resource is not logged as it may carry an image pull secret.
+	wasmLog.Debugf("original extension config resource %s", ec.GetName())
 	if ec.GetTypedConfig() == nil && ec.GetTypedConfig().TypeUrl != typedStructType {
 		wasmLog.Debugf("cannot find typed struct in %+v", ec)
 		return
@@ -133,6 +138,20 @@ func convert(resource *any.Any, cache Cache) (newExtensionConfig *any.Any, sendN
 		return
 	}
 
+	// The pull secret is consumed here and must never reach Envoy, whatever the outcome of the conversion.
+	pullSecret, hasSecret := stripPullSecret(wasmHTTPFilterConfig)
+	if hasSecret {
+		stripped, err := marshalExtensionConfig(ec, wasmHTTPFilterConfig)
+		if err != nil {
+			status = marshalFailure
+			wasmLog.Errorf("failed to marshal extension config %s without pull secret: %v", ec.GetName(), err)
+			// The original resource must not be forwarded, the response is rejected instead.
+			sendNack = true
+			return
+		}
+		newExtensionConfig = stripped
+	}
+
 	if wasmHTTPFilterConfig.Config.GetVmConfig().GetCode().GetRemote() == nil {
 		wasmLog.Debugf("no remote load found in Wasm HTTP filter %+v", wasmHTTPFilterConfig)
 		return
@@ -156,7 +175,7 @@ func convert(resource *any.Any, cache Cache) (newExtensionConfig *any.Any, sendN
 	if remote.GetHttpUri().Timeout != nil {
 		timeout = remote.GetHttpUri().Timeout.AsDuration()
 	}
-	f, err := cache.Get(httpURI.GetUri(), remote.GetSha256(), timeout)
+	f, err := cache.Get(httpURI.GetUri(), remote.GetSha256(), timeout, pullSecret)
 	if err != nil {
 		status = fetchFailure
 		wasmLog.Errorf("cannot fetch Wasm module %v: %v", remote.GetHttpUri().GetUri(), err)
@@ -174,21 +193,13 @@ func convert(resource *any.Any, cache Cache) (newExtensionConfig *any.Any, sendN
 		},
 	}
 
-	wasmTypedConfig, err = anypb.New(wasmHTTPFilterConfig)
-	if err != nil {
-		status = marshalFailure
-		wasmLog.Errorf("failed to marshal new wasm HTTP filter %+v to protobuf Any: %v", wasmHTTPFilterConfig, err)
-		return
-	}
-	ec.TypedConfig = wasmTypedConfig
-	wasmLog.Debugf("new extension config resource %+v", ec)
-
-	nec, err := anypb.New(ec)
+	nec, err := marshalExtensionConfig(ec, wasmHTTPFilterConfig)
 	if err != nil {
 		status = marshalFailure
 		wasmLog.Errorf("failed to marshal new extension config resource: %v", err)
 		return
 	}
+	wasmLog.Debugf("new extension config resource %+v", ec)
 
 	// At this point, we are certain that wasm module has been downloaded and config is rewritten.
 	// ECDS has been rewritten successfully and should not nack.
@@ -196,3 +207,27 @@ func convert(resource *any.Any, cache Cache) (newExtensionConfig *any.Any, sendN
 	sendNack = false
 	return
 }
+
+// stripPullSecret removes the image pull secret from the environment of the Wasm VM, returning it.
+func stripPullSecret(filter *wasm.Wasm) ([]byte, bool) {
+	envs := filter.GetConfig().GetVmConfig().GetEnvironmentVariables()
+	if envs == nil {
+		return nil, false
+	}
+	secret, ok := envs.KeyValues[WasmSecretEnv]
+	if !ok {
+		return nil, false
+	}
+	delete(envs.KeyValues, WasmSecretEnv)
+	return []byte(secret), true
+}
+
+// marshalExtensionConfig sets the Wasm HTTP filter as the typed config of the extension config, and marshals it.
+func marshalExtensionConfig(ec *core.TypedExtensionConfig, filter *wasm.Wasm) (*any.Any, error) {
+	typedConfig, err := anypb.New(filter)
+	if err != nil {
+		return nil, err
+	}
+	ec.TypedConfig = typedConfig
+	return anypb.New(ec)
+}
@@ -16,6 +16,7 @@ package wasm
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
 	"testing"
 	"time"
@@ -34,7 +35,7 @@ import (
 
 type mockCache struct{}
 
-func (c *mockCache) Get(downloadURL, checksum string, timeout time.Duration) (string, error) {
+func (c *mockCache) Get(downloadURL, checksum string, timeout time.Duration, pullSecret []byte) (string, error) {
 	url, _ := url.Parse(downloadURL)
 	query := url.Query()
 
@@ -44,6 +45,9 @@ func (c *mockCache) Get(downloadURL, checksum string, timeout time.Duration) (st
 	if errMsg != "" {
 		err = errors.New(errMsg)
 	}
+	if secret := query.Get("secret"); secret != string(pullSecret) {
+		err = fmt.Errorf("got pull secret %q, want %q", pullSecret, secret)
+	}
 
 	return module, err
 }
@@ -66,6 +70,16 @@ func TestWasmConvert(t *testing.T) {
 			},
 			wantNack: false,
 		},
+		{
+			name: "remote load with pull secret",
+			input: []*core.TypedExtensionConfig{
+				extensionConfigMap["remote-load-pull-secret"],
+			},
+			wantOutput: []*core.TypedExtensionConfig{
+				extensionConfigMap["remote-load-pull-secret-local-file"],
+			},
+			wantNack: false,
+		},
 		{
 			name: "remote load fail",
 			input: []*core.TypedExtensionConfig{
@@ -98,6 +112,16 @@ func TestWasmConvert(t *testing.T) {
 			},
 			wantNack: false,
 		},
+		{
+			name: "remote load fail open with pull secret",
+			input: []*core.TypedExtensionConfig{
+				extensionConfigMap["remote-load-pull-secret-fail-open"],
+			},
+			wantOutput: []*core.TypedExtensionConfig{
+				extensionConfigMap["remote-load-pull-secret-fail-open-stripped"],
+			},
+			wantNack: false,
+		},
 		{
 			name: "no typed struct",
 			input: []*core.TypedExtensionConfig{
@@ -257,6 +281,47 @@ var extensionConfigMap = map[string]*core.TypedExtensionConfig{
 			},
 		},
 	}),
+	"remote-load-pull-secret": buildTypedStructExtensionConfig("remote-load-pull-secret", &wasm.Wasm{
+		Config: &v3.PluginConfig{
+			Vm: &v3.PluginConfig_VmConfig{
+				VmConfig: &v3.VmConfig{
+					Code: &core.AsyncDataSource{Specifier: &core.AsyncDataSource_Remote{
+						Remote: &core.RemoteDataSource{
+							HttpUri: &core.HttpUri{
+								Uri: "oci://test?module=test.wasm&secret=pull-secret",
+							},
+						},
+					}},
+					EnvironmentVariables: &v3.EnvironmentVariables{
+						KeyValues: map[string]string{
+							WasmSecretEnv: "pull-secret",
+							"FOO":         "bar",
+						},
+					},
+				},
+			},
+		},
+	}),
+	"remote-load-pull-secret-local-file": buildWasmExtensionConfig("remote-load-pull-secret", &wasm.Wasm{
+		Config: &v3.PluginConfig{
+			Vm: &v3.PluginConfig_VmConfig{
+				VmConfig: &v3.VmConfig{
+					Code: &core.AsyncDataSource{Specifier: &core.AsyncDataSource_Local{
+						Local: &core.DataSource{
+							Specifier: &core.DataSource_Filename{
+								Filename: "test.wasm",
+							},
+						},
+					}},
+					EnvironmentVariables: &v3.EnvironmentVariables{
+						KeyValues: map[string]string{
+							"FOO": "bar",
+						},
+					},
+				},
+			},
+		},
+	}),
 	"remote-load-fail": buildTypedStructExtensionConfig("remote-load-fail", &wasm.Wasm{
 		Config: &v3.PluginConfig{
 			Vm: &v3.PluginConfig_VmConfig{
@@ -288,4 +353,47 @@ var extensionConfigMap = map[string]*core.TypedExtensionConfig{
 			FailOpen: true,
 		},
 	}),
+	"remote-load-pull-secret-fail-open": buildTypedStructExtensionConfig("remote-load-pull-secret-fail-open", &wasm.Wasm{
+		Config: &v3.PluginConfig{
+			Vm: &v3.PluginConfig_VmConfig{
+				VmConfig: &v3.VmConfig{
+					Code: &core.AsyncDataSource{Specifier: &core.AsyncDataSource_Remote{
+						Remote: &core.RemoteDataSource{
+							HttpUri: &core.HttpUri{
+								Uri: "oci://test?module=test.wasm&error=download-error",
+							},
+						},
+					}},
+					EnvironmentVariables: &v3.EnvironmentVariables{
+						KeyValues: map[string]string{
+							WasmSecretEnv: "pull-secret",
+							"FOO":         "bar",
+						},
+					},
+				},
+			},
+			FailOpen: true,
+		},
+	}),
+	"remote-load-pull-secret-fail-open-stripped": buildWasmExtensionConfig("remote-load-pull-secret-fail-open", &wasm.Wasm{
+		Config: &v3.PluginConfig{
+			Vm: &v3.PluginConfig_VmConfig{
+				VmConfig: &v3.VmConfig{
+					Code: &core.AsyncDataSource{Specifier: &core.AsyncDataSource_Remote{
+						Remote: &core.RemoteDataSource{
+							HttpUri: &core.HttpUri{
+								Uri: "oci://test?module=test.wasm&error=download-error",
+							},
+						},
+					}},
+					EnvironmentVariables: &v3.EnvironmentVariables{
+						KeyValues: map[string]string{
+							"FOO": "bar",
+						},
+					},
+				},
+			},
+			FailOpen: true,
+		},
+	}),
 }
@@ -0,0 +1,236 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wasm
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+const (
+	// ociURLScheme is the URL scheme used to reference Wasm modules stored in an OCI registry.
+	ociURLScheme = "oci"
+
+	// wasmLayerMediaType is the layer media type of Wasm artifacts,
+	// i.e. images whose single layer is the raw Wasm binary.
+	wasmLayerMediaType = "application/vnd.module.wasm.content.layer.v1+wasm"
+
+	// wasmImageFileName is the file name looked up in the tarball layer of a single layer Wasm image.
+	wasmImageFileName = "plugin.wasm"
+)
+
+// ImageFetcherOption contains the options used to pull Wasm modules from an OCI registry.
+type ImageFetcherOption struct {
+	// PullSecret is the docker config json used to authenticate against the registry.
+	// Anonymous access is used if it is empty.
+	PullSecret []byte
+}
+
+// ImageFetcher fetches Wasm modules stored in an OCI registry.
+type ImageFetcher struct {
+	fetchOpts []remote.Option
+}
+
+// NewImageFetcher creates a new OCI image Wasm module fetcher.
+func NewImageFetcher(ctx context.Context, opt ImageFetcherOption) *ImageFetcher {
+	fetchOpts := []remote.Option{remote.WithContext(ctx)}
+	if len(opt.PullSecret) > 0 {
+		fetchOpts = append(fetchOpts, remote.WithAuthFromKeychain(&wasmKeyChain{data: opt.PullSecret}))
+	}
+	return &ImageFetcher{
+		fetchOpts: fetchOpts,
+	}
+}
+
+// Resolve resolves the given image reference to its repository and the digest of its manifest.
+// References which already pin a digest are returned without contacting the registry.
+func (f *ImageFetcher) Resolve(imageURL string) (repository string, digest string, err error) {
+	ref, err := parseImageReference(imageURL)
+	if err != nil {
+		return "", "", err
+	}
+	repository = ref.Context().Name()
+	if d, ok := ref.(name.Digest); ok {
+		h, err := v1.NewHash(d.DigestStr())
+		if err != nil {
+			return "", "", fmt.Errorf("could not parse digest of image %v: %v", imageURL, err)
+		}
+		return repository, h.Hex, nil
+	}
+	desc, err := remote.Head(ref, f.fetchOpts...)
+	if err != nil {
+		return "", "", fmt.Errorf("could not resolve digest of image %v: %v", imageURL, err)
+	}
+	return repository, desc.Digest.Hex, nil
+}
+
+// Fetch pulls the Wasm module stored in the given image, and returns the module with the digest of the image manifest.
+// Two image formats are supported: Wasm artifacts whose single layer has the Wasm layer media type,
+// and single layer container images whose layer is a tarball holding a `plugin.wasm` file.
+func (f *ImageFetcher) Fetch(imageURL string) ([]byte, string, error) {
+	ref, err := parseImageReference(imageURL)
+	if err != nil {
+		return nil, "", err
+	}
+	desc, err := remote.Get(ref, f.fetchOpts...)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not fetch manifest of image %v: %v", imageURL, err)
+	}
+	img, err := desc.Image()
+	if err != nil {
+		return nil, "", fmt.Errorf("could not parse image %v: %v", imageURL, err)
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, "", fmt.Errorf("could not fetch layers of image %v: %v", imageURL, err)
+	}
+	if len(layers) != 1 {
+		return nil, "", fmt.Errorf("image %v has %d layers, want exactly one layer", imageURL, len(layers))
+	}
+	layer := layers[0]
+	mt, err := layer.MediaType()
+	if err != nil {
+		return nil, "", fmt.Errorf("could not get media type of image %v layer: %v", imageURL, err)
+	}
+
+	var b []byte
+	switch mt {
+	case wasmLayerMediaType:
+		b, err = extractWasmArtifact(layer)
+	case types.DockerLayer, types.DockerUncompressedLayer, types.OCILayer, types.OCIUncompressedLayer:
+		b, err = extractWasmImage(layer)
+	default:
+		err = fmt.Errorf("unsupported layer media type %v", mt)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("could not extract Wasm module from image %v: %v", imageURL, err)
+	}
+	return b, desc.Digest.Hex, nil
+}
+
+func parseImageReference(imageURL string) (name.Reference, error) {
+	ref, err := name.ParseReference(strings.TrimPrefix(imageURL, ociURLScheme+"://"))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse image reference %v: %v", imageURL, err)
+	}
+	return ref, nil
+}
+
+// extractWasmArtifact returns the content of a Wasm artifact layer, which is the Wasm binary itself.
+func extractWasmArtifact(layer v1.Layer) ([]byte, error) {
+	r, err := layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// extractWasmImage looks up the Wasm module in the tarball of a container image layer.
+func extractWasmImage(layer v1.Layer) ([]byte, error) {
+	r, err := layer.Uncompressed()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if h.Typeflag != tar.TypeReg || filepath.Base(h.Name) != wasmImageFileName {
+			continue
+		}
+		return ioutil.ReadAll(tr)
+	}
+	return nil, fmt.Errorf("%v not found in image layer", wasmImageFileName)
+}
+
+// wasmKeyChain resolves registry credentials from a docker config json, which is the content of
+// a `kubernetes.io/dockerconfigjson` or `kubernetes.io/dockercfg` secret.
+type wasmKeyChain struct {
+	data []byte
+}
+
+var _ authn.Keychain = &wasmKeyChain{}
+
+// Resolve returns the credentials of the target registry, or anonymous if there is none.
+func (k *wasmKeyChain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	auths := map[string]authn.AuthConfig{}
+	cfg := struct {
+		Auths map[string]authn.AuthConfig `json:"auths"`
+	}{}
+	if err := json.Unmarshal(k.data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse image pull secret: %v", err)
+	}
+	// Fall back to the legacy dockercfg format, which does not have the `auths` wrapper.
+	if cfg.Auths != nil {
+		auths = cfg.Auths
+	} else if err := json.Unmarshal(k.data, &auths); err != nil {
+		return nil, fmt.Errorf("failed to parse image pull secret: %v", err)
+	}
+
+	registry := target.RegistryStr()
+	for host, ac := range auths {
+		if normalizeRegistryHost(host) != registry {
+			continue
+		}
+		if ac.Auth != "" && ac.Username == "" && ac.Password == "" {
+			decoded, err := base64.StdEncoding.DecodeString(ac.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode auth of registry %v: %v", host, err)
+			}
+			parts := strings.SplitN(string(decoded), ":", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid auth of registry %v", host)
+			}
+			ac.Username, ac.Password, ac.Auth = parts[0], parts[1], ""
+		}
+		return authn.FromConfig(ac), nil
+	}
+	return authn.Anonymous, nil
+}
+
+// normalizeRegistryHost strips the scheme and path from a docker config key,
+// e.g. `https://index.docker.io/v1/` becomes `index.docker.io`.
+func normalizeRegistryHost(host string) string {
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	if host == "docker.io" {
+		return name.DefaultRegistry
+	}
+	return host
+}
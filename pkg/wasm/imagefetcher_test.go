@@ -0,0 +1,293 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wasm
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// setupRegistry starts an in-process registry. If auth is not empty, requests without
+// the basic auth `user:auth` are rejected.
+func setupRegistry(t *testing.T, auth string) string {
+	t.Helper()
+	reg := registry.New(registry.Logger(log.New(ioutil.Discard, "", 0)))
+	handler := reg
+	if auth != "" {
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if u, p, ok := r.BasicAuth(); !ok || u != "user" || p != auth {
+				w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			reg.ServeHTTP(w, r)
+		})
+	}
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Host
+}
+
+// pushImage pushes a single layer image to the registry and returns its manifest digest.
+func pushImage(t *testing.T, ref string, layer v1.Layer, mt types.MediaType, opts ...remote.Option) string {
+	t.Helper()
+	img, err := mutate.Append(empty.Image, mutate.Addendum{Layer: layer, MediaType: mt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := name.ParseReference(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(r, img, opts...); err != nil {
+		t.Fatalf("failed to push image %v: %v", ref, err)
+	}
+	d, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d.Hex
+}
+
+func wasmArtifactLayer(module []byte) v1.Layer {
+	return rawLayer{content: module}
+}
+
+// rawLayer serves the Wasm binary as is, since Wasm artifact layers are not compressed tarballs.
+type rawLayer struct {
+	content []byte
+}
+
+func (l rawLayer) Digest() (v1.Hash, error) {
+	h, _, err := v1.SHA256(bytes.NewReader(l.content))
+	return h, err
+}
+
+func (l rawLayer) DiffID() (v1.Hash, error) {
+	return l.Digest()
+}
+
+func (l rawLayer) Compressed() (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(l.content)), nil
+}
+
+func (l rawLayer) Uncompressed() (io.ReadCloser, error) {
+	return l.Compressed()
+}
+
+func (l rawLayer) Size() (int64, error) {
+	return int64(len(l.content)), nil
+}
+
+func (l rawLayer) MediaType() (types.MediaType, error) {
+	return wasmLayerMediaType, nil
+}
+
+func wasmImageLayer(t *testing.T, fileName string, module []byte) v1.Layer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: fileName, Mode: 0644, Size: int64(len(module)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(module); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	l, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestImageFetcher(t *testing.T) {
+	host := setupRegistry(t, "")
+	module := []byte("\x00asm\x01\x00\x00\x00")
+
+	artifactDigest := pushImage(t, host+"/artifact:v1", wasmArtifactLayer(module), wasmLayerMediaType)
+	imageDigest := pushImage(t, host+"/image:v1", wasmImageLayer(t, "plugin.wasm", module), types.DockerLayer)
+	pushImage(t, host+"/invalid:v1", wasmImageLayer(t, "other.wasm", module), types.DockerLayer)
+
+	cases := []struct {
+		name       string
+		url        string
+		wantDigest string
+		wantError  string
+	}{
+		{
+			name:       "wasm artifact",
+			url:        "oci://" + host + "/artifact:v1",
+			wantDigest: artifactDigest,
+		},
+		{
+			name:       "single layer image",
+			url:        "oci://" + host + "/image:v1",
+			wantDigest: imageDigest,
+		},
+		{
+			name:       "pinned digest",
+			url:        "oci://" + host + "/image@sha256:" + imageDigest,
+			wantDigest: imageDigest,
+		},
+		{
+			name:      "missing plugin.wasm",
+			url:       "oci://" + host + "/invalid:v1",
+			wantError: "plugin.wasm not found in image layer",
+		},
+		{
+			name:      "missing tag",
+			url:       "oci://" + host + "/image:v2",
+			wantError: "could not fetch manifest",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fetcher := NewImageFetcher(context.Background(), ImageFetcherOption{})
+			b, digest, err := fetcher.Fetch(c.url)
+			if c.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), c.wantError) {
+					t.Fatalf("Fetch got error %v, want error containing %q", err, c.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Fetch got unexpected error %v", err)
+			}
+			if !bytes.Equal(b, module) {
+				t.Errorf("Fetch got module %q, want %q", b, module)
+			}
+			if digest != c.wantDigest {
+				t.Errorf("Fetch got digest %v, want %v", digest, c.wantDigest)
+			}
+			_, resolved, err := fetcher.Resolve(c.url)
+			if err != nil {
+				t.Fatalf("Resolve got unexpected error %v", err)
+			}
+			if resolved != c.wantDigest {
+				t.Errorf("Resolve got digest %v, want %v", resolved, c.wantDigest)
+			}
+		})
+	}
+}
+
+func TestImageFetcherPullSecret(t *testing.T) {
+	host := setupRegistry(t, "secret")
+	module := []byte("\x00asm\x01\x00\x00\x00")
+	auth := base64.StdEncoding.EncodeToString([]byte("user:secret"))
+	pullSecret := []byte(fmt.Sprintf(`{"auths":{%q:{"auth":%q}}}`, host, auth))
+
+	pushImage(t, host+"/private:v1", wasmImageLayer(t, "plugin.wasm", module), types.DockerLayer,
+		remote.WithAuthFromKeychain(&wasmKeyChain{data: pullSecret}))
+
+	if _, _, err := NewImageFetcher(context.Background(), ImageFetcherOption{}).Fetch("oci://" + host + "/private:v1"); err == nil {
+		t.Errorf("Fetch without pull secret got no error")
+	}
+	legacySecret := []byte(fmt.Sprintf(`{"https://%s/v1/":{"auth":%q}}`, host, auth))
+	for _, secret := range [][]byte{pullSecret, legacySecret} {
+		b, _, err := NewImageFetcher(context.Background(), ImageFetcherOption{PullSecret: secret}).Fetch("oci://" + host + "/private:v1")
+		if err != nil {
+			t.Fatalf("Fetch with pull secret %s got unexpected error %v", secret, err)
+		}
+		if !bytes.Equal(b, module) {
+			t.Errorf("Fetch got module %q, want %q", b, module)
+		}
+	}
+}
+
+func TestWasmCacheImage(t *testing.T) {
+	host := setupRegistry(t, "")
+	tmpDir := t.TempDir()
+	cache := NewLocalFileCache(tmpDir, DefaultWasmModulePurgeInteval, DefaultWasmModuleExpiry)
+	defer close(cache.stopChan)
+
+	module1 := []byte("\x00asm\x01\x00\x00\x00module1")
+	module2 := []byte("\x00asm\x01\x00\x00\x00module2")
+	imageURL := "oci://" + host + "/plugin:latest"
+	digest1 := pushImage(t, host+"/plugin:latest", wasmArtifactLayer(module1), wasmLayerMediaType)
+
+	wantFilePath1 := filepath.Join(tmpDir, fmt.Sprintf("%x.wasm", sha256.Sum256(module1)))
+	gotFilePath, err := cache.Get(imageURL, "", 0, nil)
+	if err != nil {
+		t.Fatalf("failed to pull Wasm module: %v", err)
+	}
+	if gotFilePath != wantFilePath1 {
+		t.Errorf("wasm pull path got %v want %v", gotFilePath, wantFilePath1)
+	}
+	if _, ok := cache.modules[cacheKey{downloadURL: "oci://" + host + "/plugin", checksum: digest1}]; !ok {
+		t.Errorf("cache entry for digest %v not found, got %v", digest1, cache.modules)
+	}
+
+	// The checksum of an image is its manifest digest.
+	if _, err := cache.Get(imageURL, digest1, 0, nil); err != nil {
+		t.Errorf("failed to pull Wasm module with digest: %v", err)
+	}
+	if _, err := cache.Get(imageURL, "wrongdigest", 0, nil); err == nil || !strings.Contains(err.Error(), "which does not match") {
+		t.Errorf("pulling Wasm module with wrong digest got error %v", err)
+	}
+
+	// Moving the tag resolves to the new digest.
+	pushImage(t, host+"/plugin:latest", wasmArtifactLayer(module2), wasmLayerMediaType)
+	wantFilePath2 := filepath.Join(tmpDir, fmt.Sprintf("%x.wasm", sha256.Sum256(module2)))
+	gotFilePath, err = cache.Get(imageURL, "", 0, nil)
+	if err != nil {
+		t.Fatalf("failed to pull Wasm module: %v", err)
+	}
+	if gotFilePath != wantFilePath2 {
+		t.Errorf("wasm pull path got %v want %v", gotFilePath, wantFilePath2)
+	}
+
+	// Pinning the old digest still serves the cached module.
+	gotFilePath, err = cache.Get("oci://"+host+"/plugin@sha256:"+digest1, "", 0, nil)
+	if err != nil {
+		t.Fatalf("failed to pull Wasm module: %v", err)
+	}
+	if gotFilePath != wantFilePath1 {
+		t.Errorf("wasm pull path got %v want %v", gotFilePath, wantFilePath1)
+	}
+	if len(cache.modules) != 2 {
+		t.Errorf("cache entries got %d want 2", len(cache.modules))
+	}
+}
@@ -22,10 +22,30 @@ import (
 	"istio.io/istio/security/pkg/credentialfetcher/plugin"
 )
 
-func NewCredFetcher(credtype, trustdomain, jwtPath, identityProvider string) (security.CredFetcher, error) {
+// NewCredFetcher creates a credential fetcher of credtype. source is where the Exec, UnixSocket and File
+// fetchers get the credential from: respectively the command line, the socket address and the token file.
+func NewCredFetcher(credtype, trustdomain, jwtPath, identityProvider, source string) (security.CredFetcher, error) {
 	switch credtype {
 	case security.GCE:
 		return plugin.CreateGCEPlugin(trustdomain, jwtPath, identityProvider), nil
+	case security.Exec:
+		p, err := plugin.CreateExecPlugin(source, jwtPath, identityProvider)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	case security.UnixSocket:
+		p, err := plugin.CreateUnixSocketPlugin(source, jwtPath, identityProvider)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	case security.File:
+		p, err := plugin.CreateFilePlugin(source, jwtPath, identityProvider)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
 	case security.Mock: // for test only
 		return plugin.CreateMockPlugin("test_token"), nil
 	default:
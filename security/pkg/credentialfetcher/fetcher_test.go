@@ -27,6 +27,7 @@ func TestNewCredFetcher(t *testing.T) {
 		trustdomain      string
 		jwtPath          string
 		identityProvider string
+		source           string
 		expectedErr      string
 		expectedToken    string
 		expectedIdp      string
@@ -49,6 +50,25 @@ func TestNewCredFetcher(t *testing.T) {
 			expectedToken:    "test_token",
 			expectedIdp:      "fakeIDP",
 		},
+		"exec test": {
+			fetcherType:      security.Exec,
+			identityProvider: "fakeIDP",
+			source:           `["echo", "exec_token"]`,
+			expectedToken:    "exec_token",
+			expectedIdp:      "fakeIDP",
+		},
+		"exec without command": {
+			fetcherType: security.Exec,
+			expectedErr: "credential fetcher command is unset",
+		},
+		"unix socket without address": {
+			fetcherType: security.UnixSocket,
+			expectedErr: "credential fetcher socket is unset",
+		},
+		"file without path": {
+			fetcherType: security.File,
+			expectedErr: "credential fetcher token file is unset",
+		},
 		"invalid test": {
 			fetcherType:      "foo",
 			trustdomain:      "",
@@ -63,10 +83,11 @@ func TestNewCredFetcher(t *testing.T) {
 	// Disable token refresh for GCE VM credential fetcher.
 	plugin.SetTokenRotation(false)
 	for id, tc := range testCases {
+		id, tc := id, tc
 		t.Run(id, func(t *testing.T) {
 			t.Parallel()
 			cf, err := NewCredFetcher(
-				tc.fetcherType, tc.trustdomain, tc.jwtPath, tc.identityProvider)
+				tc.fetcherType, tc.trustdomain, tc.jwtPath, tc.identityProvider, tc.source)
 			if cf != nil {
 				defer cf.Stop()
 			}
@@ -85,7 +106,7 @@ func TestNewCredFetcher(t *testing.T) {
 				if idp != tc.expectedIdp {
 					t.Errorf("%s: GetIdentityProvider returned %s, expected %s", id, idp, tc.expectedIdp)
 				}
-				if tc.fetcherType == security.Mock {
+				if tc.expectedToken != "" {
 					token, err := cf.GetPlatformCredential()
 					if err != nil {
 						t.Errorf("%s: unexpected error calling GetPlatformCredential: %v", id, err)
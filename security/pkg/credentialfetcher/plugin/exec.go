@@ -0,0 +1,118 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This is the exec plugin of credentialfetcher, which gets the credential from a local command.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"istio.io/istio/pkg/security"
+)
+
+// execTimeout is the maximum duration of the credential command.
+var execTimeout = 30 * time.Second
+
+// ExecPlugin fetches the credential by running a local command, which prints the token on its standard output.
+type ExecPlugin struct {
+	*tokenRefresher
+
+	command []string
+
+	// identity provider
+	identityProvider string
+}
+
+// CreateExecPlugin creates a credential fetcher plugin running command to get the credential. The command is
+// either the path of an executable, run without arguments, or a JSON list of the executable and its arguments,
+// such as ["/usr/local/bin/get-token", "--audience", "istio-ca"]. It is not split on spaces, nor run by a shell.
+// The credential is written to jwtPath, if set.
+func CreateExecPlugin(command, jwtPath, identityProvider string) (*ExecPlugin, error) {
+	args, err := parseCommand(command)
+	if err != nil {
+		return nil, err
+	}
+	p := &ExecPlugin{
+		command:          args,
+		identityProvider: identityProvider,
+	}
+	p.tokenRefresher = newTokenRefresher(p.exec, jwtPath)
+	return p, nil
+}
+
+// parseCommand returns the arguments of command, a JSON list or the path of an executable.
+func parseCommand(command string) ([]string, error) {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return nil, fmt.Errorf("credential fetcher command is unset")
+	}
+	if !strings.HasPrefix(command, "[") {
+		return []string{command}, nil
+	}
+	var args []string
+	if err := json.Unmarshal([]byte(command), &args); err != nil {
+		return nil, fmt.Errorf("invalid credential fetcher command %s: %v", command, err)
+	}
+	if len(args) == 0 || args[0] == "" {
+		return nil, fmt.Errorf("credential fetcher command %s has no executable", command)
+	}
+	return args, nil
+}
+
+func (p *ExecPlugin) exec() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), execTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, p.command[0], p.command[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return "", fmt.Errorf("credential command %s failed: %v: %s", p.command[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("credential command %s failed: %v", p.command[0], err)
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("credential command %s returned an empty token", p.command[0])
+	}
+	return token, nil
+}
+
+// GetPlatformCredential returns the cached credential, running the command if it is about to expire.
+func (p *ExecPlugin) GetPlatformCredential() (string, error) {
+	return p.get()
+}
+
+// GetType returns credential fetcher type.
+func (p *ExecPlugin) GetType() string {
+	return security.Exec
+}
+
+// GetIdentityProvider returns the name of the identity provider that can authenticate the workload credential.
+func (p *ExecPlugin) GetIdentityProvider() string {
+	return p.identityProvider
+}
+
+func (p *ExecPlugin) Stop() {
+	p.stop()
+}
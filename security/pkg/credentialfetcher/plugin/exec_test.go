@@ -0,0 +1,128 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"istio.io/istio/pkg/security"
+)
+
+func TestExecPlugin(t *testing.T) {
+	SetTokenRotation(false)
+	t.Cleanup(func() { SetTokenRotation(true) })
+
+	dir := t.TempDir()
+	ok := filepath.Join(dir, "ok.sh")
+	writeTokenFile(t, ok, "#!/bin/sh\necho \"  $1  \"\n")
+	fail := filepath.Join(dir, "fail.sh")
+	writeTokenFile(t, fail, "#!/bin/sh\necho denied >&2\nexit 1\n")
+	empty := filepath.Join(dir, "empty.sh")
+	writeTokenFile(t, empty, "#!/bin/sh\n")
+
+	testCases := map[string]struct {
+		command     string
+		expectedErr string
+	}{
+		"token is printed": {
+			command: `["sh", "` + ok + `", "exec-token"]`,
+		},
+		"command fails": {
+			command:     `["sh", "` + fail + `"]`,
+			expectedErr: "credential command sh failed: exit status 1: denied",
+		},
+		"empty token": {
+			command:     `["sh", "` + empty + `"]`,
+			expectedErr: "credential command sh returned an empty token",
+		},
+		"command not found": {
+			command:     filepath.Join(dir, "missing"),
+			expectedErr: "no such file or directory",
+		},
+	}
+	for id, tc := range testCases {
+		t.Run(id, func(t *testing.T) {
+			jwtPath := filepath.Join(t.TempDir(), "token")
+			p, err := CreateExecPlugin(tc.command, jwtPath, "idp")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer p.Stop()
+			if p.GetType() != security.Exec || p.GetIdentityProvider() != "idp" {
+				t.Errorf("unexpected type %s or identity provider %s", p.GetType(), p.GetIdentityProvider())
+			}
+			token, err := p.GetPlatformCredential()
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("got error %v, want %q", err, tc.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifyToken(t, id, jwtPath, []string{token}, "exec-token")
+		})
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	testCases := map[string]struct {
+		command     string
+		want        []string
+		expectedErr string
+	}{
+		"path with spaces": {
+			command: "/opt/token tools/get-token",
+			want:    []string{"/opt/token tools/get-token"},
+		},
+		"arguments with spaces": {
+			command: `["/usr/bin/get-token", "--audience", "istio ca"]`,
+			want:    []string{"/usr/bin/get-token", "--audience", "istio ca"},
+		},
+		"unset": {
+			command:     " ",
+			expectedErr: "credential fetcher command is unset",
+		},
+		"invalid list": {
+			command:     `["/usr/bin/get-token"`,
+			expectedErr: "invalid credential fetcher command",
+		},
+		"empty list": {
+			command:     `[]`,
+			expectedErr: "has no executable",
+		},
+	}
+	for id, tc := range testCases {
+		t.Run(id, func(t *testing.T) {
+			got, err := parseCommand(tc.command)
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("got error %v, want %q", err, tc.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This is the file plugin of credentialfetcher, which reads the credential from a file rotated by
+// another process.
+package plugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"istio.io/istio/pkg/security"
+	"istio.io/pkg/filewatcher"
+)
+
+// FilePlugin reads the credential from a token file, and reloads it when the file changes.
+type FilePlugin struct {
+	*tokenRefresher
+
+	tokenPath string
+	watcher   filewatcher.FileWatcher
+
+	// identity provider
+	identityProvider string
+}
+
+// CreateFilePlugin creates a credential fetcher plugin reading the credential from tokenPath.
+// The credential is copied to jwtPath, if set to another file.
+func CreateFilePlugin(tokenPath, jwtPath, identityProvider string) (*FilePlugin, error) {
+	if tokenPath == "" {
+		return nil, fmt.Errorf("credential fetcher token file is unset")
+	}
+	if filepath.Clean(jwtPath) == filepath.Clean(tokenPath) {
+		jwtPath = ""
+	}
+	p := &FilePlugin{
+		tokenPath:        tokenPath,
+		watcher:          filewatcher.NewWatcher(),
+		identityProvider: identityProvider,
+	}
+	if err := p.watcher.Add(tokenPath); err != nil {
+		_ = p.watcher.Close()
+		return nil, fmt.Errorf("failed to watch token file %s: %v", tokenPath, err)
+	}
+	p.tokenRefresher = newTokenRefresher(p.read, jwtPath)
+	go p.watch()
+	return p, nil
+}
+
+func (p *FilePlugin) read() (string, error) {
+	b, err := ioutil.ReadFile(p.tokenPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %v", err)
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", p.tokenPath)
+	}
+	return token, nil
+}
+
+func (p *FilePlugin) watch() {
+	for {
+		select {
+		case <-p.watcher.Events(p.tokenPath):
+			credLog.Infof("token file %s changed, reloading credential", p.tokenPath)
+			if _, err := p.refresh(); err != nil {
+				credLog.Errorf("failed to reload credential: %v", err)
+			}
+		case err := <-p.watcher.Errors(p.tokenPath):
+			credLog.Errorf("error watching token file %s: %v", p.tokenPath, err)
+		case <-p.closing:
+			return
+		}
+	}
+}
+
+// GetPlatformCredential returns the last credential read from the token file.
+func (p *FilePlugin) GetPlatformCredential() (string, error) {
+	return p.get()
+}
+
+// GetType returns credential fetcher type.
+func (p *FilePlugin) GetType() string {
+	return security.File
+}
+
+// GetIdentityProvider returns the name of the identity provider that can authenticate the workload credential.
+func (p *FilePlugin) GetIdentityProvider() string {
+	return p.identityProvider
+}
+
+func (p *FilePlugin) Stop() {
+	p.stop()
+	_ = p.watcher.Close()
+}
@@ -0,0 +1,92 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"istio.io/istio/pkg/test/util/retry"
+)
+
+func TestFilePlugin(t *testing.T) {
+	SetTokenRotation(false)
+	t.Cleanup(func() { SetTokenRotation(true) })
+
+	dir := t.TempDir()
+	tokenPath := filepath.Join(dir, "source-token")
+	jwtPath := filepath.Join(dir, "istio-token")
+	writeTokenFile(t, tokenPath, "token-1\n")
+
+	p, err := CreateFilePlugin(tokenPath, jwtPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Stop()
+	token, err := p.GetPlatformCredential()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyToken(t, "initial token", jwtPath, []string{token}, "token-1")
+
+	// Rotate the file the way the kubelet does, by replacing it.
+	tmp := filepath.Join(dir, "tmp")
+	writeTokenFile(t, tmp, "token-2")
+	if err := os.Rename(tmp, tokenPath); err != nil {
+		t.Fatal(err)
+	}
+	retry.UntilSuccessOrFail(t, func() error {
+		token, err := p.GetPlatformCredential()
+		if err != nil {
+			return err
+		}
+		if token != "token-2" {
+			return fmt.Errorf("got token %q, want token-2", token)
+		}
+		if b, _ := ioutil.ReadFile(jwtPath); string(b) != "token-2" {
+			return fmt.Errorf("got copied token %q, want token-2", b)
+		}
+		return nil
+	}, retry.Delay(10*time.Millisecond), retry.Timeout(5*time.Second))
+}
+
+func TestFilePluginErrors(t *testing.T) {
+	SetTokenRotation(false)
+	t.Cleanup(func() { SetTokenRotation(true) })
+
+	dir := t.TempDir()
+	if _, err := CreateFilePlugin(filepath.Join(dir, "missing", "token"), "", ""); err == nil {
+		t.Error("expected an error for a token file in a missing directory")
+	}
+
+	// The token file is not copied onto itself.
+	tokenPath := filepath.Join(dir, "token")
+	writeTokenFile(t, tokenPath, "")
+	p, err := CreateFilePlugin(tokenPath, tokenPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Stop()
+	if p.jwtPath != "" {
+		t.Errorf("got jwtPath %q, want the token file not to be copied", p.jwtPath)
+	}
+	if _, err := p.GetPlatformCredential(); err == nil {
+		t.Error("expected an error for an empty token file")
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"istio.io/istio/security/pkg/util"
+	"istio.io/pkg/log"
+)
+
+var credLog = log.RegisterScope("credfetcher", "Credential fetcher plugins for istio agent", 0)
+
+// refreshRetryInterval is the delay before retrying a failed background refresh.
+var refreshRetryInterval = 30 * time.Second
+
+// tokenRefresher caches the token returned by fetch, and fetches a new one before the cached one expires.
+// It is shared by the plugins reading the token from a local source.
+type tokenRefresher struct {
+	fetch func() (string, error)
+	// jwtPath is where the token is written, to be used by the other token consumers of the agent.
+	// No file is written if empty.
+	jwtPath string
+
+	// fetchMu serializes the fetches, which run without holding mu so that the cached token stays available.
+	fetchMu sync.Mutex
+
+	mu       sync.Mutex
+	fetching bool
+	token    string
+	// refreshAt is when the cached token should be refreshed.
+	refreshAt time.Time
+	// expiry is when the cached token expires, zero if it has no expiration time.
+	expiry time.Time
+
+	closing chan struct{}
+	once    sync.Once
+}
+
+func newTokenRefresher(fetch func() (string, error), jwtPath string) *tokenRefresher {
+	r := &tokenRefresher{
+		fetch:   fetch,
+		jwtPath: jwtPath,
+		closing: make(chan struct{}),
+	}
+	if rotateToken {
+		go r.startTokenRotationJob()
+	}
+	return r
+}
+
+func (r *tokenRefresher) stop() {
+	r.once.Do(func() { close(r.closing) })
+}
+
+func (r *tokenRefresher) startTokenRotationJob() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			wait := refreshRetryInterval
+			if _, err := r.get(); err != nil {
+				credLog.Errorf("credential refresh failed: %v", err)
+			} else if next := time.Until(r.nextRefresh()); next > 0 {
+				wait = next
+			}
+			timer.Reset(wait)
+		case <-r.closing:
+			return
+		}
+	}
+}
+
+func (r *tokenRefresher) nextRefresh() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.refreshAt
+}
+
+// get returns the cached token, fetching a new one if there is none or if it should be refreshed.
+// While another call fetches a token, the cached one is returned if it has not expired yet.
+func (r *tokenRefresher) get() (string, error) {
+	r.mu.Lock()
+	if r.token != "" && (time.Now().Before(r.refreshAt) || r.fetching && r.validLocked()) {
+		token := r.token
+		r.mu.Unlock()
+		return token, nil
+	}
+	r.mu.Unlock()
+	return r.doRefresh(true)
+}
+
+// refresh fetches a new token, regardless of the cached one.
+func (r *tokenRefresher) refresh() (string, error) {
+	return r.doRefresh(false)
+}
+
+// doRefresh fetches a new token and caches it. If onlyIfStale is set, the token cached by a concurrent fetch is
+// returned instead of fetching a new one.
+func (r *tokenRefresher) doRefresh(onlyIfStale bool) (string, error) {
+	r.fetchMu.Lock()
+	defer r.fetchMu.Unlock()
+
+	r.mu.Lock()
+	if onlyIfStale && r.token != "" && time.Now().Before(r.refreshAt) {
+		token := r.token
+		r.mu.Unlock()
+		return token, nil
+	}
+	r.fetching = true
+	r.mu.Unlock()
+
+	token, err := r.fetch()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fetching = false
+	if err != nil {
+		// Keep using the cached token until it expires, the source may only be temporarily unavailable.
+		if r.token != "" && r.validLocked() {
+			credLog.Warnf("failed to fetch credential, using the cached one: %v", err)
+			return r.token, nil
+		}
+		return "", err
+	}
+	if r.jwtPath != "" {
+		if err := ioutil.WriteFile(r.jwtPath, []byte(token), 0o640); err != nil {
+			credLog.Errorf("Encountered error when writing credential: %v", err)
+			return "", err
+		}
+	}
+	now := time.Now()
+	r.token = token
+	r.expiry, r.refreshAt = refreshTime(token, now)
+	credLog.Debugf("got credential of length %d, expiration: %v, refresh at: %v", len(token), r.expiry, r.refreshAt)
+	return token, nil
+}
+
+// validLocked returns true if the cached token has not expired. Must be called with mu held.
+func (r *tokenRefresher) validLocked() bool {
+	return r.expiry.IsZero() || time.Now().Before(r.expiry)
+}
+
+// refreshTime returns the expiration time of the token and when it should be refreshed. Tokens are
+// refreshed within the grace period before they expire, or half way through their lifetime if it is
+// shorter. Tokens without expiration time are refreshed every rotation interval.
+func refreshTime(token string, now time.Time) (time.Time, time.Time) {
+	exp, err := util.GetExp(token)
+	if err != nil || exp.IsZero() {
+		return time.Time{}, now.Add(rotationInterval)
+	}
+	grace := gracePeriod
+	if half := exp.Sub(now) / 2; half < grace {
+		grace = half
+	}
+	return exp, exp.Add(-grace)
+}
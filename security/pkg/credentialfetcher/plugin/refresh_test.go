@@ -0,0 +1,205 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testJwt returns an unsigned JWT expiring at exp.
+func testJwt(exp time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+	return "eyJhbGciOiJub25lIn0." + payload + ".sig"
+}
+
+func TestRefreshTime(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	testCases := map[string]struct {
+		token         string
+		wantExpiry    time.Time
+		wantRefreshAt time.Time
+	}{
+		"long lived token is refreshed in the grace period": {
+			token:         testJwt(now.Add(time.Hour)),
+			wantExpiry:    now.Add(time.Hour),
+			wantRefreshAt: now.Add(time.Hour - gracePeriod),
+		},
+		"short lived token is refreshed half way through its lifetime": {
+			token:         testJwt(now.Add(10 * time.Minute)),
+			wantExpiry:    now.Add(10 * time.Minute),
+			wantRefreshAt: now.Add(5 * time.Minute),
+		},
+		"token without expiration time is refreshed every rotation interval": {
+			token:         firstPartyJwt,
+			wantRefreshAt: now.Add(rotationInterval),
+		},
+		"opaque token is refreshed every rotation interval": {
+			token:         "opaque",
+			wantRefreshAt: now.Add(rotationInterval),
+		},
+	}
+	for id, tc := range testCases {
+		t.Run(id, func(t *testing.T) {
+			expiry, refreshAt := refreshTime(tc.token, now)
+			if !expiry.Equal(tc.wantExpiry) || !refreshAt.Equal(tc.wantRefreshAt) {
+				t.Errorf("got expiry %v refresh at %v, want expiry %v refresh at %v",
+					expiry, refreshAt, tc.wantExpiry, tc.wantRefreshAt)
+			}
+		})
+	}
+}
+
+func TestTokenRefresher(t *testing.T) {
+	SetTokenRotation(false)
+	t.Cleanup(func() { SetTokenRotation(true) })
+
+	valid := testJwt(time.Now().Add(time.Hour))
+	expiring := testJwt(time.Now().Add(time.Minute))
+	var token string
+	var fetchErr error
+	calls := 0
+	jwtPath := filepath.Join(t.TempDir(), "token")
+	r := newTokenRefresher(func() (string, error) {
+		calls++
+		return token, fetchErr
+	}, jwtPath)
+	defer r.stop()
+
+	get := func(want string, wantCalls int) {
+		t.Helper()
+		got, err := r.get()
+		if err != nil {
+			t.Fatalf("get() returns err: %v", err)
+		}
+		if got != want || calls != wantCalls {
+			t.Fatalf("got token %q after %d fetches, want %q after %d fetches", got, calls, want, wantCalls)
+		}
+	}
+
+	// The first call fails, there is no cached token.
+	fetchErr = errors.New("unavailable")
+	if _, err := r.get(); err == nil {
+		t.Fatal("expected an error without cached token")
+	}
+
+	// The token is cached until it should be refreshed, and written to jwtPath.
+	token, fetchErr = valid, nil
+	get(valid, 2)
+	get(valid, 2)
+	verifyToken(t, "valid token", jwtPath, []string{valid}, valid)
+
+	// Tokens are fetched again once they should be refreshed.
+	token = expiring
+	r.refreshAt = time.Now().Add(-time.Second)
+	get(expiring, 3)
+	verifyToken(t, "refreshed token", jwtPath, []string{expiring}, expiring)
+
+	// The cached token is used while the source is unavailable, until it expires.
+	fetchErr = errors.New("unavailable")
+	r.refreshAt = time.Now().Add(-time.Second)
+	get(expiring, 4)
+	r.expiry = time.Now().Add(-time.Second)
+	if _, err := r.get(); err == nil {
+		t.Fatal("expected an error once the cached token expired")
+	}
+}
+
+func TestTokenRefresherSlowFetch(t *testing.T) {
+	SetTokenRotation(false)
+	t.Cleanup(func() { SetTokenRotation(true) })
+
+	valid := testJwt(time.Now().Add(time.Hour))
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	r := newTokenRefresher(func() (string, error) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		return valid, nil
+	}, "")
+	defer r.stop()
+
+	close(release)
+	if _, err := r.get(); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	// While a token is fetched, the cached token is returned if it has not expired.
+	release = make(chan struct{})
+	r.mu.Lock()
+	r.refreshAt = time.Now().Add(-time.Second)
+	r.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		_, _ = r.refresh()
+		close(done)
+	}()
+	<-started
+	got := make(chan string)
+	go func() {
+		token, _ := r.get()
+		got <- token
+	}()
+	select {
+	case token := <-got:
+		if token != valid {
+			t.Fatalf("got token %q, want the cached token", token)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("get() blocked by a fetch while the cached token is valid")
+	}
+	close(release)
+	<-done
+}
+
+func TestTokenRotation(t *testing.T) {
+	refreshRetryInterval = 10 * time.Millisecond
+	t.Cleanup(func() { refreshRetryInterval = 30 * time.Second })
+	SetTokenRotation(true)
+
+	calls := make(chan struct{}, 10)
+	r := newTokenRefresher(func() (string, error) {
+		select {
+		case calls <- struct{}{}:
+		default:
+		}
+		// Already expired, it is refreshed as soon as possible.
+		return testJwt(time.Now().Add(-time.Minute)), nil
+	}, "")
+	defer r.stop()
+	for i := 0; i < 3; i++ {
+		select {
+		case <-calls:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %d background refreshes, want 3", i)
+		}
+	}
+}
+
+func writeTokenFile(t *testing.T, path, token string) {
+	t.Helper()
+	if err := ioutil.WriteFile(path, []byte(token), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This is the Unix domain socket plugin of credentialfetcher, which gets the credential from a local
+// token endpoint.
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"istio.io/istio/pkg/security"
+)
+
+// socketRequestTimeout is the maximum duration of a request to the token endpoint.
+var socketRequestTimeout = 10 * time.Second
+
+// UnixSocketPlugin fetches the credential with an HTTP GET request to a token endpoint listening
+// on a Unix domain socket. The endpoint returns either the raw token, or a JSON object holding it
+// in its "token" field.
+type UnixSocketPlugin struct {
+	*tokenRefresher
+
+	client *http.Client
+	path   string
+
+	// identity provider
+	identityProvider string
+}
+
+// CreateUnixSocketPlugin creates a credential fetcher plugin getting the credential from the endpoint
+// at address, in the form <socket path>[:<HTTP path>]. The HTTP path defaults to "/".
+// The credential is written to jwtPath, if set.
+func CreateUnixSocketPlugin(address, jwtPath, identityProvider string) (*UnixSocketPlugin, error) {
+	socket, path := address, "/"
+	if i := strings.Index(address, ":/"); i >= 0 {
+		socket, path = address[:i], address[i+1:]
+	}
+	if socket == "" {
+		return nil, fmt.Errorf("credential fetcher socket is unset")
+	}
+	p := &UnixSocketPlugin{
+		client: &http.Client{
+			Timeout: socketRequestTimeout,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+		path:             path,
+		identityProvider: identityProvider,
+	}
+	p.tokenRefresher = newTokenRefresher(p.request, jwtPath)
+	return p, nil
+}
+
+func (p *UnixSocketPlugin) request() (string, error) {
+	// The host is ignored, the connection is always made to the socket.
+	resp, err := p.client.Get("http://localhost" + p.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get credential from token endpoint: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read credential from token endpoint: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return parseTokenResponse(body)
+}
+
+// parseTokenResponse returns the token held in body, either raw or as the "token" field of a JSON object.
+func parseTokenResponse(body []byte) (string, error) {
+	token := strings.TrimSpace(string(body))
+	if strings.HasPrefix(token, "{") {
+		var r struct {
+			Token string `json:"token"`
+		}
+		if err := json.Unmarshal(body, &r); err != nil {
+			return "", fmt.Errorf("failed to parse token endpoint response: %v", err)
+		}
+		token = r.Token
+	}
+	if token == "" {
+		return "", fmt.Errorf("token endpoint returned an empty token")
+	}
+	return token, nil
+}
+
+// GetPlatformCredential returns the cached credential, requesting a new one if it is about to expire.
+func (p *UnixSocketPlugin) GetPlatformCredential() (string, error) {
+	return p.get()
+}
+
+// GetType returns credential fetcher type.
+func (p *UnixSocketPlugin) GetType() string {
+	return security.UnixSocket
+}
+
+// GetIdentityProvider returns the name of the identity provider that can authenticate the workload credential.
+func (p *UnixSocketPlugin) GetIdentityProvider() string {
+	return p.identityProvider
+}
+
+func (p *UnixSocketPlugin) Stop() {
+	p.stop()
+	p.client.CloseIdleConnections()
+}
@@ -0,0 +1,99 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnixSocketPlugin(t *testing.T) {
+	SetTokenRotation(false)
+	t.Cleanup(func() { SetTokenRotation(true) })
+
+	socket := filepath.Join(t.TempDir(), "token.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, "raw-token\n")
+		case "/v1/token":
+			fmt.Fprint(w, `{"token":"json-token","expires_in":3600}`)
+		case "/empty":
+			fmt.Fprint(w, `{}`)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	server.Listener = l
+	server.Start()
+	t.Cleanup(server.Close)
+
+	testCases := map[string]struct {
+		address       string
+		expectedToken string
+		expectedErr   string
+	}{
+		"raw token": {
+			address:       socket,
+			expectedToken: "raw-token",
+		},
+		"json token": {
+			address:       socket + ":/v1/token",
+			expectedToken: "json-token",
+		},
+		"empty token": {
+			address:     socket + ":/empty",
+			expectedErr: "token endpoint returned an empty token",
+		},
+		"error status": {
+			address:     socket + ":/missing",
+			expectedErr: "token endpoint returned status 404: not found",
+		},
+		"socket not found": {
+			address:     socket + ".missing",
+			expectedErr: "failed to get credential from token endpoint",
+		},
+	}
+	for id, tc := range testCases {
+		t.Run(id, func(t *testing.T) {
+			jwtPath := filepath.Join(t.TempDir(), "token")
+			p, err := CreateUnixSocketPlugin(tc.address, jwtPath, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer p.Stop()
+			token, err := p.GetPlatformCredential()
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("got error %v, want %q", err, tc.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifyToken(t, id, jwtPath, []string{token}, tc.expectedToken)
+		})
+	}
+}
@@ -70,7 +70,7 @@ func TestGetTokenForXDS(t *testing.T) {
 		SecretRotationGracePeriodRatio: 0.5,
 	}
 	secOpts, err := options.SetupSecurityOptions(proxyConfig, sop, jwtPolicy,
-		credFetcherTypeEnv, credIdentityProvider)
+		credFetcherTypeEnv, credIdentityProvider, "")
 	if err != nil {
 		t.Fatalf("failed to setup security options: %v", err)
 	}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sync"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -267,6 +268,12 @@ type IstioCA struct {
 	// rootCertRotator periodically rotates self-signed root cert for CA. It is nil
 	// if CA is not self-signed CA.
 	rootCertRotator *SelfSignedCARootCertRotator
+
+	revocationMutex sync.RWMutex
+	revocationList  *RevocationList
+	// crl is the cached CertificateRevocationList, signed by crlIssuer.
+	crl       []byte
+	crlIssuer []byte
 }
 
 // NewIstioCA returns a new IstioCA instance.
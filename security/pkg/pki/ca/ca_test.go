@@ -463,7 +463,7 @@ func TestSignCSRForCA(t *testing.T) {
 		}
 
 		fields := &util.VerifyFields{
-			KeyUsage: x509.KeyUsageCertSign,
+			KeyUsage: x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
 			IsCA:     true,
 			Host:     subjectID,
 		}
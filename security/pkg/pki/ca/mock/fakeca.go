@@ -26,6 +26,8 @@ type FakeCA struct {
 	SignErr       *caerror.Error
 	KeyCertBundle *util.KeyCertBundle
 	ReceivedIDs   []string
+	// RevokedIDs are the identities reported as revoked by IsRevoked.
+	RevokedIDs []string
 }
 
 // Sign returns the SignErr if SignErr is not nil, otherwise, it returns SignedCert.
@@ -57,3 +59,15 @@ func (ca *FakeCA) GetCAKeyCertBundle() *util.KeyCertBundle {
 	}
 	return ca.KeyCertBundle
 }
+
+// IsRevoked returns true if one of the identities is in RevokedIDs.
+func (ca *FakeCA) IsRevoked(identities []string) bool {
+	for _, id := range identities {
+		for _, revoked := range ca.RevokedIDs {
+			if id == revoked {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,328 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ca
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"istio.io/istio/pkg/spiffe"
+)
+
+const (
+	// RevocationListConfigMap is the name of the ConfigMap, in the namespace of the CA, holding the
+	// identities and the certificate serial numbers revoked by the Istio CA.
+	RevocationListConfigMap = "istio-ca-revocation-list"
+	// RevocationListKey is the key of the revocation list in RevocationListConfigMap.
+	RevocationListKey = "revoked"
+	// RevocationListCRLsKey is the key of the PEM encoded CRLs of the other CAs trusted by the proxies in
+	// RevocationListConfigMap: the CAs above a plugged-in CA certificate and the other trust anchors of the mesh.
+	RevocationListCRLsKey = "crls"
+)
+
+// RevocationList is the list of the identities and the certificate serial numbers revoked by the Istio CA.
+// It is stored as text, with one SPIFFE ID or hexadecimal serial number per line. Empty lines and lines
+// starting with '#' are ignored.
+type RevocationList struct {
+	// Identities are the revoked SPIFFE IDs. The CA refuses to sign certificates for them, and istiod denies
+	// their requests in the proxies by principal, whichever CA replica signed their certificates.
+	Identities map[string]struct{}
+	// Serials are the revoked serial numbers, in lower case hexadecimal.
+	Serials map[string]struct{}
+	// CRLs are the revocation lists of the other CAs trusted by the proxies, pushed along with the CRL of the
+	// Istio CA. The proxies reject the peer certificates issued by a CA without a CRL.
+	CRLs []*pkix.CertificateList
+}
+
+// NewRevocationList returns an empty RevocationList.
+func NewRevocationList() *RevocationList {
+	return &RevocationList{
+		Identities: map[string]struct{}{},
+		Serials:    map[string]struct{}{},
+	}
+}
+
+// ParseRevocationList parses a revocation list in the format of RevocationListConfigMap.
+func ParseRevocationList(data string) (*RevocationList, error) {
+	rl := NewRevocationList()
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := rl.Add(line); err != nil {
+			return nil, err
+		}
+	}
+	return rl, nil
+}
+
+// ParseCRLs parses the PEM encoded CRLs of RevocationListCRLsKey.
+func ParseCRLs(data string) ([]*pkix.CertificateList, error) {
+	var crls []*pkix.CertificateList
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "X509 CRL" {
+			return nil, fmt.Errorf("unexpected PEM block %q, expected X509 CRL", block.Type)
+		}
+		crl, err := x509.ParseDERCRL(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid revocation list: %v", err)
+		}
+		crls = append(crls, crl)
+	}
+	if len(bytes.TrimSpace(rest)) != 0 {
+		return nil, fmt.Errorf("invalid PEM encoded revocation lists")
+	}
+	return crls, nil
+}
+
+// RevokedIdentities returns the sorted revoked SPIFFE IDs.
+func (rl *RevocationList) RevokedIdentities() []string {
+	if rl == nil {
+		return nil
+	}
+	ids := make([]string, 0, len(rl.Identities))
+	for id := range rl.Identities {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// normalizeRevocationEntry returns whether the entry is an identity and its canonical form.
+func normalizeRevocationEntry(entry string) (bool, string, error) {
+	if strings.HasPrefix(entry, spiffe.URIPrefix) {
+		return true, entry, nil
+	}
+	serial, ok := new(big.Int).SetString(strings.ReplaceAll(entry, ":", ""), 16)
+	if !ok || serial.Sign() <= 0 {
+		return false, "", fmt.Errorf("%q is neither a SPIFFE ID nor a hexadecimal serial number", entry)
+	}
+	return false, serial.Text(16), nil
+}
+
+// Add revokes an identity or a serial number.
+func (rl *RevocationList) Add(entry string) error {
+	identity, key, err := normalizeRevocationEntry(entry)
+	if err != nil {
+		return err
+	}
+	if identity {
+		rl.Identities[key] = struct{}{}
+	} else {
+		rl.Serials[key] = struct{}{}
+	}
+	return nil
+}
+
+// Remove removes an identity or a serial number from the list. It returns false if it was not revoked.
+func (rl *RevocationList) Remove(entry string) (bool, error) {
+	identity, key, err := normalizeRevocationEntry(entry)
+	if err != nil {
+		return false, err
+	}
+	entries := rl.Serials
+	if identity {
+		entries = rl.Identities
+	}
+	if _, f := entries[key]; !f {
+		return false, nil
+	}
+	delete(entries, key)
+	return true, nil
+}
+
+// Empty returns true if nothing is revoked.
+func (rl *RevocationList) Empty() bool {
+	return rl == nil || len(rl.Identities)+len(rl.Serials) == 0
+}
+
+// IsRevoked returns true if one of the identities is revoked.
+func (rl *RevocationList) IsRevoked(identities []string) bool {
+	if rl == nil {
+		return false
+	}
+	for _, id := range identities {
+		if _, f := rl.Identities[id]; f {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the list in the format of RevocationListConfigMap, identities first.
+func (rl *RevocationList) String() string {
+	var lines []string
+	for _, entries := range []map[string]struct{}{rl.Identities, rl.Serials} {
+		sorted := make([]string, 0, len(entries))
+		for e := range entries {
+			sorted = append(sorted, e)
+		}
+		sort.Strings(sorted)
+		lines = append(lines, sorted...)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// UpdateRevocationList replaces the revocation list of the CA.
+func (ca *IstioCA) UpdateRevocationList(rl *RevocationList) {
+	ca.revocationMutex.Lock()
+	defer ca.revocationMutex.Unlock()
+	ca.revocationList = rl
+	ca.crl = nil
+}
+
+// IsRevoked returns true if one of the identities is revoked.
+func (ca *IstioCA) IsRevoked(identities []string) bool {
+	ca.revocationMutex.RLock()
+	defer ca.revocationMutex.RUnlock()
+	return ca.revocationList.IsRevoked(identities)
+}
+
+// CertificateRevocationList returns the PEM encoded CRLs pushed to the proxies: the CRL of the CA, listing the
+// revoked serial numbers, followed by the CRLs of the other CAs of the revocation list. It returns nil if no serial
+// number is revoked, since the revoked identities are denied by principal instead.
+// The proxies check every certificate of a peer chain against the CRL of its issuer, so a CRL must be provided
+// for each CA above the signing certificate of a plugged-in CA, and for each of the other trustAnchors.
+func (ca *IstioCA) CertificateRevocationList(trustAnchors []*x509.Certificate) ([]byte, error) {
+	signingCert, signingKey, certChain, rootCert := ca.keyCertBundle.GetAll()
+	if signingCert == nil {
+		return nil, fmt.Errorf("istio CA is not ready")
+	}
+
+	ca.revocationMutex.Lock()
+	defer ca.revocationMutex.Unlock()
+	if ca.revocationList == nil || len(ca.revocationList.Serials) == 0 {
+		return nil, nil
+	}
+
+	crl, err := ca.signRevocationList(signingCert, signingKey)
+	if err != nil {
+		return nil, err
+	}
+	issuers, err := parseCertificates(append(certChain, rootCert...))
+	if err != nil {
+		return nil, err
+	}
+	own, err := x509.ParseCRL(crl)
+	if err != nil {
+		return nil, err
+	}
+	// The CRL of the CA also covers the previous root certificates sharing its name and key.
+	crls := append([]*pkix.CertificateList{own}, ca.revocationList.CRLs...)
+	now := time.Now()
+	seen := map[string]bool{}
+	out := append([]byte(nil), crl...)
+	for _, issuer := range append(issuers, trustAnchors...) {
+		if seen[string(issuer.Raw)] {
+			continue
+		}
+		seen[string(issuer.Raw)] = true
+		found := false
+		for _, c := range crls {
+			if issuer.CheckCRLSignature(c) == nil && !c.HasExpired(now) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no valid revocation list of the CA %q in the %s key of ConfigMap %s",
+				issuer.Subject, RevocationListCRLsKey, RevocationListConfigMap)
+		}
+	}
+	for _, c := range ca.revocationList.CRLs {
+		der, err := asn1.Marshal(*c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the revocation list of %q: %v", c.TBSCertList.Issuer, err)
+		}
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})...)
+	}
+	return out, nil
+}
+
+// signRevocationList returns the PEM encoded CRL of the revoked serial numbers, signed by the CA certificate.
+// The CRL is cached until the revocation list or the CA certificate changes.
+func (ca *IstioCA) signRevocationList(signingCert *x509.Certificate, signingKey *crypto.PrivateKey) ([]byte, error) {
+	if ca.crl != nil && bytes.Equal(ca.crlIssuer, signingCert.Raw) {
+		return ca.crl, nil
+	}
+	if signingCert.KeyUsage&x509.KeyUsageCRLSign == 0 {
+		return nil, fmt.Errorf("the CA certificate is not allowed to sign revocation lists")
+	}
+	signer, ok := (*signingKey).(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported CA private key type %T", *signingKey)
+	}
+
+	now := time.Now()
+	revoked := make([]pkix.RevokedCertificate, 0, len(ca.revocationList.Serials))
+	for s := range ca.revocationList.Serials {
+		serial, _ := new(big.Int).SetString(s, 16)
+		revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: serial, RevocationTime: now})
+	}
+	sort.Slice(revoked, func(i, j int) bool {
+		return revoked[i].SerialNumber.Cmp(revoked[j].SerialNumber) < 0
+	})
+
+	template := &x509.RevocationList{
+		Number:              big.NewInt(now.UnixNano()),
+		ThisUpdate:          now,
+		NextUpdate:          signingCert.NotAfter,
+		RevokedCertificates: revoked,
+	}
+	crlBytes, err := x509.CreateRevocationList(rand.Reader, template, signingCert, signer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the revocation list: %v", err)
+	}
+	ca.crl = pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crlBytes})
+	ca.crlIssuer = signingCert.Raw
+	return ca.crl, nil
+}
+
+// parseCertificates parses the PEM encoded certificates of a chain or a bundle.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs, nil
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the CA certificates: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+}
@@ -0,0 +1,312 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ca
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"istio.io/istio/security/pkg/pki/util"
+)
+
+func TestParseRevocationList(t *testing.T) {
+	cases := map[string]struct {
+		data       string
+		identities []string
+		serials    []string
+		str        string
+		err        bool
+	}{
+		"empty": {
+			data: "\n# nothing revoked\n",
+			str:  "",
+		},
+		"identities and serials": {
+			data:       "  spiffe://cluster.local/ns/foo/sa/bar\n0A:1B:ff\n# comment\n00AB\n",
+			identities: []string{"spiffe://cluster.local/ns/foo/sa/bar"},
+			serials:    []string{"a1bff", "ab"},
+			str:        "spiffe://cluster.local/ns/foo/sa/bar\na1bff\nab\n",
+		},
+		"invalid entry": {
+			data: "foo/bar",
+			err:  true,
+		},
+		"zero serial": {
+			data: "00",
+			err:  true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rl, err := ParseRevocationList(tc.data)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rl.Identities) != len(tc.identities) || len(rl.Serials) != len(tc.serials) {
+				t.Fatalf("unexpected list %v", rl)
+			}
+			for _, id := range tc.identities {
+				if _, f := rl.Identities[id]; !f {
+					t.Errorf("identity %s not found", id)
+				}
+			}
+			for _, s := range tc.serials {
+				if _, f := rl.Serials[s]; !f {
+					t.Errorf("serial %s not found", s)
+				}
+			}
+			if got := rl.String(); got != tc.str {
+				t.Errorf("got %q, want %q", got, tc.str)
+			}
+		})
+	}
+}
+
+func TestRevocationListRemove(t *testing.T) {
+	rl, err := ParseRevocationList("spiffe://cluster.local/ns/foo/sa/bar\nab\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := rl.Remove("AB"); err != nil || !removed {
+		t.Fatalf("expected the serial to be removed: %v", err)
+	}
+	if removed, err := rl.Remove("spiffe://cluster.local/ns/foo/sa/other"); err != nil || removed {
+		t.Fatalf("expected the identity not to be found: %v", err)
+	}
+	if !rl.IsRevoked([]string{"spiffe://cluster.local/ns/foo/sa/bar"}) {
+		t.Fatal("expected the identity to be revoked")
+	}
+}
+
+// parseCRLs returns the revoked serial numbers of each CRL of a PEM bundle, by issuer.
+func parseCRLs(t *testing.T, data []byte) map[string][]string {
+	t.Helper()
+	crls, err := ParseCRLs(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := map[string][]string{}
+	for _, crl := range crls {
+		issuer := crl.TBSCertList.Issuer.String()
+		out[issuer] = []string{}
+		for _, r := range crl.TBSCertList.RevokedCertificates {
+			out[issuer] = append(out[issuer], r.SerialNumber.Text(16))
+		}
+	}
+	return out
+}
+
+func genCAKeyCert(t *testing.T, org string, signerCert *x509.Certificate, signerKey crypto.PrivateKey) (
+	[]byte, []byte, *x509.Certificate, crypto.PrivateKey) {
+	t.Helper()
+	certPem, keyPem, err := util.GenCertKeyFromOptions(util.CertOptions{
+		IsCA:         true,
+		IsSelfSigned: signerCert == nil,
+		SignerCert:   signerCert,
+		SignerPriv:   signerKey,
+		TTL:          time.Hour,
+		Org:          org,
+		RSAKeySize:   2048,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := util.ParsePemEncodedCertificate(certPem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := util.ParsePemEncodedKey(keyPem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certPem, keyPem, cert, key
+}
+
+func genCRL(t *testing.T, cert *x509.Certificate, key crypto.PrivateKey, serials ...int64) []byte {
+	t.Helper()
+	var revoked []pkix.RevokedCertificate
+	for _, s := range serials {
+		revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: big.NewInt(s), RevocationTime: time.Now()})
+	}
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:              big.NewInt(1),
+		ThisUpdate:          time.Now(),
+		NextUpdate:          time.Now().Add(time.Hour),
+		RevokedCertificates: revoked,
+	}, cert, key.(crypto.Signer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+}
+
+func TestCertificateRevocationList(t *testing.T) {
+	rootCertPem, rootKeyPem, _, _ := genCAKeyCert(t, "Root CA", nil, nil)
+	bundle, err := util.NewVerifiedKeyCertBundleFromPem(rootCertPem, rootKeyPem, nil, rootCertPem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := NewIstioCA(&IstioCAOptions{
+		DefaultCertTTL: time.Hour,
+		MaxCertTTL:     time.Hour,
+		KeyCertBundle:  bundle,
+		RotatorConfig:  &SelfSignedCARootCertRotatorConfig{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if crl, err := ca.CertificateRevocationList(nil); err != nil || crl != nil {
+		t.Fatalf("expected no revocation list, got %v: %v", crl, err)
+	}
+
+	// Revoked identities are denied by principal, they do not need a CRL.
+	revokedID := "spiffe://cluster.local/ns/foo/sa/revoked"
+	rl := NewRevocationList()
+	if err := rl.Add(revokedID); err != nil {
+		t.Fatal(err)
+	}
+	ca.UpdateRevocationList(rl)
+	if !ca.IsRevoked([]string{revokedID}) {
+		t.Fatal("expected the identity to be revoked")
+	}
+	if crl, err := ca.CertificateRevocationList(nil); err != nil || crl != nil {
+		t.Fatalf("expected no revocation list for revoked identities, got %v: %v", crl, err)
+	}
+
+	if err := rl.Add("0a:0b"); err != nil {
+		t.Fatal(err)
+	}
+	ca.UpdateRevocationList(rl)
+	crlPem, err := ca.CertificateRevocationList(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(crlPem)
+	if block == nil || block.Type != "X509 CRL" {
+		t.Fatalf("invalid revocation list %s", crlPem)
+	}
+	crl, err := x509.ParseCRL(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingCert, _, _, _ := ca.GetCAKeyCertBundle().GetAll()
+	if err := signingCert.CheckCRLSignature(crl); err != nil {
+		t.Fatalf("invalid revocation list signature: %v", err)
+	}
+	if got := parseCRLs(t, crlPem); !reflect.DeepEqual(got, map[string][]string{"O=Root CA": {"a0b"}}) {
+		t.Fatalf("unexpected revoked certificates %v", got)
+	}
+
+	// The revocation list is cached until the list changes.
+	if again, _ := ca.CertificateRevocationList(nil); string(again) != string(crlPem) {
+		t.Fatal("expected the cached revocation list")
+	}
+}
+
+func TestCertificateRevocationListPluggedCA(t *testing.T) {
+	rootCertPem, _, rootCert, rootKey := genCAKeyCert(t, "Root CA", nil, nil)
+	caCertPem, caKeyPem, _, _ := genCAKeyCert(t, "Intermediate CA", rootCert, rootKey)
+	bundle, err := util.NewVerifiedKeyCertBundleFromPem(caCertPem, caKeyPem, caCertPem, rootCertPem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := NewIstioCA(&IstioCAOptions{
+		CAType:         pluggedCertCA,
+		DefaultCertTTL: time.Hour,
+		MaxCertTTL:     time.Hour,
+		KeyCertBundle:  bundle,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rl, err := ParseRevocationList("0a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca.UpdateRevocationList(rl)
+	if _, err := ca.CertificateRevocationList(nil); err == nil {
+		t.Fatal("expected an error without the CRL of the root CA")
+	}
+
+	// The CRL of another CA does not cover the root CA.
+	_, _, otherCert, otherKey := genCAKeyCert(t, "Other CA", nil, nil)
+	otherCRL := genCRL(t, otherCert, otherKey, 3)
+	if rl.CRLs, err = ParseCRLs(string(otherCRL)); err != nil {
+		t.Fatal(err)
+	}
+	ca.UpdateRevocationList(rl)
+	if _, err := ca.CertificateRevocationList(nil); err == nil {
+		t.Fatal("expected an error without the CRL of the root CA")
+	}
+
+	if rl.CRLs, err = ParseCRLs(string(genCRL(t, rootCert, rootKey, 2)) + string(otherCRL)); err != nil {
+		t.Fatal(err)
+	}
+	ca.UpdateRevocationList(rl)
+	crl, err := ca.CertificateRevocationList(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]string{
+		"O=Intermediate CA": {"a"},
+		"O=Root CA":         {"2"},
+		"O=Other CA":        {"3"},
+	}
+	if got := parseCRLs(t, crl); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected revocation lists %v, want %v", got, expected)
+	}
+
+	// Each trust anchor of a multi-root mesh needs a CRL.
+	if _, err := ca.CertificateRevocationList([]*x509.Certificate{otherCert}); err != nil {
+		t.Fatal(err)
+	}
+	_, _, missingCert, _ := genCAKeyCert(t, "Missing CA", nil, nil)
+	if _, err := ca.CertificateRevocationList([]*x509.Certificate{otherCert, missingCert}); err == nil {
+		t.Fatal("expected an error without the CRL of a trust anchor")
+	}
+}
+
+func TestParseCRLs(t *testing.T) {
+	_, _, cert, key := genCAKeyCert(t, "Root CA", nil, nil)
+	crls, err := ParseCRLs(string(genCRL(t, cert, key, 1)) + "\n" + string(genCRL(t, cert, key, 2)))
+	if err != nil || len(crls) != 2 {
+		t.Fatalf("expected 2 revocation lists, got %d: %v", len(crls), err)
+	}
+	if crls, err := ParseCRLs(""); err != nil || len(crls) != 0 {
+		t.Fatalf("expected no revocation list, got %d: %v", len(crls), err)
+	}
+	certPem, _, _, _ := genCAKeyCert(t, "Root CA", nil, nil)
+	if _, err := ParseCRLs(string(certPem)); err == nil {
+		t.Fatal("expected an error for a certificate")
+	}
+	if _, err := ParseCRLs("not a crl"); err == nil {
+		t.Fatal("expected an error for invalid data")
+	}
+}
@@ -50,6 +50,16 @@ type IstioRAOptions struct {
 	K8sClient certificatesv1beta1.CertificatesV1beta1Interface
 	// TrustDomain
 	TrustDomain string
+	// SignerAddr : Address of the external signer when using the Istio CA gRPC API
+	SignerAddr string
+	// SignerClientCertFile : File containing the PEM encoded client certificate used to authenticate to the external signer
+	SignerClientCertFile string
+	// SignerClientKeyFile : File containing the PEM encoded private key of SignerClientCertFile
+	SignerClientKeyFile string
+	// SignerTimeout : Timeout of each request to the external signer
+	SignerTimeout time.Duration
+	// SignerRetries : Number of times a failed request to the external signer is retried
+	SignerRetries int
 }
 
 const (
@@ -61,6 +71,9 @@ const (
 
 	// DefaultExtCACertDir : Location of external CA certificate
 	DefaultExtCACertDir string = "./etc/external-ca-cert"
+
+	// DefaultExtCAClientCertDir : Location of the client certificate used to authenticate to the external signer
+	DefaultExtCAClientCertDir string = "./etc/external-ca-client-cert"
 )
 
 // ValidateCSR : Validate all SAN extensions in csrPEM match authenticated identities
@@ -99,6 +112,13 @@ func NewIstioRA(opts *IstioRAOptions) (RegistrationAuthority, error) {
 		}
 		return istioRA, err
 	}
+	if opts.ExternalCAType == ExtCAGrpc {
+		istioRA, err := NewGrpcRA(opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create a gRPC CA: %v", err)
+		}
+		return istioRA, err
+	}
 	return nil, fmt.Errorf("invalid CA Name %s", opts.ExternalCAType)
 }
 
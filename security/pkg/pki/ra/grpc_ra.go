@@ -0,0 +1,355 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ra
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+
+	pb "istio.io/api/security/v1alpha1"
+	"istio.io/istio/security/pkg/pki/ca"
+	raerror "istio.io/istio/security/pkg/pki/error"
+	"istio.io/istio/security/pkg/pki/util"
+	"istio.io/pkg/filewatcher"
+	"istio.io/pkg/log"
+)
+
+var grpcRALog = log.RegisterScope("grpcra", "gRPC registration authority debugging", 0)
+
+const (
+	// DefaultSignerTimeout : Default timeout of each request to the external signer
+	DefaultSignerTimeout = 10 * time.Second
+)
+
+// watchDebounceDelay is the delay before reloading a watched file, so that a rotation writing several files is
+// read once.
+const watchDebounceDelay = 100 * time.Millisecond
+
+// GrpcRA integrates with an external CA serving the Istio CA gRPC API, authenticating with mTLS.
+type GrpcRA struct {
+	conn   *grpc.ClientConn
+	client pb.IstioCertificateServiceClient
+	raOpts *IstioRAOptions
+
+	// watcher reloads the root certificate and the client certificate when their files change.
+	watcher filewatcher.FileWatcher
+	stop    chan struct{}
+
+	// mutex protects the credentials read from the watched files.
+	mutex         sync.RWMutex
+	keyCertBundle *util.KeyCertBundle
+	rootCAs       *x509.CertPool
+	clientCert    *tls.Certificate
+}
+
+// NewGrpcRA : Create a RA that forwards CSRs to an external signer over gRPC
+func NewGrpcRA(raOpts *IstioRAOptions) (*GrpcRA, error) {
+	if raOpts.SignerAddr == "" {
+		return nil, raerror.NewError(raerror.CAIllegalConfig, fmt.Errorf("address of the external signer is not set"))
+	}
+	if raOpts.SignerClientCertFile == "" || raOpts.SignerClientKeyFile == "" {
+		return nil, raerror.NewError(raerror.CAIllegalConfig,
+			fmt.Errorf("client certificate and key for external signer are not set"))
+	}
+	r := &GrpcRA{
+		raOpts: raOpts,
+		stop:   make(chan struct{}),
+	}
+	if err := r.loadRootCert(); err != nil {
+		return nil, raerror.NewError(raerror.CAInitFail, fmt.Errorf("error processing Certificate Bundle for gRPC RA: %v", err))
+	}
+	if err := r.loadClientCert(); err != nil {
+		return nil, raerror.NewError(raerror.CAInitFail, err)
+	}
+	r.watcher = filewatcher.NewWatcher()
+	for _, file := range []string{raOpts.CaCertFile, raOpts.SignerClientCertFile, raOpts.SignerClientKeyFile} {
+		if err := r.watcher.Add(file); err != nil {
+			_ = r.watcher.Close()
+			return nil, raerror.NewError(raerror.CAInitFail, fmt.Errorf("could not watch %s: %v", file, err))
+		}
+	}
+	go r.watch()
+
+	timeout := raOpts.SignerTimeout
+	if timeout <= 0 {
+		timeout = DefaultSignerTimeout
+	}
+	retries := raOpts.SignerRetries
+	if retries < 0 {
+		retries = 0
+	}
+	// WithMax counts the first attempt too. It must be at least 1, otherwise the interceptor is bypassed
+	// along with the per attempt timeout.
+	retryOpts := []retry.CallOption{
+		retry.WithMax(uint(retries + 1)),
+		retry.WithPerRetryTimeout(timeout),
+		retry.WithBackoff(retry.BackoffExponentialWithJitter(100*time.Millisecond, 0.1)),
+		retry.WithCodes(codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal, codes.Unavailable),
+	}
+	conn, err := grpc.Dial(raOpts.SignerAddr,
+		grpc.WithTransportCredentials(credentials.NewTLS(r.tlsConfig())),
+		grpc.WithUnaryInterceptor(retry.UnaryClientInterceptor(retryOpts...)))
+	if err != nil {
+		r.Close()
+		return nil, raerror.NewError(raerror.CAInitFail, fmt.Errorf("failed to connect to external signer %s: %v",
+			raOpts.SignerAddr, err))
+	}
+	r.conn = conn
+	r.client = pb.NewIstioCertificateServiceClient(conn)
+	return r, nil
+}
+
+// tlsConfig returns the TLS configuration used to connect to the external signer. The server certificate is
+// verified with the current root certificate of the external CA, and the current client certificate is sent on
+// every handshake, so that both can be rotated without restarting istiod.
+func (r *GrpcRA) tlsConfig() *tls.Config {
+	serverName := signerServerName(r.raOpts.SignerAddr)
+	return &tls.Config{
+		// The server certificate is verified by VerifyPeerCertificate, with the reloaded root certificate.
+		InsecureSkipVerify: true, // nolint: gosec
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return fmt.Errorf("external signer did not send a certificate")
+			}
+			certs := make([]*x509.Certificate, 0, len(rawCerts))
+			for _, raw := range rawCerts {
+				cert, err := x509.ParseCertificate(raw)
+				if err != nil {
+					return fmt.Errorf("failed to parse certificate of external signer: %v", err)
+				}
+				certs = append(certs, cert)
+			}
+			intermediates := x509.NewCertPool()
+			for _, cert := range certs[1:] {
+				intermediates.AddCert(cert)
+			}
+			r.mutex.RLock()
+			roots := r.rootCAs
+			r.mutex.RUnlock()
+			_, err := certs[0].Verify(x509.VerifyOptions{
+				DNSName:       serverName,
+				Roots:         roots,
+				Intermediates: intermediates,
+			})
+			return err
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			r.mutex.RLock()
+			defer r.mutex.RUnlock()
+			return r.clientCert, nil
+		},
+	}
+}
+
+// signerServerName returns the name the certificate of the external signer is verified against: the host of
+// the authority of its address.
+func signerServerName(addr string) string {
+	if i := strings.LastIndex(addr, "/"); i >= 0 {
+		addr = addr[i+1:]
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// loadRootCert reads the root certificate of the external CA from CaCertFile.
+func (r *GrpcRA) loadRootCert() error {
+	bundle, err := util.NewKeyCertBundleWithRootCertFromFile(r.raOpts.CaCertFile)
+	if err != nil {
+		return err
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(bundle.GetRootCertPem()) {
+		return fmt.Errorf("no certificate found in %s", r.raOpts.CaCertFile)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.keyCertBundle = bundle
+	r.rootCAs = rootCAs
+	return nil
+}
+
+// loadClientCert reads the client certificate used to authenticate to the external signer.
+func (r *GrpcRA) loadClientCert() error {
+	cert, err := tls.LoadX509KeyPair(r.raOpts.SignerClientCertFile, r.raOpts.SignerClientKeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load client certificate for external signer: %v", err)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.clientCert = &cert
+	return nil
+}
+
+// watch reloads the root certificate and the client certificate when their files change, until Close is called.
+// Invalid files are ignored, the current credentials are kept until the files are fixed.
+func (r *GrpcRA) watch() {
+	var rootCertTimerC, clientCertTimerC <-chan time.Time
+	for {
+		select {
+		case <-rootCertTimerC:
+			rootCertTimerC = nil
+			if err := r.loadRootCert(); err != nil {
+				grpcRALog.Errorf("failed to reload root certificate of external CA, keeping the current one: %v", err)
+				continue
+			}
+			grpcRALog.Infof("root certificate of external CA reloaded from %s", r.raOpts.CaCertFile)
+		case <-clientCertTimerC:
+			clientCertTimerC = nil
+			if err := r.loadClientCert(); err != nil {
+				grpcRALog.Errorf("failed to reload client certificate for external signer, keeping the current one: %v", err)
+				continue
+			}
+			grpcRALog.Infof("client certificate for external signer reloaded from %s", r.raOpts.SignerClientCertFile)
+		case <-r.watcher.Events(r.raOpts.CaCertFile):
+			if rootCertTimerC == nil {
+				rootCertTimerC = time.After(watchDebounceDelay)
+			}
+		case <-r.watcher.Events(r.raOpts.SignerClientCertFile):
+			if clientCertTimerC == nil {
+				clientCertTimerC = time.After(watchDebounceDelay)
+			}
+		case <-r.watcher.Events(r.raOpts.SignerClientKeyFile):
+			if clientCertTimerC == nil {
+				clientCertTimerC = time.After(watchDebounceDelay)
+			}
+		case err := <-r.watcher.Errors(r.raOpts.CaCertFile):
+			grpcRALog.Errorf("error watching %s: %v", r.raOpts.CaCertFile, err)
+		case err := <-r.watcher.Errors(r.raOpts.SignerClientCertFile):
+			grpcRALog.Errorf("error watching %s: %v", r.raOpts.SignerClientCertFile, err)
+		case err := <-r.watcher.Errors(r.raOpts.SignerClientKeyFile):
+			grpcRALog.Errorf("error watching %s: %v", r.raOpts.SignerClientKeyFile, err)
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+func (r *GrpcRA) grpcSign(csrPEM []byte, lifetime time.Duration) ([]byte, error) {
+	req := &pb.IstioCertificateRequest{
+		Csr:              string(csrPEM),
+		ValidityDuration: int64(lifetime.Seconds()),
+	}
+	resp, err := r.client.CreateCertificate(context.Background(), req)
+	if err != nil {
+		return nil, raerror.NewError(raerror.CertGenError, fmt.Errorf("external signer failed to sign CSR: %v", err))
+	}
+	if len(resp.CertChain) == 0 {
+		return nil, raerror.NewError(raerror.CertGenError, fmt.Errorf("external signer returned an empty cert chain"))
+	}
+	// The root certificate is served from the bundle instead, drop it from the chain when present.
+	certs := resp.CertChain
+	if len(certs) > 1 && r.isRootCert([]byte(certs[len(certs)-1])) {
+		certs = certs[:len(certs)-1]
+	}
+	var certChain []byte
+	for _, c := range certs {
+		certChain = util.AppendCertByte(certChain, []byte(c))
+	}
+	if r.raOpts.VerifyAppendCA {
+		if err := r.verify(certs); err != nil {
+			return nil, raerror.NewError(raerror.CertGenError, err)
+		}
+	}
+	return certChain, nil
+}
+
+// isRootCert returns whether the PEM encoded certificate is the root certificate of the external CA.
+func (r *GrpcRA) isRootCert(certPem []byte) bool {
+	cert, err := util.ParsePemEncodedCertificate(certPem)
+	if err != nil {
+		return false
+	}
+	if cert.IsCA && bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil {
+		return true
+	}
+	return bytes.Contains(r.GetCAKeyCertBundle().GetRootCertPem(), bytes.TrimSpace(certPem))
+}
+
+// verify checks that the leaf certificate chains up to the root certificate of the external CA.
+func (r *GrpcRA) verify(certs []string) error {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(r.GetCAKeyCertBundle().GetRootCertPem()) {
+		return fmt.Errorf("failed to parse root certificate of external CA")
+	}
+	leaf, err := util.ParsePemEncodedCertificate([]byte(certs[0]))
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate returned by external signer: %v", err)
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AppendCertsFromPEM([]byte(c))
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("failed to verify certificate returned by external signer: %v", err)
+	}
+	return nil
+}
+
+// Sign takes a PEM-encoded CSR and cert opts, and returns a certificate signed by the external signer.
+func (r *GrpcRA) Sign(csrPEM []byte, certOpts ca.CertOpts) ([]byte, error) {
+	lifetime, err := preSign(r.raOpts, csrPEM, certOpts.SubjectIDs, certOpts.TTL, certOpts.ForCA)
+	if err != nil {
+		return nil, err
+	}
+	return r.grpcSign(csrPEM, lifetime)
+}
+
+// SignWithCertChain is similar to Sign but returns the leaf cert and the entire cert chain.
+func (r *GrpcRA) SignWithCertChain(csrPEM []byte, certOpts ca.CertOpts) ([]byte, error) {
+	cert, err := r.Sign(csrPEM, certOpts)
+	if err != nil {
+		return nil, err
+	}
+	chainPem := r.GetCAKeyCertBundle().GetCertChainPem()
+	if len(chainPem) > 0 {
+		cert = append(cert, chainPem...)
+	}
+	return cert, nil
+}
+
+// GetCAKeyCertBundle returns the KeyCertBundle for the CA. The root certificate is reloaded when CaCertFile
+// changes.
+func (r *GrpcRA) GetCAKeyCertBundle() *util.KeyCertBundle {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.keyCertBundle
+}
+
+// Close stops watching the certificate files and closes the connection to the external signer.
+func (r *GrpcRA) Close() {
+	close(r.stop)
+	_ = r.watcher.Close()
+	if r.conn != nil {
+		_ = r.conn.Close()
+	}
+}
@@ -0,0 +1,313 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ra
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"istio.io/istio/pkg/test/util/retry"
+	"istio.io/istio/security/pkg/pki/ca"
+	raerror "istio.io/istio/security/pkg/pki/error"
+	"istio.io/istio/security/pkg/pki/ra/mock"
+	pkiutil "istio.io/istio/security/pkg/pki/util"
+)
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(file, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func createGrpcRA(t *testing.T, signer *mock.Signer, modify func(opts *IstioRAOptions)) *GrpcRA {
+	t.Helper()
+	dir := t.TempDir()
+	clientCert, clientKey, err := signer.IssueClientCert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &IstioRAOptions{
+		ExternalCAType:       ExtCAGrpc,
+		DefaultCertTTL:       time.Hour,
+		MaxCertTTL:           24 * time.Hour,
+		CaCertFile:           writeFile(t, dir, "root-cert.pem", signer.RootCertPem),
+		VerifyAppendCA:       true,
+		TrustDomain:          "cluster.local",
+		SignerAddr:           signer.URL,
+		SignerClientCertFile: writeFile(t, dir, "tls.crt", clientCert),
+		SignerClientKeyFile:  writeFile(t, dir, "tls.key", clientKey),
+		SignerTimeout:        time.Second,
+		SignerRetries:        2,
+	}
+	if modify != nil {
+		modify(opts)
+	}
+	r, err := NewIstioRA(opts)
+	if err != nil {
+		t.Fatalf("failed to create gRPC RA: %v", err)
+	}
+	t.Cleanup(r.(*GrpcRA).Close)
+	return r.(*GrpcRA)
+}
+
+func newSigner(t *testing.T) *mock.Signer {
+	t.Helper()
+	signer, err := mock.NewSigner()
+	if err != nil {
+		t.Fatalf("failed to create mock signer: %v", err)
+	}
+	t.Cleanup(signer.Stop)
+	return signer
+}
+
+func TestGrpcRASign(t *testing.T) {
+	signer := newSigner(t)
+	r := createGrpcRA(t, signer, nil)
+	certOpts := ca.CertOpts{SubjectIDs: []string{testCsrHostName}, TTL: time.Hour}
+
+	certChain, err := r.SignWithCertChain(createFakeCsr(t), certOpts)
+	if err != nil {
+		t.Fatalf("failed to sign CSR: %v", err)
+	}
+	certs := bytes.Count(certChain, []byte("BEGIN CERTIFICATE"))
+	if certs != 2 {
+		t.Errorf("got %d certificates, want leaf and intermediate certificates", certs)
+	}
+	leaf, err := pkiutil.ParsePemEncodedCertificate(certChain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err := pkiutil.ExtractIDs(leaf.Extensions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != testCsrHostName {
+		t.Errorf("got identities %v, want %v", ids, testCsrHostName)
+	}
+	if !bytes.Equal(r.GetCAKeyCertBundle().GetRootCertPem(), signer.RootCertPem) {
+		t.Errorf("root certificate of the bundle does not match the signer")
+	}
+}
+
+func TestGrpcRASignErrors(t *testing.T) {
+	cases := []struct {
+		name         string
+		modify       func(opts *IstioRAOptions)
+		subjectIDs   []string
+		failures     int
+		delay        time.Duration
+		wantErr      bool
+		wantRequests int
+	}{
+		{
+			name:         "invalid identity",
+			subjectIDs:   []string{"spiffe://cluster.local/ns/default/sa/other"},
+			wantErr:      true,
+			wantRequests: 0,
+		},
+		{
+			name:         "retried",
+			failures:     2,
+			wantRequests: 3,
+		},
+		{
+			name:         "retries exhausted",
+			failures:     3,
+			wantErr:      true,
+			wantRequests: 3,
+		},
+		{
+			name:         "no retries",
+			modify:       func(opts *IstioRAOptions) { opts.SignerRetries = 0 },
+			failures:     1,
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name: "timeout",
+			modify: func(opts *IstioRAOptions) {
+				opts.SignerTimeout = 50 * time.Millisecond
+				opts.SignerRetries = 1
+			},
+			delay:        time.Second,
+			wantErr:      true,
+			wantRequests: 2,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := newSigner(t)
+			r := createGrpcRA(t, signer, tt.modify)
+			signer.FailRequests(tt.failures)
+			signer.Delay(tt.delay)
+			subjectIDs := tt.subjectIDs
+			if subjectIDs == nil {
+				subjectIDs = []string{testCsrHostName}
+			}
+
+			_, err := r.Sign(createFakeCsr(t), ca.CertOpts{SubjectIDs: subjectIDs, TTL: time.Hour})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if _, ok := err.(*raerror.Error); !ok {
+					t.Errorf("got error of type %T, want *error.Error", err)
+				}
+			}
+			if got := signer.Requests(); got != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestGrpcRACertReload(t *testing.T) {
+	signer := newSigner(t)
+	r := createGrpcRA(t, signer, nil)
+
+	newRoot, newRootKey, err := pkiutil.GenCertKeyFromOptions(pkiutil.CertOptions{
+		TTL:          time.Hour,
+		Org:          "new-root",
+		IsCA:         true,
+		IsSelfSigned: true,
+		RSAKeySize:   2048,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRootCert, err := pkiutil.ParsePemEncodedCertificate(newRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRootPriv, err := pkiutil.ParsePemEncodedKey(newRootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverCertPem, _, err := pkiutil.GenCertKeyFromOptions(pkiutil.CertOptions{
+		TTL:        time.Hour,
+		Host:       "localhost",
+		IsServer:   true,
+		SignerCert: newRootCert,
+		SignerPriv: newRootPriv,
+		RSAKeySize: 2048,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverCert, err := pkiutil.ParsePemEncodedCertificate(serverCertPem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify := r.tlsConfig().VerifyPeerCertificate
+	if err := verify([][]byte{serverCert.Raw}, nil); err == nil {
+		t.Fatal("expected a server certificate of an unknown CA to be rejected")
+	}
+
+	// The server certificates are verified with the reloaded root certificate.
+	rootCert := append(append([]byte{}, signer.RootCertPem...), newRoot...)
+	if err := ioutil.WriteFile(r.raOpts.CaCertFile, rootCert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	retry.UntilSuccessOrFail(t, func() error {
+		if got := r.GetCAKeyCertBundle().GetRootCertPem(); !bytes.Equal(got, rootCert) {
+			return fmt.Errorf("root certificate was not reloaded")
+		}
+		return verify([][]byte{serverCert.Raw}, nil)
+	}, retry.Timeout(5*time.Second))
+
+	// An invalid file keeps the current root certificate.
+	if err := ioutil.WriteFile(r.raOpts.CaCertFile, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * watchDebounceDelay)
+	if got := r.GetCAKeyCertBundle().GetRootCertPem(); !bytes.Equal(got, rootCert) {
+		t.Errorf("root certificate was replaced by an invalid one")
+	}
+
+	// The rotated client certificate is sent on the next handshakes.
+	clientCert, clientKey, err := signer.IssueClientCert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(r.raOpts.SignerClientKeyFile, clientKey, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(r.raOpts.SignerClientCertFile, clientCert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(clientCert)
+	retry.UntilSuccessOrFail(t, func() error {
+		got, err := r.tlsConfig().GetClientCertificate(nil)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(got.Certificate[0], block.Bytes) {
+			return fmt.Errorf("client certificate was not reloaded")
+		}
+		return nil
+	}, retry.Timeout(5*time.Second))
+}
+
+func TestSignerServerName(t *testing.T) {
+	for addr, want := range map[string]string{
+		"localhost:8443":          "localhost",
+		"dns:///signer.ca:443":    "signer.ca",
+		"signer.ca":               "signer.ca",
+		"[2001:db8::1]:443":       "2001:db8::1",
+		"dns://8.8.8.8/signer.ca": "signer.ca",
+	} {
+		if got := signerServerName(addr); got != want {
+			t.Errorf("signerServerName(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
+
+func TestNewGrpcRAErrors(t *testing.T) {
+	signer := newSigner(t)
+	dir := t.TempDir()
+	rootCertFile := writeFile(t, dir, "root-cert.pem", signer.RootCertPem)
+	cases := []struct {
+		name string
+		opts *IstioRAOptions
+	}{
+		{
+			name: "missing address",
+			opts: &IstioRAOptions{ExternalCAType: ExtCAGrpc, CaCertFile: rootCertFile},
+		},
+		{
+			name: "missing client certificate",
+			opts: &IstioRAOptions{ExternalCAType: ExtCAGrpc, CaCertFile: rootCertFile, SignerAddr: signer.URL},
+		},
+		{
+			name: "missing root certificate",
+			opts: &IstioRAOptions{ExternalCAType: ExtCAGrpc, CaCertFile: filepath.Join(dir, "missing"), SignerAddr: signer.URL},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewIstioRA(tt.opts); err == nil {
+				t.Errorf("expected error creating gRPC RA")
+			}
+		})
+	}
+}
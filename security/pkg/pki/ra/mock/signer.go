@@ -0,0 +1,213 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	pb "istio.io/api/security/v1alpha1"
+	"istio.io/istio/security/pkg/pki/util"
+	"istio.io/pkg/log"
+)
+
+var signerLog = log.RegisterScope("mocksigner", "mock external signer debugging", 0)
+
+// Signer is a mock external signer serving the Istio certificate API over mTLS. Certificates are issued by
+// an intermediate CA for the identities requested in the CSRs, and only clients presenting a certificate
+// issued by the root CA of the signer are accepted.
+type Signer struct {
+	URL         string
+	RootCertPem []byte
+	GRPCServer  *grpc.Server
+
+	rootCert         *x509.Certificate
+	rootKey          crypto.PrivateKey
+	intermediateCert *x509.Certificate
+	intermediateKey  crypto.PrivateKey
+	intermediatePem  []byte
+
+	mutex    sync.Mutex
+	failures int
+	delay    time.Duration
+	requests int
+}
+
+// NewSigner creates a mock external signer listening on a random local port.
+func NewSigner() (*Signer, error) {
+	s := &Signer{}
+	rootCert, rootKey, err := util.GenCertKeyFromOptions(util.CertOptions{
+		TTL:          24 * time.Hour,
+		Org:          "external-signer",
+		IsCA:         true,
+		IsSelfSigned: true,
+		RSAKeySize:   2048,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create root certificate: %v", err)
+	}
+	s.RootCertPem = rootCert
+	if s.rootCert, s.rootKey, err = parseCertKey(rootCert, rootKey); err != nil {
+		return nil, err
+	}
+
+	intermediateCert, intermediateKey, err := s.issue(util.CertOptions{IsCA: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create intermediate certificate: %v", err)
+	}
+	s.intermediatePem = intermediateCert
+	if s.intermediateCert, s.intermediateKey, err = parseCertKey(intermediateCert, intermediateKey); err != nil {
+		return nil, err
+	}
+
+	serverCert, serverKey, err := s.issue(util.CertOptions{Host: "localhost", IsServer: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server certificate: %v", err)
+	}
+	keyPair, err := tls.X509KeyPair(serverCert, serverKey)
+	if err != nil {
+		return nil, err
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(s.rootCert)
+	s.GRPCServer = grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{keyPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	})))
+	pb.RegisterIstioCertificateServiceServer(s.GRPCServer, s)
+	return s, s.start()
+}
+
+// IssueClientCert returns a certificate and key accepted by the signer to authenticate clients.
+func (s *Signer) IssueClientCert() (certPem []byte, keyPem []byte, err error) {
+	return s.issue(util.CertOptions{Host: "spiffe://cluster.local/ns/istio-system/sa/istiod", IsClient: true})
+}
+
+// FailRequests makes the signer reject the next n CSRs as unavailable.
+func (s *Signer) FailRequests(n int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failures = n
+}
+
+// Delay makes the signer wait for d before answering each CSR.
+func (s *Signer) Delay(d time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.delay = d
+}
+
+// Requests returns the number of CSRs received by the signer.
+func (s *Signer) Requests() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+// Stop stops the signer.
+func (s *Signer) Stop() {
+	s.GRPCServer.Stop()
+}
+
+// CreateCertificate handles CSR.
+func (s *Signer) CreateCertificate(ctx context.Context, request *pb.IstioCertificateRequest) (
+	*pb.IstioCertificateResponse, error) {
+	s.mutex.Lock()
+	s.requests++
+	fail := s.failures > 0
+	if fail {
+		s.failures--
+	}
+	delay := s.delay
+	s.mutex.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	if fail {
+		signerLog.Info("force rejecting CSR request")
+		return nil, status.Error(codes.Unavailable, "signer is not available")
+	}
+
+	csr, err := util.ParsePemEncodedCSR([]byte(request.Csr))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse CSR: %v", err)
+	}
+	ids, err := util.ExtractIDs(csr.Extensions)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to extract identities from CSR: %v", err)
+	}
+	ttl := time.Duration(request.ValidityDuration) * time.Second
+	certBytes, err := util.GenCertFromCSR(csr, s.intermediateCert, csr.PublicKey, s.intermediateKey, ids, ttl, false)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to sign CSR: %v", err)
+	}
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes})
+	return &pb.IstioCertificateResponse{
+		CertChain: []string{string(cert), string(s.intermediatePem), string(s.RootCertPem)},
+	}, nil
+}
+
+func (s *Signer) issue(options util.CertOptions) ([]byte, []byte, error) {
+	options.TTL = 24 * time.Hour
+	options.Org = "external-signer"
+	options.RSAKeySize = 2048
+	options.SignerCert = s.rootCert
+	options.SignerPriv = s.rootKey
+	return util.GenCertKeyFromOptions(options)
+}
+
+func (s *Signer) start() error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	s.URL = fmt.Sprintf("localhost:%d", listener.Addr().(*net.TCPAddr).Port)
+	go func() {
+		if err := s.GRPCServer.Serve(listener); err != nil && err != grpc.ErrServerStopped {
+			signerLog.Errorf("signer failed to serve on %q: %v", s.URL, err)
+		}
+	}()
+	return nil
+}
+
+func parseCertKey(certPem, keyPem []byte) (*x509.Certificate, crypto.PrivateKey, error) {
+	cert, err := util.ParsePemEncodedCertificate(certPem)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := util.ParsePemEncodedKey(keyPem)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
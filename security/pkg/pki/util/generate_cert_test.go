@@ -102,7 +102,7 @@ func TestGenCertKeyFromOptions(t *testing.T) {
 		NotBefore:   caCertNotBefore,
 		TTL:         caCertTTL,
 		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
-		KeyUsage:    x509.KeyUsageCertSign,
+		KeyUsage:    x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
 		IsCA:        true,
 		Org:         "MyOrg",
 		Host:        host,
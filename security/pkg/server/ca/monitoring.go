@@ -35,6 +35,11 @@ var (
 		"The number of authentication failures.",
 	)
 
+	revokedIdentityCounts = monitoring.NewSum(
+		"citadel_server_revoked_identity_count",
+		"The number of CSRs refused because the identity is revoked.",
+	)
+
 	csrParsingErrorCounts = monitoring.NewSum(
 		"citadel_server_csr_parsing_err_count",
 		"The number of errors occurred when parsing the CSR.",
@@ -72,6 +77,7 @@ func init() {
 	monitoring.MustRegister(
 		csrCounts,
 		authnErrorCounts,
+		revokedIdentityCounts,
 		csrParsingErrorCounts,
 		idExtractionErrorCounts,
 		certSignErrorCounts,
@@ -85,6 +91,7 @@ func init() {
 type monitoringMetrics struct {
 	CSR               monitoring.Metric
 	AuthnError        monitoring.Metric
+	RevokedIdentity   monitoring.Metric
 	Success           monitoring.Metric
 	CSRError          monitoring.Metric
 	IDExtractionError monitoring.Metric
@@ -96,6 +103,7 @@ func newMonitoringMetrics() monitoringMetrics {
 	return monitoringMetrics{
 		CSR:               csrCounts,
 		AuthnError:        authnErrorCounts,
+		RevokedIdentity:   revokedIdentityCounts,
 		Success:           successCounts,
 		CSRError:          csrParsingErrorCounts,
 		IDExtractionError: idExtractionErrorCounts,
@@ -44,6 +44,12 @@ type CertificateAuthority interface {
 	GetCAKeyCertBundle() *util.KeyCertBundle
 }
 
+// revocationChecker is implemented by the CAs which can revoke identities.
+type revocationChecker interface {
+	// IsRevoked returns true if one of the identities is revoked.
+	IsRevoked(identities []string) bool
+}
+
 // Server implements IstioCAService and IstioCertificateService and provides the services on the
 // specified port.
 type Server struct {
@@ -79,6 +85,12 @@ func (s *Server) CreateCertificate(ctx context.Context, request *pb.IstioCertifi
 
 	// TODO: Call authorizer.
 
+	if rc, ok := s.ca.(revocationChecker); ok && rc.IsRevoked(caller.Identities) {
+		s.monitoring.RevokedIdentity.Increment()
+		serverCaLog.Warnf("Refusing to sign a certificate for the revoked identities %v", caller.Identities)
+		return nil, status.Error(codes.PermissionDenied, "the identity is revoked")
+	}
+
 	_, _, certChainBytes, rootCertBytes := s.ca.GetCAKeyCertBundle().GetAll()
 	certOpts := ca.CertOpts{
 		SubjectIDs: caller.Identities,
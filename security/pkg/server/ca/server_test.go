@@ -218,6 +218,16 @@ func TestCreateCertificate(t *testing.T) {
 			ca:             &mockca.FakeCA{SignErr: caerror.NewError(caerror.CertGenError, fmt.Errorf("cannot sign"))},
 			code:           codes.Internal,
 		},
+		"Revoked identity": {
+			authenticators: []security.Authenticator{&mockAuthenticator{
+				identities: []string{"spiffe://cluster.local/ns/foo/sa/bar"},
+			}},
+			ca: &mockca.FakeCA{
+				SignedCert: []byte("cert"),
+				RevokedIDs: []string{"spiffe://cluster.local/ns/foo/sa/bar"},
+			},
+			code: codes.PermissionDenied,
+		},
 		"Successful signing": {
 			authenticators: []security.Authenticator{&mockAuthenticator{}},
 			ca: &mockca.FakeCA{